@@ -3,54 +3,41 @@ package main
 import (
 	"log"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
 
-	"github.com/gin-gonic/gin"
+	"github.com/OshakbayAigerim/read_space/api_gateway/internal/config"
+	"github.com/OshakbayAigerim/read_space/api_gateway/internal/handler"
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	exchangepb "github.com/OshakbayAigerim/read_space/exchange_service/proto"
+	orderpb "github.com/OshakbayAigerim/read_space/order_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+	userpb "github.com/OshakbayAigerim/read_space/user_service/proto"
 )
 
-// proxy возвращает gin.HandlerFunc, проксирующий запрос к target
-func proxy(target *url.URL) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		director := func(req *http.Request) {
-			req.URL.Scheme = target.Scheme
-			req.URL.Host = target.Host
-			req.URL.Path = c.Param("proxyPath")
-			if raw := c.Request.URL.RawQuery; raw != "" {
-				req.URL.RawQuery = raw
-			}
-			req.Header = c.Request.Header
-		}
-		p := &httputil.ReverseProxy{Director: director}
-		p.ServeHTTP(c.Writer, c.Request)
-	}
-}
-
 func main() {
-	r := gin.Default()
+	cfg := config.Load()
 
-	// Список сервисов и их базовые URL
-	services := map[string]string{
-		"books":         "http://book_service:50051",
-		"users":         "http://user_service:50052",
-		"libraries":     "http://user_library_service:50053",
-		"exchange":      "http://exchange_service:50054",
-		"orders":        "http://order_service:50055",
-		"notifications": "http://notification_service:50056",
-	}
+	bookConn := config.Dial("BookService", cfg.BookAddr)
+	defer bookConn.Close()
+	userConn := config.Dial("UserService", cfg.UserAddr)
+	defer userConn.Close()
+	orderConn := config.Dial("OrderService", cfg.OrderAddr)
+	defer orderConn.Close()
+	exchangeConn := config.Dial("ExchangeService", cfg.ExchangeAddr)
+	defer exchangeConn.Close()
+	libConn := config.Dial("UserLibraryService", cfg.UserLibraryAddr)
+	defer libConn.Close()
 
-	// Для каждого сервиса заводим маршрут вида /<service>/*proxyPath
-	for prefix, addr := range services {
-		target, err := url.Parse(addr)
-		if err != nil {
-			log.Fatalf("invalid URL for %s: %v", prefix, err)
-		}
-		group := r.Group("/" + prefix)
-		group.Any("/*proxyPath", proxy(target))
-	}
+	// Каждый обработчик переводит REST-запросы в вызовы своего gRPC-сервиса
+	mux := http.NewServeMux()
+	handler.NewBookHandler(bookpb.NewBookServiceClient(bookConn)).Register(mux)
+	handler.NewUserHandler(userpb.NewUserServiceClient(userConn)).Register(mux)
+	handler.NewOrderHandler(orderpb.NewOrderServiceClient(orderConn)).Register(mux)
+	handler.NewExchangeHandler(exchangepb.NewExchangeServiceClient(exchangeConn)).Register(mux)
+	handler.NewLibraryHandler(userlibpb.NewUserLibraryServiceClient(libConn)).Register(mux)
 
-	log.Println("🚀 API Gateway running on :8080")
-	if err := r.Run(":8080"); err != nil {
+	log.Printf("🚀 API Gateway running on %s", cfg.HTTPAddr)
+	if err := http.ListenAndServe(cfg.HTTPAddr, handler.Authenticate(auth.SecretFromEnv(), mux)); err != nil {
 		log.Fatalf("failed to run API Gateway: %v", err)
 	}
 }
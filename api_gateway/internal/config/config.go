@@ -0,0 +1,45 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Config struct {
+	HTTPAddr        string
+	BookAddr        string
+	UserAddr        string
+	OrderAddr       string
+	ExchangeAddr    string
+	UserLibraryAddr string
+}
+
+func Load() *Config {
+	return &Config{
+		HTTPAddr:        getEnv("GATEWAY_HTTP_ADDR", ":8080"),
+		BookAddr:        getEnv("BOOK_SERVICE_ADDR", "localhost:50051"),
+		UserAddr:        getEnv("USER_SERVICE_ADDR", "localhost:50052"),
+		OrderAddr:       getEnv("ORDER_SERVICE_ADDR", "localhost:50053"),
+		ExchangeAddr:    getEnv("EXCHANGE_SERVICE_ADDR", "localhost:50054"),
+		UserLibraryAddr: getEnv("USER_LIBRARY_SERVICE_ADDR", "localhost:50055"),
+	}
+}
+
+func Dial(name, addr string) *grpc.ClientConn {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("cannot dial %s at %s: %v", name, addr, err)
+	}
+	log.Printf(" %s client ready (%s)", name, addr)
+	return conn
+}
+
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
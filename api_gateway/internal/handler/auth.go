@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+)
+
+// Authenticate validates the bearer token of incoming requests and forwards
+// it to the downstream services, which make the authorization decisions.
+// Requests without a token are passed on anonymously.
+func Authenticate(secret []byte, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, ok := auth.BearerToken(header)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "malformed authorization header")
+			return
+		}
+		if _, err := auth.ParseToken(secret, token); err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		ctx := auth.OutgoingContext(r.Context(), "Bearer "+token)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
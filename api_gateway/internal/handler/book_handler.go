@@ -0,0 +1,356 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
+)
+
+type BookHandler struct {
+	client bookpb.BookServiceClient
+}
+
+func NewBookHandler(client bookpb.BookServiceClient) *BookHandler {
+	return &BookHandler{client: client}
+}
+
+func (h *BookHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /books", h.createBook)
+	mux.HandleFunc("GET /books", h.listBooks)
+	mux.HandleFunc("GET /books/query", h.queryBooks)
+	mux.HandleFunc("GET /books/search", h.searchBooks)
+	mux.HandleFunc("GET /books/suggest", h.suggestTitles)
+	mux.HandleFunc("GET /books/top-rated", h.listTopRated)
+	mux.HandleFunc("GET /books/new-arrivals", h.listNewArrivals)
+	mux.HandleFunc("GET /books/{id}", h.getBook)
+	mux.HandleFunc("PUT /books/{id}", h.updateBook)
+	mux.HandleFunc("DELETE /books/{id}", h.deleteBook)
+	mux.HandleFunc("GET /books/{id}/recommendations", h.recommendBooks)
+	mux.HandleFunc("GET /isbn/{isbn}", h.getBookByISBN)
+	mux.HandleFunc("GET /books/{id}/reviews", h.listBookReviews)
+	mux.HandleFunc("POST /books/{id}/reviews", h.submitReview)
+	mux.HandleFunc("PUT /reviews/{id}", h.updateReview)
+	mux.HandleFunc("DELETE /reviews/{id}", h.deleteReview)
+	mux.HandleFunc("GET /users/{id}/reviews", h.listUserReviews)
+	mux.HandleFunc("GET /users/{id}/recommendations", h.recommendForUser)
+	mux.HandleFunc("POST /authors", h.createAuthor)
+	mux.HandleFunc("GET /authors", h.listAuthors)
+	mux.HandleFunc("GET /authors/lookup", h.findAuthor)
+	mux.HandleFunc("GET /authors/{id}", h.getAuthor)
+	mux.HandleFunc("PUT /authors/{id}", h.updateAuthor)
+	mux.HandleFunc("DELETE /authors/{id}", h.deleteAuthor)
+	mux.HandleFunc("GET /authors/{id}/books", h.listAuthorBooks)
+}
+
+func (h *BookHandler) createBook(w http.ResponseWriter, r *http.Request) {
+	var book bookpb.Book
+	if err := decode(r, &book); err != nil {
+		badRequest(w, err)
+		return
+	}
+	resp, err := h.client.CreateBook(r.Context(), &bookpb.CreateBookRequest{Book: &book})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusCreated, resp.Book)
+}
+
+func (h *BookHandler) getBook(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetBook(r.Context(), &bookpb.BookID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Book)
+}
+
+func (h *BookHandler) getBookByISBN(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetBookByISBN(r.Context(), &bookpb.ISBNRequest{Isbn: r.PathValue("isbn")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Book)
+}
+
+func (h *BookHandler) updateBook(w http.ResponseWriter, r *http.Request) {
+	var book bookpb.Book
+	if err := decode(r, &book); err != nil {
+		badRequest(w, err)
+		return
+	}
+	book.Id = r.PathValue("id")
+	resp, err := h.client.UpdateBook(r.Context(), &bookpb.UpdateBookRequest{Book: &book})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Book)
+}
+
+func (h *BookHandler) deleteBook(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.client.DeleteBook(r.Context(), &bookpb.BookID{Id: r.PathValue("id")}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listBooks serves GET /books. At most one of the genre, author, language
+// or q query parameters selects the matching BookService listing.
+func (h *BookHandler) listBooks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	ctx := r.Context()
+
+	var (
+		resp *bookpb.BookList
+		err  error
+	)
+	switch {
+	case q.Get("q") != "":
+		resp, err = h.client.SearchBooks(ctx, &bookpb.SearchRequest{Keyword: q.Get("q")})
+	case q.Get("genre") != "":
+		resp, err = h.client.ListBooksByGenre(ctx, &bookpb.GenreRequest{Genre: q.Get("genre")})
+	case q.Get("author") != "":
+		resp, err = h.client.ListBooksByAuthor(ctx, &bookpb.AuthorRequest{Author: q.Get("author")})
+	case q.Get("language") != "":
+		resp, err = h.client.ListBooksByLanguage(ctx, &bookpb.LanguageRequest{Language: q.Get("language")})
+	default:
+		resp, err = h.client.ListAllBooks(ctx, &bookpb.Empty{})
+	}
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Books)
+}
+
+// queryBooks serves GET /books/query. Every QueryBooksRequest field can be
+// given as a query parameter, e.g. ?genre=Fantasy&min_price=5&sort=-rating.
+func (h *BookHandler) queryBooks(w http.ResponseWriter, r *http.Request) {
+	var req bookpb.QueryBooksRequest
+	if err := decodeQuery(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid query: "+err.Error())
+		return
+	}
+	resp, err := h.client.QueryBooks(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp)
+}
+
+// searchBooks serves GET /books/search?keyword=&limit=. Unlike GET /books?q=
+// it returns each book with its score and highlighted snippets.
+func (h *BookHandler) searchBooks(w http.ResponseWriter, r *http.Request) {
+	var req bookpb.SearchRequest
+	if err := decodeQuery(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid query: "+err.Error())
+		return
+	}
+	resp, err := h.client.SearchWithHighlights(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Hits)
+}
+
+// suggestTitles serves GET /books/suggest?prefix=&limit=.
+func (h *BookHandler) suggestTitles(w http.ResponseWriter, r *http.Request) {
+	var req bookpb.SuggestRequest
+	if err := decodeQuery(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid query: "+err.Error())
+		return
+	}
+	resp, err := h.client.SuggestTitles(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Suggestions)
+}
+
+func (h *BookHandler) listTopRated(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListTopRatedBooks(r.Context(), &bookpb.Empty{})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Books)
+}
+
+func (h *BookHandler) listNewArrivals(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListNewArrivals(r.Context(), &bookpb.Empty{})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Books)
+}
+
+func (h *BookHandler) recommendBooks(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.RecommendBooks(r.Context(), &bookpb.BookID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Books)
+}
+
+func (h *BookHandler) listBookReviews(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListBookReviews(r.Context(), &bookpb.BookID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Reviews)
+}
+
+// submitReview serves POST /books/{id}/reviews with a body of
+// {"user_id", "rating", "text"}.
+func (h *BookHandler) submitReview(w http.ResponseWriter, r *http.Request) {
+	var req bookpb.SubmitReviewRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.BookId = r.PathValue("id")
+	resp, err := h.client.SubmitReview(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusCreated, resp.Review)
+}
+
+func (h *BookHandler) updateReview(w http.ResponseWriter, r *http.Request) {
+	var req bookpb.UpdateReviewRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.Id = r.PathValue("id")
+	resp, err := h.client.UpdateReview(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Review)
+}
+
+func (h *BookHandler) deleteReview(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.client.DeleteReview(r.Context(), &bookpb.ReviewID{Id: r.PathValue("id")}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BookHandler) listUserReviews(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListUserReviews(r.Context(), &bookpb.UserReviewsRequest{UserId: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Reviews)
+}
+
+// recommendForUser serves GET /users/{id}/recommendations with an optional
+// ?limit=.
+func (h *BookHandler) recommendForUser(w http.ResponseWriter, r *http.Request) {
+	var limit int64
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.ParseInt(l, 10, 32); err != nil {
+			badRequest(w, err)
+			return
+		}
+	}
+	resp, err := h.client.RecommendForUser(r.Context(), &bookpb.UserRecommendationsRequest{
+		UserId: r.PathValue("id"),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Books)
+}
+
+func (h *BookHandler) createAuthor(w http.ResponseWriter, r *http.Request) {
+	var author bookpb.Author
+	if err := decode(r, &author); err != nil {
+		badRequest(w, err)
+		return
+	}
+	resp, err := h.client.CreateAuthor(r.Context(), &bookpb.CreateAuthorRequest{Author: &author})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusCreated, resp.Author)
+}
+
+func (h *BookHandler) listAuthors(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListAuthors(r.Context(), &bookpb.Empty{})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Authors)
+}
+
+// findAuthor serves GET /authors/lookup?name=, matching names and aliases.
+func (h *BookHandler) findAuthor(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.FindAuthor(r.Context(), &bookpb.FindAuthorRequest{Name: r.URL.Query().Get("name")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Author)
+}
+
+func (h *BookHandler) getAuthor(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetAuthor(r.Context(), &bookpb.AuthorID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Author)
+}
+
+func (h *BookHandler) updateAuthor(w http.ResponseWriter, r *http.Request) {
+	var author bookpb.Author
+	if err := decode(r, &author); err != nil {
+		badRequest(w, err)
+		return
+	}
+	author.Id = r.PathValue("id")
+	resp, err := h.client.UpdateAuthor(r.Context(), &bookpb.UpdateAuthorRequest{Author: &author})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Author)
+}
+
+func (h *BookHandler) deleteAuthor(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.client.DeleteAuthor(r.Context(), &bookpb.AuthorID{Id: r.PathValue("id")}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BookHandler) listAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListBooksByAuthorID(r.Context(), &bookpb.AuthorID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Books)
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"net/http"
+
+	exchangepb "github.com/OshakbayAigerim/read_space/exchange_service/proto"
+)
+
+type ExchangeHandler struct {
+	client exchangepb.ExchangeServiceClient
+}
+
+func NewExchangeHandler(client exchangepb.ExchangeServiceClient) *ExchangeHandler {
+	return &ExchangeHandler{client: client}
+}
+
+func (h *ExchangeHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /exchange", h.createOffer)
+	mux.HandleFunc("GET /exchange", h.listOffers)
+	mux.HandleFunc("GET /exchange/pending", h.listPending)
+	mux.HandleFunc("GET /exchange/{id}", h.getOffer)
+	mux.HandleFunc("PUT /exchange/{id}", h.updateOffer)
+	mux.HandleFunc("DELETE /exchange/{id}", h.deleteOffer)
+	mux.HandleFunc("POST /exchange/{id}/accept", h.acceptOffer)
+	mux.HandleFunc("POST /exchange/{id}/decline", h.declineOffer)
+	mux.HandleFunc("POST /exchange/{id}/counter", h.counterOffer)
+	mux.HandleFunc("GET /exchange/{id}/thread", h.getThread)
+	mux.HandleFunc("POST /exchange/{id}/books", h.addOfferedBook)
+	mux.HandleFunc("DELETE /exchange/{id}/books/{book_id}", h.removeOfferedBook)
+	mux.HandleFunc("GET /users/{id}/exchange", h.listOffersByUser)
+
+	mux.HandleFunc("GET /users/{id}/wishlist", h.getWishlist)
+	mux.HandleFunc("PUT /users/{id}/wishlist", h.setWishlist)
+	mux.HandleFunc("GET /users/{id}/exchange-groups", h.listGroupsByUser)
+	mux.HandleFunc("POST /exchange-groups/match", h.matchGroups)
+	mux.HandleFunc("GET /exchange-groups/{id}", h.getGroup)
+	mux.HandleFunc("POST /exchange-groups/{id}/accept", h.acceptGroup)
+	mux.HandleFunc("POST /exchange-groups/{id}/decline", h.declineGroup)
+}
+
+func (h *ExchangeHandler) createOffer(w http.ResponseWriter, r *http.Request) {
+	var req exchangepb.CreateOfferRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	resp, err := h.client.CreateOffer(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusCreated, resp.Offer)
+}
+
+func (h *ExchangeHandler) getOffer(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetOffer(r.Context(), &exchangepb.OfferID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Offer)
+}
+
+// listOffers serves GET /exchange, optionally filtered by ?status=.
+func (h *ExchangeHandler) listOffers(w http.ResponseWriter, r *http.Request) {
+	var (
+		resp *exchangepb.OfferList
+		err  error
+	)
+	if st := r.URL.Query().Get("status"); st != "" {
+		resp, err = h.client.ListOffersByStatus(r.Context(), &exchangepb.StatusRequest{Status: st})
+	} else {
+		resp, err = h.client.ListAllOffers(r.Context(), &exchangepb.Empty{})
+	}
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Offers)
+}
+
+func (h *ExchangeHandler) listPending(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListPendingOffers(r.Context(), &exchangepb.Empty{})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Offers)
+}
+
+func (h *ExchangeHandler) listOffersByUser(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListOffersByUser(r.Context(), &exchangepb.UserID{UserId: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Offers)
+}
+
+func (h *ExchangeHandler) updateOffer(w http.ResponseWriter, r *http.Request) {
+	var offer exchangepb.ExchangeOffer
+	if err := decode(r, &offer); err != nil {
+		badRequest(w, err)
+		return
+	}
+	offer.Id = r.PathValue("id")
+	resp, err := h.client.UpdateOffer(r.Context(), &exchangepb.UpdateOfferRequest{Offer: &offer})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Offer)
+}
+
+func (h *ExchangeHandler) deleteOffer(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.client.DeleteOffer(r.Context(), &exchangepb.OfferID{Id: r.PathValue("id")}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ExchangeHandler) counterOffer(w http.ResponseWriter, r *http.Request) {
+	var req exchangepb.CounterOfferRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.OfferId = r.PathValue("id")
+	resp, err := h.client.CounterOffer(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusCreated, resp.Offer)
+}
+
+func (h *ExchangeHandler) getThread(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetOfferThread(r.Context(), &exchangepb.OfferID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp)
+}
+
+func (h *ExchangeHandler) acceptOffer(w http.ResponseWriter, r *http.Request) {
+	var req exchangepb.AcceptOfferRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.OfferId = r.PathValue("id")
+	resp, err := h.client.AcceptOffer(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Offer)
+}
+
+func (h *ExchangeHandler) declineOffer(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.DeclineOffer(r.Context(), &exchangepb.OfferID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Offer)
+}
+
+func (h *ExchangeHandler) addOfferedBook(w http.ResponseWriter, r *http.Request) {
+	var req exchangepb.BookOpRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.OfferId = r.PathValue("id")
+	resp, err := h.client.AddOfferedBook(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Offer)
+}
+
+func (h *ExchangeHandler) removeOfferedBook(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.RemoveOfferedBook(r.Context(), &exchangepb.BookOpRequest{
+		OfferId: r.PathValue("id"),
+		BookId:  r.PathValue("book_id"),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Offer)
+}
+
+func (h *ExchangeHandler) getWishlist(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetWishlist(r.Context(), &exchangepb.UserID{UserId: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Wishlist)
+}
+
+func (h *ExchangeHandler) setWishlist(w http.ResponseWriter, r *http.Request) {
+	var req exchangepb.SetWishlistRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.UserId = r.PathValue("id")
+	resp, err := h.client.SetWishlist(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Wishlist)
+}
+
+func (h *ExchangeHandler) listGroupsByUser(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListGroupsByUser(r.Context(), &exchangepb.UserID{UserId: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Groups)
+}
+
+func (h *ExchangeHandler) matchGroups(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.MatchGroups(r.Context(), &exchangepb.Empty{})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Groups)
+}
+
+func (h *ExchangeHandler) getGroup(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetGroup(r.Context(), &exchangepb.GroupID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Group)
+}
+
+func (h *ExchangeHandler) acceptGroup(w http.ResponseWriter, r *http.Request) {
+	var req exchangepb.GroupActionRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.GroupId = r.PathValue("id")
+	resp, err := h.client.AcceptGroup(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Group)
+}
+
+func (h *ExchangeHandler) declineGroup(w http.ResponseWriter, r *http.Request) {
+	var req exchangepb.GroupActionRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.GroupId = r.PathValue("id")
+	resp, err := h.client.DeclineGroup(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Group)
+}
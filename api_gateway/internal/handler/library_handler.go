@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+)
+
+type LibraryHandler struct {
+	client userlibpb.UserLibraryServiceClient
+}
+
+func NewLibraryHandler(client userlibpb.UserLibraryServiceClient) *LibraryHandler {
+	return &LibraryHandler{client: client}
+}
+
+func (h *LibraryHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /users/{id}/library", h.listUserBooks)
+	mux.HandleFunc("POST /users/{id}/library", h.assignBook)
+	mux.HandleFunc("DELETE /users/{id}/library/{book_id}", h.unassignBook)
+	mux.HandleFunc("GET /library", h.listEntries)
+	mux.HandleFunc("GET /library/{id}", h.getEntry)
+	mux.HandleFunc("PUT /library/{id}", h.updateEntry)
+	mux.HandleFunc("DELETE /library/{id}", h.deleteEntry)
+	mux.HandleFunc("PUT /library/{id}/progress", h.updateProgress)
+}
+
+// listUserBooks serves GET /users/{id}/library, optionally filtered by
+// reading ?status=.
+func (h *LibraryHandler) listUserBooks(w http.ResponseWriter, r *http.Request) {
+	var (
+		resp *userlibpb.ListUserBooksResponse
+		err  error
+	)
+	if st := r.URL.Query().Get("status"); st != "" {
+		resp, err = h.client.ListUserBooksByStatus(r.Context(), &userlibpb.ListByStatusRequest{UserId: r.PathValue("id"), Status: st})
+	} else {
+		resp, err = h.client.ListUserBooks(r.Context(), &userlibpb.ListUserBooksRequest{UserId: r.PathValue("id")})
+	}
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Entries)
+}
+
+func (h *LibraryHandler) assignBook(w http.ResponseWriter, r *http.Request) {
+	var req userlibpb.AssignBookRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.UserId = r.PathValue("id")
+	resp, err := h.client.AssignBook(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	code := http.StatusCreated
+	if !resp.Created {
+		code = http.StatusOK
+	}
+	writeProto(w, code, resp.Entry)
+}
+
+func (h *LibraryHandler) unassignBook(w http.ResponseWriter, r *http.Request) {
+	_, err := h.client.UnassignBook(r.Context(), &userlibpb.UnassignBookRequest{
+		UserId: r.PathValue("id"),
+		BookId: r.PathValue("book_id"),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listEntries serves GET /library, optionally filtered by ?book_id=.
+func (h *LibraryHandler) listEntries(w http.ResponseWriter, r *http.Request) {
+	var (
+		resp *userlibpb.ListUserBooksResponse
+		err  error
+	)
+	if bookID := r.URL.Query().Get("book_id"); bookID != "" {
+		resp, err = h.client.ListByBook(r.Context(), &userlibpb.ListByBookRequest{BookId: bookID})
+	} else {
+		resp, err = h.client.ListAllEntries(r.Context(), &emptypb.Empty{})
+	}
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Entries)
+}
+
+func (h *LibraryHandler) getEntry(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetEntry(r.Context(), &userlibpb.GetEntryRequest{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Entry)
+}
+
+func (h *LibraryHandler) updateEntry(w http.ResponseWriter, r *http.Request) {
+	var entry userlibpb.UserBook
+	if err := decode(r, &entry); err != nil {
+		badRequest(w, err)
+		return
+	}
+	entry.Id = r.PathValue("id")
+	resp, err := h.client.UpdateEntry(r.Context(), &userlibpb.UpdateEntryRequest{Entry: &entry})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Entry)
+}
+
+func (h *LibraryHandler) deleteEntry(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.client.DeleteEntry(r.Context(), &userlibpb.DeleteEntryRequest{Id: r.PathValue("id")}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *LibraryHandler) updateProgress(w http.ResponseWriter, r *http.Request) {
+	var req userlibpb.UpdateProgressRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.EntryId = r.PathValue("id")
+	resp, err := h.client.UpdateProgress(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Entry)
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	orderpb "github.com/OshakbayAigerim/read_space/order_service/proto"
+)
+
+type OrderHandler struct {
+	client orderpb.OrderServiceClient
+}
+
+func NewOrderHandler(client orderpb.OrderServiceClient) *OrderHandler {
+	return &OrderHandler{client: client}
+}
+
+func (h *OrderHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /orders", h.createOrder)
+	mux.HandleFunc("GET /orders", h.listOrders)
+	mux.HandleFunc("GET /orders/{id}", h.getOrder)
+	mux.HandleFunc("PUT /orders/{id}", h.updateOrder)
+	mux.HandleFunc("DELETE /orders/{id}", h.deleteOrder)
+	mux.HandleFunc("POST /orders/{id}/cancel", h.cancelOrder)
+	mux.HandleFunc("POST /orders/{id}/return", h.returnBook)
+	mux.HandleFunc("PUT /orders/{id}/status", h.updateStatus)
+	mux.HandleFunc("POST /orders/{id}/pay", h.payOrder)
+	mux.HandleFunc("POST /orders/{id}/refund", h.refundOrder)
+	mux.HandleFunc("POST /orders/{id}/books", h.addBook)
+	mux.HandleFunc("DELETE /orders/{id}/books/{book_id}", h.removeBook)
+	mux.HandleFunc("GET /users/{id}/orders", h.listOrdersByUser)
+}
+
+func (h *OrderHandler) createOrder(w http.ResponseWriter, r *http.Request) {
+	var req orderpb.CreateOrderRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	resp, err := h.client.CreateOrder(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusCreated, resp.Order)
+}
+
+func (h *OrderHandler) getOrder(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetOrder(r.Context(), &orderpb.OrderID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
+
+// listOrders serves GET /orders, optionally filtered by ?status=.
+func (h *OrderHandler) listOrders(w http.ResponseWriter, r *http.Request) {
+	var (
+		resp *orderpb.OrderList
+		err  error
+	)
+	if st := r.URL.Query().Get("status"); st != "" {
+		resp, err = h.client.ListOrdersByStatus(r.Context(), &orderpb.StatusRequest{Status: st})
+	} else {
+		resp, err = h.client.ListAllOrders(r.Context(), &orderpb.Empty{})
+	}
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Orders)
+}
+
+func (h *OrderHandler) listOrdersByUser(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ListOrdersByUser(r.Context(), &orderpb.ListOrdersByUserRequest{UserId: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProtoList(w, http.StatusOK, resp.Orders)
+}
+
+func (h *OrderHandler) updateOrder(w http.ResponseWriter, r *http.Request) {
+	var order orderpb.Order
+	if err := decode(r, &order); err != nil {
+		badRequest(w, err)
+		return
+	}
+	order.Id = r.PathValue("id")
+	resp, err := h.client.UpdateOrder(r.Context(), &orderpb.UpdateOrderRequest{Order: &order})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
+
+func (h *OrderHandler) deleteOrder(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.client.DeleteOrder(r.Context(), &orderpb.OrderID{Id: r.PathValue("id")}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *OrderHandler) cancelOrder(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.CancelOrder(r.Context(), &orderpb.OrderID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
+
+func (h *OrderHandler) updateStatus(w http.ResponseWriter, r *http.Request) {
+	var req orderpb.OrderStatusRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.OrderId = r.PathValue("id")
+	resp, err := h.client.UpdateOrderStatus(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
+
+func (h *OrderHandler) payOrder(w http.ResponseWriter, r *http.Request) {
+	var req orderpb.PayOrderRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.OrderId = r.PathValue("id")
+	resp, err := h.client.PayOrder(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
+
+func (h *OrderHandler) refundOrder(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.RefundOrder(r.Context(), &orderpb.OrderID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
+
+func (h *OrderHandler) returnBook(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.ReturnBook(r.Context(), &orderpb.OrderID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
+
+func (h *OrderHandler) addBook(w http.ResponseWriter, r *http.Request) {
+	var req orderpb.BookOperationRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.OrderId = r.PathValue("id")
+	resp, err := h.client.AddBookToOrder(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
+
+// removeBook drops the whole line unless ?quantity= is given.
+func (h *OrderHandler) removeBook(w http.ResponseWriter, r *http.Request) {
+	var quantity int64
+	if q := r.URL.Query().Get("quantity"); q != "" {
+		var err error
+		if quantity, err = strconv.ParseInt(q, 10, 32); err != nil {
+			badRequest(w, err)
+			return
+		}
+	}
+	resp, err := h.client.RemoveBookFromOrder(r.Context(), &orderpb.BookOperationRequest{
+		OrderId:  r.PathValue("id"),
+		BookId:   r.PathValue("book_id"),
+		Quantity: int32(quantity),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.Order)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	marshaler   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	unmarshaler = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// decode reads a JSON request body into a protobuf message.
+// An empty body leaves the message untouched.
+func decode(r *http.Request, msg proto.Message) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return unmarshaler.Unmarshal(body, msg)
+}
+
+// decodeQuery fills a protobuf message from the URL query, one parameter
+// per field named as in the .proto file. Numeric parameters are parsed by
+// protojson, which accepts numbers given as strings.
+func decodeQuery(r *http.Request, msg proto.Message) error {
+	fields := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		fields[key] = values[0]
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return unmarshaler.Unmarshal(data, msg)
+}
+
+func writeProto(w http.ResponseWriter, code int, msg proto.Message) {
+	data, err := marshaler.Marshal(msg)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeRaw(w, code, data)
+}
+
+func writeProtoList[T proto.Message](w http.ResponseWriter, code int, list []T) {
+	items := make([]json.RawMessage, 0, len(list))
+	for _, m := range list {
+		data, err := marshaler.Marshal(m)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		items = append(items, data)
+	}
+	data, _ := json.Marshal(items)
+	writeRaw(w, code, data)
+}
+
+func writeRaw(w http.ResponseWriter, code int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if _, err := w.Write(data); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	data, _ := json.Marshal(map[string]string{"error": msg})
+	writeRaw(w, code, data)
+}
+
+func badRequest(w http.ResponseWriter, err error) {
+	writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+}
+
+// writeGRPCError translates an error returned by a backend client into
+// the matching HTTP status code.
+func writeGRPCError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeError(w, httpStatusFromCode(st.Code()), st.Message())
+}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	userpb "github.com/OshakbayAigerim/read_space/user_service/proto"
+)
+
+type UserHandler struct {
+	client userpb.UserServiceClient
+}
+
+func NewUserHandler(client userpb.UserServiceClient) *UserHandler {
+	return &UserHandler{client: client}
+}
+
+func (h *UserHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /users", h.createUser)
+	mux.HandleFunc("GET /users", h.listUsers)
+	mux.HandleFunc("GET /users/{id}", h.getUser)
+	mux.HandleFunc("PUT /users/{id}", h.updateUser)
+	mux.HandleFunc("DELETE /users/{id}", h.deleteUser)
+	mux.HandleFunc("PUT /users/{id}/password", h.changePassword)
+	mux.HandleFunc("POST /users/{id}/roles", h.grantRole)
+	mux.HandleFunc("DELETE /users/{id}/roles/{role}", h.revokeRole)
+	mux.HandleFunc("POST /auth/login", h.login)
+	mux.HandleFunc("POST /auth/password-reset/request", h.requestPasswordReset)
+	mux.HandleFunc("POST /auth/password-reset", h.resetPassword)
+}
+
+func (h *UserHandler) createUser(w http.ResponseWriter, r *http.Request) {
+	var req userpb.CreateUserRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	resp, err := h.client.CreateUser(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusCreated, resp.User)
+}
+
+func (h *UserHandler) getUser(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.GetUser(r.Context(), &userpb.UserID{Id: r.PathValue("id")})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.User)
+}
+
+func (h *UserHandler) updateUser(w http.ResponseWriter, r *http.Request) {
+	var user userpb.User
+	if err := decode(r, &user); err != nil {
+		badRequest(w, err)
+		return
+	}
+	user.Id = r.PathValue("id")
+	resp, err := h.client.UpdateUser(r.Context(), &userpb.UpdateUserRequest{User: &user})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.User)
+}
+
+func (h *UserHandler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.client.DeleteUser(r.Context(), &userpb.UserID{Id: r.PathValue("id")}); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) listUsers(w http.ResponseWriter, r *http.Request) {
+	if email := r.URL.Query().Get("email"); email != "" {
+		resp, err := h.client.GetUserByEmail(r.Context(), &userpb.EmailRequest{Email: email})
+		if err != nil {
+			writeGRPCError(w, err)
+			return
+		}
+		writeProto(w, http.StatusOK, resp.User)
+		return
+	}
+	stream, err := h.client.ListAllUsers(r.Context(), &userpb.Empty{})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	var users []*userpb.User
+	for {
+		u, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			writeGRPCError(w, err)
+			return
+		}
+		users = append(users, u)
+	}
+	writeProtoList(w, http.StatusOK, users)
+}
+
+func (h *UserHandler) login(w http.ResponseWriter, r *http.Request) {
+	var req userpb.LoginRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	resp, err := h.client.Login(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp)
+}
+
+func (h *UserHandler) changePassword(w http.ResponseWriter, r *http.Request) {
+	var req userpb.ChangePasswordRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.UserId = r.PathValue("id")
+	if _, err := h.client.ChangePassword(r.Context(), &req); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) requestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req userpb.PasswordResetRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	if _, err := h.client.RequestPasswordReset(r.Context(), &req); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *UserHandler) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var req userpb.ResetPasswordRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	if _, err := h.client.ResetPassword(r.Context(), &req); err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) grantRole(w http.ResponseWriter, r *http.Request) {
+	var req userpb.RoleRequest
+	if err := decode(r, &req); err != nil {
+		badRequest(w, err)
+		return
+	}
+	req.UserId = r.PathValue("id")
+	resp, err := h.client.GrantRole(r.Context(), &req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.User)
+}
+
+func (h *UserHandler) revokeRole(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.RevokeRole(r.Context(), &userpb.RoleRequest{
+		UserId: r.PathValue("id"),
+		Role:   r.PathValue("role"),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, resp.User)
+}
@@ -0,0 +1,203 @@
+// Command catalog imports books into a running BookService in bulk and
+// exports the catalog, as CSV, JSON Lines or ONIX 3.0. The format follows
+// the file extension unless -format is given. Calls are made with the
+// bearer token of a librarian or admin, from -token or READSPACE_TOKEN:
+//
+//	go run ./book_service/cmd/catalog import -dry-run books.csv
+//	go run ./book_service/cmd/catalog import -format onix < feed.xml
+//	go run ./book_service/cmd/catalog export -o catalog.jsonl
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"google.golang.org/grpc"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/catalog"
+	pb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+)
+
+const importChunkSize = 64 << 10
+
+type options struct {
+	addr  string
+	token string
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.addr, "addr", "localhost:50051", "BookService address")
+	fs.StringVar(&o.token, "token", os.Getenv("READSPACE_TOKEN"), "bearer token of a librarian or admin")
+}
+
+func (o *options) connect() (pb.BookServiceClient, context.Context, func(), error) {
+	if o.token == "" {
+		return nil, nil, nil, errors.New("no token: pass -token or set READSPACE_TOKEN")
+	}
+	conn, err := grpc.Dial(o.addr, grpc.WithInsecure())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ctx := auth.OutgoingContext(context.Background(), "Bearer "+o.token)
+	return pb.NewBookServiceClient(conn), ctx, func() { conn.Close() }, nil
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatalf("🔴 %s: %v", os.Args[1], err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: catalog import [-format f] [-dry-run] [file]")
+	fmt.Fprintln(os.Stderr, "       catalog export [-format f] [-o file]")
+	os.Exit(2)
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	var opts options
+	opts.register(fs)
+	format := fs.String("format", "", "csv, jsonl or onix")
+	dryRun := fs.Bool("dry-run", false, "validate the file without saving anything")
+	fs.Parse(args)
+
+	in := os.Stdin
+	if path := fs.Arg(0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+		if *format == "" {
+			*format = catalog.FormatOf(path)
+		}
+	}
+	if *format == "" {
+		return errors.New("cannot tell the format, pass -format")
+	}
+
+	client, ctx, closeConn, err := opts.connect()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+	stream, err := client.ImportBooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ImportBooksRequest{Format: *format, DryRun: *dryRun}
+	buf := make([]byte, importChunkSize)
+	for {
+		n, readErr := in.Read(buf)
+		if n > 0 || req.Format != "" {
+			req.Chunk = buf[:n]
+			// io.EOF от Send значит, что сервер уже ответил ошибкой: её вернёт CloseAndRecv
+			if err := stream.Send(req); err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			req = &pb.ImportBooksRequest{}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range resp.Errors {
+		if e.Isbn != "" {
+			log.Printf("row %d (ISBN %s): %s", e.Row, e.Isbn, e.Error)
+		} else {
+			log.Printf("row %d: %s", e.Row, e.Error)
+		}
+	}
+	if int(resp.Failed) > len(resp.Errors) {
+		log.Printf("… and %d more failed rows", int(resp.Failed)-len(resp.Errors))
+	}
+	if resp.DryRun {
+		log.Printf("🟢 dry run: %d books would be created, %d updated, %d rows failed", resp.Created, resp.Updated, resp.Failed)
+	} else {
+		log.Printf("🟢 %d books created, %d updated, %d rows failed", resp.Created, resp.Updated, resp.Failed)
+	}
+	if resp.Failed > 0 {
+		return fmt.Errorf("%d rows failed", resp.Failed)
+	}
+	return nil
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var opts options
+	opts.register(fs)
+	format := fs.String("format", "", "csv, jsonl or onix (default from -o, else csv)")
+	output := fs.String("o", "", "output file (default stdout)")
+	fs.Parse(args)
+
+	if *format == "" {
+		*format = catalog.FormatOf(*output)
+	}
+	if *format == "" {
+		*format = catalog.FormatCSV
+	}
+
+	client, ctx, closeConn, err := opts.connect()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+	stream, err := client.ExportBooks(ctx, &pb.ExportBooksRequest{Format: *format})
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" && *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := out.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
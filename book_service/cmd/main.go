@@ -11,9 +11,14 @@ import (
 	"github.com/OshakbayAigerim/read_space/book_service/internal/cache"
 	"github.com/OshakbayAigerim/read_space/book_service/internal/config"
 	"github.com/OshakbayAigerim/read_space/book_service/internal/handler"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/migration"
 	"github.com/OshakbayAigerim/read_space/book_service/internal/repository"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/search"
 	"github.com/OshakbayAigerim/read_space/book_service/internal/usecase"
 	pb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	orderpb "github.com/OshakbayAigerim/read_space/order_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
 )
 
 func main() {
@@ -26,6 +31,15 @@ func main() {
 		}
 	}()
 
+	db := mongoClient.Database("readspace")
+	migrations.CreateBookIndexes(db)
+	migrations.BackfillContributors(db)
+	migrations.BackfillStock(db)
+	migrations.CreateAuthorIndexes(db)
+	migrations.LinkAuthors(db)
+	migrations.CreateReviewIndexes(db)
+	migrations.ResetManualRatings(db)
+
 	redisClient := config.ConnectRedis()
 	defer func() {
 		if err := redisClient.Close(); err != nil {
@@ -39,21 +53,70 @@ func main() {
 	}
 	defer nc.Close()
 
+	jwtSecret := auth.SecretFromEnv()
+
+	// ——— Библиотеки и заказы читателей нужны для рекомендаций ———
+	libraryConn, err := grpc.Dial("localhost:50055",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "book_service")),
+	)
+	if err != nil {
+		log.Fatalf(" cannot dial UserLibraryService: %v", err)
+	}
+	defer libraryConn.Close()
+	orderConn, err := grpc.Dial("localhost:50053",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "book_service")),
+	)
+	if err != nil {
+		log.Fatalf(" cannot dial OrderService: %v", err)
+	}
+	defer orderConn.Close()
+
 	bookCache := cache.NewRedisBookCache(redisClient)
 
 	bookRepo := repository.NewMongoBookRepository(mongoClient)
 	cachedBookRepo := repository.NewCachedBookRepository(bookRepo, bookCache)
 
-	bookUC := usecase.NewBookUseCase(cachedBookRepo)
+	reviewRepo := repository.NewMongoReviewRepository(mongoClient)
+	authorRepo := repository.NewMongoAuthorRepository(mongoClient)
 
-	srv := handler.NewBookHandler(bookUC, nc)
+	authorUC := usecase.NewAuthorUseCase(authorRepo, cachedBookRepo)
+	bookUC := usecase.NewBookUseCase(cachedBookRepo, authorUC)
+	catalogUC := usecase.NewCatalogUseCase(bookUC, cachedBookRepo)
+	reviewUC := usecase.NewReviewUseCase(reviewRepo, cachedBookRepo)
+	recommendUC := usecase.NewRecommendUseCase(
+		cachedBookRepo,
+		userlibpb.NewUserLibraryServiceClient(libraryConn),
+		orderpb.NewOrderServiceClient(orderConn),
+		bookCache,
+	)
+
+	// подписываемся до построения индекса, чтобы не потерять изменения
+	searchUC := usecase.NewSearchUseCase(cachedBookRepo, search.New())
+	if err := handler.SubscribeSearchIndex(nc, searchUC); err != nil {
+		log.Fatalf(" NATS subscribe error: %v", err)
+	}
+	n, err := searchUC.Reindex(ctx)
+	if err != nil {
+		log.Fatalf(" cannot build search index: %v", err)
+	}
+	log.Printf("Search index built from %d books", n)
+
+	srv := handler.NewBookHandler(bookUC, reviewUC, recommendUC, searchUC, authorUC, catalogUC, nc)
+	if err := handler.SubscribeDeletions(nc, reviewUC); err != nil {
+		log.Fatalf(" NATS subscribe error: %v", err)
+	}
 
 	lis, err := net.Listen("tcp", ":50051")
 	if err != nil {
 		log.Fatalf(" Failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(jwtSecret)),
+		grpc.StreamInterceptor(auth.StreamServerInterceptor(jwtSecret)),
+	)
 	pb.RegisterBookServiceServer(grpcServer, srv)
 
 	log.Println("BookService gRPC server started on port 50051")
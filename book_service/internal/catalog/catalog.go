@@ -0,0 +1,198 @@
+// Package catalog reads and writes books in the bulk exchange formats used
+// for imports and exports: CSV, JSON Lines and ONIX for Books 3.0.
+package catalog
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatONIX  = "onix"
+)
+
+var ErrUnknownFormat = errors.New("format must be csv, jsonl or onix")
+
+// Record is one book read from a file. Err is set when the row could not
+// be parsed; reading goes on with the next row.
+type Record struct {
+	// Row is the line number in CSV and JSON Lines files and the position
+	// of the <Product> in ONIX messages.
+	Row  int
+	Book *domain.Book
+	// Fields holds the CSV column names of the fields the row gave a value
+	// for; the other fields of Book are only zero values.
+	Fields map[string]bool
+	Err    error
+}
+
+// Has tells whether the row gave a value for field, named as the CSV
+// column.
+func (r *Record) Has(field string) bool {
+	return r.Fields[field]
+}
+
+// Merge copies the fields the row gave onto b and leaves the others as
+// they are. Author and contributors are replaced together, as they are
+// one set of credits. Stock is never copied, the caller adjusts it.
+func (r *Record) Merge(b *domain.Book) {
+	src := r.Book
+	if r.Has("author") || r.Has("contributors") {
+		b.Author, b.Contributors = src.Author, src.Contributors
+	}
+	for field, set := range map[string]func(){
+		"isbn":            func() { b.ISBN = src.ISBN },
+		"title":           func() { b.Title = src.Title },
+		"genre":           func() { b.Genre = src.Genre },
+		"language":        func() { b.Language = src.Language },
+		"description":     func() { b.Description = src.Description },
+		"price":           func() { b.Price = src.Price },
+		"pages":           func() { b.Pages = src.Pages },
+		"published_date":  func() { b.PublishedDate = src.PublishedDate },
+		"publisher":       func() { b.Publisher = src.Publisher },
+		"series":          func() { b.Series = src.Series },
+		"series_position": func() { b.SeriesPosition = src.SeriesPosition },
+		"edition":         func() { b.Edition = src.Edition },
+		"format":          func() { b.Format = src.Format },
+	} {
+		if r.Has(field) {
+			set()
+		}
+	}
+}
+
+type Reader interface {
+	// Next returns the next record, or io.EOF after the last one. Any other
+	// error means the rest of the file cannot be read.
+	Next() (*Record, error)
+}
+
+type Writer interface {
+	Write(book *domain.Book) error
+	// Close finishes the file. It does not close the underlying writer.
+	Close() error
+}
+
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r), nil
+	case FormatJSONL:
+		return newJSONLReader(r), nil
+	case FormatONIX:
+		return newONIXReader(r), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	case FormatONIX:
+		return newONIXWriter(w), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// FormatOf guesses the format from a file name, "" if the extension is
+// not known.
+func FormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".xml", ".onix":
+		return FormatONIX
+	}
+	return ""
+}
+
+// entry is the flat form of a book shared by the CSV and JSON Lines
+// formats; its JSON names are also the CSV column names. Ratings and IDs
+// belong to this catalog and are not exchanged.
+type entry struct {
+	ISBN           string        `json:"isbn,omitempty"`
+	Title          string        `json:"title"`
+	Author         string        `json:"author,omitempty"`
+	Contributors   []contributor `json:"contributors,omitempty"`
+	Genre          string        `json:"genre,omitempty"`
+	Language       string        `json:"language,omitempty"`
+	Description    string        `json:"description,omitempty"`
+	Price          float32       `json:"price,omitempty"`
+	Pages          int           `json:"pages,omitempty"`
+	PublishedDate  string        `json:"published_date,omitempty"`
+	Stock          *int          `json:"stock,omitempty"`
+	Publisher      string        `json:"publisher,omitempty"`
+	Series         string        `json:"series,omitempty"`
+	SeriesPosition int           `json:"series_position,omitempty"`
+	Edition        string        `json:"edition,omitempty"`
+	Format         string        `json:"format,omitempty"`
+}
+
+type contributor struct {
+	Name string `json:"name"`
+	Role string `json:"role,omitempty"`
+}
+
+// record builds the book from e; fields are the names of the values the
+// row gave, as CSV columns or JSON keys.
+func (e *entry) record(row int, fields map[string]bool) *Record {
+	book := &domain.Book{
+		ISBN:           e.ISBN,
+		Title:          e.Title,
+		Author:         e.Author,
+		Genre:          e.Genre,
+		Language:       e.Language,
+		Description:    e.Description,
+		Price:          e.Price,
+		Pages:          e.Pages,
+		PublishedDate:  e.PublishedDate,
+		Publisher:      e.Publisher,
+		Series:         e.Series,
+		SeriesPosition: e.SeriesPosition,
+		Edition:        e.Edition,
+		Format:         e.Format,
+	}
+	for _, c := range e.Contributors {
+		book.Contributors = append(book.Contributors, domain.Contributor{Name: c.Name, Role: c.Role})
+	}
+	if e.Stock != nil {
+		book.Stock = *e.Stock
+	}
+	return &Record{Row: row, Book: book, Fields: fields}
+}
+
+func entryOf(b *domain.Book) *entry {
+	stock := b.Stock
+	e := &entry{
+		ISBN:           b.ISBN,
+		Title:          b.Title,
+		Author:         b.Author,
+		Genre:          b.Genre,
+		Language:       b.Language,
+		Description:    b.Description,
+		Price:          b.Price,
+		Pages:          b.Pages,
+		PublishedDate:  b.PublishedDate,
+		Stock:          &stock,
+		Publisher:      b.Publisher,
+		Series:         b.Series,
+		SeriesPosition: b.SeriesPosition,
+		Edition:        b.Edition,
+		Format:         b.Format,
+	}
+	for _, c := range b.Contributors {
+		e.Contributors = append(e.Contributors, contributor{Name: c.Name, Role: c.Role})
+	}
+	return e
+}
@@ -0,0 +1,186 @@
+package catalog
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+func readAll(t *testing.T, format, data string) []*Record {
+	t.Helper()
+	r, err := NewReader(format, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res []*Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return res
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		res = append(res, rec)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	hobbit := &domain.Book{
+		ID:    primitive.NewObjectID(),
+		Title: "The Hobbit",
+		Contributors: []domain.Contributor{
+			{Name: "J. R. R. Tolkien", Role: domain.RoleAuthor},
+			{Name: "Alan Lee", Role: domain.RoleIllustrator},
+		},
+		Genre:          "Fantasy",
+		Language:       "English",
+		Description:    "In a hole in the ground, \"there lived\" a hobbit; <not> a nasty one.",
+		Price:          12.5,
+		Pages:          310,
+		PublishedDate:  "1937-09-21",
+		Stock:          4,
+		ISBN:           "9780261102217",
+		Publisher:      "Allen & Unwin",
+		Series:         "Middle-earth",
+		SeriesPosition: 1,
+		Edition:        "First edition",
+		Format:         domain.FormatHardcover,
+	}
+	bare := &domain.Book{ID: primitive.NewObjectID(), Title: "Notes"}
+
+	for _, format := range []string{FormatCSV, FormatJSONL, FormatONIX} {
+		var buf bytes.Buffer
+		w, err := NewWriter(format, &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, b := range []*domain.Book{hobbit, bare} {
+			if err := w.Write(b); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		recs := readAll(t, format, buf.String())
+		if len(recs) != 2 {
+			t.Fatalf("%s: expected 2 records, got %d", format, len(recs))
+		}
+		got := recs[0].Book
+		if recs[0].Err != nil || !recs[0].Has("stock") {
+			t.Errorf("%s: unexpected record %+v", format, recs[0])
+		}
+		got.Author = hobbit.Author
+		want := *hobbit
+		want.ID = primitive.NilObjectID
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("%s: round trip changed the book\ngot  %+v\nwant %+v", format, *got, want)
+		}
+		if recs[1].Book.Title != "Notes" || recs[1].Book.ISBN != "" {
+			t.Errorf("%s: bare book came back as %+v", format, recs[1].Book)
+		}
+	}
+}
+
+func TestCSVRowErrors(t *testing.T) {
+	data := "\uFEFFTitle,price,contributors,stock\n" +
+		"Emma,7.99,Jane Austen,\n" +
+		"Dune,cheap,Frank Herbert,2\n" +
+		"\"Beowulf\",5,\"Seamus Heaney (translator)\",\n" +
+		"Short,1\n"
+	recs := readAll(t, FormatCSV, data)
+	if len(recs) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(recs))
+	}
+	if recs[0].Err != nil || recs[0].Has("stock") || recs[0].Book.Price != 7.99 {
+		t.Errorf("unexpected first record %+v", recs[0])
+	}
+	if recs[1].Row != 3 || recs[1].Err == nil {
+		t.Errorf("expected an invalid price on line 3, got %+v", recs[1])
+	}
+	c := recs[2].Book.Contributors
+	if recs[2].Err != nil || len(c) != 1 || c[0] != (domain.Contributor{Name: "Seamus Heaney", Role: "translator"}) {
+		t.Errorf("unexpected contributors %+v (%v)", c, recs[2].Err)
+	}
+	if recs[3].Row != 5 || recs[3].Err == nil {
+		t.Errorf("expected a field count error on line 5, got %+v", recs[3])
+	}
+
+	r, _ := NewReader(FormatCSV, strings.NewReader("title,rating\nX,5\n"))
+	if _, err := r.Next(); err == nil {
+		t.Error("expected an unknown column to fail the file")
+	}
+}
+
+func TestJSONLRowErrors(t *testing.T) {
+	data := `{"title":"Emma","stock":0}` + "\n\n" +
+		`{"title":"Dune","prize":3}` + "\n" +
+		`{"title":` + "\n" +
+		`{"title":"Last"}`
+	recs := readAll(t, FormatJSONL, data)
+	if len(recs) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(recs))
+	}
+	if recs[0].Err != nil || !recs[0].Has("stock") {
+		t.Errorf("explicit zero stock lost: %+v", recs[0])
+	}
+	if recs[1].Row != 3 || recs[1].Err == nil || recs[2].Row != 4 || recs[2].Err == nil {
+		t.Errorf("expected errors on lines 3 and 4, got %+v %+v", recs[1], recs[2])
+	}
+	if recs[3].Row != 5 || recs[3].Book.Title != "Last" {
+		t.Errorf("unexpected last record %+v", recs[3])
+	}
+}
+
+func TestONIXReader(t *testing.T) {
+	data := `<?xml version="1.0"?>
+<ONIXMessage release="3.0" xmlns="http://ns.editeur.org/onix/3.0/reference">
+  <Header><Sender><SenderName>Publisher</SenderName></Sender><SentDateTime>20260101</SentDateTime></Header>
+  <Product>
+    <RecordReference>p1</RecordReference>
+    <NotificationType>03</NotificationType>
+    <ProductIdentifier><ProductIDType>03</ProductIDType><IDValue>9780141439518</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <ProductComposition>00</ProductComposition>
+      <ProductForm>BC</ProductForm>
+      <TitleDetail><TitleType>01</TitleType><TitleElement><TitleElementLevel>01</TitleElementLevel>
+        <TitlePrefix>The</TitlePrefix><TitleWithoutPrefix>Picture of Dorian Gray</TitleWithoutPrefix></TitleElement></TitleDetail>
+      <Contributor><ContributorRole>A01</ContributorRole><PersonNameInverted>Wilde, Oscar</PersonNameInverted></Contributor>
+      <Contributor><ContributorRole>A99</ContributorRole><PersonName>Someone Else</PersonName></Contributor>
+      <Language><LanguageRole>01</LanguageRole><LanguageCode>eng</LanguageCode></Language>
+    </DescriptiveDetail>
+    <PublishingDetail><PublishingDate><PublishingDateRole>01</PublishingDateRole><Date dateformat="05">1890</Date></PublishingDate></PublishingDetail>
+  </Product>
+  <Product><RecordReference>p2</RecordReference><NotificationType>05</NotificationType></Product>
+</ONIXMessage>`
+	recs := readAll(t, FormatONIX, data)
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	b := recs[0].Book
+	if b.Title != "The Picture of Dorian Gray" || b.ISBN != "9780141439518" || b.Format != domain.FormatPaperback ||
+		b.Language != "English" || b.PublishedDate != "1890" || recs[0].Has("stock") {
+		t.Errorf("unexpected book %+v", b)
+	}
+	if len(b.Contributors) != 1 || b.Contributors[0].Name != "Oscar Wilde" {
+		t.Errorf("unexpected contributors %+v", b.Contributors)
+	}
+	if recs[1].Row != 2 || recs[1].Err == nil {
+		t.Errorf("expected the deletion notice to be rejected, got %+v", recs[1])
+	}
+
+	for _, doc := range []string{`<ONIXMessage release="2.1"><Product/></ONIXMessage>`, `<ONIXmessage><product/></ONIXmessage>`, ``} {
+		r, _ := NewReader(FormatONIX, strings.NewReader(doc))
+		if _, err := r.Next(); err == nil || err == io.EOF {
+			t.Errorf("expected %q to be rejected, got %v", doc, err)
+		}
+	}
+}
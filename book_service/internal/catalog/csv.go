@@ -0,0 +1,225 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+// csvColumns are written in this order. Imported files may use any subset
+// in any order, but need a header row with a title column.
+var csvColumns = []string{
+	"isbn", "title", "author", "contributors", "genre", "language", "description",
+	"price", "pages", "published_date", "stock",
+	"publisher", "series", "series_position", "edition", "format",
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) *csvReader {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	return &csvReader{r: cr}
+}
+
+func (r *csvReader) Next() (*Record, error) {
+	if r.columns == nil {
+		if err := r.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+	fields, err := r.r.Read()
+	var perr *csv.ParseError
+	if errors.As(err, &perr) {
+		return &Record{Row: perr.StartLine, Err: perr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	row, _ := r.r.FieldPos(0)
+	e, err := r.entry(fields)
+	if err != nil {
+		return &Record{Row: row, Err: err}, nil
+	}
+	return e.record(row, r.given(fields)), nil
+}
+
+func (r *csvReader) readHeader() error {
+	header, err := r.r.Read()
+	if err == io.EOF {
+		return errors.New("CSV file has no header row")
+	}
+	if err != nil {
+		return err
+	}
+	r.columns = make(map[string]int)
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\uFEFF") // BOM от Excel
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known(name) {
+			return fmt.Errorf("unknown CSV column %q", name)
+		}
+		if _, dup := r.columns[name]; dup {
+			return fmt.Errorf("duplicate CSV column %q", name)
+		}
+		r.columns[name] = i
+	}
+	if _, ok := r.columns["title"]; !ok {
+		return errors.New("CSV header has no title column")
+	}
+	return nil
+}
+
+func known(column string) bool {
+	for _, c := range csvColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// given names the columns with a non-empty cell: an empty cell cannot be
+// told from a missing one, so it leaves the field as it is.
+func (r *csvReader) given(fields []string) map[string]bool {
+	given := make(map[string]bool)
+	for column, i := range r.columns {
+		if i < len(fields) && strings.TrimSpace(fields[i]) != "" {
+			given[column] = true
+		}
+	}
+	return given
+}
+
+func (r *csvReader) entry(fields []string) (*entry, error) {
+	get := func(column string) string {
+		if i, ok := r.columns[column]; ok && i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+	e := &entry{
+		ISBN:          get("isbn"),
+		Title:         get("title"),
+		Author:        get("author"),
+		Contributors:  parseContributors(get("contributors")),
+		Genre:         get("genre"),
+		Language:      get("language"),
+		Description:   get("description"),
+		PublishedDate: get("published_date"),
+		Publisher:     get("publisher"),
+		Series:        get("series"),
+		Edition:       get("edition"),
+		Format:        get("format"),
+	}
+	if s := get("price"); s != "" {
+		price, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q", s)
+		}
+		e.Price = float32(price)
+	}
+	for column, dst := range map[string]*int{"pages": &e.Pages, "series_position": &e.SeriesPosition} {
+		if s := get(column); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", column, s)
+			}
+			*dst = n
+		}
+	}
+	if s := get("stock"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stock %q", s)
+		}
+		e.Stock = &n
+	}
+	return e, nil
+}
+
+// parseContributors reads "J. R. R. Tolkien; Alan Lee (illustrator)": names
+// separated by semicolons, each with an optional role in parentheses.
+func parseContributors(s string) []contributor {
+	var res []contributor
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		c := contributor{Name: part}
+		if open := strings.LastIndex(part, "("); open > 0 && strings.HasSuffix(part, ")") {
+			c.Name = strings.TrimSpace(part[:open])
+			c.Role = strings.TrimSpace(part[open+1 : len(part)-1])
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+func formatContributors(contributors []contributor) string {
+	parts := make([]string, len(contributors))
+	for i, c := range contributors {
+		parts[i] = c.Name
+		if c.Role != "" {
+			parts[i] += " (" + c.Role + ")"
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Write(book *domain.Book) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	e := entryOf(book)
+	return w.w.Write([]string{
+		e.ISBN, e.Title, e.Author, formatContributors(e.Contributors), e.Genre, e.Language, e.Description,
+		formatNumber(float64(e.Price)), formatNumber(float64(e.Pages)), e.PublishedDate, strconv.Itoa(*e.Stock),
+		e.Publisher, e.Series, formatNumber(float64(e.SeriesPosition)), e.Edition, e.Format,
+	})
+}
+
+// Close writes the header even when there were no books.
+func (w *csvWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	return w.w.Write(csvColumns)
+}
+
+// formatNumber leaves zero cells empty.
+func formatNumber(n float64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(n, 'f', -1, 32)
+}
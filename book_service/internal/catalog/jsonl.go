@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+// jsonlReader reads one JSON object per line. Blank lines are skipped and
+// unknown fields are errors, so that misspelled keys are not silently lost.
+type jsonlReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+	return &jsonlReader{r: bufio.NewReader(r)}
+}
+
+func (r *jsonlReader) Next() (*Record, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return nil, err
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		r.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.DisallowUnknownFields()
+		var e entry
+		if err := dec.Decode(&e); err != nil {
+			return &Record{Row: r.line, Err: err}, nil
+		}
+		if dec.More() {
+			return &Record{Row: r.line, Err: errors.New("more than one JSON value on the line")}, nil
+		}
+		// ключи, которых нет в строке, не затирают поля книги; регистр
+		// ключей не важен, как и при разборе entry
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(line, &keys); err != nil {
+			return &Record{Row: r.line, Err: err}, nil
+		}
+		fields := make(map[string]bool, len(keys))
+		for key := range keys {
+			fields[strings.ToLower(key)] = true
+		}
+		return e.record(r.line, fields), nil
+	}
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlWriter{enc: enc}
+}
+
+func (w *jsonlWriter) Write(book *domain.Book) error {
+	return w.enc.Encode(entryOf(book))
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
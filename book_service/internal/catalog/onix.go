@@ -0,0 +1,613 @@
+package catalog
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+// ONIX for Books 3.0, reference tags. Only the parts of a <Product> that
+// map onto a Book are read and written; codes are from the EDItEUR code
+// lists named next to them.
+const onixNamespace = "http://ns.editeur.org/onix/3.0/reference"
+
+var errNotONIX = errors.New("not an ONIX 3.0 message with reference tags")
+
+const (
+	notificationConfirmed = "03" // List 1
+	notificationDelete    = "05"
+
+	idProprietary = "01" // List 5
+	idGTIN13      = "03"
+	idISBN13      = "15"
+
+	titleDistinctive = "01" // List 15
+	levelProduct     = "01" // List 149
+	levelCollection  = "02"
+	collectionSeries = "10" // List 148
+
+	languageOfText = "01" // List 22
+	extentMain     = "00" // List 23
+	extentPages    = "03" // List 24
+	subjectKeyword = "20" // List 27
+	textDesc       = "03" // List 153
+	textShortDesc  = "02"
+	audienceAll    = "00" // List 154
+
+	publisherRole   = "01" // List 45
+	publicationDate = "01" // List 163
+	supplierRole    = "00" // List 93
+	priceRRP        = "01" // List 58
+	priceToCome     = "02" // List 57
+)
+
+// List 17, contributor roles.
+var onixRoles = map[string]string{
+	domain.RoleAuthor:      "A01",
+	domain.RoleEditor:      "B01",
+	domain.RoleTranslator:  "B06",
+	domain.RoleIllustrator: "A12",
+	domain.RoleNarrator:    "E07",
+}
+
+// List 150, product forms.
+var onixForms = map[string]string{
+	domain.FormatHardcover: "BB",
+	domain.FormatPaperback: "BC",
+	domain.FormatEbook:     "EA",
+	domain.FormatAudiobook: "AJ",
+}
+
+// ISO 639-2/B codes of the languages the catalog is kept in. Other
+// languages are exchanged as the code itself.
+var onixLanguages = map[string]string{
+	"english": "eng",
+	"russian": "rus",
+	"kazakh":  "kaz",
+	"german":  "ger",
+	"french":  "fre",
+	"spanish": "spa",
+	"italian": "ita",
+	"turkish": "tur",
+	"chinese": "chi",
+}
+
+type onixHeader struct {
+	XMLName      xml.Name `xml:"Header"`
+	SenderName   string   `xml:"Sender>SenderName"`
+	SentDateTime string   `xml:"SentDateTime"`
+}
+
+type onixProduct struct {
+	XMLName          xml.Name           `xml:"Product"`
+	RecordReference  string             `xml:"RecordReference"`
+	NotificationType string             `xml:"NotificationType"`
+	Identifiers      []onixIdentifier   `xml:"ProductIdentifier"`
+	Descriptive      onixDescriptive    `xml:"DescriptiveDetail"`
+	Texts            []onixText         `xml:"CollateralDetail>TextContent"`
+	Publishing       *onixPublishing    `xml:"PublishingDetail"`
+	Supply           []onixSupplyDetail `xml:"ProductSupply>SupplyDetail"`
+}
+
+type onixIdentifier struct {
+	Type     string `xml:"ProductIDType"`
+	TypeName string `xml:"IDTypeName,omitempty"`
+	Value    string `xml:"IDValue"`
+}
+
+type onixDescriptive struct {
+	Composition  string            `xml:"ProductComposition"`
+	Form         string            `xml:"ProductForm"`
+	Collections  []onixCollection  `xml:"Collection"`
+	Titles       []onixTitleDetail `xml:"TitleDetail"`
+	Contributors []onixContributor `xml:"Contributor"`
+	Edition      string            `xml:"EditionStatement,omitempty"`
+	Languages    []onixLanguage    `xml:"Language"`
+	Extents      []onixExtent      `xml:"Extent"`
+	Subjects     []onixSubject     `xml:"Subject"`
+}
+
+type onixCollection struct {
+	Type   string            `xml:"CollectionType"`
+	Titles []onixTitleDetail `xml:"TitleDetail"`
+}
+
+type onixTitleDetail struct {
+	Type     string             `xml:"TitleType"`
+	Elements []onixTitleElement `xml:"TitleElement"`
+}
+
+type onixTitleElement struct {
+	Level         string `xml:"TitleElementLevel"`
+	PartNumber    string `xml:"PartNumber,omitempty"`
+	Prefix        string `xml:"TitlePrefix,omitempty"`
+	WithoutPrefix string `xml:"TitleWithoutPrefix,omitempty"`
+	Text          string `xml:"TitleText,omitempty"`
+	Subtitle      string `xml:"Subtitle,omitempty"`
+}
+
+type onixContributor struct {
+	Sequence     int      `xml:"SequenceNumber,omitempty"`
+	Roles        []string `xml:"ContributorRole"`
+	PersonName   string   `xml:"PersonName,omitempty"`
+	NameInverted string   `xml:"PersonNameInverted,omitempty"`
+	Corporate    string   `xml:"CorporateName,omitempty"`
+}
+
+type onixLanguage struct {
+	Role string `xml:"LanguageRole"`
+	Code string `xml:"LanguageCode"`
+}
+
+type onixExtent struct {
+	Type  string `xml:"ExtentType"`
+	Value string `xml:"ExtentValue"`
+	Unit  string `xml:"ExtentUnit"`
+}
+
+type onixSubject struct {
+	Scheme  string `xml:"SubjectSchemeIdentifier"`
+	Code    string `xml:"SubjectCode,omitempty"`
+	Heading string `xml:"SubjectHeadingText,omitempty"`
+}
+
+type onixText struct {
+	Type     string `xml:"TextType"`
+	Audience string `xml:"ContentAudience"`
+	Text     string `xml:"Text"`
+}
+
+type onixPublishing struct {
+	Publishers []onixPublisher `xml:"Publisher"`
+	Dates      []onixDate      `xml:"PublishingDate"`
+}
+
+type onixPublisher struct {
+	Role string `xml:"PublishingRole"`
+	Name string `xml:"PublisherName"`
+}
+
+type onixDate struct {
+	Role string `xml:"PublishingDateRole"`
+	Date struct {
+		Format string `xml:"dateformat,attr,omitempty"`
+		Value  string `xml:",chardata"`
+	} `xml:"Date"`
+}
+
+type onixSupplyDetail struct {
+	SupplierRole string      `xml:"Supplier>SupplierRole"`
+	SupplierName string      `xml:"Supplier>SupplierName,omitempty"`
+	Availability string      `xml:"ProductAvailability"`
+	Stock        []onixStock `xml:"Stock"`
+	Prices       []onixPrice `xml:"Price"`
+	Unpriced     string      `xml:"UnpricedItemType,omitempty"`
+}
+
+type onixStock struct {
+	OnHand int `xml:"OnHand"`
+}
+
+// Prices are written without a currency: the catalog keeps all prices in
+// the store's one currency.
+type onixPrice struct {
+	Type   string  `xml:"PriceType"`
+	Amount float32 `xml:"PriceAmount"`
+}
+
+// onixReader decodes one <Product> at a time, so that a large message is
+// never held in memory whole.
+type onixReader struct {
+	dec     *xml.Decoder
+	root    bool
+	product int
+}
+
+func newONIXReader(r io.Reader) *onixReader {
+	return &onixReader{dec: xml.NewDecoder(r)}
+}
+
+func (r *onixReader) Next() (*Record, error) {
+	for {
+		tok, err := r.dec.Token()
+		if err == io.EOF && !r.root {
+			return nil, errNotONIX
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !r.root && start.Name.Local != "ONIXMessage" {
+			return nil, errNotONIX
+		}
+		switch start.Name.Local {
+		case "ONIXMessage":
+			r.root = true
+			if release := attr(start, "release"); release != "" && !strings.HasPrefix(release, "3.") {
+				return nil, fmt.Errorf("ONIX release %s is not supported, only 3.0", release)
+			}
+		case "Product":
+			r.product++
+			var p onixProduct
+			if err := r.dec.DecodeElement(&p, &start); err != nil {
+				return nil, err
+			}
+			return p.record(r.product), nil
+		default:
+			if err := r.dec.Skip(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (p *onixProduct) record(row int) *Record {
+	rec := &Record{Row: row, Fields: make(map[string]bool)}
+	if p.NotificationType == notificationDelete {
+		rec.Err = errors.New("deletion notices are not imported")
+		return rec
+	}
+	d := &p.Descriptive
+	book := &domain.Book{Edition: strings.TrimSpace(d.Edition)}
+	rec.Book = book
+	rec.Fields["edition"] = book.Edition != ""
+
+	for _, id := range p.Identifiers {
+		if id.Type == idISBN13 || (id.Type == idGTIN13 && book.ISBN == "") {
+			book.ISBN = strings.TrimSpace(id.Value)
+			rec.Fields["isbn"] = true
+		}
+	}
+	for form, code := range onixForms {
+		if d.Form == code {
+			book.Format = form
+			rec.Fields["format"] = true
+		}
+	}
+	for _, t := range d.Titles {
+		if t.Type == titleDistinctive {
+			if e, ok := t.element(levelProduct); ok {
+				book.Title = e.title()
+				rec.Fields["title"] = true
+			}
+		}
+	}
+	for _, c := range d.Collections {
+		if c.Type != collectionSeries {
+			continue
+		}
+		for _, t := range c.Titles {
+			e, ok := t.element(levelCollection)
+			if !ok {
+				continue
+			}
+			book.Series = e.title()
+			rec.Fields["series"] = true
+			if e.PartNumber != "" {
+				n, err := strconv.Atoi(strings.TrimSpace(e.PartNumber))
+				if err != nil {
+					rec.Err = fmt.Errorf("invalid series part number %q", e.PartNumber)
+					return rec
+				}
+				book.SeriesPosition = n
+				rec.Fields["series_position"] = true
+			}
+		}
+	}
+	for _, c := range d.Contributors {
+		if role := contributorRole(c.Roles); role != "" && c.name() != "" {
+			book.Contributors = append(book.Contributors, domain.Contributor{Name: c.name(), Role: role})
+			rec.Fields["contributors"] = true
+		}
+	}
+	for _, l := range d.Languages {
+		if l.Role == languageOfText {
+			book.Language = languageName(l.Code)
+			rec.Fields["language"] = true
+			break
+		}
+	}
+	for _, e := range d.Extents {
+		if e.Type == extentMain && e.Unit == extentPages {
+			n, err := strconv.Atoi(strings.TrimSpace(e.Value))
+			if err != nil {
+				rec.Err = fmt.Errorf("invalid page count %q", e.Value)
+				return rec
+			}
+			book.Pages = n
+			rec.Fields["pages"] = true
+		}
+	}
+	for _, s := range d.Subjects {
+		if s.Scheme == subjectKeyword && s.Heading != "" {
+			book.Genre = strings.TrimSpace(s.Heading)
+			rec.Fields["genre"] = true
+			break
+		}
+	}
+	for _, t := range p.Texts {
+		if t.Type == textDesc || (t.Type == textShortDesc && book.Description == "") {
+			book.Description = strings.TrimSpace(t.Text)
+			rec.Fields["description"] = true
+		}
+	}
+	if p.Publishing != nil {
+		for _, pub := range p.Publishing.Publishers {
+			if pub.Role == publisherRole {
+				book.Publisher = strings.TrimSpace(pub.Name)
+				rec.Fields["publisher"] = true
+			}
+		}
+		for _, date := range p.Publishing.Dates {
+			if date.Role == publicationDate {
+				book.PublishedDate = fromONIXDate(date.Date.Value)
+				rec.Fields["published_date"] = true
+			}
+		}
+	}
+	for _, s := range p.Supply {
+		for _, price := range s.Prices {
+			if price.Type == priceRRP {
+				book.Price = price.Amount
+				rec.Fields["price"] = true
+			}
+		}
+		if len(s.Stock) > 0 {
+			rec.Fields["stock"] = true
+			for _, st := range s.Stock {
+				book.Stock += st.OnHand
+			}
+		}
+	}
+	return rec
+}
+
+func (t *onixTitleDetail) element(level string) (*onixTitleElement, bool) {
+	for i := range t.Elements {
+		if t.Elements[i].Level == level {
+			return &t.Elements[i], true
+		}
+	}
+	return nil, false
+}
+
+func (e *onixTitleElement) title() string {
+	title := e.Text
+	if title == "" {
+		title = strings.TrimSpace(e.Prefix + " " + e.WithoutPrefix)
+	}
+	if e.Subtitle != "" {
+		title += ": " + e.Subtitle
+	}
+	return strings.TrimSpace(title)
+}
+
+// name prefers the natural order; "Tolkien, J. R. R." is turned around.
+func (c *onixContributor) name() string {
+	switch {
+	case c.PersonName != "":
+		return strings.TrimSpace(c.PersonName)
+	case c.NameInverted != "":
+		last, first, ok := strings.Cut(c.NameInverted, ",")
+		if !ok {
+			return strings.TrimSpace(c.NameInverted)
+		}
+		return strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+	default:
+		return strings.TrimSpace(c.Corporate)
+	}
+}
+
+func contributorRole(codes []string) string {
+	for _, code := range codes {
+		for role, c := range onixRoles {
+			if c == code {
+				return role
+			}
+		}
+	}
+	return ""
+}
+
+func languageName(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	for name, c := range onixLanguages {
+		if c == code {
+			return strings.ToUpper(name[:1]) + name[1:]
+		}
+	}
+	return code
+}
+
+func languageCode(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if code, ok := onixLanguages[name]; ok {
+		return code
+	}
+	if len(name) == 3 {
+		return name
+	}
+	return ""
+}
+
+// ONIX dates are YYYYMMDD by default; the catalog writes YYYY-MM-DD.
+func fromONIXDate(s string) string {
+	s = strings.TrimSpace(s)
+	switch len(s) {
+	case 8:
+		return s[:4] + "-" + s[4:6] + "-" + s[6:]
+	case 6:
+		return s[:4] + "-" + s[4:]
+	}
+	return s
+}
+
+// toONIXDate returns the date with its List 55 format code.
+func toONIXDate(s string) (string, string) {
+	compact := strings.ReplaceAll(s, "-", "")
+	switch len(compact) {
+	case 4:
+		return compact, "05"
+	case 6:
+		return compact, "01"
+	}
+	return compact, ""
+}
+
+type onixWriter struct {
+	w       io.Writer
+	enc     *xml.Encoder
+	started bool
+}
+
+func newONIXWriter(w io.Writer) *onixWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return &onixWriter{w: w, enc: enc}
+}
+
+func (w *onixWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	if _, err := io.WriteString(w.w, xml.Header); err != nil {
+		return err
+	}
+	root := xml.StartElement{
+		Name: xml.Name{Local: "ONIXMessage"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: onixNamespace},
+			{Name: xml.Name{Local: "release"}, Value: "3.0"},
+		},
+	}
+	if err := w.enc.EncodeToken(root); err != nil {
+		return err
+	}
+	return w.enc.Encode(onixHeader{
+		SenderName:   "ReadSpace",
+		SentDateTime: time.Now().UTC().Format("20060102T1504Z"),
+	})
+}
+
+func (w *onixWriter) Write(book *domain.Book) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	return w.enc.Encode(productOf(book))
+}
+
+func (w *onixWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "ONIXMessage"}}); err != nil {
+		return err
+	}
+	if err := w.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, "\n")
+	return err
+}
+
+func productOf(b *domain.Book) *onixProduct {
+	p := &onixProduct{
+		RecordReference:  "readspace:" + b.ID.Hex(),
+		NotificationType: notificationConfirmed,
+		Identifiers:      []onixIdentifier{{Type: idProprietary, TypeName: "ReadSpace ID", Value: b.ID.Hex()}},
+		Descriptive: onixDescriptive{
+			Composition: "00",
+			Form:        onixForms[b.Format],
+			Titles: []onixTitleDetail{{
+				Type:     titleDistinctive,
+				Elements: []onixTitleElement{{Level: levelProduct, Text: b.Title}},
+			}},
+			Edition: b.Edition,
+		},
+	}
+	if b.ISBN != "" {
+		p.Identifiers = append(p.Identifiers, onixIdentifier{Type: idISBN13, Value: b.ISBN})
+	}
+	d := &p.Descriptive
+	if d.Form == "" {
+		d.Form = "00" // не определена
+	}
+	if b.Series != "" {
+		e := onixTitleElement{Level: levelCollection, Text: b.Series}
+		if b.SeriesPosition > 0 {
+			e.PartNumber = strconv.Itoa(b.SeriesPosition)
+		}
+		d.Collections = []onixCollection{{
+			Type:   collectionSeries,
+			Titles: []onixTitleDetail{{Type: titleDistinctive, Elements: []onixTitleElement{e}}},
+		}}
+	}
+	for i, c := range b.Contributors {
+		d.Contributors = append(d.Contributors, onixContributor{
+			Sequence:   i + 1,
+			Roles:      []string{onixRoles[c.Role]},
+			PersonName: c.Name,
+		})
+	}
+	if code := languageCode(b.Language); code != "" {
+		d.Languages = []onixLanguage{{Role: languageOfText, Code: code}}
+	}
+	if b.Pages > 0 {
+		d.Extents = []onixExtent{{Type: extentMain, Value: strconv.Itoa(b.Pages), Unit: extentPages}}
+	}
+	if b.Genre != "" {
+		d.Subjects = []onixSubject{{Scheme: subjectKeyword, Heading: b.Genre}}
+	}
+	if b.Description != "" {
+		p.Texts = []onixText{{Type: textDesc, Audience: audienceAll, Text: b.Description}}
+	}
+
+	if b.Publisher != "" || b.PublishedDate != "" {
+		p.Publishing = &onixPublishing{}
+		if b.Publisher != "" {
+			p.Publishing.Publishers = []onixPublisher{{Role: publisherRole, Name: b.Publisher}}
+		}
+		if b.PublishedDate != "" {
+			date := onixDate{Role: publicationDate}
+			date.Date.Value, date.Date.Format = toONIXDate(b.PublishedDate)
+			p.Publishing.Dates = []onixDate{date}
+		}
+	}
+
+	availability := "21" // List 65: в наличии
+	if b.Stock <= 0 {
+		availability = "31" // нет в наличии
+	}
+	supply := onixSupplyDetail{
+		SupplierRole: supplierRole,
+		SupplierName: "ReadSpace",
+		Availability: availability,
+		Stock:        []onixStock{{OnHand: b.Stock}},
+	}
+	if b.Price > 0 {
+		supply.Prices = []onixPrice{{Type: priceRRP, Amount: b.Price}}
+	} else {
+		supply.Unpriced = priceToCome
+	}
+	p.Supply = []onixSupplyDetail{supply}
+	return p
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrAuthorNameRequired = errors.New("author name is required")
+	ErrAuthorNameTaken    = errors.New("name or alias already belongs to another author")
+	ErrInvalidAuthorDate  = errors.New("author dates must be YYYY or YYYY-MM-DD and death must not precede birth")
+	ErrAuthorHasBooks     = errors.New("author is still credited on books")
+	ErrUnknownAuthor      = errors.New("contributor refers to an unknown author")
+)
+
+// Author is a person who can be credited on books. Besides the canonical
+// Name an author may be known under aliases ("JRR Tolkien", pen names);
+// any of them finds the same author.
+type Author struct {
+	ID      primitive.ObjectID `bson:"_id"`
+	Name    string             `bson:"name"`
+	Aliases []string           `bson:"aliases"`
+	Bio     string             `bson:"bio"`
+	// Dates are kept as written, either a year or a full date.
+	BirthDate string `bson:"birth_date,omitempty"`
+	DeathDate string `bson:"death_date,omitempty"`
+	// NameKeys are the normalized name and aliases; a unique index on them
+	// keeps two authors from claiming the same name.
+	NameKeys  []string           `bson:"name_keys"`
+	CreatedAt primitive.DateTime `bson:"created_at"`
+	UpdatedAt primitive.DateTime `bson:"updated_at"`
+}
+
+// NameKey reduces a name to lower-case letters and digits, so spelling
+// variants such as "J.R.R. Tolkien", "J. R. R. Tolkien" and "JRR Tolkien"
+// share one key.
+func NameKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Normalize trims the fields, drops aliases that only repeat the name or
+// each other, checks the dates and derives NameKeys.
+func (a *Author) Normalize() error {
+	a.Name = strings.TrimSpace(a.Name)
+	if NameKey(a.Name) == "" {
+		return ErrAuthorNameRequired
+	}
+	a.NameKeys = []string{NameKey(a.Name)}
+	aliases := a.Aliases
+	a.Aliases = nil
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := NameKey(alias)
+		if key == "" || containsKey(a.NameKeys, key) {
+			continue
+		}
+		a.Aliases = append(a.Aliases, alias)
+		a.NameKeys = append(a.NameKeys, key)
+	}
+
+	a.Bio = strings.TrimSpace(a.Bio)
+	a.BirthDate = strings.TrimSpace(a.BirthDate)
+	a.DeathDate = strings.TrimSpace(a.DeathDate)
+	birth, err := parseAuthorDate(a.BirthDate)
+	if err != nil {
+		return err
+	}
+	death, err := parseAuthorDate(a.DeathDate)
+	if err != nil {
+		return err
+	}
+	if !birth.IsZero() && !death.IsZero() && death.Before(birth) {
+		return ErrInvalidAuthorDate
+	}
+	return nil
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAuthorDate accepts an empty string, a year or a full date.
+func parseAuthorDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidAuthorDate, s)
+}
@@ -1,9 +1,27 @@
 package domain
 
 import (
+	"errors"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// LowStockThreshold is the number of remaining copies at or below which
+// book.stock.low is published.
+const LowStockThreshold = 3
+
+// DefaultStock is given to books created before stock was tracked, so they
+// stay orderable until a librarian adjusts the count.
+const DefaultStock = 10
+
+var (
+	ErrInvalidQuantity   = errors.New("quantity must be positive")
+	ErrInsufficientStock = errors.New("not enough copies in stock")
+)
+
+// Book is a catalog entry. Rating and RatingCount aggregate the book's
+// reviews and are only changed by the review use case. Author is the
+// display name of the first author; the full credits are in Contributors.
 type Book struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty"`
 	Title         string             `bson:"title"`
@@ -12,7 +30,31 @@ type Book struct {
 	Language      string             `bson:"language"`
 	Description   string             `bson:"description"`
 	Rating        float32            `bson:"rating"`
+	RatingCount   int                `bson:"rating_count"`
 	Price         float32            `bson:"price"`
 	Pages         int                `bson:"pages"`
 	PublishedDate string             `bson:"published_date"`
+	Stock         int                `bson:"stock"`
+
+	// ISBN is stored as ISBN-13 and is unique when set.
+	ISBN           string        `bson:"isbn,omitempty"`
+	Contributors   []Contributor `bson:"contributors"`
+	Publisher      string        `bson:"publisher"`
+	Series         string        `bson:"series"`
+	SeriesPosition int           `bson:"series_position"`
+	Edition        string        `bson:"edition"`
+	Format         string        `bson:"format"`
+}
+
+// StockEvent is published on book.stock.low and book.stock.out.
+type StockEvent struct {
+	BookID string `json:"book_id"`
+	Title  string `json:"title"`
+	Stock  int    `json:"stock"`
+}
+
+// BookDeletedEvent is published on "book.deleted" so that other services
+// can drop library entries, offers and wishlist items pointing at the book.
+type BookDeletedEvent struct {
+	BookID string `json:"book_id"`
 }
@@ -0,0 +1,51 @@
+package domain
+
+import "errors"
+
+// Outcomes of an imported row.
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportFailed  = "failed"
+)
+
+// MaxImportErrors caps the failed rows kept in an ImportSummary; Failed
+// still counts all of them.
+const MaxImportErrors = 1000
+
+var (
+	ErrTitleRequired = errors.New("title is required")
+	ErrNegativeValue = errors.New("price and stock must not be negative")
+)
+
+// ImportResult is the outcome of one row of an import file. Book is the
+// book as saved, or as it would have been saved in a dry run.
+type ImportResult struct {
+	Row    int
+	ISBN   string
+	Action string
+	Book   *Book
+	Err    error
+}
+
+type ImportSummary struct {
+	DryRun  bool
+	Created int
+	Updated int
+	Failed  int
+	Errors  []*ImportResult
+}
+
+func (s *ImportSummary) Add(r *ImportResult) {
+	switch r.Action {
+	case ImportCreated:
+		s.Created++
+	case ImportUpdated:
+		s.Updated++
+	default:
+		s.Failed++
+		if len(s.Errors) < MaxImportErrors {
+			s.Errors = append(s.Errors, r)
+		}
+	}
+}
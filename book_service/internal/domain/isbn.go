@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidISBN = errors.New("invalid ISBN")
+
+// NormalizeISBN checks an ISBN-10 or ISBN-13 and returns it as ISBN-13
+// digits, so that both forms of the same book compare equal. Hyphens and
+// spaces are ignored.
+func NormalizeISBN(isbn string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(isbn)))
+
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", ErrInvalidISBN
+		}
+		// ISBN-10 становится ISBN-13 с префиксом 978 и новой контрольной цифрой
+		body := "978" + digits[:9]
+		return body + string(isbn13Check(body)), nil
+	case 13:
+		if !allDigits(digits) || isbn13Check(digits[:12]) != digits[12] {
+			return "", ErrInvalidISBN
+		}
+		return digits, nil
+	default:
+		return "", ErrInvalidISBN
+	}
+}
+
+// validISBN10 checks the mod 11 checksum; the check digit may be X (10).
+func validISBN10(s string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var d int
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			d = int(c - '0')
+		case c == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * d
+	}
+	return sum%11 == 0
+}
+
+// isbn13Check computes the check digit for the first twelve digits.
+func isbn13Check(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
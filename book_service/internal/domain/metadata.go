@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Contributor roles.
+const (
+	RoleAuthor      = "author"
+	RoleEditor      = "editor"
+	RoleTranslator  = "translator"
+	RoleIllustrator = "illustrator"
+	RoleNarrator    = "narrator"
+)
+
+// Edition formats.
+const (
+	FormatHardcover = "hardcover"
+	FormatPaperback = "paperback"
+	FormatEbook     = "ebook"
+	FormatAudiobook = "audiobook"
+)
+
+var (
+	ErrDuplicateISBN      = errors.New("a book with this ISBN already exists")
+	ErrInvalidContributor = errors.New("invalid contributor")
+	ErrInvalidFormat      = errors.New("unknown edition format")
+	ErrInvalidSeries      = errors.New("series position needs a series name and must not be negative")
+)
+
+// Contributor is a person credited on a book.
+type Contributor struct {
+	Name string `bson:"name"`
+	Role string `bson:"role"`
+	// AuthorID links the contributor to an Author; Name then repeats the
+	// author's canonical name.
+	AuthorID primitive.ObjectID `bson:"author_id,omitempty"`
+}
+
+func validRole(role string) bool {
+	switch role {
+	case RoleAuthor, RoleEditor, RoleTranslator, RoleIllustrator, RoleNarrator:
+		return true
+	}
+	return false
+}
+
+func validFormat(format string) bool {
+	switch format {
+	case "", FormatHardcover, FormatPaperback, FormatEbook, FormatAudiobook:
+		return true
+	}
+	return false
+}
+
+// NormalizeMetadata validates the structured fields of a book before it
+// is stored. The ISBN is converted to ISBN-13. Author stays the display
+// name of the first author: it is filled from Contributors, or turned into
+// the only contributor when no contributors are given. A contributor may
+// be given by AuthorID alone, its name is filled in when it is linked.
+func (b *Book) NormalizeMetadata() error {
+	if strings.TrimSpace(b.ISBN) != "" {
+		isbn, err := NormalizeISBN(b.ISBN)
+		if err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidISBN, b.ISBN)
+		}
+		b.ISBN = isbn
+	} else {
+		b.ISBN = ""
+	}
+
+	b.Author = strings.TrimSpace(b.Author)
+	if len(b.Contributors) == 0 && b.Author != "" {
+		b.Contributors = []Contributor{{Name: b.Author, Role: RoleAuthor}}
+	}
+	for i := range b.Contributors {
+		c := &b.Contributors[i]
+		c.Name = strings.TrimSpace(c.Name)
+		c.Role = strings.ToLower(strings.TrimSpace(c.Role))
+		if c.Role == "" {
+			c.Role = RoleAuthor
+		}
+		if (c.Name == "" && c.AuthorID.IsZero()) || !validRole(c.Role) {
+			return fmt.Errorf("%w: %q as %q", ErrInvalidContributor, c.Name, c.Role)
+		}
+	}
+	b.SyncAuthor()
+
+	b.Format = strings.ToLower(strings.TrimSpace(b.Format))
+	if !validFormat(b.Format) {
+		return fmt.Errorf("%w: %q", ErrInvalidFormat, b.Format)
+	}
+
+	b.Series = strings.TrimSpace(b.Series)
+	if b.SeriesPosition < 0 || (b.SeriesPosition > 0 && b.Series == "") {
+		return ErrInvalidSeries
+	}
+	b.Publisher = strings.TrimSpace(b.Publisher)
+	b.Edition = strings.TrimSpace(b.Edition)
+	return nil
+}
+
+// SyncAuthor sets Author to the name of the first credited author, if any.
+func (b *Book) SyncAuthor() {
+	for _, c := range b.Contributors {
+		if c.Role == RoleAuthor && c.Name != "" {
+			b.Author = c.Name
+			return
+		}
+	}
+}
+
+// ContributorNames lists everyone credited on the book, authors first.
+func (b *Book) ContributorNames() []string {
+	var names []string
+	for _, c := range b.Contributors {
+		if c.Role == RoleAuthor {
+			names = append(names, c.Name)
+		}
+	}
+	for _, c := range b.Contributors {
+		if c.Role != RoleAuthor {
+			names = append(names, c.Name)
+		}
+	}
+	if len(names) == 0 && b.Author != "" {
+		names = append(names, b.Author)
+	}
+	return names
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+
+	// MaxAuthorFacets caps the author facet; genres and languages are few.
+	MaxAuthorFacets = 20
+)
+
+// Sort keys accepted by BookQuery.Sort. A leading "-" sorts descending.
+const (
+	SortTitle     = "title"
+	SortPrice     = "price"
+	SortRating    = "rating"
+	SortPages     = "pages"
+	SortPublished = "published"
+)
+
+var ErrInvalidQuery = errors.New("invalid book query")
+
+// BookQuery combines the catalog filters. Zero values leave a filter out,
+// so a zero maximum means "no upper bound". Publication years are matched
+// against the leading year of PublishedDate.
+type BookQuery struct {
+	Keyword  string
+	Genre    string
+	Author   string
+	Language string
+
+	MinPrice, MaxPrice   float32
+	MinRating, MaxRating float32
+	MinPages, MaxPages   int
+	YearFrom, YearTo     int
+
+	Sort     string
+	PageSize int
+	// Cursor is the opaque NextCursor of the previous page.
+	Cursor string
+}
+
+// Normalize fills in defaults and rejects contradictory or negative bounds.
+func (q *BookQuery) Normalize() error {
+	q.Keyword = strings.TrimSpace(q.Keyword)
+	if q.MinPrice < 0 || q.MaxPrice < 0 || q.MinRating < 0 || q.MaxRating < 0 ||
+		q.MinPages < 0 || q.MaxPages < 0 || q.YearFrom < 0 || q.YearTo < 0 {
+		return fmt.Errorf("%w: bounds must not be negative", ErrInvalidQuery)
+	}
+	if q.MaxPrice > 0 && q.MinPrice > q.MaxPrice {
+		return fmt.Errorf("%w: min_price is above max_price", ErrInvalidQuery)
+	}
+	if q.MaxRating > 0 && q.MinRating > q.MaxRating {
+		return fmt.Errorf("%w: min_rating is above max_rating", ErrInvalidQuery)
+	}
+	if q.MaxRating > MaxRating {
+		return fmt.Errorf("%w: ratings go up to %d", ErrInvalidQuery, MaxRating)
+	}
+	if q.MaxPages > 0 && q.MinPages > q.MaxPages {
+		return fmt.Errorf("%w: min_pages is above max_pages", ErrInvalidQuery)
+	}
+	if q.YearTo > 0 && q.YearFrom > q.YearTo {
+		return fmt.Errorf("%w: year_from is after year_to", ErrInvalidQuery)
+	}
+
+	if q.Sort == "" {
+		q.Sort = SortTitle
+	}
+	switch strings.TrimPrefix(q.Sort, "-") {
+	case SortTitle, SortPrice, SortRating, SortPages, SortPublished:
+	default:
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidQuery, q.Sort)
+	}
+
+	switch {
+	case q.PageSize <= 0:
+		q.PageSize = DefaultPageSize
+	case q.PageSize > MaxPageSize:
+		q.PageSize = MaxPageSize
+	}
+	return nil
+}
+
+// Facet is the number of matching books sharing one value of a field.
+type Facet struct {
+	Value string
+	Count int
+}
+
+// BookPage is one page of a BookQuery. Total and the facets cover every
+// matching book, not just this page. NextCursor is empty on the last page.
+type BookPage struct {
+	Books      []*Book
+	NextCursor string
+	Total      int
+	Genres     []Facet
+	Languages  []Facet
+	Authors    []Facet
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+	// MaxReviewLength caps the review text, in characters.
+	MaxReviewLength = 5000
+)
+
+var (
+	ErrInvalidRating   = errors.New("rating must be between 1 and 5")
+	ErrReviewTooLong   = errors.New("review text is too long")
+	ErrAlreadyReviewed = errors.New("user has already reviewed this book")
+	// ErrRatingChanged means another update stored the book's rating after
+	// it was read.
+	ErrRatingChanged = errors.New("book rating changed concurrently")
+)
+
+// Review is a reader's rating and opinion of a book. A user reviews a book
+// at most once; later changes edit the same review.
+type Review struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	BookID    primitive.ObjectID `bson:"book_id"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	Rating    int                `bson:"rating"`
+	Text      string             `bson:"text"`
+	CreatedAt primitive.DateTime `bson:"created_at"`
+	UpdatedAt primitive.DateTime `bson:"updated_at"`
+}
+
+// Validate checks the rating range and the text length.
+func (r *Review) Validate() error {
+	if r.Rating < MinRating || r.Rating > MaxRating {
+		return ErrInvalidRating
+	}
+	if len([]rune(r.Text)) > MaxReviewLength {
+		return ErrReviewTooLong
+	}
+	return nil
+}
+
+// RatingSummary is the aggregate of a book's reviews.
+type RatingSummary struct {
+	Average float32
+	Count   int
+}
+
+// ReviewEvent is published on book.review.submitted, book.review.updated
+// and book.review.deleted.
+type ReviewEvent struct {
+	ReviewID string `json:"review_id"`
+	BookID   string `json:"book_id"`
+	UserID   string `json:"user_id"`
+	Rating   int    `json:"rating"`
+}
+
+// UserDeletedEvent comes from the user service; the user's reviews are
+// removed and the ratings of the books they reviewed recomputed.
+type UserDeletedEvent struct {
+	UserID string `json:"user_id"`
+}
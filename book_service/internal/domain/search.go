@@ -0,0 +1,29 @@
+package domain
+
+// Highlight is a fragment of a book field with the matched words wrapped
+// in <em></em>.
+type Highlight struct {
+	Field   string
+	Snippet string
+}
+
+// SearchHit is a book found by the full-text search, best hits first.
+type SearchHit struct {
+	Book       *Book
+	Score      float64
+	Highlights []Highlight
+}
+
+// TitleSuggestion completes what a reader is typing into a book title.
+type TitleSuggestion struct {
+	BookID      string
+	Title       string
+	Highlighted string
+}
+
+// BookEvent is published on "book.created" and "book.updated".
+type BookEvent struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	pb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+)
+
+func (h *BookHandler) CreateAuthor(ctx context.Context, req *pb.CreateAuthorRequest) (*pb.AuthorResponse, error) {
+	if req == nil || req.Author == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	author, err := h.authors.CreateAuthor(ctx, fromProtoAuthor(req.Author))
+	if err != nil {
+		return nil, authorError(err)
+	}
+	return &pb.AuthorResponse{Author: toProtoAuthor(author)}, nil
+}
+
+func (h *BookHandler) GetAuthor(ctx context.Context, req *pb.AuthorID) (*pb.AuthorResponse, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "author ID is required")
+	}
+	author, err := h.authors.GetAuthor(ctx, req.Id)
+	if err != nil {
+		return nil, authorError(err)
+	}
+	return &pb.AuthorResponse{Author: toProtoAuthor(author)}, nil
+}
+
+func (h *BookHandler) FindAuthor(ctx context.Context, req *pb.FindAuthorRequest) (*pb.AuthorResponse, error) {
+	if req == nil || req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	author, err := h.authors.FindAuthor(ctx, req.Name)
+	if err != nil {
+		return nil, authorError(err)
+	}
+	return &pb.AuthorResponse{Author: toProtoAuthor(author)}, nil
+}
+
+// UpdateAuthor replaces the author's details. Books crediting a renamed
+// author are updated too and announced as book.updated.
+func (h *BookHandler) UpdateAuthor(ctx context.Context, req *pb.UpdateAuthorRequest) (*pb.AuthorResponse, error) {
+	if req == nil || req.Author == nil || req.Author.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "author ID is required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	id, err := primitive.ObjectIDFromHex(req.Author.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid author ID")
+	}
+	author := fromProtoAuthor(req.Author)
+	author.ID = id
+	updated, books, err := h.authors.UpdateAuthor(ctx, author)
+	if err != nil {
+		return nil, authorError(err)
+	}
+	for _, b := range books {
+		h.publishBook("book.updated", b)
+	}
+	return &pb.AuthorResponse{Author: toProtoAuthor(updated)}, nil
+}
+
+func (h *BookHandler) DeleteAuthor(ctx context.Context, req *pb.AuthorID) (*pb.Empty, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "author ID is required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := h.authors.DeleteAuthor(ctx, req.Id); err != nil {
+		return nil, authorError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (h *BookHandler) ListAuthors(ctx context.Context, _ *pb.Empty) (*pb.AuthorList, error) {
+	authors, err := h.authors.ListAuthors(ctx)
+	if err != nil {
+		return nil, authorError(err)
+	}
+	res := make([]*pb.Author, 0, len(authors))
+	for _, a := range authors {
+		res = append(res, toProtoAuthor(a))
+	}
+	return &pb.AuthorList{Authors: res}, nil
+}
+
+func (h *BookHandler) ListBooksByAuthorID(ctx context.Context, req *pb.AuthorID) (*pb.BookList, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "author ID is required")
+	}
+	books, err := h.authors.ListBooksByAuthorID(ctx, req.Id)
+	if err != nil {
+		return nil, authorError(err)
+	}
+	return &pb.BookList{Books: toProtoList(books)}, nil
+}
+
+func authorError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrAuthorNameRequired), errors.Is(err, domain.ErrInvalidAuthorDate):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrAuthorNameTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrAuthorHasBooks):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, "author not found")
+	default:
+		return status.Errorf(codes.Internal, "author operation failed: %v", err)
+	}
+}
+
+func toProtoAuthor(a *domain.Author) *pb.Author {
+	return &pb.Author{
+		Id:        a.ID.Hex(),
+		Name:      a.Name,
+		Aliases:   a.Aliases,
+		Bio:       a.Bio,
+		BirthDate: a.BirthDate,
+		DeathDate: a.DeathDate,
+	}
+}
+
+func fromProtoAuthor(a *pb.Author) *domain.Author {
+	return &domain.Author{
+		Name:      a.Name,
+		Aliases:   a.Aliases,
+		Bio:       a.Bio,
+		BirthDate: a.BirthDate,
+		DeathDate: a.DeathDate,
+	}
+}
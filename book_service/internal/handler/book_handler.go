@@ -3,27 +3,41 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log"
 
 	"github.com/nats-io/nats.go"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
 	"github.com/OshakbayAigerim/read_space/book_service/internal/usecase"
 	pb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
 )
 
 type BookHandler struct {
 	pb.UnimplementedBookServiceServer
-	usecase usecase.BookUseCase
-	nc      *nats.Conn
+	usecase   usecase.BookUseCase
+	reviews   usecase.ReviewUseCase
+	recommend usecase.RecommendUseCase
+	search    usecase.SearchUseCase
+	authors   usecase.AuthorUseCase
+	catalog   usecase.CatalogUseCase
+	nc        *nats.Conn
 }
 
-func NewBookHandler(u usecase.BookUseCase, nc *nats.Conn) *BookHandler {
+func NewBookHandler(u usecase.BookUseCase, reviews usecase.ReviewUseCase, recommend usecase.RecommendUseCase, search usecase.SearchUseCase, authors usecase.AuthorUseCase, catalog usecase.CatalogUseCase, nc *nats.Conn) *BookHandler {
 	return &BookHandler{
-		usecase: u,
-		nc:      nc,
+		usecase:   u,
+		reviews:   reviews,
+		recommend: recommend,
+		search:    search,
+		authors:   authors,
+		catalog:   catalog,
+		nc:        nc,
 	}
 }
 
@@ -31,71 +45,45 @@ func (h *BookHandler) CreateBook(ctx context.Context, req *pb.CreateBookRequest)
 	if req == nil || req.Book == nil {
 		return nil, status.Errorf(codes.InvalidArgument, "empty request")
 	}
-	book := &domain.Book{
-		Title:         req.Book.Title,
-		Author:        req.Book.Author,
-		Genre:         req.Book.Genre,
-		Language:      req.Book.Language,
-		Description:   req.Book.Description,
-		Rating:        req.Book.Rating,
-		Price:         req.Book.Price,
-		Pages:         int(req.Book.Pages),
-		PublishedDate: req.Book.PublishedDate,
-	}
-
-	created, err := h.usecase.CreateBook(ctx, book)
-	if err != nil {
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
 		return nil, err
 	}
-
-	evt := struct {
-		Id     string `json:"id"`
-		Title  string `json:"title"`
-		Author string `json:"author"`
-	}{
-		Id:     created.ID.Hex(),
-		Title:  created.Title,
-		Author: created.Author,
+	created, err := h.usecase.CreateBook(ctx, fromProto(req.Book))
+	if err != nil {
+		return nil, bookError("cannot create book", err)
 	}
-	if data, err := json.Marshal(evt); err == nil {
-		h.nc.Publish("book.created", data)
-	}
-
-	return &pb.BookResponse{
-		Book: &pb.Book{
-			Id:            created.ID.Hex(),
-			Title:         created.Title,
-			Author:        created.Author,
-			Genre:         created.Genre,
-			Language:      created.Language,
-			Description:   created.Description,
-			Rating:        created.Rating,
-			Price:         created.Price,
-			Pages:         int32(created.Pages),
-			PublishedDate: created.PublishedDate,
-		},
-	}, nil
+
+	h.publishBook("book.created", created)
+
+	return &pb.BookResponse{Book: toProto(created)}, nil
 }
 
 func (h *BookHandler) GetBook(ctx context.Context, req *pb.BookID) (*pb.BookResponse, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "book ID is required")
+	}
+	if _, err := primitive.ObjectIDFromHex(req.Id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid book ID")
+	}
 	book, err := h.usecase.GetBookByID(ctx, req.Id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Error(codes.NotFound, "book not found")
+	}
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.Internal, "cannot get book: %v", err)
 	}
-	return &pb.BookResponse{
-		Book: &pb.Book{
-			Id:            book.ID.Hex(),
-			Title:         book.Title,
-			Author:        book.Author,
-			Genre:         book.Genre,
-			Language:      book.Language,
-			Description:   book.Description,
-			Rating:        book.Rating,
-			Price:         book.Price,
-			Pages:         int32(book.Pages),
-			PublishedDate: book.PublishedDate,
-		},
-	}, nil
+	return &pb.BookResponse{Book: toProto(book)}, nil
+}
+
+func (h *BookHandler) GetBookByISBN(ctx context.Context, req *pb.ISBNRequest) (*pb.BookResponse, error) {
+	if req == nil || req.Isbn == "" {
+		return nil, status.Error(codes.InvalidArgument, "ISBN is required")
+	}
+	book, err := h.usecase.GetBookByISBN(ctx, req.Isbn)
+	if err != nil {
+		return nil, bookError("cannot get book", err)
+	}
+	return &pb.BookResponse{Book: toProto(book)}, nil
 }
 
 func (h *BookHandler) ListAllBooks(ctx context.Context, _ *pb.Empty) (*pb.BookList, error) {
@@ -104,71 +92,54 @@ func (h *BookHandler) ListAllBooks(ctx context.Context, _ *pb.Empty) (*pb.BookLi
 		return nil, err
 	}
 
-	var res []*pb.Book
-	for _, b := range books {
-		res = append(res, &pb.Book{
-			Id:            b.ID.Hex(),
-			Title:         b.Title,
-			Author:        b.Author,
-			Genre:         b.Genre,
-			Language:      b.Language,
-			Description:   b.Description,
-			Rating:        b.Rating,
-			Price:         b.Price,
-			Pages:         int32(b.Pages),
-			PublishedDate: b.PublishedDate,
-		})
-	}
-	return &pb.BookList{Books: res}, nil
+	return &pb.BookList{Books: toProtoList(books)}, nil
 }
 
 func (h *BookHandler) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.BookResponse, error) {
 	if req == nil || req.Book == nil {
 		return nil, status.Error(codes.InvalidArgument, "empty update request")
 	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
 	objID, err := primitive.ObjectIDFromHex(req.Book.Id)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid book ID")
 	}
-	book := &domain.Book{
-		ID:            objID,
-		Title:         req.Book.Title,
-		Author:        req.Book.Author,
-		Genre:         req.Book.Genre,
-		Language:      req.Book.Language,
-		Description:   req.Book.Description,
-		Rating:        req.Book.Rating,
-		Price:         req.Book.Price,
-		Pages:         int(req.Book.Pages),
-		PublishedDate: req.Book.PublishedDate,
-	}
+	book := fromProto(req.Book)
+	book.ID = objID
 	updated, err := h.usecase.UpdateBook(ctx, book)
 	if err != nil {
-		return nil, err
+		return nil, bookError("cannot update book", err)
 	}
-	return &pb.BookResponse{
-		Book: &pb.Book{
-			Id:            updated.ID.Hex(),
-			Title:         updated.Title,
-			Author:        updated.Author,
-			Genre:         updated.Genre,
-			Language:      updated.Language,
-			Description:   updated.Description,
-			Rating:        updated.Rating,
-			Price:         updated.Price,
-			Pages:         int32(updated.Pages),
-			PublishedDate: updated.PublishedDate,
-		},
-	}, nil
+	h.publishBook("book.updated", updated)
+	return &pb.BookResponse{Book: toProto(updated)}, nil
 }
 
 func (h *BookHandler) DeleteBook(ctx context.Context, req *pb.BookID) (*pb.Empty, error) {
 	if req == nil || req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "book ID is required")
 	}
-	if err := h.usecase.DeleteBook(ctx, req.Id); err != nil {
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	err := h.usecase.DeleteBook(ctx, req.Id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Error(codes.NotFound, "book not found")
+	}
+	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete book: %v", err)
 	}
+
+	if err := h.reviews.RemoveBookReviews(ctx, req.Id); err != nil {
+		log.Printf("⚠ cannot remove reviews of deleted book %s: %v", req.Id, err)
+	}
+
+	if data, err := json.Marshal(domain.BookDeletedEvent{BookID: req.Id}); err == nil {
+		if err := h.nc.Publish("book.deleted", data); err != nil {
+			log.Printf("⚠ NATS publish error (book.deleted): %v", err)
+		}
+	}
 	return &pb.Empty{}, nil
 }
 
@@ -177,45 +148,16 @@ func (h *BookHandler) ListBooksByGenre(ctx context.Context, req *pb.GenreRequest
 	if err != nil {
 		return nil, err
 	}
-	var res []*pb.Book
-	for _, b := range books {
-		res = append(res, &pb.Book{
-			Id:            b.ID.Hex(),
-			Title:         b.Title,
-			Author:        b.Author,
-			Genre:         b.Genre,
-			Language:      b.Language,
-			Description:   b.Description,
-			Rating:        b.Rating,
-			Price:         b.Price,
-			Pages:         int32(b.Pages),
-			PublishedDate: b.PublishedDate,
-		})
-	}
-	return &pb.BookList{Books: res}, nil
+	return &pb.BookList{Books: toProtoList(books)}, nil
 }
 
+// ListBooksByAuthor accepts the author's name or any alias.
 func (h *BookHandler) ListBooksByAuthor(ctx context.Context, req *pb.AuthorRequest) (*pb.BookList, error) {
-	books, err := h.usecase.ListBooksByAuthor(ctx, req.Author)
+	books, err := h.authors.ListBooksByAuthorName(ctx, req.Author)
 	if err != nil {
 		return nil, err
 	}
-	var res []*pb.Book
-	for _, b := range books {
-		res = append(res, &pb.Book{
-			Id:            b.ID.Hex(),
-			Title:         b.Title,
-			Author:        b.Author,
-			Genre:         b.Genre,
-			Language:      b.Language,
-			Description:   b.Description,
-			Rating:        b.Rating,
-			Price:         b.Price,
-			Pages:         int32(b.Pages),
-			PublishedDate: b.PublishedDate,
-		})
-	}
-	return &pb.BookList{Books: res}, nil
+	return &pb.BookList{Books: toProtoList(books)}, nil
 }
 
 func (h *BookHandler) ListBooksByLanguage(ctx context.Context, req *pb.LanguageRequest) (*pb.BookList, error) {
@@ -223,22 +165,7 @@ func (h *BookHandler) ListBooksByLanguage(ctx context.Context, req *pb.LanguageR
 	if err != nil {
 		return nil, err
 	}
-	var res []*pb.Book
-	for _, b := range books {
-		res = append(res, &pb.Book{
-			Id:            b.ID.Hex(),
-			Title:         b.Title,
-			Author:        b.Author,
-			Genre:         b.Genre,
-			Language:      b.Language,
-			Description:   b.Description,
-			Rating:        b.Rating,
-			Price:         b.Price,
-			Pages:         int32(b.Pages),
-			PublishedDate: b.PublishedDate,
-		})
-	}
-	return &pb.BookList{Books: res}, nil
+	return &pb.BookList{Books: toProtoList(books)}, nil
 }
 
 func (h *BookHandler) ListTopRatedBooks(ctx context.Context, _ *pb.Empty) (*pb.BookList, error) {
@@ -246,22 +173,7 @@ func (h *BookHandler) ListTopRatedBooks(ctx context.Context, _ *pb.Empty) (*pb.B
 	if err != nil {
 		return nil, err
 	}
-	var res []*pb.Book
-	for _, b := range books {
-		res = append(res, &pb.Book{
-			Id:            b.ID.Hex(),
-			Title:         b.Title,
-			Author:        b.Author,
-			Genre:         b.Genre,
-			Language:      b.Language,
-			Description:   b.Description,
-			Rating:        b.Rating,
-			Price:         b.Price,
-			Pages:         int32(b.Pages),
-			PublishedDate: b.PublishedDate,
-		})
-	}
-	return &pb.BookList{Books: res}, nil
+	return &pb.BookList{Books: toProtoList(books)}, nil
 }
 
 func (h *BookHandler) ListNewArrivals(ctx context.Context, _ *pb.Empty) (*pb.BookList, error) {
@@ -269,72 +181,306 @@ func (h *BookHandler) ListNewArrivals(ctx context.Context, _ *pb.Empty) (*pb.Boo
 	if err != nil {
 		return nil, err
 	}
-	var res []*pb.Book
-	for _, b := range books {
-		res = append(res, &pb.Book{
-			Id:            b.ID.Hex(),
-			Title:         b.Title,
-			Author:        b.Author,
-			Genre:         b.Genre,
-			Language:      b.Language,
-			Description:   b.Description,
-			Rating:        b.Rating,
-			Price:         b.Price,
-			Pages:         int32(b.Pages),
-			PublishedDate: b.PublishedDate,
-		})
-	}
-	return &pb.BookList{Books: res}, nil
+	return &pb.BookList{Books: toProtoList(books)}, nil
 }
 
 func (h *BookHandler) SearchBooks(ctx context.Context, req *pb.SearchRequest) (*pb.BookList, error) {
 	if req == nil || req.Keyword == "" {
 		return nil, status.Error(codes.InvalidArgument, "keyword is required")
 	}
-	books, err := h.usecase.SearchBooks(ctx, req.Keyword)
+	hits, err := h.search.Search(ctx, req.Keyword, int(req.Limit))
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.Internal, "cannot search books: %v", err)
 	}
-	var res []*pb.Book
-	for _, b := range books {
-		res = append(res, &pb.Book{
-			Id:            b.ID.Hex(),
-			Title:         b.Title,
-			Author:        b.Author,
-			Genre:         b.Genre,
-			Language:      b.Language,
-			Description:   b.Description,
-			Rating:        b.Rating,
-			Price:         b.Price,
-			Pages:         int32(b.Pages),
-			PublishedDate: b.PublishedDate,
+	books := make([]*domain.Book, 0, len(hits))
+	for _, hit := range hits {
+		books = append(books, hit.Book)
+	}
+	return &pb.BookList{Books: toProtoList(books)}, nil
+}
+
+// SearchWithHighlights is SearchBooks with relevance scores and the
+// matched words marked in each book's fields.
+func (h *BookHandler) SearchWithHighlights(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResults, error) {
+	if req == nil || req.Keyword == "" {
+		return nil, status.Error(codes.InvalidArgument, "keyword is required")
+	}
+	hits, err := h.search.Search(ctx, req.Keyword, int(req.Limit))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot search books: %v", err)
+	}
+	res := &pb.SearchResults{Hits: make([]*pb.SearchHit, 0, len(hits))}
+	for _, hit := range hits {
+		ph := &pb.SearchHit{Book: toProto(hit.Book), Score: hit.Score}
+		for _, hl := range hit.Highlights {
+			ph.Highlights = append(ph.Highlights, &pb.Highlight{Field: hl.Field, Snippet: hl.Snippet})
+		}
+		res.Hits = append(res.Hits, ph)
+	}
+	return res, nil
+}
+
+// SuggestTitles autocompletes a partly typed title.
+func (h *BookHandler) SuggestTitles(ctx context.Context, req *pb.SuggestRequest) (*pb.Suggestions, error) {
+	if req == nil || req.Prefix == "" {
+		return nil, status.Error(codes.InvalidArgument, "prefix is required")
+	}
+	suggestions, err := h.search.SuggestTitles(ctx, req.Prefix, int(req.Limit))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot suggest titles: %v", err)
+	}
+	res := &pb.Suggestions{Suggestions: make([]*pb.TitleSuggestion, 0, len(suggestions))}
+	for _, s := range suggestions {
+		res.Suggestions = append(res.Suggestions, &pb.TitleSuggestion{
+			BookId:      s.BookID,
+			Title:       s.Title,
+			Highlighted: s.Highlighted,
 		})
 	}
-	return &pb.BookList{Books: res}, nil
+	return res, nil
+}
+
+func (h *BookHandler) QueryBooks(ctx context.Context, req *pb.QueryBooksRequest) (*pb.QueryBooksResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	page, err := h.usecase.QueryBooks(ctx, domain.BookQuery{
+		Keyword:   req.Keyword,
+		Genre:     req.Genre,
+		Author:    req.Author,
+		Language:  req.Language,
+		MinPrice:  req.MinPrice,
+		MaxPrice:  req.MaxPrice,
+		MinRating: req.MinRating,
+		MaxRating: req.MaxRating,
+		MinPages:  int(req.MinPages),
+		MaxPages:  int(req.MaxPages),
+		YearFrom:  int(req.YearFrom),
+		YearTo:    int(req.YearTo),
+		Sort:      req.Sort,
+		PageSize:  int(req.PageSize),
+		Cursor:    req.Cursor,
+	})
+	if errors.Is(err, domain.ErrInvalidQuery) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot query books: %v", err)
+	}
+	return &pb.QueryBooksResponse{
+		Books:      toProtoList(page.Books),
+		NextCursor: page.NextCursor,
+		Total:      int32(page.Total),
+		Genres:     toProtoFacets(page.Genres),
+		Languages:  toProtoFacets(page.Languages),
+		Authors:    toProtoFacets(page.Authors),
+	}, nil
 }
 
 func (h *BookHandler) RecommendBooks(ctx context.Context, req *pb.BookID) (*pb.BookList, error) {
 	if req == nil || req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "book ID is required")
 	}
-	books, err := h.usecase.RecommendBooks(ctx, req.Id)
+	// рекомендации обращаются к другим сервисам, анонимам они недоступны
+	if _, err := auth.RequireIdentity(ctx); err != nil {
+		return nil, err
+	}
+	books, err := h.recommend.RecommendForBook(ctx, req.Id, usecase.DefaultRecommendations)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Error(codes.NotFound, "book not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot recommend books: %v", err)
+	}
+	return &pb.BookList{Books: toProtoList(books)}, nil
+}
+
+// RecommendForUser suggests books the user does not own yet.
+func (h *BookHandler) RecommendForUser(ctx context.Context, req *pb.UserRecommendationsRequest) (*pb.BookList, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user ID is required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	books, err := h.recommend.RecommendForUser(ctx, req.UserId, int(req.Limit))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot recommend books: %v", err)
+	}
+	return &pb.BookList{Books: toProtoList(books)}, nil
+}
+
+// ReserveStock is called by order_service when copies are ordered.
+func (h *BookHandler) ReserveStock(ctx context.Context, req *pb.StockRequest) (*pb.BookResponse, error) {
+	if req == nil || req.BookId == "" {
+		return nil, status.Error(codes.InvalidArgument, "book ID is required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	book, err := h.usecase.ReserveStock(ctx, req.BookId, int(req.Quantity))
 	if err != nil {
+		return nil, stockError(err)
+	}
+	h.publishStockLevel(book)
+	return &pb.BookResponse{Book: toProto(book)}, nil
+}
+
+func (h *BookHandler) ReleaseStock(ctx context.Context, req *pb.StockRequest) (*pb.BookResponse, error) {
+	if req == nil || req.BookId == "" {
+		return nil, status.Error(codes.InvalidArgument, "book ID is required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
 		return nil, err
 	}
-	var pbBooks []*pb.Book
+	book, err := h.usecase.ReleaseStock(ctx, req.BookId, int(req.Quantity))
+	if err != nil {
+		return nil, stockError(err)
+	}
+	return &pb.BookResponse{Book: toProto(book)}, nil
+}
+
+func (h *BookHandler) publishBook(subject string, book *domain.Book) {
+	evt := domain.BookEvent{
+		ID:     book.ID.Hex(),
+		Title:  book.Title,
+		Author: book.Author,
+	}
+	if data, err := json.Marshal(evt); err == nil {
+		if err := h.nc.Publish(subject, data); err != nil {
+			log.Printf("⚠ NATS publish error (%s): %v", subject, err)
+		}
+	}
+}
+
+func (h *BookHandler) publishStockLevel(book *domain.Book) {
+	var subject string
+	switch {
+	case book.Stock == 0:
+		subject = "book.stock.out"
+	case book.Stock <= domain.LowStockThreshold:
+		subject = "book.stock.low"
+	default:
+		return
+	}
+	evt := domain.StockEvent{
+		BookID: book.ID.Hex(),
+		Title:  book.Title,
+		Stock:  book.Stock,
+	}
+	if data, err := json.Marshal(evt); err == nil {
+		if err := h.nc.Publish(subject, data); err != nil {
+			log.Printf("⚠ NATS publish error (%s): %v", subject, err)
+		}
+	}
+}
+
+func bookError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidISBN),
+		errors.Is(err, domain.ErrInvalidContributor),
+		errors.Is(err, domain.ErrInvalidFormat),
+		errors.Is(err, domain.ErrInvalidSeries),
+		errors.Is(err, domain.ErrUnknownAuthor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrDuplicateISBN):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, "book not found")
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+func stockError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidQuantity):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInsufficientStock):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, "book not found")
+	default:
+		return status.Errorf(codes.Internal, "cannot update stock: %v", err)
+	}
+}
+
+func toProto(b *domain.Book) *pb.Book {
+	return &pb.Book{
+		Id:             b.ID.Hex(),
+		Title:          b.Title,
+		Author:         b.Author,
+		Genre:          b.Genre,
+		Language:       b.Language,
+		Description:    b.Description,
+		Rating:         b.Rating,
+		Price:          b.Price,
+		Pages:          int32(b.Pages),
+		PublishedDate:  b.PublishedDate,
+		Stock:          int32(b.Stock),
+		RatingCount:    int32(b.RatingCount),
+		Isbn:           b.ISBN,
+		Contributors:   toProtoContributors(b.Contributors),
+		Publisher:      b.Publisher,
+		Series:         b.Series,
+		SeriesPosition: int32(b.SeriesPosition),
+		Edition:        b.Edition,
+		Format:         b.Format,
+	}
+}
+
+// fromProto takes the editable fields of a book; the rating is derived
+// from reviews and never read from requests.
+func fromProto(b *pb.Book) *domain.Book {
+	book := &domain.Book{
+		Title:          b.Title,
+		Author:         b.Author,
+		Genre:          b.Genre,
+		Language:       b.Language,
+		Description:    b.Description,
+		Price:          b.Price,
+		Pages:          int(b.Pages),
+		PublishedDate:  b.PublishedDate,
+		Stock:          int(b.Stock),
+		ISBN:           b.Isbn,
+		Publisher:      b.Publisher,
+		Series:         b.Series,
+		SeriesPosition: int(b.SeriesPosition),
+		Edition:        b.Edition,
+		Format:         b.Format,
+	}
+	for _, c := range b.Contributors {
+		// неверный author_id не связывает вклад, автор ищется по имени
+		authorID, _ := primitive.ObjectIDFromHex(c.AuthorId)
+		book.Contributors = append(book.Contributors, domain.Contributor{Name: c.Name, Role: c.Role, AuthorID: authorID})
+	}
+	return book
+}
+
+func toProtoContributors(contributors []domain.Contributor) []*pb.Contributor {
+	res := make([]*pb.Contributor, 0, len(contributors))
+	for _, c := range contributors {
+		var authorID string
+		if !c.AuthorID.IsZero() {
+			authorID = c.AuthorID.Hex()
+		}
+		res = append(res, &pb.Contributor{Name: c.Name, Role: c.Role, AuthorId: authorID})
+	}
+	return res
+}
+
+func toProtoList(books []*domain.Book) []*pb.Book {
+	var res []*pb.Book
 	for _, b := range books {
-		pbBooks = append(pbBooks, &pb.Book{
-			Id:            b.ID.Hex(),
-			Title:         b.Title,
-			Author:        b.Author,
-			Genre:         b.Genre,
-			Language:      b.Language,
-			Description:   b.Description,
-			Rating:        b.Rating,
-			Price:         b.Price,
-			Pages:         int32(b.Pages),
-			PublishedDate: b.PublishedDate,
-		})
+		res = append(res, toProto(b))
+	}
+	return res
+}
+
+func toProtoFacets(facets []domain.Facet) []*pb.FacetCount {
+	res := make([]*pb.FacetCount, 0, len(facets))
+	for _, f := range facets {
+		res = append(res, &pb.FacetCount{Value: f.Value, Count: int32(f.Count)})
 	}
-	return &pb.BookList{Books: pbBooks}, nil
+	return res
 }
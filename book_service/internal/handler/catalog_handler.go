@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/catalog"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	pb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+)
+
+// exportChunkSize is how much of the exported file goes into one message.
+const exportChunkSize = 32 << 10
+
+// ImportBooks reads a catalog file sent in chunks and saves its books,
+// publishing book.created and book.updated as it goes. Rows that fail are
+// reported in the response; a file that cannot be read at all fails the
+// call, keeping the rows saved before the problem.
+func (h *BookHandler) ImportBooks(stream pb.BookService_ImportBooksServer) error {
+	ctx := stream.Context()
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return err
+	}
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "empty request")
+	}
+	if err != nil {
+		return err
+	}
+	records, err := catalog.NewReader(first.Format, &chunkReader{stream: stream, buf: first.Chunk})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	summary, err := h.catalog.ImportBooks(ctx, records, first.DryRun, func(res *domain.ImportResult) {
+		switch {
+		case first.DryRun:
+		case res.Action == domain.ImportCreated:
+			h.publishBook("book.created", res.Book)
+		case res.Action == domain.ImportUpdated:
+			h.publishBook("book.updated", res.Book)
+		}
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		msg := fmt.Sprintf("cannot read import file: %v (created %d, updated %d, failed %d before that)",
+			err, summary.Created, summary.Updated, summary.Failed)
+		return status.Error(codes.InvalidArgument, msg)
+	}
+	log.Printf("Imported books: %d created, %d updated, %d failed (dry run: %v)",
+		summary.Created, summary.Updated, summary.Failed, summary.DryRun)
+	return stream.SendAndClose(toProtoImportSummary(summary))
+}
+
+// ExportBooks streams the whole catalog in the requested format.
+func (h *BookHandler) ExportBooks(req *pb.ExportBooksRequest, stream pb.BookService_ExportBooksServer) error {
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "empty request")
+	}
+	if err := auth.RequireRole(stream.Context(), auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return err
+	}
+	out := bufio.NewWriterSize(chunkWriter{stream: stream}, exportChunkSize)
+	w, err := catalog.NewWriter(req.Format, out)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err := h.catalog.ExportBooks(stream.Context(), w); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Internal, "cannot export books: %v", err)
+	}
+	return out.Flush()
+}
+
+// chunkReader joins the chunks of an ImportBooks stream into one file.
+type chunkReader struct {
+	stream pb.BookService_ImportBooksServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = req.Chunk
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+type chunkWriter struct {
+	stream pb.BookService_ExportBooksServer
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&pb.ExportChunk{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func toProtoImportSummary(s *domain.ImportSummary) *pb.ImportBooksResponse {
+	resp := &pb.ImportBooksResponse{
+		DryRun:  s.DryRun,
+		Created: int32(s.Created),
+		Updated: int32(s.Updated),
+		Failed:  int32(s.Failed),
+	}
+	for _, e := range s.Errors {
+		resp.Errors = append(resp.Errors, &pb.ImportRowError{
+			Row:   int32(e.Row),
+			Isbn:  e.ISBN,
+			Error: e.Err.Error(),
+		})
+	}
+	return resp
+}
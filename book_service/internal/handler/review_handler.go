@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	pb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+)
+
+func (h *BookHandler) SubmitReview(ctx context.Context, req *pb.SubmitReviewRequest) (*pb.ReviewResponse, error) {
+	if req == nil || req.BookId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "book ID and user ID are required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	bookID, err := primitive.ObjectIDFromHex(req.BookId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid book ID")
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+
+	review, err := h.reviews.SubmitReview(ctx, &domain.Review{
+		BookID: bookID,
+		UserID: userID,
+		Rating: int(req.Rating),
+		Text:   req.Text,
+	})
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	h.publishReview("book.review.submitted", review)
+	return &pb.ReviewResponse{Review: toProtoReview(review)}, nil
+}
+
+func (h *BookHandler) UpdateReview(ctx context.Context, req *pb.UpdateReviewRequest) (*pb.ReviewResponse, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "review ID is required")
+	}
+	if err := h.authorizeReviewAuthor(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	review, err := h.reviews.UpdateReview(ctx, req.Id, int(req.Rating), req.Text)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	h.publishReview("book.review.updated", review)
+	return &pb.ReviewResponse{Review: toProtoReview(review)}, nil
+}
+
+func (h *BookHandler) DeleteReview(ctx context.Context, req *pb.ReviewID) (*pb.Empty, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "review ID is required")
+	}
+	if err := h.authorizeReviewAuthor(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	review, err := h.reviews.DeleteReview(ctx, req.Id)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	h.publishReview("book.review.deleted", review)
+	return &pb.Empty{}, nil
+}
+
+func (h *BookHandler) ListBookReviews(ctx context.Context, req *pb.BookID) (*pb.ReviewList, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "book ID is required")
+	}
+	reviews, err := h.reviews.ListBookReviews(ctx, req.Id)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	return &pb.ReviewList{Reviews: toProtoReviewList(reviews)}, nil
+}
+
+func (h *BookHandler) ListUserReviews(ctx context.Context, req *pb.UserReviewsRequest) (*pb.ReviewList, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user ID is required")
+	}
+	reviews, err := h.reviews.ListUserReviews(ctx, req.UserId)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	return &pb.ReviewList{Reviews: toProtoReviewList(reviews)}, nil
+}
+
+// authorizeReviewAuthor lets only the author of a review (or an admin) change it.
+func (h *BookHandler) authorizeReviewAuthor(ctx context.Context, id string) error {
+	review, err := h.reviews.GetReview(ctx, id)
+	if err != nil {
+		return reviewError(err)
+	}
+	return auth.AuthorizeUser(ctx, review.UserID.Hex())
+}
+
+func (h *BookHandler) publishReview(subject string, r *domain.Review) {
+	evt := domain.ReviewEvent{
+		ReviewID: r.ID.Hex(),
+		BookID:   r.BookID.Hex(),
+		UserID:   r.UserID.Hex(),
+		Rating:   r.Rating,
+	}
+	if data, err := json.Marshal(evt); err == nil {
+		if err := h.nc.Publish(subject, data); err != nil {
+			log.Printf("⚠ NATS publish error (%s): %v", subject, err)
+		}
+	}
+}
+
+func reviewError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidRating), errors.Is(err, domain.ErrReviewTooLong):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrAlreadyReviewed):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, "book or review not found")
+	case errors.Is(err, primitive.ErrInvalidHex):
+		return status.Error(codes.InvalidArgument, "invalid ID")
+	default:
+		return status.Errorf(codes.Internal, "review operation failed: %v", err)
+	}
+}
+
+func toProtoReview(r *domain.Review) *pb.Review {
+	return &pb.Review{
+		Id:        r.ID.Hex(),
+		BookId:    r.BookID.Hex(),
+		UserId:    r.UserID.Hex(),
+		Rating:    int32(r.Rating),
+		Text:      r.Text,
+		CreatedAt: r.CreatedAt.Time().String(),
+		UpdatedAt: r.UpdatedAt.Time().String(),
+	}
+}
+
+func toProtoReviewList(reviews []*domain.Review) []*pb.Review {
+	var res []*pb.Review
+	for _, r := range reviews {
+		res = append(res, toProtoReview(r))
+	}
+	return res
+}
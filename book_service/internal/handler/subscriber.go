@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/usecase"
+)
+
+// SubscribeDeletions drops the reviews of deleted users so that they no
+// longer count towards book ratings.
+func SubscribeDeletions(nc *nats.Conn, reviews usecase.ReviewUseCase) error {
+	_, err := nc.Subscribe("user.deleted", func(m *nats.Msg) {
+		var evt domain.UserDeletedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal user.deleted: %v", err)
+			return
+		}
+		n, err := reviews.RemoveUserReviews(context.Background(), evt.UserID)
+		if err != nil {
+			log.Printf("⚠ cannot remove reviews of user %s: %v", evt.UserID, err)
+			return
+		}
+		log.Printf("Removed reviews of deleted user %s, recomputed %d ratings", evt.UserID, n)
+	})
+	return err
+}
+
+// SubscribeSearchIndex keeps the search index of this replica in step with
+// catalog changes made through any replica.
+func SubscribeSearchIndex(nc *nats.Conn, s usecase.SearchUseCase) error {
+	refresh := func(m *nats.Msg) {
+		var evt domain.BookEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal %s: %v", m.Subject, err)
+			return
+		}
+		if err := s.Refresh(context.Background(), evt.ID); err != nil {
+			log.Printf("⚠ cannot reindex book %s: %v", evt.ID, err)
+		}
+	}
+	for _, subject := range []string{"book.created", "book.updated"} {
+		if _, err := nc.Subscribe(subject, refresh); err != nil {
+			return err
+		}
+	}
+
+	_, err := nc.Subscribe("book.deleted", func(m *nats.Msg) {
+		var evt domain.BookDeletedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal book.deleted: %v", err)
+			return
+		}
+		s.Forget(evt.BookID)
+	})
+	return err
+}
@@ -0,0 +1,259 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+// CreateBookIndexes creates the text index used by QueryBooks keywords and
+// the indexes behind its filters and sorts.
+func CreateBookIndexes(db *mongo.Database) {
+	collection := db.Collection("books")
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "author", Value: "text"},
+				{Key: "description", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("books_text").
+				SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "author", Value: 5}, {Key: "description", Value: 1}}).
+				SetDefaultLanguage("none"),
+		},
+		{
+			Keys: bson.D{{Key: "isbn", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"isbn": bson.M{"$type": "string"}}),
+		},
+		{Keys: bson.D{{Key: "contributors.name", Value: 1}}},
+		{Keys: bson.D{{Key: "contributors.author_id", Value: 1}}},
+		{Keys: bson.D{{Key: "series", Value: 1}, {Key: "series_position", Value: 1}}},
+		{Keys: bson.D{{Key: "genre", Value: 1}, {Key: "title", Value: 1}}},
+		{Keys: bson.D{{Key: "author", Value: 1}, {Key: "title", Value: 1}}},
+		{Keys: bson.D{{Key: "language", Value: 1}, {Key: "title", Value: 1}}},
+		{Keys: bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "rating", Value: -1}, {Key: "rating_count", Value: -1}}},
+		{Keys: bson.D{{Key: "rating", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "pages", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "published_date", Value: 1}, {Key: "_id", Value: 1}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	log.Println("Created indexes for books collection")
+}
+
+// BackfillContributors gives books created before structured metadata a
+// contributor list made of their free-text author, and empty publisher,
+// series and edition fields.
+func BackfillContributors(db *mongo.Database) {
+	collection := db.Collection("books")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	filter := bson.M{"contributors": bson.M{"$exists": false}}
+	author := bson.M{"$trim": bson.M{"input": bson.M{"$ifNull": bson.A{"$author", ""}}}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"contributors": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{author, ""}},
+			bson.A{},
+			bson.A{bson.M{"name": author, "role": "author"}},
+		}},
+		"publisher":       bson.M{"$ifNull": bson.A{"$publisher", ""}},
+		"series":          bson.M{"$ifNull": bson.A{"$series", ""}},
+		"series_position": bson.M{"$ifNull": bson.A{"$series_position", 0}},
+		"edition":         bson.M{"$ifNull": bson.A{"$edition", ""}},
+		"format":          bson.M{"$ifNull": bson.A{"$format", ""}},
+	}}}
+	res, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		log.Fatalf("Failed to backfill contributors: %v", err)
+	}
+
+	log.Printf("Backfilled contributors of %d books", res.ModifiedCount)
+}
+
+// BackfillStock gives books that predate stock tracking DefaultStock copies;
+// ReserveStock never matches a book without a stock field.
+func BackfillStock(db *mongo.Database) {
+	collection := db.Collection("books")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	filter := bson.M{"stock": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"stock": domain.DefaultStock}}
+	res, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		log.Fatalf("Failed to backfill stock: %v", err)
+	}
+
+	log.Printf("Backfilled stock of %d books", res.ModifiedCount)
+}
+
+// CreateReviewIndexes enforces one review per user per book.
+func CreateReviewIndexes(db *mongo.Database) {
+	collection := db.Collection("book_reviews")
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "book_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	log.Println("Created indexes for book_reviews collection")
+}
+
+// ResetManualRatings clears ratings that were typed in by librarians before
+// ratings were derived from reviews. Such books have no rating_count yet.
+func ResetManualRatings(db *mongo.Database) {
+	collection := db.Collection("books")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	filter := bson.M{"rating_count": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"rating": 0, "rating_count": 0}}
+	res, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		log.Fatalf("Failed to reset manual ratings: %v", err)
+	}
+
+	log.Printf("Reset manual ratings of %d books", res.ModifiedCount)
+}
+
+// CreateAuthorIndexes keeps a name or alias from belonging to two authors.
+func CreateAuthorIndexes(db *mongo.Database) {
+	collection := db.Collection("authors")
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name_keys", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "name", Value: 1}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	log.Println("Created indexes for authors collection")
+}
+
+// LinkAuthors links the contributors of books saved before authors existed
+// to authors, creating an author for every name not known yet. Spelling
+// variants of a name end up with the same author. Contributors whose name
+// has no letters or digits cannot be linked and are left alone, so books
+// only crediting such names are not read again on every start.
+func LinkAuthors(db *mongo.Database) {
+	books := db.Collection("books")
+	authors := db.Collection("authors")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// то же условие, что domain.NameKey(name) != ""
+	named := bson.M{"$regex": `[\p{L}\p{Nd}]`}
+	filter := bson.M{"contributors": bson.M{"$elemMatch": bson.M{
+		"author_id": bson.M{"$exists": false},
+		"name":      named,
+	}}}
+	cursor, err := books.Find(ctx, filter)
+	if err != nil {
+		log.Fatalf("Failed to link authors: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	known := make(map[string]*domain.Author)
+	linked := 0
+	for cursor.Next(ctx) {
+		var book domain.Book
+		if err := cursor.Decode(&book); err != nil {
+			log.Fatalf("Failed to link authors: %v", err)
+		}
+		for i := range book.Contributors {
+			c := &book.Contributors[i]
+			key := domain.NameKey(c.Name)
+			if !c.AuthorID.IsZero() || key == "" {
+				continue
+			}
+			author, ok := known[key]
+			if !ok {
+				if author, err = authorByKey(ctx, authors, c.Name, key); err != nil {
+					log.Fatalf("Failed to link authors: %v", err)
+				}
+				known[key] = author
+			}
+			c.AuthorID, c.Name = author.ID, author.Name
+		}
+		book.SyncAuthor()
+
+		update := bson.M{"$set": bson.M{"contributors": book.Contributors, "author": book.Author}}
+		if _, err := books.UpdateOne(ctx, bson.M{"_id": book.ID}, update); err != nil {
+			log.Fatalf("Failed to link authors: %v", err)
+		}
+		linked++
+	}
+	if err := cursor.Err(); err != nil {
+		log.Fatalf("Failed to link authors: %v", err)
+	}
+
+	log.Printf("Linked contributors of %d books to %d authors", linked, len(known))
+}
+
+// authorByKey finds the author known by key, creating one named name.
+func authorByKey(ctx context.Context, authors *mongo.Collection, name, key string) (*domain.Author, error) {
+	var author domain.Author
+	err := authors.FindOne(ctx, bson.M{"name_keys": key}).Decode(&author)
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return &author, err
+	}
+	now := primitive.NewDateTimeFromTime(time.Now())
+	author = domain.Author{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		NameKeys:  []string{key},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := authors.InsertOne(ctx, author); err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
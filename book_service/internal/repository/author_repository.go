@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+type AuthorRepository interface {
+	Create(ctx context.Context, author *domain.Author) (*domain.Author, error)
+	GetByID(ctx context.Context, id string) (*domain.Author, error)
+	// GetByNameKey finds the author whose name or alias has this key.
+	GetByNameKey(ctx context.Context, key string) (*domain.Author, error)
+	Update(ctx context.Context, author *domain.Author) (*domain.Author, error)
+	Delete(ctx context.Context, id string) error
+	ListAll(ctx context.Context) ([]*domain.Author, error)
+}
@@ -9,14 +9,30 @@ import (
 type BookRepository interface {
 	Create(ctx context.Context, book *domain.Book) (*domain.Book, error)
 	GetByID(ctx context.Context, id string) (*domain.Book, error)
+	GetByISBN(ctx context.Context, isbn string) (*domain.Book, error)
 	ListAll(ctx context.Context) ([]*domain.Book, error)
+	// ForEach calls fn for every book in _id order without loading the
+	// whole catalog, stopping at the first error.
+	ForEach(ctx context.Context, fn func(*domain.Book) error) error
 	Update(ctx context.Context, book *domain.Book) (*domain.Book, error)
 	Delete(ctx context.Context, id string) error
 	ListByGenre(ctx context.Context, genre string) ([]*domain.Book, error)
 	ListByAuthor(ctx context.Context, author string) ([]*domain.Book, error)
+	ListByAuthorID(ctx context.Context, authorID string) ([]*domain.Book, error)
+	// RenameContributor sets the name of the author's contributor entries
+	// and the derived display author, and returns the books it changed.
+	RenameContributor(ctx context.Context, authorID, name string) ([]*domain.Book, error)
 	ListByLanguage(ctx context.Context, language string) ([]*domain.Book, error)
 	ListTopRated(ctx context.Context) ([]*domain.Book, error)
+	// ListRelated returns at most limit books by one of the authors, in one
+	// of the genres or with one of the ids, best rated first.
+	ListRelated(ctx context.Context, authors, genres, ids []string, limit int) ([]*domain.Book, error)
 	ListNewArrivals(ctx context.Context) ([]*domain.Book, error)
-	SearchBooks(ctx context.Context, keyword string) ([]*domain.Book, error)
-	RecommendBooks(ctx context.Context, bookID string) ([]*domain.Book, error)
+	Query(ctx context.Context, q domain.BookQuery) (*domain.BookPage, error)
+	ReserveStock(ctx context.Context, id string, quantity int) (*domain.Book, error)
+	ReleaseStock(ctx context.Context, id string, quantity int) (*domain.Book, error)
+	// RatingVersion counts how many times the book's rating was stored;
+	// SetRating only succeeds against the current version.
+	RatingVersion(ctx context.Context, id string) (int, error)
+	SetRating(ctx context.Context, id string, summary domain.RatingSummary, version int) (*domain.Book, error)
 }
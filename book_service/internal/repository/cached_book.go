@@ -58,6 +58,10 @@ func (r *cachedBookRepo) GetByID(ctx context.Context, id string) (*domain.Book,
 	return book, nil
 }
 
+func (r *cachedBookRepo) GetByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	return r.repo.GetByISBN(ctx, isbn)
+}
+
 func (r *cachedBookRepo) ListAll(ctx context.Context) ([]*domain.Book, error) {
 	cacheKey := r.getCacheKeyForList("all")
 	if books, err := r.cache.GetList(ctx, cacheKey); err == nil && books != nil {
@@ -72,6 +76,10 @@ func (r *cachedBookRepo) ListAll(ctx context.Context) ([]*domain.Book, error) {
 	return books, nil
 }
 
+func (r *cachedBookRepo) ForEach(ctx context.Context, fn func(*domain.Book) error) error {
+	return r.repo.ForEach(ctx, fn)
+}
+
 func (r *cachedBookRepo) Update(ctx context.Context, book *domain.Book) (*domain.Book, error) {
 	updated, err := r.repo.Update(ctx, book)
 	if err != nil {
@@ -82,6 +90,20 @@ func (r *cachedBookRepo) Update(ctx context.Context, book *domain.Book) (*domain
 	return updated, nil
 }
 
+func (r *cachedBookRepo) RenameContributor(ctx context.Context, authorID, name string) ([]*domain.Book, error) {
+	books, err := r.repo.RenameContributor(ctx, authorID, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range books {
+		r.cache.Delete(ctx, r.getCacheKeyForBook(b.ID.Hex()))
+	}
+	if len(books) > 0 {
+		r.cache.Delete(ctx, r.getCacheKeyForList("all"))
+	}
+	return books, nil
+}
+
 func (r *cachedBookRepo) Delete(ctx context.Context, id string) error {
 	err := r.repo.Delete(ctx, id)
 	if err != nil {
@@ -118,6 +140,12 @@ func (r *cachedBookRepo) ListByAuthor(ctx context.Context, author string) ([]*do
 	return books, nil
 }
 
+// ListByAuthorID is not cached: a book edit can link or unlink any
+// number of authors, and their lists could not all be invalidated.
+func (r *cachedBookRepo) ListByAuthorID(ctx context.Context, authorID string) ([]*domain.Book, error) {
+	return r.repo.ListByAuthorID(ctx, authorID)
+}
+
 func (r *cachedBookRepo) ListByLanguage(ctx context.Context, language string) ([]*domain.Book, error) {
 	cacheKey := r.getCacheKeyForList("language:" + language)
 	if books, err := r.cache.GetList(ctx, cacheKey); err == nil && books != nil {
@@ -157,19 +185,49 @@ func (r *cachedBookRepo) ListNewArrivals(ctx context.Context) ([]*domain.Book, e
 	return books, nil
 }
 
-func (r *cachedBookRepo) SearchBooks(ctx context.Context, keyword string) ([]*domain.Book, error) {
-	return r.repo.SearchBooks(ctx, keyword)
+// Query is not cached: filter combinations and cursors rarely repeat.
+func (r *cachedBookRepo) Query(ctx context.Context, q domain.BookQuery) (*domain.BookPage, error) {
+	return r.repo.Query(ctx, q)
 }
 
-func (r *cachedBookRepo) RecommendBooks(ctx context.Context, bookID string) ([]*domain.Book, error) {
-	cacheKey := r.getCacheKeyForList("recommend:" + bookID)
-	if books, err := r.cache.GetList(ctx, cacheKey); err == nil && books != nil {
-		return books, nil
+func (r *cachedBookRepo) ReserveStock(ctx context.Context, id string, quantity int) (*domain.Book, error) {
+	book, err := r.repo.ReserveStock(ctx, id, quantity)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Delete(ctx, r.getCacheKeyForBook(id))
+	r.cache.Delete(ctx, r.getCacheKeyForList("all"))
+	return book, nil
+}
+
+func (r *cachedBookRepo) ReleaseStock(ctx context.Context, id string, quantity int) (*domain.Book, error) {
+	book, err := r.repo.ReleaseStock(ctx, id, quantity)
+	if err != nil {
+		return nil, err
 	}
-	books, err := r.repo.RecommendBooks(ctx, bookID)
+	r.cache.Delete(ctx, r.getCacheKeyForBook(id))
+	r.cache.Delete(ctx, r.getCacheKeyForList("all"))
+	return book, nil
+}
+
+// ListRelated is not cached: the recommendations built from it are.
+func (r *cachedBookRepo) ListRelated(ctx context.Context, authors, genres, ids []string, limit int) ([]*domain.Book, error) {
+	return r.repo.ListRelated(ctx, authors, genres, ids, limit)
+}
+
+// RatingVersion is never cached: a stale version would only make
+// SetRating fail.
+func (r *cachedBookRepo) RatingVersion(ctx context.Context, id string) (int, error) {
+	return r.repo.RatingVersion(ctx, id)
+}
+
+func (r *cachedBookRepo) SetRating(ctx context.Context, id string, summary domain.RatingSummary, version int) (*domain.Book, error) {
+	book, err := r.repo.SetRating(ctx, id, summary, version)
 	if err != nil {
 		return nil, err
 	}
-	r.cache.SetList(ctx, cacheKey, books, 30*time.Minute)
-	return books, nil
+	r.cache.Delete(ctx, r.getCacheKeyForBook(id))
+	r.cache.Delete(ctx, r.getCacheKeyForList("all"))
+	r.cache.Delete(ctx, r.getCacheKeyForList("top_rated"))
+	return book, nil
 }
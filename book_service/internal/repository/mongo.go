@@ -25,6 +25,9 @@ func (r *mongoBookRepo) Create(ctx context.Context, book *domain.Book) (*domain.
 		book.ID = primitive.NewObjectID()
 	}
 	_, err := r.collection.InsertOne(ctx, book)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domain.ErrDuplicateISBN
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -63,30 +66,91 @@ func (r *mongoBookRepo) ListAll(ctx context.Context) ([]*domain.Book, error) {
 	return books, nil
 }
 
+func (r *mongoBookRepo) ForEach(ctx context.Context, fn func(*domain.Book) error) error {
+	opts := options.Find().SetSort(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var book domain.Book
+		if err := cursor.Decode(&book); err != nil {
+			return err
+		}
+		if err := fn(&book); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
 func (r *mongoBookRepo) Update(ctx context.Context, book *domain.Book) (*domain.Book, error) {
 	if book.ID == primitive.NilObjectID {
 		return nil, errors.New("book ID is empty")
 	}
 	filter := bson.M{"_id": book.ID}
-	update := bson.M{"$set": book}
+	// рейтинг считается по отзывам и здесь не меняется; остаток меняют только
+	// ReserveStock/ReleaseStock через $inc, иначе $set затёр бы параллельные резервы
+	update := bson.M{"$set": bson.M{
+		"title":           book.Title,
+		"author":          book.Author,
+		"genre":           book.Genre,
+		"language":        book.Language,
+		"description":     book.Description,
+		"price":           book.Price,
+		"pages":           book.Pages,
+		"published_date":  book.PublishedDate,
+		"contributors":    book.Contributors,
+		"publisher":       book.Publisher,
+		"series":          book.Series,
+		"series_position": book.SeriesPosition,
+		"edition":         book.Edition,
+		"format":          book.Format,
+	}}
+	// пустой ISBN не храним: уникальный индекс строится только по заданным
+	if book.ISBN != "" {
+		update["$set"].(bson.M)["isbn"] = book.ISBN
+	} else {
+		update["$unset"] = bson.M{"isbn": ""}
+	}
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var updatedBook domain.Book
 
 	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updatedBook)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domain.ErrDuplicateISBN
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &updatedBook, nil
 }
 
+// GetByISBN finds a book by its ISBN-13.
+func (r *mongoBookRepo) GetByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	var book domain.Book
+	if err := r.collection.FindOne(ctx, bson.M{"isbn": isbn}).Decode(&book); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
 func (r *mongoBookRepo) Delete(ctx context.Context, id string) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return errors.New("invalid id format")
 	}
-	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	return err
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
 func (r *mongoBookRepo) ListByGenre(ctx context.Context, genre string) ([]*domain.Book, error) {
@@ -95,18 +159,114 @@ func (r *mongoBookRepo) ListByGenre(ctx context.Context, genre string) ([]*domai
 }
 
 func (r *mongoBookRepo) ListByAuthor(ctx context.Context, author string) ([]*domain.Book, error) {
-	filter := bson.M{"author": author}
+	filter := authorFilter(author)
 	return r.findByFilter(ctx, filter)
 }
 
+// ListByAuthorID returns the books crediting the author in any role.
+func (r *mongoBookRepo) ListByAuthorID(ctx context.Context, authorID string) ([]*domain.Book, error) {
+	oid, err := primitive.ObjectIDFromHex(authorID)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	return r.findByFilter(ctx, bson.M{"contributors.author_id": oid})
+}
+
+// RenameContributor updates only the books still crediting the author under
+// another name. Each book is rewritten by one pipeline update, which also
+// derives the display author again like Book.SyncAuthor.
+func (r *mongoBookRepo) RenameContributor(ctx context.Context, authorID, name string) ([]*domain.Book, error) {
+	oid, err := primitive.ObjectIDFromHex(authorID)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	filter := bson.M{"contributors": bson.M{"$elemMatch": bson.M{"author_id": oid, "name": bson.M{"$ne": name}}}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+	}
+
+	firstAuthor := bson.M{"$arrayElemAt": bson.A{
+		bson.M{"$filter": bson.M{
+			"input": "$contributors",
+			"cond": bson.M{"$and": bson.A{
+				bson.M{"$eq": bson.A{"$$this.role", domain.RoleAuthor}},
+				bson.M{"$ne": bson.A{"$$this.name", ""}},
+			}},
+		}},
+		0,
+	}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"contributors": bson.M{"$map": bson.M{
+			"input": "$contributors",
+			"in": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$$this.author_id", oid}},
+				bson.M{"$mergeObjects": bson.A{"$$this", bson.M{"name": name}}},
+				"$$this",
+			}},
+		}}}}},
+		{{Key: "$set", Value: bson.M{"author": bson.M{"$ifNull": bson.A{
+			bson.M{"$let": bson.M{"vars": bson.M{"c": firstAuthor}, "in": "$$c.name"}},
+			"$author",
+		}}}}},
+	}
+	if _, err := r.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update); err != nil {
+		return nil, err
+	}
+	return r.findByFilter(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
 func (r *mongoBookRepo) ListByLanguage(ctx context.Context, language string) ([]*domain.Book, error) {
 	filter := bson.M{"language": language}
 	return r.findByFilter(ctx, filter)
 }
 
+// ListTopRated returns the ten best reviewed books; books nobody has
+// reviewed yet are left out.
 func (r *mongoBookRepo) ListTopRated(ctx context.Context) ([]*domain.Book, error) {
-	opts := options.Find().SetSort(bson.M{"rating": -1}).SetLimit(10)
-	return r.findByFilterWithOpts(ctx, bson.M{}, opts)
+	sort := bson.D{{Key: "rating", Value: -1}, {Key: "rating_count", Value: -1}}
+	opts := options.Find().SetSort(sort).SetLimit(10)
+	return r.findByFilterWithOpts(ctx, bson.M{"rating_count": bson.M{"$gt": 0}}, opts)
+}
+
+// ListRelated matches authors, genres and ids separately so that each
+// branch of the query is served by its own index.
+func (r *mongoBookRepo) ListRelated(ctx context.Context, authors, genres, ids []string, limit int) ([]*domain.Book, error) {
+	var or bson.A
+	if len(authors) > 0 {
+		or = append(or, bson.M{"author": bson.M{"$in": authors}}, bson.M{"contributors.name": bson.M{"$in": authors}})
+	}
+	if len(genres) > 0 {
+		or = append(or, bson.M{"genre": bson.M{"$in": genres}})
+	}
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) > 0 {
+		or = append(or, bson.M{"_id": bson.M{"$in": oids}})
+	}
+	if len(or) == 0 {
+		return nil, nil
+	}
+	sort := bson.D{{Key: "rating", Value: -1}, {Key: "rating_count", Value: -1}}
+	opts := options.Find().SetSort(sort).SetLimit(int64(limit))
+	return r.findByFilterWithOpts(ctx, bson.M{"$or": or}, opts)
 }
 
 func (r *mongoBookRepo) ListNewArrivals(ctx context.Context) ([]*domain.Book, error) {
@@ -114,13 +274,47 @@ func (r *mongoBookRepo) ListNewArrivals(ctx context.Context) ([]*domain.Book, er
 	return r.findByFilterWithOpts(ctx, bson.M{}, opts)
 }
 
-func (r *mongoBookRepo) SearchBooks(ctx context.Context, keyword string) ([]*domain.Book, error) {
-	filter := bson.M{"$text": bson.M{"$search": keyword}}
-	return r.findByFilter(ctx, filter)
+// ReserveStock atomically takes quantity copies out of stock. The update only
+// matches while enough copies remain, so concurrent reservations can never
+// drive the stock below zero.
+func (r *mongoBookRepo) ReserveStock(ctx context.Context, id string, quantity int) (*domain.Book, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid id format")
+	}
+	filter := bson.M{"_id": objID, "stock": bson.M{"$gte": quantity}}
+	update := bson.M{"$inc": bson.M{"stock": -quantity}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var book domain.Book
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&book)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// различаем «нет такой книги» и «не хватает экземпляров»
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInsufficientStock
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &book, nil
 }
 
-func (r *mongoBookRepo) RecommendBooks(ctx context.Context, bookID string) ([]*domain.Book, error) {
-	return r.ListTopRated(ctx)
+// ReleaseStock puts copies back: cancelled orders and librarians restocking.
+func (r *mongoBookRepo) ReleaseStock(ctx context.Context, id string, quantity int) (*domain.Book, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid id format")
+	}
+	update := bson.M{"$inc": bson.M{"stock": quantity}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var book domain.Book
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, update, opts).Decode(&book); err != nil {
+		return nil, err
+	}
+	return &book, nil
 }
 
 func (r *mongoBookRepo) findByFilter(ctx context.Context, filter interface{}) ([]*domain.Book, error) {
@@ -144,3 +338,59 @@ func (r *mongoBookRepo) findByFilterWithOpts(ctx context.Context, filter interfa
 	}
 	return books, nil
 }
+
+func (r *mongoBookRepo) RatingVersion(ctx context.Context, id string) (int, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, errors.New("invalid id format")
+	}
+	var doc struct {
+		Version int `bson:"rating_version"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"rating_version": 1})
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}, opts).Decode(&doc); err != nil {
+		return 0, err
+	}
+	return doc.Version, nil
+}
+
+// SetRating stores the aggregate of the book's reviews if the rating is
+// still at the given version; otherwise it fails with
+// domain.ErrRatingChanged.
+func (r *mongoBookRepo) SetRating(ctx context.Context, id string, summary domain.RatingSummary, version int) (*domain.Book, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid id format")
+	}
+	// книги без rating_version ещё ни разу не пересчитывались
+	filter := bson.M{"_id": objID, "rating_version": version}
+	if version == 0 {
+		filter["rating_version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	update := bson.M{
+		"$set": bson.M{"rating": summary.Average, "rating_count": summary.Count},
+		"$inc": bson.M{"rating_version": 1},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var book domain.Book
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&book)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRatingChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// authorFilter matches the display author as well as any contributor.
+func authorFilter(name string) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"author": name},
+		bson.M{"contributors.name": name},
+	}}
+}
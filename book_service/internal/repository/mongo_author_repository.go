@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+type mongoAuthorRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAuthorRepository(client *mongo.Client) AuthorRepository {
+	return &mongoAuthorRepo{
+		collection: client.Database("readspace").Collection("authors"),
+	}
+}
+
+// Create stores a new author; a name or alias already used by another
+// author is rejected by the unique name_keys index.
+func (r *mongoAuthorRepo) Create(ctx context.Context, author *domain.Author) (*domain.Author, error) {
+	if author.ID.IsZero() {
+		author.ID = primitive.NewObjectID()
+	}
+	now := primitive.NewDateTimeFromTime(time.Now())
+	author.CreatedAt, author.UpdatedAt = now, now
+	if _, err := r.collection.InsertOne(ctx, author); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrAuthorNameTaken
+		}
+		return nil, err
+	}
+	return author, nil
+}
+
+func (r *mongoAuthorRepo) GetByID(ctx context.Context, id string) (*domain.Author, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	return r.findOne(ctx, bson.M{"_id": oid})
+}
+
+func (r *mongoAuthorRepo) GetByNameKey(ctx context.Context, key string) (*domain.Author, error) {
+	return r.findOne(ctx, bson.M{"name_keys": key})
+}
+
+func (r *mongoAuthorRepo) Update(ctx context.Context, author *domain.Author) (*domain.Author, error) {
+	update := bson.M{"$set": bson.M{
+		"name":       author.Name,
+		"aliases":    author.Aliases,
+		"bio":        author.Bio,
+		"birth_date": author.BirthDate,
+		"death_date": author.DeathDate,
+		"name_keys":  author.NameKeys,
+		"updated_at": primitive.NewDateTimeFromTime(time.Now()),
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated domain.Author
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": author.ID}, update, opts).Decode(&updated)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domain.ErrAuthorNameTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *mongoAuthorRepo) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return mongo.ErrNoDocuments
+	}
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *mongoAuthorRepo) ListAll(ctx context.Context) ([]*domain.Author, error) {
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var authors []*domain.Author
+	for cursor.Next(ctx) {
+		var author domain.Author
+		if err := cursor.Decode(&author); err != nil {
+			return nil, err
+		}
+		authors = append(authors, &author)
+	}
+	return authors, cursor.Err()
+}
+
+func (r *mongoAuthorRepo) findOne(ctx context.Context, filter bson.M) (*domain.Author, error) {
+	var author domain.Author
+	if err := r.collection.FindOne(ctx, filter).Decode(&author); err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+// sortFields maps BookQuery sort keys to document fields.
+var sortFields = map[string]string{
+	domain.SortTitle:     "title",
+	domain.SortPrice:     "price",
+	domain.SortRating:    "rating",
+	domain.SortPages:     "pages",
+	domain.SortPublished: "published_date",
+}
+
+// pageCursor is the position after the last book of a page: its value of
+// the sort field and its _id, which breaks ties.
+type pageCursor struct {
+	Value interface{} `json:"v"`
+	ID    string      `json:"id"`
+}
+
+// Query returns one page of the books matching q, sorted by q.Sort and then
+// by _id. Pagination is keyset based, so pages stay stable while books are
+// added. q must be normalized.
+func (r *mongoBookRepo) Query(ctx context.Context, q domain.BookQuery) (*domain.BookPage, error) {
+	filter := queryFilter(q)
+
+	field := sortFields[strings.TrimPrefix(q.Sort, "-")]
+	dir := 1
+	if strings.HasPrefix(q.Sort, "-") {
+		dir = -1
+	}
+
+	pageFilter := filter
+	if q.Cursor != "" {
+		after, err := afterCursor(q.Cursor, field, dir)
+		if err != nil {
+			return nil, err
+		}
+		pageFilter = bson.M{"$and": bson.A{filter, after}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: field, Value: dir}, {Key: "_id", Value: dir}}).
+		SetLimit(int64(q.PageSize + 1))
+	books, err := r.findByFilterWithOpts(ctx, pageFilter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.BookPage{Books: books}
+	if len(books) > q.PageSize {
+		page.Books = books[:q.PageSize]
+		last := page.Books[q.PageSize-1]
+		page.NextCursor, err = encodeCursor(last, field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.facets(ctx, filter, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func queryFilter(q domain.BookQuery) bson.M {
+	filter := bson.M{}
+	if q.Keyword != "" {
+		filter["$text"] = bson.M{"$search": q.Keyword}
+	}
+	if q.Genre != "" {
+		filter["genre"] = q.Genre
+	}
+	if q.Author != "" {
+		filter["$or"] = authorFilter(q.Author)["$or"]
+	}
+	if q.Language != "" {
+		filter["language"] = q.Language
+	}
+	addRange(filter, "price", q.MinPrice, q.MaxPrice)
+	addRange(filter, "rating", q.MinRating, q.MaxRating)
+	addRange(filter, "pages", q.MinPages, q.MaxPages)
+
+	// даты хранятся строками вида "2006-01-02", поэтому год сравниваем
+	// лексикографически
+	year := bson.M{}
+	if q.YearFrom > 0 {
+		year["$gte"] = fmt.Sprintf("%04d", q.YearFrom)
+	}
+	if q.YearTo > 0 {
+		year["$lt"] = fmt.Sprintf("%04d", q.YearTo+1)
+	}
+	if len(year) > 0 {
+		filter["published_date"] = year
+	}
+	return filter
+}
+
+func addRange[T float32 | int](filter bson.M, field string, min, max T) {
+	bounds := bson.M{}
+	if min > 0 {
+		bounds["$gte"] = min
+	}
+	if max > 0 {
+		bounds["$lte"] = max
+	}
+	if len(bounds) > 0 {
+		filter[field] = bounds
+	}
+}
+
+func encodeCursor(last *domain.Book, field string) (string, error) {
+	// float32 значения расширяем заранее, иначе JSON округлит их и
+	// сравнение с сохранённым double не совпадёт
+	c := pageCursor{ID: last.ID.Hex()}
+	switch field {
+	case "title":
+		c.Value = last.Title
+	case "price":
+		c.Value = float64(last.Price)
+	case "rating":
+		c.Value = float64(last.Rating)
+	case "pages":
+		c.Value = last.Pages
+	case "published_date":
+		c.Value = last.PublishedDate
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// afterCursor matches the books that come after the cursor in the sort
+// order.
+func afterCursor(cursor, field string, dir int) (bson.M, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidQuery)
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidQuery)
+	}
+	id, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidQuery)
+	}
+
+	op := "$gt"
+	if dir < 0 {
+		op = "$lt"
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: c.Value}},
+		bson.M{field: c.Value, "_id": bson.M{op: id}},
+	}}, nil
+}
+
+// facets counts the matching books per genre, language and author.
+func (r *mongoBookRepo) facets(ctx context.Context, filter bson.M, page *domain.BookPage) error {
+	countBy := func(field string, limit int) bson.A {
+		stages := bson.A{
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		}
+		if limit > 0 {
+			stages = append(stages, bson.M{"$limit": limit})
+		}
+		return stages
+	}
+	pipeline := bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$facet": bson.M{
+			"total":     bson.A{bson.M{"$count": "n"}},
+			"genres":    countBy("genre", 0),
+			"languages": countBy("language", 0),
+			"authors":   countBy("author", domain.MaxAuthorFacets),
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	type bucket struct {
+		Value interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+	}
+	var res []struct {
+		Total     []struct{ N int } `bson:"total"`
+		Genres    []bucket          `bson:"genres"`
+		Languages []bucket          `bson:"languages"`
+		Authors   []bucket          `bson:"authors"`
+	}
+	if err := cursor.All(ctx, &res); err != nil {
+		return err
+	}
+	if len(res) == 0 {
+		return nil
+	}
+
+	toFacets := func(buckets []bucket) []domain.Facet {
+		facets := make([]domain.Facet, 0, len(buckets))
+		for _, b := range buckets {
+			value, _ := b.Value.(string)
+			if value == "" {
+				continue
+			}
+			facets = append(facets, domain.Facet{Value: value, Count: b.Count})
+		}
+		return facets
+	}
+	if len(res[0].Total) > 0 {
+		page.Total = res[0].Total[0].N
+	}
+	page.Genres = toFacets(res[0].Genres)
+	page.Languages = toFacets(res[0].Languages)
+	page.Authors = toFacets(res[0].Authors)
+	return nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+type mongoReviewRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMongoReviewRepository(client *mongo.Client) ReviewRepository {
+	return &mongoReviewRepo{
+		collection: client.Database("readspace").Collection("book_reviews"),
+	}
+}
+
+// Create stores a new review; a second review of the same book by the same
+// user is rejected by the unique (book_id, user_id) index.
+func (r *mongoReviewRepo) Create(ctx context.Context, review *domain.Review) (*domain.Review, error) {
+	if review.ID.IsZero() {
+		review.ID = primitive.NewObjectID()
+	}
+	now := primitive.NewDateTimeFromTime(time.Now())
+	review.CreatedAt, review.UpdatedAt = now, now
+	if _, err := r.collection.InsertOne(ctx, review); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrAlreadyReviewed
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+func (r *mongoReviewRepo) GetByID(ctx context.Context, id string) (*domain.Review, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	var review domain.Review
+	if err := r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// Update changes the rating and text of a review.
+func (r *mongoReviewRepo) Update(ctx context.Context, review *domain.Review) (*domain.Review, error) {
+	update := bson.M{"$set": bson.M{
+		"rating":     review.Rating,
+		"text":       review.Text,
+		"updated_at": primitive.NewDateTimeFromTime(time.Now()),
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated domain.Review
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": review.ID}, update, opts).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *mongoReviewRepo) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return mongo.ErrNoDocuments
+	}
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *mongoReviewRepo) ListByBook(ctx context.Context, bookID string) ([]*domain.Review, error) {
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, err
+	}
+	return r.find(ctx, bson.M{"book_id": bid})
+}
+
+func (r *mongoReviewRepo) ListByUser(ctx context.Context, userID string) ([]*domain.Review, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.find(ctx, bson.M{"user_id": uid})
+}
+
+// Summarize averages the ratings of a book's reviews.
+func (r *mongoReviewRepo) Summarize(ctx context.Context, bookID string) (domain.RatingSummary, error) {
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return domain.RatingSummary{}, err
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"book_id": bid}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"average": bson.M{"$avg": "$rating"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return domain.RatingSummary{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var row struct {
+		Average float64 `bson:"average"`
+		Count   int     `bson:"count"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&row); err != nil {
+			return domain.RatingSummary{}, err
+		}
+	}
+	return domain.RatingSummary{
+		Average: float32(math.Round(row.Average*100) / 100),
+		Count:   row.Count,
+	}, cursor.Err()
+}
+
+func (r *mongoReviewRepo) DeleteByBook(ctx context.Context, bookID string) error {
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteMany(ctx, bson.M{"book_id": bid})
+	return err
+}
+
+// DeleteByUser removes the user's reviews and returns the books they were
+// about.
+func (r *mongoReviewRepo) DeleteByUser(ctx context.Context, userID string) ([]string, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	reviewed, err := r.collection.Distinct(ctx, "book_id", bson.M{"user_id": uid})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"user_id": uid}); err != nil {
+		return nil, err
+	}
+
+	var books []string
+	for _, b := range reviewed {
+		if id, ok := b.(primitive.ObjectID); ok {
+			books = append(books, id.Hex())
+		}
+	}
+	return books, nil
+}
+
+func (r *mongoReviewRepo) find(ctx context.Context, filter bson.M) ([]*domain.Review, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []*domain.Review
+	for cursor.Next(ctx) {
+		var review domain.Review
+		if err := cursor.Decode(&review); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, &review)
+	}
+	return reviews, cursor.Err()
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+type ReviewRepository interface {
+	Create(ctx context.Context, review *domain.Review) (*domain.Review, error)
+	GetByID(ctx context.Context, id string) (*domain.Review, error)
+	Update(ctx context.Context, review *domain.Review) (*domain.Review, error)
+	Delete(ctx context.Context, id string) error
+	ListByBook(ctx context.Context, bookID string) ([]*domain.Review, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.Review, error)
+	Summarize(ctx context.Context, bookID string) (domain.RatingSummary, error)
+	DeleteByBook(ctx context.Context, bookID string) error
+	DeleteByUser(ctx context.Context, userID string) ([]string, error)
+}
@@ -0,0 +1,412 @@
+// Package search is an in-memory full-text index over the catalog. Books
+// are ranked with BM25 over title, author and description; query words
+// may be misspelled (matched through a trigram index of the vocabulary)
+// and the last word may be incomplete.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	prefixWeight = 0.8
+	// каждая опечатка снижает вес найденного слова на четверть
+	typoPenalty = 0.25
+
+	snippetWords = 24
+)
+
+type field struct {
+	name   string
+	weight float64
+	// window > 0 cuts long fields down to a snippet of that many words.
+	window int
+}
+
+var fields = []field{
+	{name: "title", weight: 3},
+	{name: "author", weight: 2},
+	{name: "description", weight: 1, window: snippetWords},
+}
+
+type document struct {
+	id     string
+	texts  []string // indexed like fields
+	title  []string
+	terms  map[string]bool
+	length float64
+}
+
+// Hit is a matching book ID with its relevance and highlighted fields.
+type Hit struct {
+	ID         string
+	Score      float64
+	Highlights []domain.Highlight
+}
+
+// Index is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*document
+	postings map[string]map[string]float64 // term → book → weighted frequency
+	grams    map[string]map[string]bool    // trigram → terms
+	totalLen float64
+
+	// vocab and titleVocab are the terms of postings and titles, sorted
+	// for prefix lookups
+	vocab      []string
+	titles     map[string]map[string]bool // title term → books
+	titleVocab []string
+}
+
+func New() *Index {
+	return &Index{
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string]float64),
+		grams:    make(map[string]map[string]bool),
+		titles:   make(map[string]map[string]bool),
+	}
+}
+
+// Add indexes a book, replacing an earlier version of it.
+func (ix *Index) Add(book *domain.Book) {
+	id := book.ID.Hex()
+	doc := &document{
+		id:    id,
+		texts: []string{book.Title, strings.Join(book.ContributorNames(), ", "), book.Description},
+		title: terms(book.Title),
+		terms: make(map[string]bool),
+	}
+	freq := make(map[string]float64)
+	for i, f := range fields {
+		for _, t := range terms(doc.texts[i]) {
+			freq[t] += f.weight
+			doc.length += f.weight
+		}
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(id)
+	for t, n := range freq {
+		doc.terms[t] = true
+		if ix.postings[t] == nil {
+			ix.postings[t] = make(map[string]float64)
+			ix.vocab = insertSorted(ix.vocab, t)
+			for _, g := range trigrams(t) {
+				if ix.grams[g] == nil {
+					ix.grams[g] = make(map[string]bool)
+				}
+				ix.grams[g][t] = true
+			}
+		}
+		ix.postings[t][id] = n
+	}
+	for _, t := range doc.title {
+		if ix.titles[t] == nil {
+			ix.titles[t] = make(map[string]bool)
+			ix.titleVocab = insertSorted(ix.titleVocab, t)
+		}
+		ix.titles[t][id] = true
+	}
+	ix.docs[id] = doc
+	ix.totalLen += doc.length
+}
+
+// Remove drops a book from the index.
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(id)
+}
+
+// Len returns the number of indexed books.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+func (ix *Index) remove(id string) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	for t := range doc.terms {
+		delete(ix.postings[t], id)
+		if len(ix.postings[t]) > 0 {
+			continue
+		}
+		delete(ix.postings, t)
+		ix.vocab = removeSorted(ix.vocab, t)
+		for _, g := range trigrams(t) {
+			delete(ix.grams[g], t)
+			if len(ix.grams[g]) == 0 {
+				delete(ix.grams, g)
+			}
+		}
+	}
+	for _, t := range doc.title {
+		delete(ix.titles[t], id)
+		if len(ix.titles[t]) == 0 {
+			delete(ix.titles, t)
+			ix.titleVocab = removeSorted(ix.titleVocab, t)
+		}
+	}
+	delete(ix.docs, id)
+	ix.totalLen -= doc.length
+}
+
+// Search returns up to limit books matching query, best first. Books that
+// match only some of the query words are ranked below those matching all.
+// Unless query ends with a space, its last word is also matched as a
+// prefix.
+func (ix *Index) Search(query string, limit int) []Hit {
+	qterms := terms(query)
+	if len(qterms) == 0 || limit <= 0 {
+		return nil
+	}
+	partial := !strings.HasSuffix(query, " ")
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if len(ix.docs) == 0 {
+		return nil
+	}
+	avgLen := ix.totalLen / float64(len(ix.docs))
+
+	scores := make(map[string]float64)
+	covered := make(map[string]int)
+	matched := make(map[string]bool)
+	for i, qt := range qterms {
+		best := make(map[string]float64)
+		for t, w := range ix.expand(qt, partial && i == len(qterms)-1) {
+			matched[t] = true
+			posting := ix.postings[t]
+			df := float64(len(posting))
+			idf := math.Log(1 + (float64(len(ix.docs))-df+0.5)/(df+0.5))
+			for id, tf := range posting {
+				norm := tf + bm25K1*(1-bm25B+bm25B*ix.docs[id].length/avgLen)
+				if s := w * idf * tf * (bm25K1 + 1) / norm; s > best[id] {
+					best[id] = s
+				}
+			}
+		}
+		for id, s := range best {
+			scores[id] += s
+			covered[id]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		coverage := float64(covered[id]) / float64(len(qterms))
+		hits = append(hits, Hit{ID: id, Score: s * coverage * coverage})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	for i := range hits {
+		doc := ix.docs[hits[i].ID]
+		for j, f := range fields {
+			if snippet, ok := highlight(doc.texts[j], matched, f.window); ok {
+				hits[i].Highlights = append(hits[i].Highlights, domain.Highlight{Field: f.name, Snippet: snippet})
+			}
+		}
+	}
+	return hits
+}
+
+// expand maps a query word to the indexed terms it may stand for, each
+// with a weight: 1 for the word itself, less for completions and typos.
+func (ix *Index) expand(qt string, partial bool) map[string]float64 {
+	exp := make(map[string]float64)
+	add := func(t string, w float64) {
+		if w > exp[t] {
+			exp[t] = w
+		}
+	}
+
+	if _, ok := ix.postings[qt]; ok {
+		add(qt, 1)
+	}
+	if partial && len([]rune(qt)) >= 2 {
+		for _, t := range withPrefix(ix.vocab, qt) {
+			if t != qt {
+				add(t, prefixWeight)
+			}
+		}
+	}
+
+	k := maxEdits(qt)
+	if k == 0 {
+		return exp
+	}
+	n := len([]rune(qt))
+	seen := make(map[string]bool)
+	for _, g := range trigrams(qt) {
+		for t := range ix.grams[g] {
+			if seen[t] {
+				continue
+			}
+			seen[t] = true
+			if m := len([]rune(t)); m < n-k || m > n+k {
+				continue
+			}
+			if d := distance(qt, t); d > 0 && d <= k {
+				add(t, 1-typoPenalty*float64(d))
+			}
+		}
+	}
+	return exp
+}
+
+// Suggest completes prefix into book titles. Every finished word of prefix
+// must appear in the title, allowing for typos; the last word must start a
+// title word. Titles that begin with prefix come first. Only the books
+// with a title word completing the last word are scored.
+func (ix *Index) Suggest(prefix string, limit int) []domain.TitleSuggestion {
+	qterms := terms(prefix)
+	if len(qterms) == 0 || limit <= 0 {
+		return nil
+	}
+	last, rest := qterms[len(qterms)-1], qterms[:len(qterms)-1]
+	typed := strings.Join(qterms, " ")
+
+	type scored struct {
+		domain.TitleSuggestion
+		score float64
+	}
+	var res []scored
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	for id := range ix.completing(last) {
+		doc := ix.docs[id]
+		matched := make(map[string]bool)
+		score, ok := 0.0, true
+		for _, qt := range rest {
+			t, d := closest(doc.title, qt)
+			if d > maxEdits(qt) {
+				ok = false
+				break
+			}
+			matched[t] = true
+			score += 1 - typoPenalty*float64(d)
+		}
+		if !ok {
+			continue
+		}
+		t, w := completion(doc.title, last)
+		if w == 0 {
+			continue
+		}
+		matched[t] = true
+		score += w
+		if strings.HasPrefix(strings.Join(doc.title, " "), typed) {
+			score++
+		}
+
+		title := doc.texts[0]
+		marked, _ := highlight(title, matched, 0)
+		res = append(res, scored{
+			TitleSuggestion: domain.TitleSuggestion{BookID: id, Title: title, Highlighted: marked},
+			score:           score,
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		switch {
+		case res[i].score != res[j].score:
+			return res[i].score > res[j].score
+		case len(res[i].Title) != len(res[j].Title):
+			return len(res[i].Title) < len(res[j].Title)
+		default:
+			return res[i].BookID < res[j].BookID
+		}
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	suggestions := make([]domain.TitleSuggestion, len(res))
+	for i, r := range res {
+		suggestions[i] = r.TitleSuggestion
+	}
+	return suggestions
+}
+
+// completing returns the books with a title word that completion would
+// accept for qt. Misspelled words are found through the trigram index, so
+// like in Search a typo is forgiven when the word shares a trigram with qt.
+func (ix *Index) completing(qt string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, t := range withPrefix(ix.titleVocab, qt) {
+		for id := range ix.titles[t] {
+			ids[id] = true
+		}
+	}
+	n := len([]rune(qt))
+	if n < 4 {
+		return ids
+	}
+	seen := make(map[string]bool)
+	for _, g := range trigrams(qt) {
+		for t := range ix.grams[g] {
+			if seen[t] || ix.titles[t] == nil {
+				continue
+			}
+			seen[t] = true
+			if r := []rune(t); len(r) >= n && distance(qt, string(r[:n])) == 1 {
+				for id := range ix.titles[t] {
+					ids[id] = true
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// closest finds the title word nearest to qt.
+func closest(title []string, qt string) (string, int) {
+	best, bestD := "", math.MaxInt
+	for _, t := range title {
+		if d := distance(qt, t); d < bestD {
+			best, bestD = t, d
+		}
+	}
+	return best, bestD
+}
+
+// completion finds a title word that starts with qt, or with qt misspelled
+// once. It returns the word and how well it matches, 0 if none does.
+func completion(title []string, qt string) (string, float64) {
+	n := len([]rune(qt))
+	fuzzy := ""
+	for _, t := range title {
+		if strings.HasPrefix(t, qt) {
+			return t, 1
+		}
+		if r := []rune(t); fuzzy == "" && n >= 4 && len(r) >= n && distance(qt, string(r[:n])) == 1 {
+			fuzzy = t
+		}
+	}
+	if fuzzy != "" {
+		return fuzzy, 1 - typoPenalty
+	}
+	return "", 0
+}
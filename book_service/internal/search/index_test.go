@@ -0,0 +1,105 @@
+package search
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+func book(title, author, description string) *domain.Book {
+	return &domain.Book{ID: primitive.NewObjectID(), Title: title, Author: author, Description: description}
+}
+
+func TestSearch(t *testing.T) {
+	hobbit := book("The Hobbit", "J. R. R. Tolkien", "Bilbo Baggins leaves the Shire on an unexpected journey.")
+	potter := book("Harry Potter and the Philosopher's Stone", "J. K. Rowling", "A boy learns he is a wizard.")
+	pottery := book("Pottery for Beginners", "Ann Clay", "Throwing bowls on the wheel.")
+	ix := New()
+	for _, b := range []*domain.Book{hobbit, potter, pottery} {
+		ix.Add(b)
+	}
+
+	hits := ix.Search("tolkein", 10)
+	if len(hits) != 1 || hits[0].ID != hobbit.ID.Hex() {
+		t.Fatalf("expected the misspelled author to find The Hobbit, got %+v", hits)
+	}
+	if hits[0].Highlights[0].Field != "author" || hits[0].Highlights[0].Snippet != "J. R. R. <em>Tolkien</em>" {
+		t.Errorf("unexpected highlight %+v", hits[0].Highlights)
+	}
+
+	hits = ix.Search("harry pot", 10)
+	if len(hits) != 2 || hits[0].ID != potter.ID.Hex() {
+		t.Fatalf("expected Harry Potter first, got %+v", hits)
+	}
+
+	ix.Remove(potter.ID.Hex())
+	if hits := ix.Search("harry", 10); len(hits) != 0 {
+		t.Errorf("removed book still found: %+v", hits)
+	}
+
+	pottery.Title = "Ceramics for Beginners"
+	ix.Add(pottery)
+	if hits := ix.Search("pottery ", 10); len(hits) != 0 {
+		t.Errorf("old title still indexed: %+v", hits)
+	}
+	if ix.Len() != 2 {
+		t.Errorf("expected 2 books, got %d", ix.Len())
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	ix := New()
+	ix.Add(book("The Lord of the Rings", "", ""))
+	jim := book("Lord Jim", "", "")
+	ix.Add(jim)
+	ix.Add(book("Lolita", "", ""))
+
+	got := ix.Suggest("lord", 10)
+	if len(got) != 2 || got[0].Title != "Lord Jim" {
+		t.Fatalf("expected Lord Jim first, got %+v", got)
+	}
+	if got[1].Highlighted != "The <em>Lord</em> of the Rings" {
+		t.Errorf("unexpected highlight %q", got[1].Highlighted)
+	}
+
+	got = ix.Suggest("lord of the rins", 10)
+	if len(got) != 1 || got[0].Title != "The Lord of the Rings" {
+		t.Errorf("expected a typo in the last word to be forgiven, got %+v", got)
+	}
+
+	ix.Remove(jim.ID.Hex())
+	if got := ix.Suggest("ji", 10); len(got) != 0 {
+		t.Errorf("removed title still suggested: %+v", got)
+	}
+}
+
+func TestHighlightEscapesHTML(t *testing.T) {
+	ix := New()
+	ix.Add(book("Tom & Jerry <script>", "", ""))
+	got := ix.Suggest("tom", 10)
+	if len(got) != 1 || got[0].Highlighted != "<em>Tom</em> &amp; Jerry &lt;script&gt;" {
+		t.Errorf("title not escaped: %+v", got)
+	}
+	hits := ix.Search("jerry", 10)
+	if len(hits) != 1 || hits[0].Highlights[0].Snippet != "Tom &amp; <em>Jerry</em> &lt;script&gt;" {
+		t.Errorf("snippet not escaped: %+v", hits)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{
+		{"tolkein", "tolkien", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"книга", "книги", 1},
+	} {
+		if got := distance(c.a, c.b); got != c.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// span is one word of a text: its normalized form and where it sits in the
+// original string.
+type span struct {
+	term       string
+	start, end int
+}
+
+// words splits text into lower-cased words made of letters and digits,
+// remembering their byte offsets for highlighting.
+func words(text string) []span {
+	var res []span
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			res = append(res, span{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		res = append(res, span{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return res
+}
+
+func terms(text string) []string {
+	spans := words(text)
+	res := make([]string, len(spans))
+	for i, s := range spans {
+		res[i] = s.term
+	}
+	return res
+}
+
+// trigrams returns the character trigrams of a term padded with "$", so
+// that the first and last letters weigh as much as the middle ones.
+func trigrams(term string) []string {
+	r := []rune("$" + term + "$")
+	if len(r) < 3 {
+		return nil
+	}
+	res := make([]string, 0, len(r)-2)
+	for i := 0; i+3 <= len(r); i++ {
+		res = append(res, string(r[i:i+3]))
+	}
+	return res
+}
+
+// maxEdits is how many typos a query term of this length may contain.
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the optimal string alignment distance: insertions, deletions,
+// substitutions and transpositions of adjacent letters ("tolkein" →
+// "tolkien") each cost one edit.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+// highlight wraps the words of text whose terms are in matched with
+// <em></em>; the text itself is HTML-escaped. With window > 0 only about
+// window words around the first match are kept, with "…" marking what was
+// cut.
+func highlight(text string, matched map[string]bool, window int) (string, bool) {
+	spans := words(text)
+	first := -1
+	for i, s := range spans {
+		if matched[s.term] {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	from, to := 0, len(spans)
+	if window > 0 && len(spans) > window {
+		from = max(0, first-window/4)
+		to = min(len(spans), from+window)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := spans[from].start
+	for _, s := range spans[from:to] {
+		b.WriteString(html.EscapeString(text[pos:s.start]))
+		if matched[s.term] {
+			b.WriteString("<em>" + html.EscapeString(text[s.start:s.end]) + "</em>")
+		} else {
+			b.WriteString(html.EscapeString(text[s.start:s.end]))
+		}
+		pos = s.end
+	}
+	if to < len(spans) {
+		b.WriteString("…")
+	} else {
+		b.WriteString(html.EscapeString(text[pos:]))
+	}
+	return b.String(), true
+}
+
+// insertSorted adds t to the sorted slice s unless it is there already.
+func insertSorted(s []string, t string) []string {
+	i := sort.SearchStrings(s, t)
+	if i < len(s) && s[i] == t {
+		return s
+	}
+	s = append(s, "")
+	copy(s[i+1:], s[i:])
+	s[i] = t
+	return s
+}
+
+// removeSorted drops t from the sorted slice s.
+func removeSorted(s []string, t string) []string {
+	i := sort.SearchStrings(s, t)
+	if i == len(s) || s[i] != t {
+		return s
+	}
+	return append(s[:i], s[i+1:]...)
+}
+
+// withPrefix returns the terms of the sorted slice s that start with prefix.
+func withPrefix(s []string, prefix string) []string {
+	from := sort.SearchStrings(s, prefix)
+	to := from
+	for to < len(s) && strings.HasPrefix(s[to], prefix) {
+		to++
+	}
+	return s[from:to]
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/repository"
+)
+
+type AuthorUseCase interface {
+	CreateAuthor(ctx context.Context, author *domain.Author) (*domain.Author, error)
+	GetAuthor(ctx context.Context, id string) (*domain.Author, error)
+	// FindAuthor looks an author up by name or alias.
+	FindAuthor(ctx context.Context, name string) (*domain.Author, error)
+	ListAuthors(ctx context.Context) ([]*domain.Author, error)
+	// UpdateAuthor also renames the author on the books crediting them and
+	// returns the books it changed.
+	UpdateAuthor(ctx context.Context, author *domain.Author) (*domain.Author, []*domain.Book, error)
+	// DeleteAuthor refuses to delete an author still credited on books.
+	DeleteAuthor(ctx context.Context, id string) error
+
+	ListBooksByAuthorID(ctx context.Context, id string) ([]*domain.Book, error)
+	// ListBooksByAuthorName resolves name through the authors' names and
+	// aliases; books of names no author carries are matched by the
+	// credited name as before.
+	ListBooksByAuthorName(ctx context.Context, name string) ([]*domain.Book, error)
+
+	ContributorLinker
+}
+
+// ContributorLinker resolves the contributors of a book to authors.
+type ContributorLinker interface {
+	// LinkContributors sets the AuthorID of every contributor and replaces
+	// its name with the author's canonical one. A name no author is known
+	// by gets a new author.
+	LinkContributors(ctx context.Context, book *domain.Book) error
+}
+
+type authorUseCase struct {
+	authors repository.AuthorRepository
+	books   repository.BookRepository
+}
+
+func NewAuthorUseCase(authors repository.AuthorRepository, books repository.BookRepository) AuthorUseCase {
+	return &authorUseCase{authors: authors, books: books}
+}
+
+func (u *authorUseCase) CreateAuthor(ctx context.Context, author *domain.Author) (*domain.Author, error) {
+	if err := author.Normalize(); err != nil {
+		return nil, err
+	}
+	return u.authors.Create(ctx, author)
+}
+
+func (u *authorUseCase) GetAuthor(ctx context.Context, id string) (*domain.Author, error) {
+	return u.authors.GetByID(ctx, id)
+}
+
+func (u *authorUseCase) FindAuthor(ctx context.Context, name string) (*domain.Author, error) {
+	key := domain.NameKey(name)
+	if key == "" {
+		return nil, domain.ErrAuthorNameRequired
+	}
+	return u.authors.GetByNameKey(ctx, key)
+}
+
+func (u *authorUseCase) ListAuthors(ctx context.Context) ([]*domain.Author, error) {
+	return u.authors.ListAll(ctx)
+}
+
+func (u *authorUseCase) UpdateAuthor(ctx context.Context, author *domain.Author) (*domain.Author, []*domain.Book, error) {
+	if err := author.Normalize(); err != nil {
+		return nil, nil, err
+	}
+	updated, err := u.authors.Update(ctx, author)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changed, err := u.books.RenameContributor(ctx, updated.ID.Hex(), updated.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, changed, nil
+}
+
+func (u *authorUseCase) DeleteAuthor(ctx context.Context, id string) error {
+	if _, err := u.authors.GetByID(ctx, id); err != nil {
+		return err
+	}
+	books, err := u.books.ListByAuthorID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(books) > 0 {
+		return fmt.Errorf("%w: %d", domain.ErrAuthorHasBooks, len(books))
+	}
+	return u.authors.Delete(ctx, id)
+}
+
+func (u *authorUseCase) ListBooksByAuthorID(ctx context.Context, id string) ([]*domain.Book, error) {
+	if _, err := u.authors.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.books.ListByAuthorID(ctx, id)
+}
+
+func (u *authorUseCase) ListBooksByAuthorName(ctx context.Context, name string) ([]*domain.Book, error) {
+	author, err := u.FindAuthor(ctx, name)
+	switch {
+	case err == nil:
+		return u.books.ListByAuthorID(ctx, author.ID.Hex())
+	case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, domain.ErrAuthorNameRequired):
+		return u.books.ListByAuthor(ctx, name)
+	default:
+		return nil, err
+	}
+}
+
+func (u *authorUseCase) LinkContributors(ctx context.Context, book *domain.Book) error {
+	for i := range book.Contributors {
+		c := &book.Contributors[i]
+		author, err := u.resolve(ctx, *c)
+		if err != nil {
+			return err
+		}
+		c.AuthorID, c.Name = author.ID, author.Name
+	}
+	book.SyncAuthor()
+	return nil
+}
+
+func (u *authorUseCase) resolve(ctx context.Context, c domain.Contributor) (*domain.Author, error) {
+	if !c.AuthorID.IsZero() {
+		author, err := u.authors.GetByID(ctx, c.AuthorID.Hex())
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnknownAuthor, c.AuthorID.Hex())
+		}
+		return author, err
+	}
+
+	key := domain.NameKey(c.Name)
+	if key == "" {
+		return nil, fmt.Errorf("%w: %q as %q", domain.ErrInvalidContributor, c.Name, c.Role)
+	}
+	author, err := u.authors.GetByNameKey(ctx, key)
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return author, err
+	}
+	author = &domain.Author{Name: c.Name}
+	if err := author.Normalize(); err != nil {
+		return nil, err
+	}
+	created, err := u.authors.Create(ctx, author)
+	if errors.Is(err, domain.ErrAuthorNameTaken) {
+		// автора только что завёл параллельный запрос
+		return u.authors.GetByNameKey(ctx, key)
+	}
+	return created, err
+}
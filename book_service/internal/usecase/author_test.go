@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/repository"
+)
+
+type fakeAuthors struct {
+	repository.AuthorRepository
+	authors map[string]*domain.Author
+}
+
+func newFakeAuthors() *fakeAuthors {
+	return &fakeAuthors{authors: map[string]*domain.Author{}}
+}
+
+func (f *fakeAuthors) taken(a *domain.Author) bool {
+	for _, other := range f.authors {
+		if other.ID == a.ID {
+			continue
+		}
+		for _, k := range a.NameKeys {
+			if containsKey(other.NameKeys, k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeAuthors) Create(ctx context.Context, a *domain.Author) (*domain.Author, error) {
+	if f.taken(a) {
+		return nil, domain.ErrAuthorNameTaken
+	}
+	a.ID = primitive.NewObjectID()
+	f.authors[a.ID.Hex()] = a
+	return a, nil
+}
+func (f *fakeAuthors) GetByID(ctx context.Context, id string) (*domain.Author, error) {
+	if a, ok := f.authors[id]; ok {
+		return a, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+func (f *fakeAuthors) GetByNameKey(ctx context.Context, key string) (*domain.Author, error) {
+	for _, a := range f.authors {
+		if containsKey(a.NameKeys, key) {
+			return a, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+func (f *fakeAuthors) Update(ctx context.Context, a *domain.Author) (*domain.Author, error) {
+	if f.taken(a) {
+		return nil, domain.ErrAuthorNameTaken
+	}
+	f.authors[a.ID.Hex()] = a
+	return a, nil
+}
+func (f *fakeAuthors) Delete(ctx context.Context, id string) error {
+	delete(f.authors, id)
+	return nil
+}
+
+func (f *fakeBooks) Update(ctx context.Context, b *domain.Book) (*domain.Book, error) {
+	// как и настоящий репозиторий, Update не пишет остаток
+	if old, ok := f.books[b.ID.Hex()]; ok {
+		b.Stock = old.Stock
+	}
+	f.books[b.ID.Hex()] = b
+	return b, nil
+}
+func (f *fakeBooks) RenameContributor(ctx context.Context, id, name string) ([]*domain.Book, error) {
+	var res []*domain.Book
+	for _, b := range f.books {
+		renamed := false
+		for i := range b.Contributors {
+			if c := &b.Contributors[i]; c.AuthorID.Hex() == id && c.Name != name {
+				c.Name = name
+				renamed = true
+			}
+		}
+		if renamed {
+			b.SyncAuthor()
+			res = append(res, b)
+		}
+	}
+	return res, nil
+}
+func (f *fakeBooks) ListByAuthorID(ctx context.Context, id string) ([]*domain.Book, error) {
+	var res []*domain.Book
+	for _, b := range f.books {
+		for _, c := range b.Contributors {
+			if c.AuthorID.Hex() == id {
+				res = append(res, b)
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+func TestAuthors(t *testing.T) {
+	ctx := context.Background()
+	books := &fakeBooks{books: map[string]*domain.Book{}}
+	authors := NewAuthorUseCase(newFakeAuthors(), books)
+	bookUC := NewBookUseCase(books, authors)
+
+	tolkien, err := authors.CreateAuthor(ctx, &domain.Author{
+		Name:      " J.R.R. Tolkien ",
+		Aliases:   []string{"John Ronald Reuel Tolkien", "J. R. R. Tolkien", ""},
+		BirthDate: "1892-01-03",
+		DeathDate: "1973",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tolkien.Aliases) != 1 {
+		t.Errorf("aliases repeating the name not dropped: %q", tolkien.Aliases)
+	}
+
+	hobbit, err := bookUC.CreateBook(ctx, &domain.Book{Title: "The Hobbit", Author: "JRR Tolkien"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := hobbit.Contributors[0]; c.AuthorID != tolkien.ID || hobbit.Author != "J.R.R. Tolkien" {
+		t.Errorf("spelling variant not linked to the author: %q %+v", hobbit.Author, c)
+	}
+	silmarillion, err := bookUC.CreateBook(ctx, &domain.Book{
+		Title:        "The Silmarillion",
+		Contributors: []domain.Contributor{{AuthorID: tolkien.ID}, {Name: "Christopher Tolkien", Role: domain.RoleEditor}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if silmarillion.Author != "J.R.R. Tolkien" || silmarillion.Contributors[1].AuthorID.IsZero() {
+		t.Errorf("contributors not linked: %q %+v", silmarillion.Author, silmarillion.Contributors)
+	}
+	if _, err := bookUC.CreateBook(ctx, &domain.Book{
+		Title:        "Lost",
+		Contributors: []domain.Contributor{{AuthorID: primitive.NewObjectID()}},
+	}); !errors.Is(err, domain.ErrUnknownAuthor) {
+		t.Errorf("expected ErrUnknownAuthor, got %v", err)
+	}
+
+	found, err := authors.ListBooksByAuthorName(ctx, "john ronald reuel tolkien")
+	if err != nil || len(found) != 2 {
+		t.Fatalf("expected both books by alias, got %d (%v)", len(found), err)
+	}
+
+	tolkien.Name = "J. R. R. Tolkien"
+	tolkien.Aliases = append(tolkien.Aliases, "Christopher Tolkien")
+	if _, _, err := authors.UpdateAuthor(ctx, tolkien); !errors.Is(err, domain.ErrAuthorNameTaken) {
+		t.Errorf("expected ErrAuthorNameTaken, got %v", err)
+	}
+	tolkien.Aliases = []string{"Tolkien"}
+	_, changed, err := authors.UpdateAuthor(ctx, tolkien)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 2 || books.books[hobbit.ID.Hex()].Author != "J. R. R. Tolkien" {
+		t.Errorf("rename not carried over to books: %d changed", len(changed))
+	}
+
+	if err := authors.DeleteAuthor(ctx, tolkien.ID.Hex()); !errors.Is(err, domain.ErrAuthorHasBooks) {
+		t.Errorf("expected ErrAuthorHasBooks, got %v", err)
+	}
+	if _, err := authors.CreateAuthor(ctx, &domain.Author{Name: "X", BirthDate: "1900", DeathDate: "1899-12-31"}); !errors.Is(err, domain.ErrInvalidAuthorDate) {
+		t.Errorf("expected ErrInvalidAuthorDate, got %v", err)
+	}
+}
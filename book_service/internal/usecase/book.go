@@ -9,41 +9,67 @@ import (
 type BookUseCase interface {
 	CreateBook(ctx context.Context, book *domain.Book) (*domain.Book, error)
 	GetBookByID(ctx context.Context, id string) (*domain.Book, error)
+	GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error)
 	ListBooks(ctx context.Context) ([]*domain.Book, error)
 	UpdateBook(ctx context.Context, book *domain.Book) (*domain.Book, error)
 	DeleteBook(ctx context.Context, id string) error
 	ListBooksByGenre(ctx context.Context, genre string) ([]*domain.Book, error)
-	ListBooksByAuthor(ctx context.Context, author string) ([]*domain.Book, error)
 	ListBooksByLanguage(ctx context.Context, language string) ([]*domain.Book, error)
 	ListTopRated(ctx context.Context) ([]*domain.Book, error)
 	ListNewArrivals(ctx context.Context) ([]*domain.Book, error)
-	SearchBooks(ctx context.Context, keyword string) ([]*domain.Book, error)
-	RecommendBooks(ctx context.Context, bookID string) ([]*domain.Book, error)
+	QueryBooks(ctx context.Context, q domain.BookQuery) (*domain.BookPage, error)
+	ReserveStock(ctx context.Context, bookID string, quantity int) (*domain.Book, error)
+	ReleaseStock(ctx context.Context, bookID string, quantity int) (*domain.Book, error)
 }
 
 type bookUseCase struct {
-	repo repository.BookRepository
+	repo    repository.BookRepository
+	authors ContributorLinker
 }
 
-func NewBookUseCase(r repository.BookRepository) BookUseCase {
+func NewBookUseCase(r repository.BookRepository, authors ContributorLinker) BookUseCase {
 	return &bookUseCase{
-		repo: r,
+		repo:    r,
+		authors: authors,
 	}
 }
 
 func (u *bookUseCase) CreateBook(ctx context.Context, book *domain.Book) (*domain.Book, error) {
+	if err := u.prepare(ctx, book); err != nil {
+		return nil, err
+	}
 	return u.repo.Create(ctx, book)
 }
 
+// prepare validates the metadata and links the contributors to authors.
+func (u *bookUseCase) prepare(ctx context.Context, book *domain.Book) error {
+	if err := book.NormalizeMetadata(); err != nil {
+		return err
+	}
+	return u.authors.LinkContributors(ctx, book)
+}
+
 func (u *bookUseCase) GetBookByID(ctx context.Context, id string) (*domain.Book, error) {
 	return u.repo.GetByID(ctx, id)
 }
 
+// GetBookByISBN accepts either ISBN form, with or without hyphens.
+func (u *bookUseCase) GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	normalized, err := domain.NormalizeISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.GetByISBN(ctx, normalized)
+}
+
 func (u *bookUseCase) ListBooks(ctx context.Context) ([]*domain.Book, error) {
 	return u.repo.ListAll(ctx)
 }
 
 func (u *bookUseCase) UpdateBook(ctx context.Context, book *domain.Book) (*domain.Book, error) {
+	if err := u.prepare(ctx, book); err != nil {
+		return nil, err
+	}
 	return u.repo.Update(ctx, book)
 }
 
@@ -55,10 +81,6 @@ func (u *bookUseCase) ListBooksByGenre(ctx context.Context, genre string) ([]*do
 	return u.repo.ListByGenre(ctx, genre)
 }
 
-func (u *bookUseCase) ListBooksByAuthor(ctx context.Context, author string) ([]*domain.Book, error) {
-	return u.repo.ListByAuthor(ctx, author)
-}
-
 func (u *bookUseCase) ListBooksByLanguage(ctx context.Context, language string) ([]*domain.Book, error) {
 	return u.repo.ListByLanguage(ctx, language)
 }
@@ -71,10 +93,24 @@ func (u *bookUseCase) ListNewArrivals(ctx context.Context) ([]*domain.Book, erro
 	return u.repo.ListNewArrivals(ctx)
 }
 
-func (u *bookUseCase) SearchBooks(ctx context.Context, keyword string) ([]*domain.Book, error) {
-	return u.repo.SearchBooks(ctx, keyword)
+// QueryBooks validates the filters and returns one page of matching books.
+func (u *bookUseCase) QueryBooks(ctx context.Context, q domain.BookQuery) (*domain.BookPage, error) {
+	if err := q.Normalize(); err != nil {
+		return nil, err
+	}
+	return u.repo.Query(ctx, q)
 }
 
-func (u *bookUseCase) RecommendBooks(ctx context.Context, bookID string) ([]*domain.Book, error) {
-	return u.repo.RecommendBooks(ctx, bookID)
+func (u *bookUseCase) ReserveStock(ctx context.Context, bookID string, quantity int) (*domain.Book, error) {
+	if quantity <= 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	return u.repo.ReserveStock(ctx, bookID, quantity)
+}
+
+func (u *bookUseCase) ReleaseStock(ctx context.Context, bookID string, quantity int) (*domain.Book, error) {
+	if quantity <= 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	return u.repo.ReleaseStock(ctx, bookID, quantity)
 }
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+func (f *fakeBooks) Create(ctx context.Context, b *domain.Book) (*domain.Book, error) {
+	for _, other := range f.books {
+		if b.ISBN != "" && other.ISBN == b.ISBN {
+			return nil, domain.ErrDuplicateISBN
+		}
+	}
+	b.ID = primitive.NewObjectID()
+	f.books[b.ID.Hex()] = b
+	return b, nil
+}
+
+func (f *fakeBooks) GetByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	for _, b := range f.books {
+		if b.ISBN == isbn {
+			return b, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+type queryRepo struct {
+	fakeBooks
+	got domain.BookQuery
+}
+
+func (r *queryRepo) Query(ctx context.Context, q domain.BookQuery) (*domain.BookPage, error) {
+	r.got = q
+	return &domain.BookPage{}, nil
+}
+
+func TestQueryBooks(t *testing.T) {
+	ctx := context.Background()
+	repo := &queryRepo{}
+	uc := NewBookUseCase(repo, nil)
+
+	if _, err := uc.QueryBooks(ctx, domain.BookQuery{Genre: "Fantasy", PageSize: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.got.Sort != domain.SortTitle || repo.got.PageSize != domain.MaxPageSize {
+		t.Errorf("defaults not applied: sort %q, page size %d", repo.got.Sort, repo.got.PageSize)
+	}
+
+	for name, q := range map[string]domain.BookQuery{
+		"price range":  {MinPrice: 20, MaxPrice: 10},
+		"rating range": {MaxRating: 7},
+		"year range":   {YearFrom: 2001, YearTo: 1999},
+		"negative":     {MinPages: -1},
+		"sort":         {Sort: "-isbn"},
+	} {
+		if _, err := uc.QueryBooks(ctx, q); !errors.Is(err, domain.ErrInvalidQuery) {
+			t.Errorf("%s: expected ErrInvalidQuery, got %v", name, err)
+		}
+	}
+}
+
+func TestCreateBook_Metadata(t *testing.T) {
+	ctx := context.Background()
+	books := &fakeBooks{books: map[string]*domain.Book{}}
+	uc := NewBookUseCase(books, NewAuthorUseCase(newFakeAuthors(), books))
+
+	created, err := uc.CreateBook(ctx, &domain.Book{
+		Title: "The Hobbit",
+		ISBN:  "0-261-10221-4",
+		Contributors: []domain.Contributor{
+			{Name: "Alan Lee", Role: "Illustrator"},
+			{Name: "J. R. R. Tolkien"},
+		},
+		Format: "Paperback",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ISBN != "9780261102217" {
+		t.Errorf("expected ISBN-13, got %q", created.ISBN)
+	}
+	if created.Author != "J. R. R. Tolkien" || created.Contributors[1].Role != domain.RoleAuthor {
+		t.Errorf("first author not derived: %q %+v", created.Author, created.Contributors)
+	}
+
+	if got, err := uc.GetBookByISBN(ctx, "978-0-261-10221-7"); err != nil || got != created {
+		t.Errorf("lookup by hyphenated ISBN-13 failed: %v", err)
+	}
+	if _, err := uc.CreateBook(ctx, &domain.Book{Title: "Copy", ISBN: "9780261102217"}); !errors.Is(err, domain.ErrDuplicateISBN) {
+		t.Errorf("expected ErrDuplicateISBN, got %v", err)
+	}
+
+	legacy, err := uc.CreateBook(ctx, &domain.Book{Title: "Emma", Author: " Jane Austen "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := legacy.Contributors; len(c) != 1 || c[0].Name != "Jane Austen" || c[0].Role != domain.RoleAuthor {
+		t.Errorf("free-text author not turned into a contributor: %+v", legacy.Contributors)
+	}
+
+	for name, b := range map[string]*domain.Book{
+		"checksum":    {ISBN: "9780261102218"},
+		"isbn10 X":    {ISBN: "080442957Y"},
+		"format":      {Format: "scroll"},
+		"role":        {Contributors: []domain.Contributor{{Name: "X", Role: "ghostwriter"}}},
+		"series":      {SeriesPosition: 2},
+		"no name":     {Contributors: []domain.Contributor{{Role: "editor"}}},
+		"short isbn":  {ISBN: "12345"},
+		"letter isbn": {ISBN: "97802611022A7"},
+	} {
+		if _, err := uc.CreateBook(ctx, b); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+	if _, err := uc.CreateBook(ctx, &domain.Book{Title: "Ten", ISBN: "080442957X"}); err != nil {
+		t.Errorf("ISBN-10 with X check digit rejected: %v", err)
+	}
+}
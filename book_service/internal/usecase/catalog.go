@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/catalog"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/repository"
+)
+
+// CatalogUseCase loads and dumps the catalog in bulk.
+type CatalogUseCase interface {
+	// ImportBooks saves every record read from records. A record whose ISBN
+	// is already in the catalog updates only the fields it gives on that
+	// book, any other creates one; a given stock level is reached by
+	// reserving or releasing the difference, so that orders placed
+	// meanwhile are not lost. In a dry run the records are validated and
+	// nothing is saved. report, if not nil, is called for every row.
+	ImportBooks(ctx context.Context, records catalog.Reader, dryRun bool, report func(*domain.ImportResult)) (*domain.ImportSummary, error)
+	// ExportBooks writes the whole catalog and closes w.
+	ExportBooks(ctx context.Context, w catalog.Writer) (int, error)
+}
+
+type catalogUseCase struct {
+	books BookUseCase
+	repo  repository.BookRepository
+}
+
+func NewCatalogUseCase(books BookUseCase, repo repository.BookRepository) CatalogUseCase {
+	return &catalogUseCase{books: books, repo: repo}
+}
+
+func (u *catalogUseCase) ImportBooks(ctx context.Context, records catalog.Reader, dryRun bool, report func(*domain.ImportResult)) (*domain.ImportSummary, error) {
+	summary := &domain.ImportSummary{DryRun: dryRun}
+	// в пробном прогоне повтор ISBN в файле — это обновление, а не создание
+	seen := make(map[string]bool)
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		rec, err := records.Next()
+		if err == io.EOF {
+			return summary, nil
+		}
+		if err != nil {
+			return summary, err
+		}
+
+		res := u.importRecord(ctx, rec, dryRun, seen)
+		summary.Add(res)
+		if report != nil {
+			report(res)
+		}
+	}
+}
+
+func (u *catalogUseCase) importRecord(ctx context.Context, rec *catalog.Record, dryRun bool, seen map[string]bool) *domain.ImportResult {
+	res := &domain.ImportResult{Row: rec.Row, Action: domain.ImportFailed}
+	if rec.Err != nil {
+		res.Err = rec.Err
+		return res
+	}
+	book := rec.Book
+	res.ISBN = book.ISBN
+	if strings.TrimSpace(book.Title) == "" {
+		res.Err = domain.ErrTitleRequired
+		return res
+	}
+	if book.Price < 0 || book.Stock < 0 {
+		res.Err = domain.ErrNegativeValue
+		return res
+	}
+	if err := book.NormalizeMetadata(); err != nil {
+		res.Err = err
+		return res
+	}
+	res.ISBN = book.ISBN
+
+	res.Action = domain.ImportCreated
+	var existing *domain.Book
+	if book.ISBN != "" {
+		var err error
+		existing, err = u.books.GetBookByISBN(ctx, book.ISBN)
+		switch {
+		case err == nil:
+			book = merged(existing, rec)
+			res.Action = domain.ImportUpdated
+		case errors.Is(err, mongo.ErrNoDocuments):
+			if dryRun && seen[book.ISBN] {
+				res.Action = domain.ImportUpdated
+			}
+		default:
+			res.Action, res.Err = domain.ImportFailed, err
+			return res
+		}
+	}
+	if dryRun {
+		if book.ISBN != "" {
+			seen[book.ISBN] = true
+		}
+		res.Book = book
+		return res
+	}
+
+	var err error
+	if existing != nil {
+		stock := book.Stock
+		res.Book, err = u.books.UpdateBook(ctx, book)
+		if err == nil && stock != existing.Stock {
+			res.Book, err = u.adjustStock(ctx, existing, stock)
+		}
+	} else {
+		res.Book, err = u.books.CreateBook(ctx, book)
+	}
+	if err != nil {
+		res.Action, res.Book, res.Err = domain.ImportFailed, nil, err
+	}
+	return res
+}
+
+// merged is existing with the fields given by rec copied over it. Stock
+// is the level the record asks for, or the current one.
+func merged(existing *domain.Book, rec *catalog.Record) *domain.Book {
+	book := *existing
+	book.Contributors = append([]domain.Contributor(nil), existing.Contributors...)
+	rec.Merge(&book)
+	if rec.Has("stock") {
+		book.Stock = rec.Book.Stock
+	}
+	return &book
+}
+
+// adjustStock moves the stock of book from the level it was read at to
+// stock. UpdateBook does not write stock, and a plain overwrite would undo
+// the reservations made since the book was read.
+func (u *catalogUseCase) adjustStock(ctx context.Context, book *domain.Book, stock int) (*domain.Book, error) {
+	id := book.ID.Hex()
+	delta := stock - book.Stock
+	if delta > 0 {
+		return u.books.ReleaseStock(ctx, id, delta)
+	}
+	return u.books.ReserveStock(ctx, id, -delta)
+}
+
+func (u *catalogUseCase) ExportBooks(ctx context.Context, w catalog.Writer) (int, error) {
+	n := 0
+	err := u.repo.ForEach(ctx, func(b *domain.Book) error {
+		n++
+		return w.Write(b)
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, w.Close()
+}
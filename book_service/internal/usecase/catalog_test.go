@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/catalog"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+)
+
+func TestImportBooks(t *testing.T) {
+	ctx := context.Background()
+	books := &fakeBooks{books: map[string]*domain.Book{}}
+	bookUC := NewBookUseCase(books, NewAuthorUseCase(newFakeAuthors(), books))
+	uc := NewCatalogUseCase(bookUC, books)
+
+	existing, err := bookUC.CreateBook(ctx, &domain.Book{Title: "Emma", ISBN: "9780141439587", Author: "Jane Austen", Genre: "Classics", Stock: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := `{"isbn":"978-0-14-143958-7","title":"Emma","price":8}` + "\n" +
+		`{"isbn":"9780261102217","title":"The Hobbit","stock":3}` + "\n" +
+		`{"isbn":"9780261102217","title":"The Hobbit","edition":"Second edition"}` + "\n" +
+		`{"isbn":"9780261102218","title":"Bad checksum"}` + "\n" +
+		`{"author":"Nobody"}` + "\n" +
+		`{"isbn":"9780141439587","title":"Emma","stock":-2}` + "\n" +
+		`{"isbn":"9780141439587","title":"Emma","stock":5}` + "\n"
+	run := func(dryRun bool) (*domain.ImportSummary, []*domain.ImportResult) {
+		r, err := catalog.NewReader(catalog.FormatJSONL, strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rows []*domain.ImportResult
+		summary, err := uc.ImportBooks(ctx, r, dryRun, func(res *domain.ImportResult) { rows = append(rows, res) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return summary, rows
+	}
+
+	summary, rows := run(true)
+	if summary.Created != 1 || summary.Updated != 3 || summary.Failed != 3 || len(rows) != 7 {
+		t.Errorf("unexpected dry run summary %+v", summary)
+	}
+	if len(books.books) != 1 || existing.Price != 0 || existing.Stock != 7 {
+		t.Errorf("dry run saved books: %d in the catalog", len(books.books))
+	}
+
+	summary, rows = run(false)
+	if summary.Created != 1 || summary.Updated != 3 || summary.Failed != 3 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if len(summary.Errors) != 3 || summary.Errors[0].Row != 4 || summary.Errors[1].Err != domain.ErrTitleRequired ||
+		summary.Errors[2].Err != domain.ErrNegativeValue {
+		t.Errorf("unexpected row errors %+v", summary.Errors)
+	}
+	if len(books.books) != 2 {
+		t.Fatalf("expected 2 books in the catalog, got %d", len(books.books))
+	}
+
+	emma, _ := books.GetByISBN(ctx, "9780141439587")
+	if emma.ID != existing.ID || emma.Price != 8 || emma.Author != "Jane Austen" || emma.Genre != "Classics" {
+		t.Errorf("Emma not upserted in place, or fields missing from the file were blanked: %+v", emma)
+	}
+	if emma.Stock != 5 || books.reserved != 2 {
+		t.Errorf("stock not moved to the imported level by reserving the difference: %d, reserved %d", emma.Stock, books.reserved)
+	}
+	hobbit, _ := books.GetByISBN(ctx, "9780261102217")
+	if hobbit.Edition != "Second edition" || hobbit.Stock != 3 || rows[2].Action != domain.ImportUpdated {
+		t.Errorf("repeated ISBN not applied as an update: %+v", hobbit)
+	}
+}
+
+func (f *fakeBooks) ReserveStock(ctx context.Context, id string, n int) (*domain.Book, error) {
+	b, ok := f.books[id]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	if b.Stock < n {
+		return nil, domain.ErrInsufficientStock
+	}
+	b.Stock -= n
+	f.reserved += n
+	return b, nil
+}
+
+func (f *fakeBooks) ReleaseStock(ctx context.Context, id string, n int) (*domain.Book, error) {
+	b, ok := f.books[id]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	b.Stock += n
+	return b, nil
+}
@@ -0,0 +1,433 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/cache"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/repository"
+	orderpb "github.com/OshakbayAigerim/read_space/order_service/proto"
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+)
+
+const (
+	DefaultRecommendations = 10
+	MaxRecommendations     = 50
+
+	// maxHistory caps how many of a reader's books seed their
+	// recommendations, and maxNeighbours how many other readers are
+	// consulted for the collaborative part. Both bound the calls to the
+	// library and order services.
+	maxHistory    = 10
+	maxNeighbours = 20
+	// maxCandidates caps the books scored for one recommendation.
+	maxCandidates = 500
+
+	cacheTTL     = 30 * time.Minute
+	userCacheTTL = 5 * time.Minute
+
+	contentWeight = 0.6
+	peopleWeight  = 0.4
+	ratingWeight  = 0.05
+)
+
+// Statuses of order_service's domain that count as a purchase. Pending
+// orders were never paid for; cancelled and returned ones were backed out
+// of.
+const (
+	orderPaid      = "Paid"
+	orderShipped   = "Shipped"
+	orderDelivered = "Delivered"
+)
+
+// RecommendUseCase suggests books by combining what a book is about
+// (author, genre, language, description) with what other readers own or
+// ordered alongside it.
+type RecommendUseCase interface {
+	RecommendForBook(ctx context.Context, bookID string, limit int) ([]*domain.Book, error)
+	RecommendForUser(ctx context.Context, userID string, limit int) ([]*domain.Book, error)
+}
+
+type recommendUseCase struct {
+	books   repository.BookRepository
+	library userlibpb.UserLibraryServiceClient
+	orders  orderpb.OrderServiceClient
+	cache   cache.BookCache
+}
+
+// NewRecommendUseCase builds the recommender. The cache may be nil.
+func NewRecommendUseCase(books repository.BookRepository, library userlibpb.UserLibraryServiceClient, orders orderpb.OrderServiceClient, c cache.BookCache) RecommendUseCase {
+	return &recommendUseCase{
+		books:   books,
+		library: library,
+		orders:  orders,
+		cache:   c,
+	}
+}
+
+// RecommendForBook returns books similar to bookID. Collaborative signals
+// are best effort: if the library or order service is down the result is
+// based on content alone.
+func (u *recommendUseCase) RecommendForBook(ctx context.Context, bookID string, limit int) ([]*domain.Book, error) {
+	limit = clamp(limit, DefaultRecommendations, MaxRecommendations)
+	key := fmt.Sprintf("books:recommend:%s:%d", bookID, limit)
+	if books := u.cached(ctx, key); books != nil {
+		return books, nil
+	}
+
+	seed, err := u.books.GetByID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	co := u.coOccurrence(ctx, u.readersOf(ctx, bookID))
+	catalog, err := u.candidates(ctx, []*domain.Book{seed}, co)
+	if err != nil {
+		return nil, err
+	}
+
+	seedTerms := terms(seed.Description)
+	exclude := map[string]bool{bookID: true}
+	res := rank(catalog, exclude, co, limit, func(b *domain.Book) float64 {
+		return similarity(seed, seedTerms, b)
+	})
+
+	u.store(ctx, key, res, cacheTTL)
+	return res, nil
+}
+
+// RecommendForUser returns books for a reader based on their library and
+// order history. Books the reader already owns or has ordered are never
+// suggested; only the first maxHistory of them seed the recommendation. A
+// reader without history gets the best rated books.
+func (u *recommendUseCase) RecommendForUser(ctx context.Context, userID string, limit int) ([]*domain.Book, error) {
+	limit = clamp(limit, DefaultRecommendations, MaxRecommendations)
+	key := fmt.Sprintf("books:recommend:user:%s:%d", userID, limit)
+	if books := u.cached(ctx, key); books != nil {
+		return books, nil
+	}
+
+	owned, err := u.libraryBooks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load library of user %s: %w", userID, err)
+	}
+	ordered, err := u.orderedBooks(ctx, userID)
+	if err != nil {
+		// без заказов рекомендации всё равно имеют смысл
+		log.Printf("⚠ recommendations for %s without order history: %v", userID, err)
+	}
+	history := make(map[string]bool, len(owned)+len(ordered))
+	var seedIDs []string
+	for _, id := range append(owned, ordered...) {
+		if !history[id] {
+			history[id] = true
+			seedIDs = append(seedIDs, id)
+		}
+	}
+	if len(seedIDs) > maxHistory {
+		seedIDs = seedIDs[:maxHistory]
+	}
+
+	seeds, err := u.books.ListRelated(ctx, nil, nil, seedIDs, maxHistory)
+	if err != nil {
+		return nil, err
+	}
+	type profileBook struct {
+		book  *domain.Book
+		terms map[string]bool
+	}
+	profile := make([]profileBook, 0, len(seeds))
+	for _, b := range seeds {
+		profile = append(profile, profileBook{book: b, terms: terms(b.Description)})
+	}
+
+	seen := map[string]bool{userID: true}
+	var neighbours []string
+	for _, id := range seedIDs {
+		if len(neighbours) >= maxNeighbours {
+			break
+		}
+		for _, reader := range u.readersOf(ctx, id) {
+			if !seen[reader] && len(neighbours) < maxNeighbours {
+				seen[reader] = true
+				neighbours = append(neighbours, reader)
+			}
+		}
+	}
+	co := u.coOccurrence(ctx, neighbours)
+
+	catalog, err := u.candidates(ctx, seeds, co)
+	if err != nil {
+		return nil, err
+	}
+	res := rank(catalog, history, co, limit, func(b *domain.Book) float64 {
+		best := 0.0
+		for _, p := range profile {
+			if s := similarity(p.book, p.terms, b); s > best {
+				best = s
+			}
+		}
+		return best
+	})
+
+	u.store(ctx, key, res, userCacheTTL)
+	return res, nil
+}
+
+// candidates loads the books worth scoring: those sharing an author or a
+// genre with the seeds, those the neighbours own or ordered most, and the
+// best rated ones, which are all a reader without history gets.
+func (u *recommendUseCase) candidates(ctx context.Context, seeds []*domain.Book, co map[string]int) ([]*domain.Book, error) {
+	var authors, genres []string
+	for _, b := range seeds {
+		if a := strings.TrimSpace(b.Author); a != "" {
+			authors = append(authors, a)
+		}
+		if g := strings.TrimSpace(b.Genre); g != "" {
+			genres = append(genres, g)
+		}
+	}
+	ids := make([]string, 0, len(co))
+	for id := range co {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if co[ids[i]] != co[ids[j]] {
+			return co[ids[i]] > co[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > maxCandidates {
+		ids = ids[:maxCandidates]
+	}
+
+	related, err := u.books.ListRelated(ctx, authors, genres, ids, maxCandidates)
+	if err != nil {
+		return nil, err
+	}
+	top, err := u.books.ListTopRated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[primitive.ObjectID]bool, len(related)+len(top))
+	res := make([]*domain.Book, 0, len(related)+len(top))
+	for _, b := range append(related, top...) {
+		if !seen[b.ID] {
+			seen[b.ID] = true
+			res = append(res, b)
+		}
+	}
+	return res, nil
+}
+
+func (u *recommendUseCase) cached(ctx context.Context, key string) []*domain.Book {
+	if u.cache == nil {
+		return nil
+	}
+	books, err := u.cache.GetList(ctx, key)
+	if err != nil {
+		return nil
+	}
+	return books
+}
+
+func (u *recommendUseCase) store(ctx context.Context, key string, books []*domain.Book, ttl time.Duration) {
+	if u.cache != nil {
+		u.cache.SetList(ctx, key, books, ttl)
+	}
+}
+
+// readersOf lists the users who own the book or have ordered it.
+func (u *recommendUseCase) readersOf(ctx context.Context, bookID string) []string {
+	seen := make(map[string]bool)
+	var readers []string
+	add := func(id string) {
+		if id != "" && !seen[id] && len(readers) < maxNeighbours {
+			seen[id] = true
+			readers = append(readers, id)
+		}
+	}
+
+	if resp, err := u.library.ListByBook(ctx, &userlibpb.ListByBookRequest{BookId: bookID}); err != nil {
+		log.Printf("⚠ cannot list owners of book %s: %v", bookID, err)
+	} else {
+		for _, e := range resp.Entries {
+			add(e.UserId)
+		}
+	}
+
+	if resp, err := u.orders.ListOrdersByBook(ctx, &orderpb.ListOrdersByBookRequest{BookId: bookID}); err != nil {
+		log.Printf("⚠ cannot list orders of book %s: %v", bookID, err)
+	} else {
+		for _, o := range resp.Orders {
+			if countsAsPurchase(o) {
+				add(o.UserId)
+			}
+		}
+	}
+	return readers
+}
+
+// coOccurrence counts, for every book, how many of the neighbours own or
+// ordered it.
+func (u *recommendUseCase) coOccurrence(ctx context.Context, neighbours []string) map[string]int {
+	co := make(map[string]int)
+	for _, n := range neighbours {
+		owned, err := u.libraryBooks(ctx, n)
+		if err != nil {
+			log.Printf("⚠ cannot load library of user %s: %v", n, err)
+		}
+		ordered, err := u.orderedBooks(ctx, n)
+		if err != nil {
+			log.Printf("⚠ cannot load orders of user %s: %v", n, err)
+		}
+		books := make(map[string]bool)
+		for _, id := range append(owned, ordered...) {
+			books[id] = true
+		}
+		for id := range books {
+			co[id]++
+		}
+	}
+	return co
+}
+
+func (u *recommendUseCase) libraryBooks(ctx context.Context, userID string) ([]string, error) {
+	resp, err := u.library.ListUserBooks(ctx, &userlibpb.ListUserBooksRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(resp.Entries))
+	for _, e := range resp.Entries {
+		ids = append(ids, e.BookId)
+	}
+	return ids, nil
+}
+
+func (u *recommendUseCase) orderedBooks(ctx context.Context, userID string) ([]string, error) {
+	resp, err := u.orders.ListOrdersByUser(ctx, &orderpb.ListOrdersByUserRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, o := range resp.Orders {
+		if !countsAsPurchase(o) {
+			continue
+		}
+		for _, it := range o.Items {
+			ids = append(ids, it.BookId)
+		}
+	}
+	return ids, nil
+}
+
+// countsAsPurchase tells whether the order was paid for and not backed out
+// of.
+func countsAsPurchase(o *orderpb.Order) bool {
+	switch o.Status {
+	case orderPaid, orderShipped, orderDelivered:
+		return true
+	}
+	return false
+}
+
+// rank scores every catalog book that is not excluded and returns the best
+// ones. Content and co-ownership dominate; the rating only breaks ties and
+// orders the rest of the catalog when there is no signal at all.
+func rank(catalog []*domain.Book, exclude map[string]bool, co map[string]int, limit int, content func(*domain.Book) float64) []*domain.Book {
+	maxCo := 0
+	for _, n := range co {
+		if n > maxCo {
+			maxCo = n
+		}
+	}
+
+	type scored struct {
+		book  *domain.Book
+		score float64
+	}
+	var candidates []scored
+	for _, b := range catalog {
+		id := b.ID.Hex()
+		if exclude[id] {
+			continue
+		}
+		s := contentWeight*content(b) + ratingWeight*float64(b.Rating)/domain.MaxRating
+		if maxCo > 0 {
+			s += peopleWeight * float64(co[id]) / float64(maxCo)
+		}
+		candidates = append(candidates, scored{book: b, score: s})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].book.Title < candidates[j].book.Title
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	res := make([]*domain.Book, 0, len(candidates))
+	for _, c := range candidates {
+		res = append(res, c.book)
+	}
+	return res
+}
+
+// similarity scores how alike two books are, from 0 to 1. seedTerms are the
+// description terms of seed, computed once by the caller.
+func similarity(seed *domain.Book, seedTerms map[string]bool, b *domain.Book) float64 {
+	s := 0.0
+	if sameField(seed.Author, b.Author) {
+		s += 0.35
+	}
+	if sameField(seed.Genre, b.Genre) {
+		s += 0.25
+	}
+	if sameField(seed.Language, b.Language) {
+		s += 0.1
+	}
+	return s + 0.3*jaccard(seedTerms, terms(b.Description))
+}
+
+func sameField(a, b string) bool {
+	a = strings.TrimSpace(a)
+	return a != "" && strings.EqualFold(a, strings.TrimSpace(b))
+}
+
+// terms splits a description into lower-cased words, ignoring short ones
+// which are mostly articles and prepositions.
+func terms(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		if len([]rune(w)) > 3 {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for w := range a {
+		if b[w] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	orderpb "github.com/OshakbayAigerim/read_space/order_service/proto"
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+)
+
+func (f *fakeBooks) ListRelated(ctx context.Context, authors, genres, ids []string, limit int) ([]*domain.Book, error) {
+	in := func(list []string, v string) bool {
+		for _, s := range list {
+			if s == v {
+				return true
+			}
+		}
+		return false
+	}
+	var res []*domain.Book
+	for _, b := range f.books {
+		if in(authors, b.Author) || in(genres, b.Genre) || in(ids, b.ID.Hex()) {
+			res = append(res, b)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Rating > res[j].Rating })
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (f *fakeBooks) ListTopRated(ctx context.Context) ([]*domain.Book, error) {
+	var res []*domain.Book
+	for _, b := range f.books {
+		if b.Rating > 0 {
+			res = append(res, b)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Rating > res[j].Rating })
+	return res, nil
+}
+
+type fakeLibrary struct {
+	userlibpb.UserLibraryServiceClient
+	owned map[string][]string // user → books
+}
+
+func (f *fakeLibrary) ListUserBooks(ctx context.Context, in *userlibpb.ListUserBooksRequest, _ ...grpc.CallOption) (*userlibpb.ListUserBooksResponse, error) {
+	resp := &userlibpb.ListUserBooksResponse{}
+	for _, b := range f.owned[in.UserId] {
+		resp.Entries = append(resp.Entries, &userlibpb.UserBook{UserId: in.UserId, BookId: b})
+	}
+	return resp, nil
+}
+
+func (f *fakeLibrary) ListByBook(ctx context.Context, in *userlibpb.ListByBookRequest, _ ...grpc.CallOption) (*userlibpb.ListUserBooksResponse, error) {
+	resp := &userlibpb.ListUserBooksResponse{}
+	for user, books := range f.owned {
+		for _, b := range books {
+			if b == in.BookId {
+				resp.Entries = append(resp.Entries, &userlibpb.UserBook{UserId: user, BookId: b})
+			}
+		}
+	}
+	return resp, nil
+}
+
+// downOrders simulates an unavailable order service.
+type downOrders struct {
+	orderpb.OrderServiceClient
+}
+
+func (downOrders) ListOrdersByUser(ctx context.Context, in *orderpb.ListOrdersByUserRequest, _ ...grpc.CallOption) (*orderpb.OrderList, error) {
+	return nil, status.Error(codes.Unavailable, "down")
+}
+
+func (downOrders) ListOrdersByBook(ctx context.Context, in *orderpb.ListOrdersByBookRequest, _ ...grpc.CallOption) (*orderpb.OrderList, error) {
+	return nil, status.Error(codes.Unavailable, "down")
+}
+
+// fakeOrders serves the orders of each user.
+type fakeOrders struct {
+	orderpb.OrderServiceClient
+	byUser map[string][]*orderpb.Order
+}
+
+func (f fakeOrders) ListOrdersByUser(ctx context.Context, in *orderpb.ListOrdersByUserRequest, _ ...grpc.CallOption) (*orderpb.OrderList, error) {
+	return &orderpb.OrderList{Orders: f.byUser[in.UserId]}, nil
+}
+
+func (f fakeOrders) ListOrdersByBook(ctx context.Context, in *orderpb.ListOrdersByBookRequest, _ ...grpc.CallOption) (*orderpb.OrderList, error) {
+	return &orderpb.OrderList{}, nil
+}
+
+func newCatalog(books ...*domain.Book) *fakeBooks {
+	f := &fakeBooks{books: map[string]*domain.Book{}}
+	for _, b := range books {
+		b.ID = primitive.NewObjectID()
+		f.books[b.ID.Hex()] = b
+	}
+	return f
+}
+
+func TestRecommendForBook_Content(t *testing.T) {
+	dune := &domain.Book{Title: "Dune", Author: "Frank Herbert", Genre: "Sci-Fi", Description: "Desert planet spice empire"}
+	messiah := &domain.Book{Title: "Dune Messiah", Author: "Frank Herbert", Genre: "Sci-Fi", Description: "Emperor of the desert planet"}
+	foundation := &domain.Book{Title: "Foundation", Author: "Isaac Asimov", Genre: "Sci-Fi", Description: "Galactic empire falls"}
+	emma := &domain.Book{Title: "Emma", Author: "Jane Austen", Genre: "Romance", Rating: 5}
+	books := newCatalog(dune, messiah, foundation, emma)
+
+	uc := NewRecommendUseCase(books, &fakeLibrary{}, downOrders{}, nil)
+	got, err := uc.RecommendForBook(context.Background(), dune.ID.Hex(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != messiah || got[1] != foundation {
+		t.Errorf("expected Dune Messiah then Foundation, got %v", titles(got))
+	}
+}
+
+func TestRecommendForUser_ExcludesOwnedAndUsesCoOwnership(t *testing.T) {
+	a := &domain.Book{Title: "A", Genre: "Poetry"}
+	b := &domain.Book{Title: "B", Genre: "Cooking"}
+	c := &domain.Book{Title: "C", Genre: "History", Rating: 5}
+	books := newCatalog(a, b, c)
+
+	library := &fakeLibrary{owned: map[string][]string{
+		"reader":   {a.ID.Hex()},
+		"neighbor": {a.ID.Hex(), b.ID.Hex()},
+	}}
+	uc := NewRecommendUseCase(books, library, downOrders{}, nil)
+
+	got, err := uc.RecommendForUser(context.Background(), "reader", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != b || got[1] != c {
+		t.Errorf("expected co-owned B before top-rated C and no owned A, got %v", titles(got))
+	}
+
+	// без истории — просто лучшие по рейтингу
+	got, err = uc.RecommendForUser(context.Background(), "newcomer", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != c {
+		t.Errorf("expected top-rated C for a newcomer, got %v", titles(got))
+	}
+}
+
+func TestRecommendForUser_CountsOnlyPaidOrders(t *testing.T) {
+	a := &domain.Book{Title: "A", Rating: 4}
+	b := &domain.Book{Title: "B", Rating: 3}
+	books := newCatalog(a, b)
+
+	orders := fakeOrders{byUser: map[string][]*orderpb.Order{"reader": {
+		{UserId: "reader", Status: orderDelivered, Items: []*orderpb.LineItem{{BookId: a.ID.Hex()}}},
+		{UserId: "reader", Status: "Pending", Items: []*orderpb.LineItem{{BookId: b.ID.Hex()}}},
+	}}}
+	uc := NewRecommendUseCase(books, &fakeLibrary{}, orders, nil)
+
+	got, err := uc.RecommendForUser(context.Background(), "reader", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// неоплаченный заказ — ещё не покупка
+	if len(got) != 1 || got[0] != b {
+		t.Errorf("expected only B, ordered but not paid for, got %v", titles(got))
+	}
+}
+
+func titles(books []*domain.Book) []string {
+	var res []string
+	for _, b := range books {
+		res = append(res, b.Title)
+	}
+	return res
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/repository"
+)
+
+// ReviewUseCase manages reader reviews and keeps each book's aggregate
+// rating in step with them.
+type ReviewUseCase interface {
+	SubmitReview(ctx context.Context, review *domain.Review) (*domain.Review, error)
+	UpdateReview(ctx context.Context, id string, rating int, text string) (*domain.Review, error)
+	DeleteReview(ctx context.Context, id string) (*domain.Review, error)
+	GetReview(ctx context.Context, id string) (*domain.Review, error)
+	ListBookReviews(ctx context.Context, bookID string) ([]*domain.Review, error)
+	ListUserReviews(ctx context.Context, userID string) ([]*domain.Review, error)
+
+	RemoveBookReviews(ctx context.Context, bookID string) error
+	RemoveUserReviews(ctx context.Context, userID string) (int, error)
+}
+
+type reviewUseCase struct {
+	reviews repository.ReviewRepository
+	books   repository.BookRepository
+}
+
+func NewReviewUseCase(reviews repository.ReviewRepository, books repository.BookRepository) ReviewUseCase {
+	return &reviewUseCase{reviews: reviews, books: books}
+}
+
+// SubmitReview adds the user's review of a book that exists in the catalog.
+func (u *reviewUseCase) SubmitReview(ctx context.Context, review *domain.Review) (*domain.Review, error) {
+	review.Text = strings.TrimSpace(review.Text)
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := u.books.GetByID(ctx, review.BookID.Hex()); err != nil {
+		return nil, err
+	}
+	review.ID = primitive.NewObjectID()
+	created, err := u.reviews.Create(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.refreshRating(ctx, created.BookID.Hex()); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (u *reviewUseCase) UpdateReview(ctx context.Context, id string, rating int, text string) (*domain.Review, error) {
+	review, err := u.reviews.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	review.Rating = rating
+	review.Text = strings.TrimSpace(text)
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+	updated, err := u.reviews.Update(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.refreshRating(ctx, updated.BookID.Hex()); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteReview removes a review and returns it.
+func (u *reviewUseCase) DeleteReview(ctx context.Context, id string) (*domain.Review, error) {
+	review, err := u.reviews.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.reviews.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := u.refreshRating(ctx, review.BookID.Hex()); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (u *reviewUseCase) GetReview(ctx context.Context, id string) (*domain.Review, error) {
+	return u.reviews.GetByID(ctx, id)
+}
+
+func (u *reviewUseCase) ListBookReviews(ctx context.Context, bookID string) ([]*domain.Review, error) {
+	return u.reviews.ListByBook(ctx, bookID)
+}
+
+func (u *reviewUseCase) ListUserReviews(ctx context.Context, userID string) ([]*domain.Review, error) {
+	return u.reviews.ListByUser(ctx, userID)
+}
+
+// RemoveBookReviews drops the reviews of a deleted book.
+func (u *reviewUseCase) RemoveBookReviews(ctx context.Context, bookID string) error {
+	return u.reviews.DeleteByBook(ctx, bookID)
+}
+
+// RemoveUserReviews drops the reviews of a deleted user and recomputes the
+// ratings of the books they reviewed. It returns how many books changed.
+func (u *reviewUseCase) RemoveUserReviews(ctx context.Context, userID string) (int, error) {
+	books, err := u.reviews.DeleteByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	for i, bookID := range books {
+		if err := u.refreshRating(ctx, bookID); err != nil {
+			return i, err
+		}
+	}
+	return len(books), nil
+}
+
+// ratingAttempts bounds how often refreshRating recomputes a rating that
+// keeps being changed by concurrent reviews.
+const ratingAttempts = 5
+
+// refreshRating recomputes the book's aggregate rating from its reviews.
+// Concurrent refreshes may read the reviews in one order and store their
+// results in another, so the rating is only stored if nobody stored one
+// since it was read; otherwise the reviews are summarized again.
+func (u *reviewUseCase) refreshRating(ctx context.Context, bookID string) error {
+	var err error
+	for i := 0; i < ratingAttempts; i++ {
+		var version int
+		if version, err = u.books.RatingVersion(ctx, bookID); err != nil {
+			return err
+		}
+		var summary domain.RatingSummary
+		if summary, err = u.reviews.Summarize(ctx, bookID); err != nil {
+			return err
+		}
+		if _, err = u.books.SetRating(ctx, bookID, summary, version); !errors.Is(err, domain.ErrRatingChanged) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/repository"
+)
+
+type fakeBooks struct {
+	repository.BookRepository
+	books    map[string]*domain.Book
+	versions map[string]int
+	// beforeSetRating runs once, between reading the rating version and
+	// storing the rating
+	beforeSetRating func()
+	// reserved counts the copies taken by ReserveStock
+	reserved int
+}
+
+func (f *fakeBooks) GetByID(ctx context.Context, id string) (*domain.Book, error) {
+	b, ok := f.books[id]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	return b, nil
+}
+func (f *fakeBooks) RatingVersion(ctx context.Context, id string) (int, error) {
+	if _, ok := f.books[id]; !ok {
+		return 0, mongo.ErrNoDocuments
+	}
+	return f.versions[id], nil
+}
+func (f *fakeBooks) SetRating(ctx context.Context, id string, s domain.RatingSummary, version int) (*domain.Book, error) {
+	if hook := f.beforeSetRating; hook != nil {
+		f.beforeSetRating = nil
+		hook()
+	}
+	b, ok := f.books[id]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	if f.versions[id] != version {
+		return nil, domain.ErrRatingChanged
+	}
+	if f.versions == nil {
+		f.versions = map[string]int{}
+	}
+	f.versions[id]++
+	b.Rating, b.RatingCount = s.Average, s.Count
+	return b, nil
+}
+
+type fakeReviews struct {
+	repository.ReviewRepository
+	reviews map[string]*domain.Review
+}
+
+func (f *fakeReviews) Create(ctx context.Context, r *domain.Review) (*domain.Review, error) {
+	for _, other := range f.reviews {
+		if other.BookID == r.BookID && other.UserID == r.UserID {
+			return nil, domain.ErrAlreadyReviewed
+		}
+	}
+	cp := *r
+	f.reviews[r.ID.Hex()] = &cp
+	return r, nil
+}
+func (f *fakeReviews) GetByID(ctx context.Context, id string) (*domain.Review, error) {
+	r, ok := f.reviews[id]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	cp := *r
+	return &cp, nil
+}
+func (f *fakeReviews) Update(ctx context.Context, r *domain.Review) (*domain.Review, error) {
+	cp := *r
+	f.reviews[r.ID.Hex()] = &cp
+	return r, nil
+}
+func (f *fakeReviews) Delete(ctx context.Context, id string) error {
+	delete(f.reviews, id)
+	return nil
+}
+func (f *fakeReviews) Summarize(ctx context.Context, bookID string) (domain.RatingSummary, error) {
+	var sum domain.RatingSummary
+	total := 0
+	for _, r := range f.reviews {
+		if r.BookID.Hex() == bookID {
+			sum.Count++
+			total += r.Rating
+		}
+	}
+	if sum.Count > 0 {
+		sum.Average = float32(total) / float32(sum.Count)
+	}
+	return sum, nil
+}
+func (f *fakeReviews) DeleteByUser(ctx context.Context, userID string) ([]string, error) {
+	var books []string
+	for id, r := range f.reviews {
+		if r.UserID.Hex() == userID {
+			books = append(books, r.BookID.Hex())
+			delete(f.reviews, id)
+		}
+	}
+	return books, nil
+}
+
+func TestReviewsDriveBookRating(t *testing.T) {
+	ctx := context.Background()
+	book := &domain.Book{ID: primitive.NewObjectID(), Title: "Dune"}
+	books := &fakeBooks{books: map[string]*domain.Book{book.ID.Hex(): book}}
+	reviews := &fakeReviews{reviews: map[string]*domain.Review{}}
+	uc := NewReviewUseCase(reviews, books)
+
+	alice, bob := primitive.NewObjectID(), primitive.NewObjectID()
+	first, err := uc.SubmitReview(ctx, &domain.Review{BookID: book.ID, UserID: alice, Rating: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.SubmitReview(ctx, &domain.Review{BookID: book.ID, UserID: bob, Rating: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if book.Rating != 3.5 || book.RatingCount != 2 {
+		t.Fatalf("expected 3.5 from 2 reviews, got %v from %d", book.Rating, book.RatingCount)
+	}
+
+	if _, err := uc.SubmitReview(ctx, &domain.Review{BookID: book.ID, UserID: alice, Rating: 1}); !errors.Is(err, domain.ErrAlreadyReviewed) {
+		t.Errorf("expected ErrAlreadyReviewed, got %v", err)
+	}
+	if _, err := uc.UpdateReview(ctx, first.ID.Hex(), 6, ""); !errors.Is(err, domain.ErrInvalidRating) {
+		t.Errorf("expected ErrInvalidRating, got %v", err)
+	}
+
+	if _, err := uc.UpdateReview(ctx, first.ID.Hex(), 4, "перечитала"); err != nil {
+		t.Fatal(err)
+	}
+	if book.Rating != 3 {
+		t.Errorf("rating not recomputed after update: %v", book.Rating)
+	}
+
+	if _, err := uc.RemoveUserReviews(ctx, bob.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if book.Rating != 4 || book.RatingCount != 1 {
+		t.Errorf("expected 4 from 1 review after user removal, got %v from %d", book.Rating, book.RatingCount)
+	}
+
+	if _, err := uc.DeleteReview(ctx, first.ID.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if book.Rating != 0 || book.RatingCount != 0 {
+		t.Errorf("expected no rating without reviews, got %v from %d", book.Rating, book.RatingCount)
+	}
+}
+
+func TestRefreshRating_ConcurrentReview(t *testing.T) {
+	ctx := context.Background()
+	book := &domain.Book{ID: primitive.NewObjectID(), Title: "Dune"}
+	books := &fakeBooks{books: map[string]*domain.Book{book.ID.Hex(): book}}
+	reviews := &fakeReviews{reviews: map[string]*domain.Review{}}
+	uc := NewReviewUseCase(reviews, books)
+
+	// второй отзыв успевает пересчитать рейтинг, пока первый его сохраняет
+	books.beforeSetRating = func() {
+		if _, err := uc.SubmitReview(ctx, &domain.Review{BookID: book.ID, UserID: primitive.NewObjectID(), Rating: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := uc.SubmitReview(ctx, &domain.Review{BookID: book.ID, UserID: primitive.NewObjectID(), Rating: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if book.Rating != 3.5 || book.RatingCount != 2 {
+		t.Errorf("stale rating stored: %v from %d", book.Rating, book.RatingCount)
+	}
+}
+
+func TestSubmitReview_UnknownBook(t *testing.T) {
+	uc := NewReviewUseCase(&fakeReviews{reviews: map[string]*domain.Review{}}, &fakeBooks{books: map[string]*domain.Book{}})
+	_, err := uc.SubmitReview(context.Background(), &domain.Review{
+		BookID: primitive.NewObjectID(),
+		UserID: primitive.NewObjectID(),
+		Rating: 3,
+	})
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments, got %v", err)
+	}
+}
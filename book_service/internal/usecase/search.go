@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/book_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/repository"
+	"github.com/OshakbayAigerim/read_space/book_service/internal/search"
+)
+
+const (
+	DefaultSearchResults = 20
+	MaxSearchResults     = 100
+	DefaultSuggestions   = 8
+	MaxSuggestions       = 20
+)
+
+// SearchUseCase answers full-text queries from the in-process index and
+// keeps the index in step with the catalog.
+type SearchUseCase interface {
+	Search(ctx context.Context, query string, limit int) ([]*domain.SearchHit, error)
+	SuggestTitles(ctx context.Context, prefix string, limit int) ([]domain.TitleSuggestion, error)
+
+	// Reindex rebuilds the index from the whole catalog.
+	Reindex(ctx context.Context) (int, error)
+	// Refresh re-reads one book after it was created or changed.
+	Refresh(ctx context.Context, bookID string) error
+	Forget(bookID string)
+}
+
+type searchUseCase struct {
+	repo  repository.BookRepository
+	index *search.Index
+}
+
+func NewSearchUseCase(repo repository.BookRepository, index *search.Index) SearchUseCase {
+	return &searchUseCase{repo: repo, index: index}
+}
+
+// Search returns the best matching books. The index only holds text, so
+// the books themselves are read through the repository, which keeps price
+// and stock current.
+func (u *searchUseCase) Search(ctx context.Context, query string, limit int) ([]*domain.SearchHit, error) {
+	hits := u.index.Search(query, clamp(limit, DefaultSearchResults, MaxSearchResults))
+	res := make([]*domain.SearchHit, 0, len(hits))
+	for _, h := range hits {
+		book, err := u.repo.GetByID(ctx, h.ID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// событие об удалении ещё не дошло
+			u.index.Remove(h.ID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &domain.SearchHit{Book: book, Score: h.Score, Highlights: h.Highlights})
+	}
+	return res, nil
+}
+
+func (u *searchUseCase) SuggestTitles(ctx context.Context, prefix string, limit int) ([]domain.TitleSuggestion, error) {
+	return u.index.Suggest(prefix, clamp(limit, DefaultSuggestions, MaxSuggestions)), nil
+}
+
+func (u *searchUseCase) Reindex(ctx context.Context) (int, error) {
+	books, err := u.repo.ListAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range books {
+		u.index.Add(b)
+	}
+	return len(books), nil
+}
+
+func (u *searchUseCase) Refresh(ctx context.Context, bookID string) error {
+	book, err := u.repo.GetByID(ctx, bookID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		u.index.Remove(bookID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	u.index.Add(book)
+	return nil
+}
+
+func (u *searchUseCase) Forget(bookID string) {
+	u.index.Remove(bookID)
+}
+
+// clamp applies the default to an unset limit and caps it at most.
+func clamp(limit, def, most int) int {
+	switch {
+	case limit <= 0:
+		return def
+	case limit > most:
+		return most
+	default:
+		return limit
+	}
+}
@@ -21,7 +21,6 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Модель книги
 type Book struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -34,6 +33,18 @@ type Book struct {
 	Price         float32                `protobuf:"fixed32,8,opt,name=price,proto3" json:"price,omitempty"`
 	Pages         int32                  `protobuf:"varint,9,opt,name=pages,proto3" json:"pages,omitempty"`
 	PublishedDate string                 `protobuf:"bytes,10,opt,name=published_date,json=publishedDate,proto3" json:"published_date,omitempty"`
+	Stock         int32                  `protobuf:"varint,11,opt,name=stock,proto3" json:"stock,omitempty"`
+	RatingCount   int32                  `protobuf:"varint,12,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	// isbn accepts ISBN-10 or ISBN-13 and is returned as ISBN-13.
+	Isbn string `protobuf:"bytes,13,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	// author is the first author; contributors lists everyone credited.
+	Contributors   []*Contributor `protobuf:"bytes,14,rep,name=contributors,proto3" json:"contributors,omitempty"`
+	Publisher      string         `protobuf:"bytes,15,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Series         string         `protobuf:"bytes,16,opt,name=series,proto3" json:"series,omitempty"`
+	SeriesPosition int32          `protobuf:"varint,17,opt,name=series_position,json=seriesPosition,proto3" json:"series_position,omitempty"`
+	Edition        string         `protobuf:"bytes,18,opt,name=edition,proto3" json:"edition,omitempty"`
+	// format is hardcover, paperback, ebook or audiobook.
+	Format        string `protobuf:"bytes,19,opt,name=format,proto3" json:"format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -138,7 +149,132 @@ func (x *Book) GetPublishedDate() string {
 	return ""
 }
 
-// Общие сообщения
+func (x *Book) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *Book) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+func (x *Book) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *Book) GetContributors() []*Contributor {
+	if x != nil {
+		return x.Contributors
+	}
+	return nil
+}
+
+func (x *Book) GetPublisher() string {
+	if x != nil {
+		return x.Publisher
+	}
+	return ""
+}
+
+func (x *Book) GetSeries() string {
+	if x != nil {
+		return x.Series
+	}
+	return ""
+}
+
+func (x *Book) GetSeriesPosition() int32 {
+	if x != nil {
+		return x.SeriesPosition
+	}
+	return 0
+}
+
+func (x *Book) GetEdition() string {
+	if x != nil {
+		return x.Edition
+	}
+	return ""
+}
+
+func (x *Book) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// role is author, editor, translator, illustrator or narrator.
+// A contributor may be given by author_id alone; books returned by the
+// service always carry both the author_id and the author's name.
+type Contributor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contributor) Reset() {
+	*x = Contributor{}
+	mi := &file_proto_book_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contributor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contributor) ProtoMessage() {}
+
+func (x *Contributor) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contributor.ProtoReflect.Descriptor instead.
+func (*Contributor) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Contributor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Contributor) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Contributor) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
 type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -147,7 +283,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_proto_book_proto_msgTypes[1]
+	mi := &file_proto_book_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -159,7 +295,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[1]
+	mi := &file_proto_book_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -172,7 +308,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{1}
+	return file_proto_book_proto_rawDescGZIP(), []int{2}
 }
 
 type BookResponse struct {
@@ -184,7 +320,7 @@ type BookResponse struct {
 
 func (x *BookResponse) Reset() {
 	*x = BookResponse{}
-	mi := &file_proto_book_proto_msgTypes[2]
+	mi := &file_proto_book_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -196,7 +332,7 @@ func (x *BookResponse) String() string {
 func (*BookResponse) ProtoMessage() {}
 
 func (x *BookResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[2]
+	mi := &file_proto_book_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -209,7 +345,7 @@ func (x *BookResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BookResponse.ProtoReflect.Descriptor instead.
 func (*BookResponse) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{2}
+	return file_proto_book_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *BookResponse) GetBook() *Book {
@@ -228,7 +364,7 @@ type BookList struct {
 
 func (x *BookList) Reset() {
 	*x = BookList{}
-	mi := &file_proto_book_proto_msgTypes[3]
+	mi := &file_proto_book_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -240,7 +376,7 @@ func (x *BookList) String() string {
 func (*BookList) ProtoMessage() {}
 
 func (x *BookList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[3]
+	mi := &file_proto_book_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -253,7 +389,7 @@ func (x *BookList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BookList.ProtoReflect.Descriptor instead.
 func (*BookList) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{3}
+	return file_proto_book_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *BookList) GetBooks() []*Book {
@@ -272,7 +408,7 @@ type BookID struct {
 
 func (x *BookID) Reset() {
 	*x = BookID{}
-	mi := &file_proto_book_proto_msgTypes[4]
+	mi := &file_proto_book_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -284,7 +420,7 @@ func (x *BookID) String() string {
 func (*BookID) ProtoMessage() {}
 
 func (x *BookID) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[4]
+	mi := &file_proto_book_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -297,7 +433,7 @@ func (x *BookID) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BookID.ProtoReflect.Descriptor instead.
 func (*BookID) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{4}
+	return file_proto_book_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *BookID) GetId() string {
@@ -307,6 +443,50 @@ func (x *BookID) GetId() string {
 	return ""
 }
 
+type ISBNRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Isbn          string                 `protobuf:"bytes,1,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ISBNRequest) Reset() {
+	*x = ISBNRequest{}
+	mi := &file_proto_book_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ISBNRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ISBNRequest) ProtoMessage() {}
+
+func (x *ISBNRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ISBNRequest.ProtoReflect.Descriptor instead.
+func (*ISBNRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ISBNRequest) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
 type CreateBookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
@@ -316,7 +496,7 @@ type CreateBookRequest struct {
 
 func (x *CreateBookRequest) Reset() {
 	*x = CreateBookRequest{}
-	mi := &file_proto_book_proto_msgTypes[5]
+	mi := &file_proto_book_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -328,7 +508,7 @@ func (x *CreateBookRequest) String() string {
 func (*CreateBookRequest) ProtoMessage() {}
 
 func (x *CreateBookRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[5]
+	mi := &file_proto_book_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -341,7 +521,7 @@ func (x *CreateBookRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateBookRequest.ProtoReflect.Descriptor instead.
 func (*CreateBookRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{5}
+	return file_proto_book_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CreateBookRequest) GetBook() *Book {
@@ -351,6 +531,8 @@ func (x *CreateBookRequest) GetBook() *Book {
 	return nil
 }
 
+// UpdateBook ignores book.stock: stock only changes through ReserveStock and
+// ReleaseStock, so that concurrent orders are never overwritten.
 type UpdateBookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
@@ -360,7 +542,7 @@ type UpdateBookRequest struct {
 
 func (x *UpdateBookRequest) Reset() {
 	*x = UpdateBookRequest{}
-	mi := &file_proto_book_proto_msgTypes[6]
+	mi := &file_proto_book_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -372,7 +554,7 @@ func (x *UpdateBookRequest) String() string {
 func (*UpdateBookRequest) ProtoMessage() {}
 
 func (x *UpdateBookRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[6]
+	mi := &file_proto_book_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,7 +567,7 @@ func (x *UpdateBookRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateBookRequest.ProtoReflect.Descriptor instead.
 func (*UpdateBookRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{6}
+	return file_proto_book_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UpdateBookRequest) GetBook() *Book {
@@ -404,7 +586,7 @@ type GenreRequest struct {
 
 func (x *GenreRequest) Reset() {
 	*x = GenreRequest{}
-	mi := &file_proto_book_proto_msgTypes[7]
+	mi := &file_proto_book_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -416,7 +598,7 @@ func (x *GenreRequest) String() string {
 func (*GenreRequest) ProtoMessage() {}
 
 func (x *GenreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[7]
+	mi := &file_proto_book_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -429,7 +611,7 @@ func (x *GenreRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GenreRequest.ProtoReflect.Descriptor instead.
 func (*GenreRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{7}
+	return file_proto_book_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GenreRequest) GetGenre() string {
@@ -448,7 +630,7 @@ type AuthorRequest struct {
 
 func (x *AuthorRequest) Reset() {
 	*x = AuthorRequest{}
-	mi := &file_proto_book_proto_msgTypes[8]
+	mi := &file_proto_book_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -460,7 +642,7 @@ func (x *AuthorRequest) String() string {
 func (*AuthorRequest) ProtoMessage() {}
 
 func (x *AuthorRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[8]
+	mi := &file_proto_book_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -473,7 +655,7 @@ func (x *AuthorRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthorRequest.ProtoReflect.Descriptor instead.
 func (*AuthorRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{8}
+	return file_proto_book_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *AuthorRequest) GetAuthor() string {
@@ -492,7 +674,7 @@ type LanguageRequest struct {
 
 func (x *LanguageRequest) Reset() {
 	*x = LanguageRequest{}
-	mi := &file_proto_book_proto_msgTypes[9]
+	mi := &file_proto_book_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -504,7 +686,7 @@ func (x *LanguageRequest) String() string {
 func (*LanguageRequest) ProtoMessage() {}
 
 func (x *LanguageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[9]
+	mi := &file_proto_book_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,7 +699,7 @@ func (x *LanguageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LanguageRequest.ProtoReflect.Descriptor instead.
 func (*LanguageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{9}
+	return file_proto_book_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *LanguageRequest) GetLanguage() string {
@@ -528,15 +710,17 @@ func (x *LanguageRequest) GetLanguage() string {
 }
 
 type SearchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Keyword       string                 `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Keyword string                 `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"`
+	// limit defaults to 20 and is capped at 100.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchRequest) Reset() {
 	*x = SearchRequest{}
-	mi := &file_proto_book_proto_msgTypes[10]
+	mi := &file_proto_book_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -548,7 +732,7 @@ func (x *SearchRequest) String() string {
 func (*SearchRequest) ProtoMessage() {}
 
 func (x *SearchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_book_proto_msgTypes[10]
+	mi := &file_proto_book_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -561,7 +745,7 @@ func (x *SearchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
 func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return file_proto_book_proto_rawDescGZIP(), []int{10}
+	return file_proto_book_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *SearchRequest) GetKeyword() string {
@@ -571,62 +755,1989 @@ func (x *SearchRequest) GetKeyword() string {
 	return ""
 }
 
-var File_proto_book_proto protoreflect.FileDescriptor
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
 
-const file_proto_book_proto_rawDesc = "" +
-	"\n" +
-	"\x10proto/book.proto\x12\x04book\"\x83\x02\n" +
-	"\x04Book\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
-	"\x06author\x18\x03 \x01(\tR\x06author\x12\x14\n" +
-	"\x05genre\x18\x04 \x01(\tR\x05genre\x12\x1a\n" +
-	"\blanguage\x18\x05 \x01(\tR\blanguage\x12 \n" +
-	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06rating\x18\a \x01(\x02R\x06rating\x12\x14\n" +
-	"\x05price\x18\b \x01(\x02R\x05price\x12\x14\n" +
-	"\x05pages\x18\t \x01(\x05R\x05pages\x12%\n" +
-	"\x0epublished_date\x18\n" +
-	" \x01(\tR\rpublishedDate\"\a\n" +
-	"\x05Empty\".\n" +
-	"\fBookResponse\x12\x1e\n" +
-	"\x04book\x18\x01 \x01(\v2\n" +
-	".book.BookR\x04book\",\n" +
-	"\bBookList\x12 \n" +
-	"\x05books\x18\x01 \x03(\v2\n" +
-	".book.BookR\x05books\"\x18\n" +
-	"\x06BookID\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"3\n" +
-	"\x11CreateBookRequest\x12\x1e\n" +
-	"\x04book\x18\x01 \x01(\v2\n" +
-	".book.BookR\x04book\"3\n" +
-	"\x11UpdateBookRequest\x12\x1e\n" +
-	"\x04book\x18\x01 \x01(\v2\n" +
-	".book.BookR\x04book\"$\n" +
-	"\fGenreRequest\x12\x14\n" +
-	"\x05genre\x18\x01 \x01(\tR\x05genre\"'\n" +
-	"\rAuthorRequest\x12\x16\n" +
-	"\x06author\x18\x01 \x01(\tR\x06author\"-\n" +
-	"\x0fLanguageRequest\x12\x1a\n" +
-	"\blanguage\x18\x01 \x01(\tR\blanguage\")\n" +
-	"\rSearchRequest\x12\x18\n" +
-	"\akeyword\x18\x01 \x01(\tR\akeyword2\xfc\x04\n" +
-	"\vBookService\x129\n" +
-	"\n" +
-	"CreateBook\x12\x17.book.CreateBookRequest\x1a\x12.book.BookResponse\x12+\n" +
-	"\aGetBook\x12\f.book.BookID\x1a\x12.book.BookResponse\x129\n" +
-	"\n" +
-	"UpdateBook\x12\x17.book.UpdateBookRequest\x1a\x12.book.BookResponse\x12'\n" +
-	"\n" +
-	"DeleteBook\x12\f.book.BookID\x1a\v.book.Empty\x12+\n" +
-	"\fListAllBooks\x12\v.book.Empty\x1a\x0e.book.BookList\x126\n" +
-	"\x10ListBooksByGenre\x12\x12.book.GenreRequest\x1a\x0e.book.BookList\x128\n" +
-	"\x11ListBooksByAuthor\x12\x13.book.AuthorRequest\x1a\x0e.book.BookList\x12<\n" +
-	"\x13ListBooksByLanguage\x12\x15.book.LanguageRequest\x1a\x0e.book.BookList\x122\n" +
-	"\vSearchBooks\x12\x13.book.SearchRequest\x1a\x0e.book.BookList\x120\n" +
-	"\x11ListTopRatedBooks\x12\v.book.Empty\x1a\x0e.book.BookList\x12.\n" +
-	"\x0fListNewArrivals\x12\v.book.Empty\x1a\x0e.book.BookList\x12.\n" +
-	"\x0eRecommendBooks\x12\f.book.BookID\x1a\x0e.book.BookListB=Z;github.com/OshakbayAigerim/book_service/proto/bookpb;bookpbb\x06proto3"
+type StockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockRequest) Reset() {
+	*x = StockRequest{}
+	mi := &file_proto_book_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockRequest) ProtoMessage() {}
+
+func (x *StockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockRequest.ProtoReflect.Descriptor instead.
+func (*StockRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StockRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *StockRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type Review struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BookId        string                 `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Rating        int32                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_proto_book_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Review) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Review) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *Review) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Review) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Review) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Review) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Review) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type SubmitReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Rating        int32                  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitReviewRequest) Reset() {
+	*x = SubmitReviewRequest{}
+	mi := &file_proto_book_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitReviewRequest) ProtoMessage() {}
+
+func (x *SubmitReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitReviewRequest.ProtoReflect.Descriptor instead.
+func (*SubmitReviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SubmitReviewRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *SubmitReviewRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubmitReviewRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *SubmitReviewRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type UpdateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Rating        int32                  `protobuf:"varint,2,opt,name=rating,proto3" json:"rating,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateReviewRequest) Reset() {
+	*x = UpdateReviewRequest{}
+	mi := &file_proto_book_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReviewRequest) ProtoMessage() {}
+
+func (x *UpdateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReviewRequest.ProtoReflect.Descriptor instead.
+func (*UpdateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateReviewRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateReviewRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *UpdateReviewRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ReviewID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewID) Reset() {
+	*x = ReviewID{}
+	mi := &file_proto_book_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewID) ProtoMessage() {}
+
+func (x *ReviewID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewID.ProtoReflect.Descriptor instead.
+func (*ReviewID) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReviewID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UserReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserReviewsRequest) Reset() {
+	*x = UserReviewsRequest{}
+	mi := &file_proto_book_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserReviewsRequest) ProtoMessage() {}
+
+func (x *UserReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserReviewsRequest.ProtoReflect.Descriptor instead.
+func (*UserReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UserReviewsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewResponse) Reset() {
+	*x = ReviewResponse{}
+	mi := &file_proto_book_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewResponse) ProtoMessage() {}
+
+func (x *ReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewResponse.ProtoReflect.Descriptor instead.
+func (*ReviewResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type ReviewList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewList) Reset() {
+	*x = ReviewList{}
+	mi := &file_proto_book_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewList) ProtoMessage() {}
+
+func (x *ReviewList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewList.ProtoReflect.Descriptor instead.
+func (*ReviewList) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReviewList) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+type Highlight struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Field string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// snippet wraps the matched words in <em></em>.
+	Snippet       string `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Highlight) Reset() {
+	*x = Highlight{}
+	mi := &file_proto_book_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Highlight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Highlight) ProtoMessage() {}
+
+func (x *Highlight) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Highlight.ProtoReflect.Descriptor instead.
+func (*Highlight) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Highlight) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Highlight) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type SearchHit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Highlights    []*Highlight           `protobuf:"bytes,3,rep,name=highlights,proto3" json:"highlights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_proto_book_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SearchHit) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+func (x *SearchHit) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchHit) GetHighlights() []*Highlight {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+type SearchResults struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          []*SearchHit           `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResults) Reset() {
+	*x = SearchResults{}
+	mi := &file_proto_book_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResults) ProtoMessage() {}
+
+func (x *SearchResults) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResults.ProtoReflect.Descriptor instead.
+func (*SearchResults) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SearchResults) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+// limit defaults to 8 and is capped at 20.
+type SuggestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestRequest) Reset() {
+	*x = SuggestRequest{}
+	mi := &file_proto_book_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestRequest) ProtoMessage() {}
+
+func (x *SuggestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestRequest.ProtoReflect.Descriptor instead.
+func (*SuggestRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SuggestRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type TitleSuggestion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Highlighted   string                 `protobuf:"bytes,3,opt,name=highlighted,proto3" json:"highlighted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TitleSuggestion) Reset() {
+	*x = TitleSuggestion{}
+	mi := &file_proto_book_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TitleSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TitleSuggestion) ProtoMessage() {}
+
+func (x *TitleSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TitleSuggestion.ProtoReflect.Descriptor instead.
+func (*TitleSuggestion) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TitleSuggestion) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *TitleSuggestion) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TitleSuggestion) GetHighlighted() string {
+	if x != nil {
+		return x.Highlighted
+	}
+	return ""
+}
+
+type Suggestions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*TitleSuggestion     `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Suggestions) Reset() {
+	*x = Suggestions{}
+	mi := &file_proto_book_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suggestions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suggestions) ProtoMessage() {}
+
+func (x *Suggestions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suggestions.ProtoReflect.Descriptor instead.
+func (*Suggestions) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Suggestions) GetSuggestions() []*TitleSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+// QueryBooksRequest combines catalog filters; zero values leave a filter
+// out. sort is one of title, price, rating, pages or published, with a
+// leading "-" for descending order. page_size defaults to 20 (max 100) and
+// cursor is the next_cursor of the previous page.
+type QueryBooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keyword       string                 `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"`
+	Genre         string                 `protobuf:"bytes,2,opt,name=genre,proto3" json:"genre,omitempty"`
+	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Language      string                 `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	MinPrice      float32                `protobuf:"fixed32,5,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice      float32                `protobuf:"fixed32,6,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	MinRating     float32                `protobuf:"fixed32,7,opt,name=min_rating,json=minRating,proto3" json:"min_rating,omitempty"`
+	MaxRating     float32                `protobuf:"fixed32,8,opt,name=max_rating,json=maxRating,proto3" json:"max_rating,omitempty"`
+	MinPages      int32                  `protobuf:"varint,9,opt,name=min_pages,json=minPages,proto3" json:"min_pages,omitempty"`
+	MaxPages      int32                  `protobuf:"varint,10,opt,name=max_pages,json=maxPages,proto3" json:"max_pages,omitempty"`
+	YearFrom      int32                  `protobuf:"varint,11,opt,name=year_from,json=yearFrom,proto3" json:"year_from,omitempty"`
+	YearTo        int32                  `protobuf:"varint,12,opt,name=year_to,json=yearTo,proto3" json:"year_to,omitempty"`
+	Sort          string                 `protobuf:"bytes,13,opt,name=sort,proto3" json:"sort,omitempty"`
+	PageSize      int32                  `protobuf:"varint,14,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Cursor        string                 `protobuf:"bytes,15,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryBooksRequest) Reset() {
+	*x = QueryBooksRequest{}
+	mi := &file_proto_book_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBooksRequest) ProtoMessage() {}
+
+func (x *QueryBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBooksRequest.ProtoReflect.Descriptor instead.
+func (*QueryBooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *QueryBooksRequest) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+func (x *QueryBooksRequest) GetGenre() string {
+	if x != nil {
+		return x.Genre
+	}
+	return ""
+}
+
+func (x *QueryBooksRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *QueryBooksRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *QueryBooksRequest) GetMinPrice() float32 {
+	if x != nil {
+		return x.MinPrice
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetMaxPrice() float32 {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetMinRating() float32 {
+	if x != nil {
+		return x.MinRating
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetMaxRating() float32 {
+	if x != nil {
+		return x.MaxRating
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetMinPages() int32 {
+	if x != nil {
+		return x.MinPages
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetMaxPages() int32 {
+	if x != nil {
+		return x.MaxPages
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetYearFrom() int32 {
+	if x != nil {
+		return x.YearFrom
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetYearTo() int32 {
+	if x != nil {
+		return x.YearTo
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *QueryBooksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *QueryBooksRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type FacetCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FacetCount) Reset() {
+	*x = FacetCount{}
+	mi := &file_proto_book_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FacetCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FacetCount) ProtoMessage() {}
+
+func (x *FacetCount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FacetCount.ProtoReflect.Descriptor instead.
+func (*FacetCount) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *FacetCount) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *FacetCount) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// total and the facets cover all matching books; next_cursor is empty on
+// the last page.
+type QueryBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Books         []*Book                `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Genres        []*FacetCount          `protobuf:"bytes,4,rep,name=genres,proto3" json:"genres,omitempty"`
+	Languages     []*FacetCount          `protobuf:"bytes,5,rep,name=languages,proto3" json:"languages,omitempty"`
+	Authors       []*FacetCount          `protobuf:"bytes,6,rep,name=authors,proto3" json:"authors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryBooksResponse) Reset() {
+	*x = QueryBooksResponse{}
+	mi := &file_proto_book_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBooksResponse) ProtoMessage() {}
+
+func (x *QueryBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBooksResponse.ProtoReflect.Descriptor instead.
+func (*QueryBooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *QueryBooksResponse) GetBooks() []*Book {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+func (x *QueryBooksResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *QueryBooksResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *QueryBooksResponse) GetGenres() []*FacetCount {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *QueryBooksResponse) GetLanguages() []*FacetCount {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *QueryBooksResponse) GetAuthors() []*FacetCount {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+// limit defaults to 10 and is capped at 50.
+type UserRecommendationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserRecommendationsRequest) Reset() {
+	*x = UserRecommendationsRequest{}
+	mi := &file_proto_book_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserRecommendationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRecommendationsRequest) ProtoMessage() {}
+
+func (x *UserRecommendationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRecommendationsRequest.ProtoReflect.Descriptor instead.
+func (*UserRecommendationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UserRecommendationsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserRecommendationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Dates are "YYYY" or "YYYY-MM-DD".
+type Author struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Aliases       []string               `protobuf:"bytes,3,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	Bio           string                 `protobuf:"bytes,4,opt,name=bio,proto3" json:"bio,omitempty"`
+	BirthDate     string                 `protobuf:"bytes,5,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	DeathDate     string                 `protobuf:"bytes,6,opt,name=death_date,json=deathDate,proto3" json:"death_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Author) Reset() {
+	*x = Author{}
+	mi := &file_proto_book_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Author) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Author) ProtoMessage() {}
+
+func (x *Author) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Author.ProtoReflect.Descriptor instead.
+func (*Author) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Author) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Author) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Author) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *Author) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *Author) GetBirthDate() string {
+	if x != nil {
+		return x.BirthDate
+	}
+	return ""
+}
+
+func (x *Author) GetDeathDate() string {
+	if x != nil {
+		return x.DeathDate
+	}
+	return ""
+}
+
+type AuthorID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorID) Reset() {
+	*x = AuthorID{}
+	mi := &file_proto_book_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorID) ProtoMessage() {}
+
+func (x *AuthorID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorID.ProtoReflect.Descriptor instead.
+func (*AuthorID) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *AuthorID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type AuthorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorResponse) Reset() {
+	*x = AuthorResponse{}
+	mi := &file_proto_book_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorResponse) ProtoMessage() {}
+
+func (x *AuthorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorResponse.ProtoReflect.Descriptor instead.
+func (*AuthorResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AuthorResponse) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+type AuthorList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Authors       []*Author              `protobuf:"bytes,1,rep,name=authors,proto3" json:"authors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorList) Reset() {
+	*x = AuthorList{}
+	mi := &file_proto_book_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorList) ProtoMessage() {}
+
+func (x *AuthorList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorList.ProtoReflect.Descriptor instead.
+func (*AuthorList) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AuthorList) GetAuthors() []*Author {
+	if x != nil {
+		return x.Authors
+	}
+	return nil
+}
+
+type CreateAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAuthorRequest) Reset() {
+	*x = CreateAuthorRequest{}
+	mi := &file_proto_book_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAuthorRequest) ProtoMessage() {}
+
+func (x *CreateAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAuthorRequest.ProtoReflect.Descriptor instead.
+func (*CreateAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CreateAuthorRequest) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+type UpdateAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        *Author                `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAuthorRequest) Reset() {
+	*x = UpdateAuthorRequest{}
+	mi := &file_proto_book_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAuthorRequest) ProtoMessage() {}
+
+func (x *UpdateAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAuthorRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UpdateAuthorRequest) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+// name may be the author's name or any alias, spelled with or without
+// punctuation.
+type FindAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindAuthorRequest) Reset() {
+	*x = FindAuthorRequest{}
+	mi := &file_proto_book_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindAuthorRequest) ProtoMessage() {}
+
+func (x *FindAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindAuthorRequest.ProtoReflect.Descriptor instead.
+func (*FindAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *FindAuthorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// ImportBooks takes a file in chunks. The first message names the format
+// (csv, jsonl or onix) and whether to only validate the file (dry_run);
+// later messages only carry chunks.
+type ImportBooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Chunk         []byte                 `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportBooksRequest) Reset() {
+	*x = ImportBooksRequest{}
+	mi := &file_proto_book_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportBooksRequest) ProtoMessage() {}
+
+func (x *ImportBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportBooksRequest.ProtoReflect.Descriptor instead.
+func (*ImportBooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ImportBooksRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportBooksRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportBooksRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// row is the line number for CSV and JSON Lines and the position of the
+// Product for ONIX.
+type ImportRowError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Isbn          string                 `protobuf:"bytes,2,opt,name=isbn,proto3" json:"isbn,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRowError) Reset() {
+	*x = ImportRowError{}
+	mi := &file_proto_book_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRowError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRowError) ProtoMessage() {}
+
+func (x *ImportRowError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRowError.ProtoReflect.Descriptor instead.
+func (*ImportRowError) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ImportRowError) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportRowError) GetIsbn() string {
+	if x != nil {
+		return x.Isbn
+	}
+	return ""
+}
+
+func (x *ImportRowError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// errors lists the first 1000 failed rows; failed counts all of them.
+type ImportBooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DryRun        bool                   `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Created       int32                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       int32                  `protobuf:"varint,3,opt,name=updated,proto3" json:"updated,omitempty"`
+	Failed        int32                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	Errors        []*ImportRowError      `protobuf:"bytes,5,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportBooksResponse) Reset() {
+	*x = ImportBooksResponse{}
+	mi := &file_proto_book_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportBooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportBooksResponse) ProtoMessage() {}
+
+func (x *ImportBooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportBooksResponse.ProtoReflect.Descriptor instead.
+func (*ImportBooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ImportBooksResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportBooksResponse) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportBooksResponse) GetUpdated() int32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *ImportBooksResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportBooksResponse) GetErrors() []*ImportRowError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ExportBooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportBooksRequest) Reset() {
+	*x = ExportBooksRequest{}
+	mi := &file_proto_book_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportBooksRequest) ProtoMessage() {}
+
+func (x *ExportBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportBooksRequest.ProtoReflect.Descriptor instead.
+func (*ExportBooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ExportBooksRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type ExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_proto_book_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_book_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_proto_book_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_proto_book_proto protoreflect.FileDescriptor
+
+const file_proto_book_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/book.proto\x12\x04book\"\x98\x04\n" +
+	"\x04Book\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x14\n" +
+	"\x05genre\x18\x04 \x01(\tR\x05genre\x12\x1a\n" +
+	"\blanguage\x18\x05 \x01(\tR\blanguage\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06rating\x18\a \x01(\x02R\x06rating\x12\x14\n" +
+	"\x05price\x18\b \x01(\x02R\x05price\x12\x14\n" +
+	"\x05pages\x18\t \x01(\x05R\x05pages\x12%\n" +
+	"\x0epublished_date\x18\n" +
+	" \x01(\tR\rpublishedDate\x12\x14\n" +
+	"\x05stock\x18\v \x01(\x05R\x05stock\x12!\n" +
+	"\frating_count\x18\f \x01(\x05R\vratingCount\x12\x12\n" +
+	"\x04isbn\x18\r \x01(\tR\x04isbn\x125\n" +
+	"\fcontributors\x18\x0e \x03(\v2\x11.book.ContributorR\fcontributors\x12\x1c\n" +
+	"\tpublisher\x18\x0f \x01(\tR\tpublisher\x12\x16\n" +
+	"\x06series\x18\x10 \x01(\tR\x06series\x12'\n" +
+	"\x0fseries_position\x18\x11 \x01(\x05R\x0eseriesPosition\x12\x18\n" +
+	"\aedition\x18\x12 \x01(\tR\aedition\x12\x16\n" +
+	"\x06format\x18\x13 \x01(\tR\x06format\"R\n" +
+	"\vContributor\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\"\a\n" +
+	"\x05Empty\".\n" +
+	"\fBookResponse\x12\x1e\n" +
+	"\x04book\x18\x01 \x01(\v2\n" +
+	".book.BookR\x04book\",\n" +
+	"\bBookList\x12 \n" +
+	"\x05books\x18\x01 \x03(\v2\n" +
+	".book.BookR\x05books\"\x18\n" +
+	"\x06BookID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"!\n" +
+	"\vISBNRequest\x12\x12\n" +
+	"\x04isbn\x18\x01 \x01(\tR\x04isbn\"3\n" +
+	"\x11CreateBookRequest\x12\x1e\n" +
+	"\x04book\x18\x01 \x01(\v2\n" +
+	".book.BookR\x04book\"3\n" +
+	"\x11UpdateBookRequest\x12\x1e\n" +
+	"\x04book\x18\x01 \x01(\v2\n" +
+	".book.BookR\x04book\"$\n" +
+	"\fGenreRequest\x12\x14\n" +
+	"\x05genre\x18\x01 \x01(\tR\x05genre\"'\n" +
+	"\rAuthorRequest\x12\x16\n" +
+	"\x06author\x18\x01 \x01(\tR\x06author\"-\n" +
+	"\x0fLanguageRequest\x12\x1a\n" +
+	"\blanguage\x18\x01 \x01(\tR\blanguage\"?\n" +
+	"\rSearchRequest\x12\x18\n" +
+	"\akeyword\x18\x01 \x01(\tR\akeyword\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"C\n" +
+	"\fStockRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\xb4\x01\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\abook_id\x18\x02 \x01(\tR\x06bookId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x05R\x06rating\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\"s\n" +
+	"\x13SubmitReviewRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x05R\x06rating\x12\x12\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\"Q\n" +
+	"\x13UpdateReviewRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06rating\x18\x02 \x01(\x05R\x06rating\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"\x1a\n" +
+	"\bReviewID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"-\n" +
+	"\x12UserReviewsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"6\n" +
+	"\x0eReviewResponse\x12$\n" +
+	"\x06review\x18\x01 \x01(\v2\f.book.ReviewR\x06review\"4\n" +
+	"\n" +
+	"ReviewList\x12&\n" +
+	"\areviews\x18\x01 \x03(\v2\f.book.ReviewR\areviews\";\n" +
+	"\tHighlight\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x18\n" +
+	"\asnippet\x18\x02 \x01(\tR\asnippet\"r\n" +
+	"\tSearchHit\x12\x1e\n" +
+	"\x04book\x18\x01 \x01(\v2\n" +
+	".book.BookR\x04book\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12/\n" +
+	"\n" +
+	"highlights\x18\x03 \x03(\v2\x0f.book.HighlightR\n" +
+	"highlights\"4\n" +
+	"\rSearchResults\x12#\n" +
+	"\x04hits\x18\x01 \x03(\v2\x0f.book.SearchHitR\x04hits\">\n" +
+	"\x0eSuggestRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"b\n" +
+	"\x0fTitleSuggestion\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vhighlighted\x18\x03 \x01(\tR\vhighlighted\"F\n" +
+	"\vSuggestions\x127\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x15.book.TitleSuggestionR\vsuggestions\"\xa8\x03\n" +
+	"\x11QueryBooksRequest\x12\x18\n" +
+	"\akeyword\x18\x01 \x01(\tR\akeyword\x12\x14\n" +
+	"\x05genre\x18\x02 \x01(\tR\x05genre\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x1a\n" +
+	"\blanguage\x18\x04 \x01(\tR\blanguage\x12\x1b\n" +
+	"\tmin_price\x18\x05 \x01(\x02R\bminPrice\x12\x1b\n" +
+	"\tmax_price\x18\x06 \x01(\x02R\bmaxPrice\x12\x1d\n" +
+	"\n" +
+	"min_rating\x18\a \x01(\x02R\tminRating\x12\x1d\n" +
+	"\n" +
+	"max_rating\x18\b \x01(\x02R\tmaxRating\x12\x1b\n" +
+	"\tmin_pages\x18\t \x01(\x05R\bminPages\x12\x1b\n" +
+	"\tmax_pages\x18\n" +
+	" \x01(\x05R\bmaxPages\x12\x1b\n" +
+	"\tyear_from\x18\v \x01(\x05R\byearFrom\x12\x17\n" +
+	"\ayear_to\x18\f \x01(\x05R\x06yearTo\x12\x12\n" +
+	"\x04sort\x18\r \x01(\tR\x04sort\x12\x1b\n" +
+	"\tpage_size\x18\x0e \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06cursor\x18\x0f \x01(\tR\x06cursor\"8\n" +
+	"\n" +
+	"FacetCount\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"\xf3\x01\n" +
+	"\x12QueryBooksResponse\x12 \n" +
+	"\x05books\x18\x01 \x03(\v2\n" +
+	".book.BookR\x05books\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12(\n" +
+	"\x06genres\x18\x04 \x03(\v2\x10.book.FacetCountR\x06genres\x12.\n" +
+	"\tlanguages\x18\x05 \x03(\v2\x10.book.FacetCountR\tlanguages\x12*\n" +
+	"\aauthors\x18\x06 \x03(\v2\x10.book.FacetCountR\aauthors\"K\n" +
+	"\x1aUserRecommendationsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\x96\x01\n" +
+	"\x06Author\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aaliases\x18\x03 \x03(\tR\aaliases\x12\x10\n" +
+	"\x03bio\x18\x04 \x01(\tR\x03bio\x12\x1d\n" +
+	"\n" +
+	"birth_date\x18\x05 \x01(\tR\tbirthDate\x12\x1d\n" +
+	"\n" +
+	"death_date\x18\x06 \x01(\tR\tdeathDate\"\x1a\n" +
+	"\bAuthorID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
+	"\x0eAuthorResponse\x12$\n" +
+	"\x06author\x18\x01 \x01(\v2\f.book.AuthorR\x06author\"4\n" +
+	"\n" +
+	"AuthorList\x12&\n" +
+	"\aauthors\x18\x01 \x03(\v2\f.book.AuthorR\aauthors\";\n" +
+	"\x13CreateAuthorRequest\x12$\n" +
+	"\x06author\x18\x01 \x01(\v2\f.book.AuthorR\x06author\";\n" +
+	"\x13UpdateAuthorRequest\x12$\n" +
+	"\x06author\x18\x01 \x01(\v2\f.book.AuthorR\x06author\"'\n" +
+	"\x11FindAuthorRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"[\n" +
+	"\x12ImportBooksRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x14\n" +
+	"\x05chunk\x18\x03 \x01(\fR\x05chunk\"L\n" +
+	"\x0eImportRowError\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x12\n" +
+	"\x04isbn\x18\x02 \x01(\tR\x04isbn\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xa8\x01\n" +
+	"\x13ImportBooksResponse\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x05R\acreated\x12\x18\n" +
+	"\aupdated\x18\x03 \x01(\x05R\aupdated\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\x12,\n" +
+	"\x06errors\x18\x05 \x03(\v2\x14.book.ImportRowErrorR\x06errors\",\n" +
+	"\x12ExportBooksRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\"!\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data2\xd0\x0e\n" +
+	"\vBookService\x129\n" +
+	"\n" +
+	"CreateBook\x12\x17.book.CreateBookRequest\x1a\x12.book.BookResponse\x12+\n" +
+	"\aGetBook\x12\f.book.BookID\x1a\x12.book.BookResponse\x126\n" +
+	"\rGetBookByISBN\x12\x11.book.ISBNRequest\x1a\x12.book.BookResponse\x129\n" +
+	"\n" +
+	"UpdateBook\x12\x17.book.UpdateBookRequest\x1a\x12.book.BookResponse\x12'\n" +
+	"\n" +
+	"DeleteBook\x12\f.book.BookID\x1a\v.book.Empty\x12+\n" +
+	"\fListAllBooks\x12\v.book.Empty\x1a\x0e.book.BookList\x126\n" +
+	"\x10ListBooksByGenre\x12\x12.book.GenreRequest\x1a\x0e.book.BookList\x128\n" +
+	"\x11ListBooksByAuthor\x12\x13.book.AuthorRequest\x1a\x0e.book.BookList\x12<\n" +
+	"\x13ListBooksByLanguage\x12\x15.book.LanguageRequest\x1a\x0e.book.BookList\x122\n" +
+	"\vSearchBooks\x12\x13.book.SearchRequest\x1a\x0e.book.BookList\x12@\n" +
+	"\x14SearchWithHighlights\x12\x13.book.SearchRequest\x1a\x13.book.SearchResults\x128\n" +
+	"\rSuggestTitles\x12\x14.book.SuggestRequest\x1a\x11.book.Suggestions\x12?\n" +
+	"\n" +
+	"QueryBooks\x12\x17.book.QueryBooksRequest\x1a\x18.book.QueryBooksResponse\x120\n" +
+	"\x11ListTopRatedBooks\x12\v.book.Empty\x1a\x0e.book.BookList\x12.\n" +
+	"\x0fListNewArrivals\x12\v.book.Empty\x1a\x0e.book.BookList\x12.\n" +
+	"\x0eRecommendBooks\x12\f.book.BookID\x1a\x0e.book.BookList\x12D\n" +
+	"\x10RecommendForUser\x12 .book.UserRecommendationsRequest\x1a\x0e.book.BookList\x126\n" +
+	"\fReserveStock\x12\x12.book.StockRequest\x1a\x12.book.BookResponse\x126\n" +
+	"\fReleaseStock\x12\x12.book.StockRequest\x1a\x12.book.BookResponse\x12?\n" +
+	"\fSubmitReview\x12\x19.book.SubmitReviewRequest\x1a\x14.book.ReviewResponse\x12?\n" +
+	"\fUpdateReview\x12\x19.book.UpdateReviewRequest\x1a\x14.book.ReviewResponse\x12+\n" +
+	"\fDeleteReview\x12\x0e.book.ReviewID\x1a\v.book.Empty\x121\n" +
+	"\x0fListBookReviews\x12\f.book.BookID\x1a\x10.book.ReviewList\x12=\n" +
+	"\x0fListUserReviews\x12\x18.book.UserReviewsRequest\x1a\x10.book.ReviewList\x12?\n" +
+	"\fCreateAuthor\x12\x19.book.CreateAuthorRequest\x1a\x14.book.AuthorResponse\x121\n" +
+	"\tGetAuthor\x12\x0e.book.AuthorID\x1a\x14.book.AuthorResponse\x12;\n" +
+	"\n" +
+	"FindAuthor\x12\x17.book.FindAuthorRequest\x1a\x14.book.AuthorResponse\x12?\n" +
+	"\fUpdateAuthor\x12\x19.book.UpdateAuthorRequest\x1a\x14.book.AuthorResponse\x12+\n" +
+	"\fDeleteAuthor\x12\x0e.book.AuthorID\x1a\v.book.Empty\x12,\n" +
+	"\vListAuthors\x12\v.book.Empty\x1a\x10.book.AuthorList\x125\n" +
+	"\x13ListBooksByAuthorID\x12\x0e.book.AuthorID\x1a\x0e.book.BookList\x12D\n" +
+	"\vImportBooks\x12\x18.book.ImportBooksRequest\x1a\x19.book.ImportBooksResponse(\x01\x12<\n" +
+	"\vExportBooks\x12\x18.book.ExportBooksRequest\x1a\x11.book.ExportChunk0\x01B=Z;github.com/OshakbayAigerim/book_service/proto/bookpb;bookpbb\x06proto3"
 
 var (
 	file_proto_book_proto_rawDescOnce sync.Once
@@ -640,54 +2751,144 @@ func file_proto_book_proto_rawDescGZIP() []byte {
 	return file_proto_book_proto_rawDescData
 }
 
-var file_proto_book_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_book_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_proto_book_proto_goTypes = []any{
-	(*Book)(nil),              // 0: book.Book
-	(*Empty)(nil),             // 1: book.Empty
-	(*BookResponse)(nil),      // 2: book.BookResponse
-	(*BookList)(nil),          // 3: book.BookList
-	(*BookID)(nil),            // 4: book.BookID
-	(*CreateBookRequest)(nil), // 5: book.CreateBookRequest
-	(*UpdateBookRequest)(nil), // 6: book.UpdateBookRequest
-	(*GenreRequest)(nil),      // 7: book.GenreRequest
-	(*AuthorRequest)(nil),     // 8: book.AuthorRequest
-	(*LanguageRequest)(nil),   // 9: book.LanguageRequest
-	(*SearchRequest)(nil),     // 10: book.SearchRequest
+	(*Book)(nil),                       // 0: book.Book
+	(*Contributor)(nil),                // 1: book.Contributor
+	(*Empty)(nil),                      // 2: book.Empty
+	(*BookResponse)(nil),               // 3: book.BookResponse
+	(*BookList)(nil),                   // 4: book.BookList
+	(*BookID)(nil),                     // 5: book.BookID
+	(*ISBNRequest)(nil),                // 6: book.ISBNRequest
+	(*CreateBookRequest)(nil),          // 7: book.CreateBookRequest
+	(*UpdateBookRequest)(nil),          // 8: book.UpdateBookRequest
+	(*GenreRequest)(nil),               // 9: book.GenreRequest
+	(*AuthorRequest)(nil),              // 10: book.AuthorRequest
+	(*LanguageRequest)(nil),            // 11: book.LanguageRequest
+	(*SearchRequest)(nil),              // 12: book.SearchRequest
+	(*StockRequest)(nil),               // 13: book.StockRequest
+	(*Review)(nil),                     // 14: book.Review
+	(*SubmitReviewRequest)(nil),        // 15: book.SubmitReviewRequest
+	(*UpdateReviewRequest)(nil),        // 16: book.UpdateReviewRequest
+	(*ReviewID)(nil),                   // 17: book.ReviewID
+	(*UserReviewsRequest)(nil),         // 18: book.UserReviewsRequest
+	(*ReviewResponse)(nil),             // 19: book.ReviewResponse
+	(*ReviewList)(nil),                 // 20: book.ReviewList
+	(*Highlight)(nil),                  // 21: book.Highlight
+	(*SearchHit)(nil),                  // 22: book.SearchHit
+	(*SearchResults)(nil),              // 23: book.SearchResults
+	(*SuggestRequest)(nil),             // 24: book.SuggestRequest
+	(*TitleSuggestion)(nil),            // 25: book.TitleSuggestion
+	(*Suggestions)(nil),                // 26: book.Suggestions
+	(*QueryBooksRequest)(nil),          // 27: book.QueryBooksRequest
+	(*FacetCount)(nil),                 // 28: book.FacetCount
+	(*QueryBooksResponse)(nil),         // 29: book.QueryBooksResponse
+	(*UserRecommendationsRequest)(nil), // 30: book.UserRecommendationsRequest
+	(*Author)(nil),                     // 31: book.Author
+	(*AuthorID)(nil),                   // 32: book.AuthorID
+	(*AuthorResponse)(nil),             // 33: book.AuthorResponse
+	(*AuthorList)(nil),                 // 34: book.AuthorList
+	(*CreateAuthorRequest)(nil),        // 35: book.CreateAuthorRequest
+	(*UpdateAuthorRequest)(nil),        // 36: book.UpdateAuthorRequest
+	(*FindAuthorRequest)(nil),          // 37: book.FindAuthorRequest
+	(*ImportBooksRequest)(nil),         // 38: book.ImportBooksRequest
+	(*ImportRowError)(nil),             // 39: book.ImportRowError
+	(*ImportBooksResponse)(nil),        // 40: book.ImportBooksResponse
+	(*ExportBooksRequest)(nil),         // 41: book.ExportBooksRequest
+	(*ExportChunk)(nil),                // 42: book.ExportChunk
 }
 var file_proto_book_proto_depIdxs = []int32{
-	0,  // 0: book.BookResponse.book:type_name -> book.Book
-	0,  // 1: book.BookList.books:type_name -> book.Book
-	0,  // 2: book.CreateBookRequest.book:type_name -> book.Book
-	0,  // 3: book.UpdateBookRequest.book:type_name -> book.Book
-	5,  // 4: book.BookService.CreateBook:input_type -> book.CreateBookRequest
-	4,  // 5: book.BookService.GetBook:input_type -> book.BookID
-	6,  // 6: book.BookService.UpdateBook:input_type -> book.UpdateBookRequest
-	4,  // 7: book.BookService.DeleteBook:input_type -> book.BookID
-	1,  // 8: book.BookService.ListAllBooks:input_type -> book.Empty
-	7,  // 9: book.BookService.ListBooksByGenre:input_type -> book.GenreRequest
-	8,  // 10: book.BookService.ListBooksByAuthor:input_type -> book.AuthorRequest
-	9,  // 11: book.BookService.ListBooksByLanguage:input_type -> book.LanguageRequest
-	10, // 12: book.BookService.SearchBooks:input_type -> book.SearchRequest
-	1,  // 13: book.BookService.ListTopRatedBooks:input_type -> book.Empty
-	1,  // 14: book.BookService.ListNewArrivals:input_type -> book.Empty
-	4,  // 15: book.BookService.RecommendBooks:input_type -> book.BookID
-	2,  // 16: book.BookService.CreateBook:output_type -> book.BookResponse
-	2,  // 17: book.BookService.GetBook:output_type -> book.BookResponse
-	2,  // 18: book.BookService.UpdateBook:output_type -> book.BookResponse
-	1,  // 19: book.BookService.DeleteBook:output_type -> book.Empty
-	3,  // 20: book.BookService.ListAllBooks:output_type -> book.BookList
-	3,  // 21: book.BookService.ListBooksByGenre:output_type -> book.BookList
-	3,  // 22: book.BookService.ListBooksByAuthor:output_type -> book.BookList
-	3,  // 23: book.BookService.ListBooksByLanguage:output_type -> book.BookList
-	3,  // 24: book.BookService.SearchBooks:output_type -> book.BookList
-	3,  // 25: book.BookService.ListTopRatedBooks:output_type -> book.BookList
-	3,  // 26: book.BookService.ListNewArrivals:output_type -> book.BookList
-	3,  // 27: book.BookService.RecommendBooks:output_type -> book.BookList
-	16, // [16:28] is the sub-list for method output_type
-	4,  // [4:16] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	1,  // 0: book.Book.contributors:type_name -> book.Contributor
+	0,  // 1: book.BookResponse.book:type_name -> book.Book
+	0,  // 2: book.BookList.books:type_name -> book.Book
+	0,  // 3: book.CreateBookRequest.book:type_name -> book.Book
+	0,  // 4: book.UpdateBookRequest.book:type_name -> book.Book
+	14, // 5: book.ReviewResponse.review:type_name -> book.Review
+	14, // 6: book.ReviewList.reviews:type_name -> book.Review
+	0,  // 7: book.SearchHit.book:type_name -> book.Book
+	21, // 8: book.SearchHit.highlights:type_name -> book.Highlight
+	22, // 9: book.SearchResults.hits:type_name -> book.SearchHit
+	25, // 10: book.Suggestions.suggestions:type_name -> book.TitleSuggestion
+	0,  // 11: book.QueryBooksResponse.books:type_name -> book.Book
+	28, // 12: book.QueryBooksResponse.genres:type_name -> book.FacetCount
+	28, // 13: book.QueryBooksResponse.languages:type_name -> book.FacetCount
+	28, // 14: book.QueryBooksResponse.authors:type_name -> book.FacetCount
+	31, // 15: book.AuthorResponse.author:type_name -> book.Author
+	31, // 16: book.AuthorList.authors:type_name -> book.Author
+	31, // 17: book.CreateAuthorRequest.author:type_name -> book.Author
+	31, // 18: book.UpdateAuthorRequest.author:type_name -> book.Author
+	39, // 19: book.ImportBooksResponse.errors:type_name -> book.ImportRowError
+	7,  // 20: book.BookService.CreateBook:input_type -> book.CreateBookRequest
+	5,  // 21: book.BookService.GetBook:input_type -> book.BookID
+	6,  // 22: book.BookService.GetBookByISBN:input_type -> book.ISBNRequest
+	8,  // 23: book.BookService.UpdateBook:input_type -> book.UpdateBookRequest
+	5,  // 24: book.BookService.DeleteBook:input_type -> book.BookID
+	2,  // 25: book.BookService.ListAllBooks:input_type -> book.Empty
+	9,  // 26: book.BookService.ListBooksByGenre:input_type -> book.GenreRequest
+	10, // 27: book.BookService.ListBooksByAuthor:input_type -> book.AuthorRequest
+	11, // 28: book.BookService.ListBooksByLanguage:input_type -> book.LanguageRequest
+	12, // 29: book.BookService.SearchBooks:input_type -> book.SearchRequest
+	12, // 30: book.BookService.SearchWithHighlights:input_type -> book.SearchRequest
+	24, // 31: book.BookService.SuggestTitles:input_type -> book.SuggestRequest
+	27, // 32: book.BookService.QueryBooks:input_type -> book.QueryBooksRequest
+	2,  // 33: book.BookService.ListTopRatedBooks:input_type -> book.Empty
+	2,  // 34: book.BookService.ListNewArrivals:input_type -> book.Empty
+	5,  // 35: book.BookService.RecommendBooks:input_type -> book.BookID
+	30, // 36: book.BookService.RecommendForUser:input_type -> book.UserRecommendationsRequest
+	13, // 37: book.BookService.ReserveStock:input_type -> book.StockRequest
+	13, // 38: book.BookService.ReleaseStock:input_type -> book.StockRequest
+	15, // 39: book.BookService.SubmitReview:input_type -> book.SubmitReviewRequest
+	16, // 40: book.BookService.UpdateReview:input_type -> book.UpdateReviewRequest
+	17, // 41: book.BookService.DeleteReview:input_type -> book.ReviewID
+	5,  // 42: book.BookService.ListBookReviews:input_type -> book.BookID
+	18, // 43: book.BookService.ListUserReviews:input_type -> book.UserReviewsRequest
+	35, // 44: book.BookService.CreateAuthor:input_type -> book.CreateAuthorRequest
+	32, // 45: book.BookService.GetAuthor:input_type -> book.AuthorID
+	37, // 46: book.BookService.FindAuthor:input_type -> book.FindAuthorRequest
+	36, // 47: book.BookService.UpdateAuthor:input_type -> book.UpdateAuthorRequest
+	32, // 48: book.BookService.DeleteAuthor:input_type -> book.AuthorID
+	2,  // 49: book.BookService.ListAuthors:input_type -> book.Empty
+	32, // 50: book.BookService.ListBooksByAuthorID:input_type -> book.AuthorID
+	38, // 51: book.BookService.ImportBooks:input_type -> book.ImportBooksRequest
+	41, // 52: book.BookService.ExportBooks:input_type -> book.ExportBooksRequest
+	3,  // 53: book.BookService.CreateBook:output_type -> book.BookResponse
+	3,  // 54: book.BookService.GetBook:output_type -> book.BookResponse
+	3,  // 55: book.BookService.GetBookByISBN:output_type -> book.BookResponse
+	3,  // 56: book.BookService.UpdateBook:output_type -> book.BookResponse
+	2,  // 57: book.BookService.DeleteBook:output_type -> book.Empty
+	4,  // 58: book.BookService.ListAllBooks:output_type -> book.BookList
+	4,  // 59: book.BookService.ListBooksByGenre:output_type -> book.BookList
+	4,  // 60: book.BookService.ListBooksByAuthor:output_type -> book.BookList
+	4,  // 61: book.BookService.ListBooksByLanguage:output_type -> book.BookList
+	4,  // 62: book.BookService.SearchBooks:output_type -> book.BookList
+	23, // 63: book.BookService.SearchWithHighlights:output_type -> book.SearchResults
+	26, // 64: book.BookService.SuggestTitles:output_type -> book.Suggestions
+	29, // 65: book.BookService.QueryBooks:output_type -> book.QueryBooksResponse
+	4,  // 66: book.BookService.ListTopRatedBooks:output_type -> book.BookList
+	4,  // 67: book.BookService.ListNewArrivals:output_type -> book.BookList
+	4,  // 68: book.BookService.RecommendBooks:output_type -> book.BookList
+	4,  // 69: book.BookService.RecommendForUser:output_type -> book.BookList
+	3,  // 70: book.BookService.ReserveStock:output_type -> book.BookResponse
+	3,  // 71: book.BookService.ReleaseStock:output_type -> book.BookResponse
+	19, // 72: book.BookService.SubmitReview:output_type -> book.ReviewResponse
+	19, // 73: book.BookService.UpdateReview:output_type -> book.ReviewResponse
+	2,  // 74: book.BookService.DeleteReview:output_type -> book.Empty
+	20, // 75: book.BookService.ListBookReviews:output_type -> book.ReviewList
+	20, // 76: book.BookService.ListUserReviews:output_type -> book.ReviewList
+	33, // 77: book.BookService.CreateAuthor:output_type -> book.AuthorResponse
+	33, // 78: book.BookService.GetAuthor:output_type -> book.AuthorResponse
+	33, // 79: book.BookService.FindAuthor:output_type -> book.AuthorResponse
+	33, // 80: book.BookService.UpdateAuthor:output_type -> book.AuthorResponse
+	2,  // 81: book.BookService.DeleteAuthor:output_type -> book.Empty
+	34, // 82: book.BookService.ListAuthors:output_type -> book.AuthorList
+	4,  // 83: book.BookService.ListBooksByAuthorID:output_type -> book.BookList
+	40, // 84: book.BookService.ImportBooks:output_type -> book.ImportBooksResponse
+	42, // 85: book.BookService.ExportBooks:output_type -> book.ExportChunk
+	53, // [53:86] is the sub-list for method output_type
+	20, // [20:53] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_proto_book_proto_init() }
@@ -701,7 +2902,7 @@ func file_proto_book_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_book_proto_rawDesc), len(file_proto_book_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
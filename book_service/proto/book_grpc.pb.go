@@ -19,28 +19,48 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BookService_CreateBook_FullMethodName          = "/book.BookService/CreateBook"
-	BookService_GetBook_FullMethodName             = "/book.BookService/GetBook"
-	BookService_UpdateBook_FullMethodName          = "/book.BookService/UpdateBook"
-	BookService_DeleteBook_FullMethodName          = "/book.BookService/DeleteBook"
-	BookService_ListAllBooks_FullMethodName        = "/book.BookService/ListAllBooks"
-	BookService_ListBooksByGenre_FullMethodName    = "/book.BookService/ListBooksByGenre"
-	BookService_ListBooksByAuthor_FullMethodName   = "/book.BookService/ListBooksByAuthor"
-	BookService_ListBooksByLanguage_FullMethodName = "/book.BookService/ListBooksByLanguage"
-	BookService_SearchBooks_FullMethodName         = "/book.BookService/SearchBooks"
-	BookService_ListTopRatedBooks_FullMethodName   = "/book.BookService/ListTopRatedBooks"
-	BookService_ListNewArrivals_FullMethodName     = "/book.BookService/ListNewArrivals"
-	BookService_RecommendBooks_FullMethodName      = "/book.BookService/RecommendBooks"
+	BookService_CreateBook_FullMethodName           = "/book.BookService/CreateBook"
+	BookService_GetBook_FullMethodName              = "/book.BookService/GetBook"
+	BookService_GetBookByISBN_FullMethodName        = "/book.BookService/GetBookByISBN"
+	BookService_UpdateBook_FullMethodName           = "/book.BookService/UpdateBook"
+	BookService_DeleteBook_FullMethodName           = "/book.BookService/DeleteBook"
+	BookService_ListAllBooks_FullMethodName         = "/book.BookService/ListAllBooks"
+	BookService_ListBooksByGenre_FullMethodName     = "/book.BookService/ListBooksByGenre"
+	BookService_ListBooksByAuthor_FullMethodName    = "/book.BookService/ListBooksByAuthor"
+	BookService_ListBooksByLanguage_FullMethodName  = "/book.BookService/ListBooksByLanguage"
+	BookService_SearchBooks_FullMethodName          = "/book.BookService/SearchBooks"
+	BookService_SearchWithHighlights_FullMethodName = "/book.BookService/SearchWithHighlights"
+	BookService_SuggestTitles_FullMethodName        = "/book.BookService/SuggestTitles"
+	BookService_QueryBooks_FullMethodName           = "/book.BookService/QueryBooks"
+	BookService_ListTopRatedBooks_FullMethodName    = "/book.BookService/ListTopRatedBooks"
+	BookService_ListNewArrivals_FullMethodName      = "/book.BookService/ListNewArrivals"
+	BookService_RecommendBooks_FullMethodName       = "/book.BookService/RecommendBooks"
+	BookService_RecommendForUser_FullMethodName     = "/book.BookService/RecommendForUser"
+	BookService_ReserveStock_FullMethodName         = "/book.BookService/ReserveStock"
+	BookService_ReleaseStock_FullMethodName         = "/book.BookService/ReleaseStock"
+	BookService_SubmitReview_FullMethodName         = "/book.BookService/SubmitReview"
+	BookService_UpdateReview_FullMethodName         = "/book.BookService/UpdateReview"
+	BookService_DeleteReview_FullMethodName         = "/book.BookService/DeleteReview"
+	BookService_ListBookReviews_FullMethodName      = "/book.BookService/ListBookReviews"
+	BookService_ListUserReviews_FullMethodName      = "/book.BookService/ListUserReviews"
+	BookService_CreateAuthor_FullMethodName         = "/book.BookService/CreateAuthor"
+	BookService_GetAuthor_FullMethodName            = "/book.BookService/GetAuthor"
+	BookService_FindAuthor_FullMethodName           = "/book.BookService/FindAuthor"
+	BookService_UpdateAuthor_FullMethodName         = "/book.BookService/UpdateAuthor"
+	BookService_DeleteAuthor_FullMethodName         = "/book.BookService/DeleteAuthor"
+	BookService_ListAuthors_FullMethodName          = "/book.BookService/ListAuthors"
+	BookService_ListBooksByAuthorID_FullMethodName  = "/book.BookService/ListBooksByAuthorID"
+	BookService_ImportBooks_FullMethodName          = "/book.BookService/ImportBooks"
+	BookService_ExportBooks_FullMethodName          = "/book.BookService/ExportBooks"
 )
 
 // BookServiceClient is the client API for BookService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-//
-// Сервис
 type BookServiceClient interface {
 	CreateBook(ctx context.Context, in *CreateBookRequest, opts ...grpc.CallOption) (*BookResponse, error)
 	GetBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*BookResponse, error)
+	GetBookByISBN(ctx context.Context, in *ISBNRequest, opts ...grpc.CallOption) (*BookResponse, error)
 	UpdateBook(ctx context.Context, in *UpdateBookRequest, opts ...grpc.CallOption) (*BookResponse, error)
 	DeleteBook(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*Empty, error)
 	ListAllBooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BookList, error)
@@ -48,9 +68,29 @@ type BookServiceClient interface {
 	ListBooksByAuthor(ctx context.Context, in *AuthorRequest, opts ...grpc.CallOption) (*BookList, error)
 	ListBooksByLanguage(ctx context.Context, in *LanguageRequest, opts ...grpc.CallOption) (*BookList, error)
 	SearchBooks(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*BookList, error)
+	SearchWithHighlights(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResults, error)
+	SuggestTitles(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*Suggestions, error)
+	QueryBooks(ctx context.Context, in *QueryBooksRequest, opts ...grpc.CallOption) (*QueryBooksResponse, error)
 	ListTopRatedBooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BookList, error)
 	ListNewArrivals(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BookList, error)
 	RecommendBooks(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*BookList, error)
+	RecommendForUser(ctx context.Context, in *UserRecommendationsRequest, opts ...grpc.CallOption) (*BookList, error)
+	ReserveStock(ctx context.Context, in *StockRequest, opts ...grpc.CallOption) (*BookResponse, error)
+	ReleaseStock(ctx context.Context, in *StockRequest, opts ...grpc.CallOption) (*BookResponse, error)
+	SubmitReview(ctx context.Context, in *SubmitReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error)
+	UpdateReview(ctx context.Context, in *UpdateReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error)
+	DeleteReview(ctx context.Context, in *ReviewID, opts ...grpc.CallOption) (*Empty, error)
+	ListBookReviews(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*ReviewList, error)
+	ListUserReviews(ctx context.Context, in *UserReviewsRequest, opts ...grpc.CallOption) (*ReviewList, error)
+	CreateAuthor(ctx context.Context, in *CreateAuthorRequest, opts ...grpc.CallOption) (*AuthorResponse, error)
+	GetAuthor(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*AuthorResponse, error)
+	FindAuthor(ctx context.Context, in *FindAuthorRequest, opts ...grpc.CallOption) (*AuthorResponse, error)
+	UpdateAuthor(ctx context.Context, in *UpdateAuthorRequest, opts ...grpc.CallOption) (*AuthorResponse, error)
+	DeleteAuthor(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*Empty, error)
+	ListAuthors(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AuthorList, error)
+	ListBooksByAuthorID(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*BookList, error)
+	ImportBooks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportBooksRequest, ImportBooksResponse], error)
+	ExportBooks(ctx context.Context, in *ExportBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
 }
 
 type bookServiceClient struct {
@@ -81,6 +121,16 @@ func (c *bookServiceClient) GetBook(ctx context.Context, in *BookID, opts ...grp
 	return out, nil
 }
 
+func (c *bookServiceClient) GetBookByISBN(ctx context.Context, in *ISBNRequest, opts ...grpc.CallOption) (*BookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookResponse)
+	err := c.cc.Invoke(ctx, BookService_GetBookByISBN_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *bookServiceClient) UpdateBook(ctx context.Context, in *UpdateBookRequest, opts ...grpc.CallOption) (*BookResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BookResponse)
@@ -151,6 +201,36 @@ func (c *bookServiceClient) SearchBooks(ctx context.Context, in *SearchRequest,
 	return out, nil
 }
 
+func (c *bookServiceClient) SearchWithHighlights(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResults, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResults)
+	err := c.cc.Invoke(ctx, BookService_SearchWithHighlights_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) SuggestTitles(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*Suggestions, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Suggestions)
+	err := c.cc.Invoke(ctx, BookService_SuggestTitles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) QueryBooks(ctx context.Context, in *QueryBooksRequest, opts ...grpc.CallOption) (*QueryBooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryBooksResponse)
+	err := c.cc.Invoke(ctx, BookService_QueryBooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *bookServiceClient) ListTopRatedBooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BookList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BookList)
@@ -181,14 +261,195 @@ func (c *bookServiceClient) RecommendBooks(ctx context.Context, in *BookID, opts
 	return out, nil
 }
 
+func (c *bookServiceClient) RecommendForUser(ctx context.Context, in *UserRecommendationsRequest, opts ...grpc.CallOption) (*BookList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookList)
+	err := c.cc.Invoke(ctx, BookService_RecommendForUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ReserveStock(ctx context.Context, in *StockRequest, opts ...grpc.CallOption) (*BookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookResponse)
+	err := c.cc.Invoke(ctx, BookService_ReserveStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ReleaseStock(ctx context.Context, in *StockRequest, opts ...grpc.CallOption) (*BookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookResponse)
+	err := c.cc.Invoke(ctx, BookService_ReleaseStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) SubmitReview(ctx context.Context, in *SubmitReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewResponse)
+	err := c.cc.Invoke(ctx, BookService_SubmitReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) UpdateReview(ctx context.Context, in *UpdateReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewResponse)
+	err := c.cc.Invoke(ctx, BookService_UpdateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DeleteReview(ctx context.Context, in *ReviewID, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, BookService_DeleteReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListBookReviews(ctx context.Context, in *BookID, opts ...grpc.CallOption) (*ReviewList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewList)
+	err := c.cc.Invoke(ctx, BookService_ListBookReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListUserReviews(ctx context.Context, in *UserReviewsRequest, opts ...grpc.CallOption) (*ReviewList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewList)
+	err := c.cc.Invoke(ctx, BookService_ListUserReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CreateAuthor(ctx context.Context, in *CreateAuthorRequest, opts ...grpc.CallOption) (*AuthorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorResponse)
+	err := c.cc.Invoke(ctx, BookService_CreateAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetAuthor(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*AuthorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorResponse)
+	err := c.cc.Invoke(ctx, BookService_GetAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) FindAuthor(ctx context.Context, in *FindAuthorRequest, opts ...grpc.CallOption) (*AuthorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorResponse)
+	err := c.cc.Invoke(ctx, BookService_FindAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) UpdateAuthor(ctx context.Context, in *UpdateAuthorRequest, opts ...grpc.CallOption) (*AuthorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorResponse)
+	err := c.cc.Invoke(ctx, BookService_UpdateAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DeleteAuthor(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, BookService_DeleteAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListAuthors(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AuthorList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorList)
+	err := c.cc.Invoke(ctx, BookService_ListAuthors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListBooksByAuthorID(ctx context.Context, in *AuthorID, opts ...grpc.CallOption) (*BookList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookList)
+	err := c.cc.Invoke(ctx, BookService_ListBooksByAuthorID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ImportBooks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportBooksRequest, ImportBooksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[0], BookService_ImportBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportBooksRequest, ImportBooksResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookService_ImportBooksClient = grpc.ClientStreamingClient[ImportBooksRequest, ImportBooksResponse]
+
+func (c *bookServiceClient) ExportBooks(ctx context.Context, in *ExportBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[1], BookService_ExportBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportBooksRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookService_ExportBooksClient = grpc.ServerStreamingClient[ExportChunk]
+
 // BookServiceServer is the server API for BookService service.
 // All implementations must embed UnimplementedBookServiceServer
 // for forward compatibility.
-//
-// Сервис
 type BookServiceServer interface {
 	CreateBook(context.Context, *CreateBookRequest) (*BookResponse, error)
 	GetBook(context.Context, *BookID) (*BookResponse, error)
+	GetBookByISBN(context.Context, *ISBNRequest) (*BookResponse, error)
 	UpdateBook(context.Context, *UpdateBookRequest) (*BookResponse, error)
 	DeleteBook(context.Context, *BookID) (*Empty, error)
 	ListAllBooks(context.Context, *Empty) (*BookList, error)
@@ -196,9 +457,29 @@ type BookServiceServer interface {
 	ListBooksByAuthor(context.Context, *AuthorRequest) (*BookList, error)
 	ListBooksByLanguage(context.Context, *LanguageRequest) (*BookList, error)
 	SearchBooks(context.Context, *SearchRequest) (*BookList, error)
+	SearchWithHighlights(context.Context, *SearchRequest) (*SearchResults, error)
+	SuggestTitles(context.Context, *SuggestRequest) (*Suggestions, error)
+	QueryBooks(context.Context, *QueryBooksRequest) (*QueryBooksResponse, error)
 	ListTopRatedBooks(context.Context, *Empty) (*BookList, error)
 	ListNewArrivals(context.Context, *Empty) (*BookList, error)
 	RecommendBooks(context.Context, *BookID) (*BookList, error)
+	RecommendForUser(context.Context, *UserRecommendationsRequest) (*BookList, error)
+	ReserveStock(context.Context, *StockRequest) (*BookResponse, error)
+	ReleaseStock(context.Context, *StockRequest) (*BookResponse, error)
+	SubmitReview(context.Context, *SubmitReviewRequest) (*ReviewResponse, error)
+	UpdateReview(context.Context, *UpdateReviewRequest) (*ReviewResponse, error)
+	DeleteReview(context.Context, *ReviewID) (*Empty, error)
+	ListBookReviews(context.Context, *BookID) (*ReviewList, error)
+	ListUserReviews(context.Context, *UserReviewsRequest) (*ReviewList, error)
+	CreateAuthor(context.Context, *CreateAuthorRequest) (*AuthorResponse, error)
+	GetAuthor(context.Context, *AuthorID) (*AuthorResponse, error)
+	FindAuthor(context.Context, *FindAuthorRequest) (*AuthorResponse, error)
+	UpdateAuthor(context.Context, *UpdateAuthorRequest) (*AuthorResponse, error)
+	DeleteAuthor(context.Context, *AuthorID) (*Empty, error)
+	ListAuthors(context.Context, *Empty) (*AuthorList, error)
+	ListBooksByAuthorID(context.Context, *AuthorID) (*BookList, error)
+	ImportBooks(grpc.ClientStreamingServer[ImportBooksRequest, ImportBooksResponse]) error
+	ExportBooks(*ExportBooksRequest, grpc.ServerStreamingServer[ExportChunk]) error
 	mustEmbedUnimplementedBookServiceServer()
 }
 
@@ -215,6 +496,9 @@ func (UnimplementedBookServiceServer) CreateBook(context.Context, *CreateBookReq
 func (UnimplementedBookServiceServer) GetBook(context.Context, *BookID) (*BookResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBook not implemented")
 }
+func (UnimplementedBookServiceServer) GetBookByISBN(context.Context, *ISBNRequest) (*BookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBookByISBN not implemented")
+}
 func (UnimplementedBookServiceServer) UpdateBook(context.Context, *UpdateBookRequest) (*BookResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateBook not implemented")
 }
@@ -236,6 +520,15 @@ func (UnimplementedBookServiceServer) ListBooksByLanguage(context.Context, *Lang
 func (UnimplementedBookServiceServer) SearchBooks(context.Context, *SearchRequest) (*BookList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchBooks not implemented")
 }
+func (UnimplementedBookServiceServer) SearchWithHighlights(context.Context, *SearchRequest) (*SearchResults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchWithHighlights not implemented")
+}
+func (UnimplementedBookServiceServer) SuggestTitles(context.Context, *SuggestRequest) (*Suggestions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestTitles not implemented")
+}
+func (UnimplementedBookServiceServer) QueryBooks(context.Context, *QueryBooksRequest) (*QueryBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryBooks not implemented")
+}
 func (UnimplementedBookServiceServer) ListTopRatedBooks(context.Context, *Empty) (*BookList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListTopRatedBooks not implemented")
 }
@@ -245,6 +538,57 @@ func (UnimplementedBookServiceServer) ListNewArrivals(context.Context, *Empty) (
 func (UnimplementedBookServiceServer) RecommendBooks(context.Context, *BookID) (*BookList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RecommendBooks not implemented")
 }
+func (UnimplementedBookServiceServer) RecommendForUser(context.Context, *UserRecommendationsRequest) (*BookList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecommendForUser not implemented")
+}
+func (UnimplementedBookServiceServer) ReserveStock(context.Context, *StockRequest) (*BookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveStock not implemented")
+}
+func (UnimplementedBookServiceServer) ReleaseStock(context.Context, *StockRequest) (*BookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseStock not implemented")
+}
+func (UnimplementedBookServiceServer) SubmitReview(context.Context, *SubmitReviewRequest) (*ReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitReview not implemented")
+}
+func (UnimplementedBookServiceServer) UpdateReview(context.Context, *UpdateReviewRequest) (*ReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReview not implemented")
+}
+func (UnimplementedBookServiceServer) DeleteReview(context.Context, *ReviewID) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteReview not implemented")
+}
+func (UnimplementedBookServiceServer) ListBookReviews(context.Context, *BookID) (*ReviewList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBookReviews not implemented")
+}
+func (UnimplementedBookServiceServer) ListUserReviews(context.Context, *UserReviewsRequest) (*ReviewList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserReviews not implemented")
+}
+func (UnimplementedBookServiceServer) CreateAuthor(context.Context, *CreateAuthorRequest) (*AuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAuthor not implemented")
+}
+func (UnimplementedBookServiceServer) GetAuthor(context.Context, *AuthorID) (*AuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthor not implemented")
+}
+func (UnimplementedBookServiceServer) FindAuthor(context.Context, *FindAuthorRequest) (*AuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindAuthor not implemented")
+}
+func (UnimplementedBookServiceServer) UpdateAuthor(context.Context, *UpdateAuthorRequest) (*AuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAuthor not implemented")
+}
+func (UnimplementedBookServiceServer) DeleteAuthor(context.Context, *AuthorID) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAuthor not implemented")
+}
+func (UnimplementedBookServiceServer) ListAuthors(context.Context, *Empty) (*AuthorList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuthors not implemented")
+}
+func (UnimplementedBookServiceServer) ListBooksByAuthorID(context.Context, *AuthorID) (*BookList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBooksByAuthorID not implemented")
+}
+func (UnimplementedBookServiceServer) ImportBooks(grpc.ClientStreamingServer[ImportBooksRequest, ImportBooksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportBooks not implemented")
+}
+func (UnimplementedBookServiceServer) ExportBooks(*ExportBooksRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportBooks not implemented")
+}
 func (UnimplementedBookServiceServer) mustEmbedUnimplementedBookServiceServer() {}
 func (UnimplementedBookServiceServer) testEmbeddedByValue()                     {}
 
@@ -302,6 +646,24 @@ func _BookService_GetBook_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookService_GetBookByISBN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ISBNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetBookByISBN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetBookByISBN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetBookByISBN(ctx, req.(*ISBNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BookService_UpdateBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateBookRequest)
 	if err := dec(in); err != nil {
@@ -428,6 +790,60 @@ func _BookService_SearchBooks_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookService_SearchWithHighlights_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).SearchWithHighlights(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_SearchWithHighlights_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).SearchWithHighlights(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_SuggestTitles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).SuggestTitles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_SuggestTitles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).SuggestTitles(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_QueryBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).QueryBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_QueryBooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).QueryBooks(ctx, req.(*QueryBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BookService_ListTopRatedBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -482,6 +898,294 @@ func _BookService_RecommendBooks_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookService_RecommendForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRecommendationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).RecommendForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_RecommendForUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).RecommendForUser(ctx, req.(*UserRecommendationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ReserveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ReserveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ReserveStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ReserveStock(ctx, req.(*StockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ReleaseStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ReleaseStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ReleaseStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ReleaseStock(ctx, req.(*StockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_SubmitReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).SubmitReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_SubmitReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).SubmitReview(ctx, req.(*SubmitReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_UpdateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).UpdateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_UpdateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).UpdateReview(ctx, req.(*UpdateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DeleteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DeleteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DeleteReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DeleteReview(ctx, req.(*ReviewID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListBookReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListBookReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListBookReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListBookReviews(ctx, req.(*BookID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListUserReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListUserReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListUserReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListUserReviews(ctx, req.(*UserReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CreateAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CreateAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CreateAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CreateAuthor(ctx, req.(*CreateAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetAuthor(ctx, req.(*AuthorID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_FindAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).FindAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_FindAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).FindAuthor(ctx, req.(*FindAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_UpdateAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).UpdateAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_UpdateAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).UpdateAuthor(ctx, req.(*UpdateAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DeleteAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DeleteAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DeleteAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DeleteAuthor(ctx, req.(*AuthorID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListAuthors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListAuthors(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListBooksByAuthorID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListBooksByAuthorID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListBooksByAuthorID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListBooksByAuthorID(ctx, req.(*AuthorID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ImportBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BookServiceServer).ImportBooks(&grpc.GenericServerStream[ImportBooksRequest, ImportBooksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookService_ImportBooksServer = grpc.ClientStreamingServer[ImportBooksRequest, ImportBooksResponse]
+
+func _BookService_ExportBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookServiceServer).ExportBooks(m, &grpc.GenericServerStream[ExportBooksRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookService_ExportBooksServer = grpc.ServerStreamingServer[ExportChunk]
+
 // BookService_ServiceDesc is the grpc.ServiceDesc for BookService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -497,6 +1201,10 @@ var BookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBook",
 			Handler:    _BookService_GetBook_Handler,
 		},
+		{
+			MethodName: "GetBookByISBN",
+			Handler:    _BookService_GetBookByISBN_Handler,
+		},
 		{
 			MethodName: "UpdateBook",
 			Handler:    _BookService_UpdateBook_Handler,
@@ -525,6 +1233,18 @@ var BookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchBooks",
 			Handler:    _BookService_SearchBooks_Handler,
 		},
+		{
+			MethodName: "SearchWithHighlights",
+			Handler:    _BookService_SearchWithHighlights_Handler,
+		},
+		{
+			MethodName: "SuggestTitles",
+			Handler:    _BookService_SuggestTitles_Handler,
+		},
+		{
+			MethodName: "QueryBooks",
+			Handler:    _BookService_QueryBooks_Handler,
+		},
 		{
 			MethodName: "ListTopRatedBooks",
 			Handler:    _BookService_ListTopRatedBooks_Handler,
@@ -537,7 +1257,78 @@ var BookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RecommendBooks",
 			Handler:    _BookService_RecommendBooks_Handler,
 		},
+		{
+			MethodName: "RecommendForUser",
+			Handler:    _BookService_RecommendForUser_Handler,
+		},
+		{
+			MethodName: "ReserveStock",
+			Handler:    _BookService_ReserveStock_Handler,
+		},
+		{
+			MethodName: "ReleaseStock",
+			Handler:    _BookService_ReleaseStock_Handler,
+		},
+		{
+			MethodName: "SubmitReview",
+			Handler:    _BookService_SubmitReview_Handler,
+		},
+		{
+			MethodName: "UpdateReview",
+			Handler:    _BookService_UpdateReview_Handler,
+		},
+		{
+			MethodName: "DeleteReview",
+			Handler:    _BookService_DeleteReview_Handler,
+		},
+		{
+			MethodName: "ListBookReviews",
+			Handler:    _BookService_ListBookReviews_Handler,
+		},
+		{
+			MethodName: "ListUserReviews",
+			Handler:    _BookService_ListUserReviews_Handler,
+		},
+		{
+			MethodName: "CreateAuthor",
+			Handler:    _BookService_CreateAuthor_Handler,
+		},
+		{
+			MethodName: "GetAuthor",
+			Handler:    _BookService_GetAuthor_Handler,
+		},
+		{
+			MethodName: "FindAuthor",
+			Handler:    _BookService_FindAuthor_Handler,
+		},
+		{
+			MethodName: "UpdateAuthor",
+			Handler:    _BookService_UpdateAuthor_Handler,
+		},
+		{
+			MethodName: "DeleteAuthor",
+			Handler:    _BookService_DeleteAuthor_Handler,
+		},
+		{
+			MethodName: "ListAuthors",
+			Handler:    _BookService_ListAuthors_Handler,
+		},
+		{
+			MethodName: "ListBooksByAuthorID",
+			Handler:    _BookService_ListBooksByAuthorID_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportBooks",
+			Handler:       _BookService_ImportBooks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportBooks",
+			Handler:       _BookService_ExportBooks_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/book.proto",
 }
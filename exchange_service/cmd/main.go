@@ -7,13 +7,19 @@ import (
 	"net"
 	"time"
 
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/cache"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/config"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/handler"
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/migration"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/repository"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/usecase"
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/worker"
 	exchangepb "github.com/OshakbayAigerim/read_space/exchange_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
 	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+	userpb "github.com/OshakbayAigerim/read_space/user_service/proto"
 	"github.com/nats-io/nats.go"
 	"google.golang.org/grpc"
 )
@@ -22,6 +28,10 @@ func main() {
 	mongoClient := config.ConnectMongo()
 	db := mongoClient.Database("readspace")
 
+	migrations.MigrateLegacyOffers(db)
+	migrations.BackfillThreads(db)
+	migrations.BackfillExpiry(db, config.OfferTTL())
+
 	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -36,24 +46,56 @@ func main() {
 	}
 	defer nc.Close()
 
-	libConn, err := grpc.Dial("localhost:50055", grpc.WithInsecure())
+	jwtSecret := auth.SecretFromEnv()
+
+	libConn, err := grpc.Dial("localhost:50055",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "exchange_service")),
+	)
 	if err != nil {
 		log.Fatalf("cannot dial UserLibraryService: %v", err)
 	}
 	defer libConn.Close()
 	libClient := userlibpb.NewUserLibraryServiceClient(libConn)
 
+	bookConn, err := grpc.Dial("localhost:50051",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "exchange_service")),
+	)
+	if err != nil {
+		log.Fatalf("cannot dial BookService: %v", err)
+	}
+	defer bookConn.Close()
+	bookClient := bookpb.NewBookServiceClient(bookConn)
+
+	userConn, err := grpc.Dial("localhost:50052",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "exchange_service")),
+	)
+	if err != nil {
+		log.Fatalf("cannot dial UserService: %v", err)
+	}
+	defer userConn.Close()
+	refs := refcheck.New(userpb.NewUserServiceClient(userConn), bookClient, config.RefCacheTTL())
+
 	repo := repository.NewMongoExchangeRepository(db)
 	redisCache := cache.NewRedisExchangeCache(repo, rdb, 5*time.Minute)
 
-	uc := usecase.NewExchangeUseCase(repo, redisCache, libClient)
-	srv := handler.NewExchangeHandler(uc, nc)
+	uc := usecase.NewExchangeUseCase(repo, redisCache, libClient, refs, config.OfferTTL())
+	groupUC := usecase.NewGroupUseCase(repository.NewMongoGroupRepository(db), libClient, refs, config.OfferTTL())
+	srv := handler.NewExchangeHandler(uc, groupUC, nc)
+	if err := srv.SubscribeDeletions(refs); err != nil {
+		log.Fatalf("NATS subscribe error: %v", err)
+	}
+
+	go worker.NewExpirySweeper(uc, srv.PublishExpired, config.SweepInterval()).Run(context.Background())
+	go worker.NewGroupMatcher(groupUC, srv.PublishGroups, config.MatchInterval()).Run(context.Background())
 
 	lis, err := net.Listen("tcp", ":50054")
 	if err != nil {
 		log.Fatalf("listen error: %v", err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(auth.UnaryServerInterceptor(jwtSecret)))
 	exchangepb.RegisterExchangeServiceServer(grpcServer, srv)
 
 	log.Println("ExchangeService listening on :50054")
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -27,3 +28,37 @@ func ConnectMongo() *mongo.Client {
 	log.Println(" Connected to MongoDB for UserService")
 	return client
 }
+
+// OfferTTL is how long offers stay open unless they set their own expiry.
+func OfferTTL() time.Duration {
+	return durationEnv("OFFER_TTL", 7*24*time.Hour)
+}
+
+// SweepInterval is how often stale offers are expired.
+func SweepInterval() time.Duration {
+	return durationEnv("OFFER_SWEEP_INTERVAL", time.Minute)
+}
+
+// MatchInterval is how often wishlists are matched into exchange groups.
+func MatchInterval() time.Duration {
+	return durationEnv("GROUP_MATCH_INTERVAL", 10*time.Minute)
+}
+
+// RefCacheTTL is how long the existence of a user or book is trusted
+// before it is checked again.
+func RefCacheTTL() time.Duration {
+	return durationEnv("REF_CACHE_TTL", 30*time.Second)
+}
+
+func durationEnv(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("invalid %s=%q, using %s", key, v, def)
+		return def
+	}
+	return d
+}
@@ -1,14 +1,111 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"errors"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StatusPending  = "PENDING"
+	StatusAccepted = "ACCEPTED"
+	StatusDeclined = "DECLINED"
+	// StatusExecuting marks a fully accepted group whose books are being
+	// moved.
+	StatusExecuting = "EXECUTING"
+	// StatusCountered marks a revision that was answered with a counter-offer.
+	StatusCountered = "COUNTERED"
+	StatusExpired   = "EXPIRED"
+	// StatusCancelled closes offers and groups whose user or book was
+	// deleted.
+	StatusCancelled = "CANCELLED"
+)
+
+var (
+	ErrOfferNotPending = errors.New("offer is no longer pending")
+	ErrBookNotOwned    = errors.New("book is not in the user's library")
+	ErrAlreadyOwned    = errors.New("book is already in the receiver's library")
+	ErrBookLocked      = errors.New("book is already committed to another pending offer")
+	ErrUnknownBook     = errors.New("book does not exist")
+	ErrUnknownUser     = errors.New("user does not exist")
+	ErrSwapFailed      = errors.New("cannot move books between libraries")
+	ErrOfferExpired    = errors.New("offer has expired")
+	ErrInvalidExpiry   = errors.New("expiry must be in the future")
+	ErrEmptyOffer      = errors.New("an offer needs offered and requested books")
+	ErrPartiesChanged  = errors.New("the parties of an offer cannot change")
+)
+
+// ExchangeOffer is one revision of an exchange. Counter-offers are new
+// revisions in the same thread: ThreadID is the ID of the first offer and
+// ParentID the revision that was countered.
 type ExchangeOffer struct {
-	ID               primitive.ObjectID
-	OwnerID          primitive.ObjectID
-	CounterpartyID   primitive.ObjectID
-	OfferedBookIDs   []primitive.ObjectID
-	RequestedBookIDs []primitive.ObjectID
-	Status           string
-	CreatedAt        primitive.DateTime
-	UpdatedAt        primitive.DateTime
+	ID               primitive.ObjectID   `bson:"_id"`
+	OwnerID          primitive.ObjectID   `bson:"owner_id"`
+	CounterpartyID   primitive.ObjectID   `bson:"counterparty_id"`
+	OfferedBookIDs   []primitive.ObjectID `bson:"offered_book_ids"`
+	RequestedBookIDs []primitive.ObjectID `bson:"requested_book_ids"`
+	Status           string               `bson:"status"`
+	CreatedAt        primitive.DateTime   `bson:"created_at"`
+	UpdatedAt        primitive.DateTime   `bson:"updated_at"`
+	ThreadID         primitive.ObjectID   `bson:"thread_id"`
+	ParentID         primitive.ObjectID   `bson:"parent_id,omitempty"`
+	Revision         int                  `bson:"revision"`
+	ExpiresAt        primitive.DateTime   `bson:"expires_at,omitempty"`
+}
+
+// Expired reports whether the offer's expiry has passed. Offers without an
+// expiry never expire.
+func (o *ExchangeOffer) Expired(now time.Time) bool {
+	return o.ExpiresAt != 0 && !o.ExpiresAt.Time().After(now)
+}
+
+// Thread returns the ID of the negotiation thread; offers created before
+// threads existed form a thread of their own.
+func (o *ExchangeOffer) Thread() primitive.ObjectID {
+	if o.ThreadID.IsZero() {
+		return o.ID
+	}
+	return o.ThreadID
+}
+
+type OfferAcceptedEvent struct {
+	OfferID          string   `json:"offer_id"`
+	OwnerID          string   `json:"owner_id"`
+	RequesterID      string   `json:"requester_id"`
+	OfferedBookIDs   []string `json:"offered_book_ids"`
+	RequestedBookIDs []string `json:"requested_book_ids"`
+}
+
+type OfferCounteredEvent struct {
+	OfferID        string `json:"offer_id"`
+	ParentID       string `json:"parent_id"`
+	ThreadID       string `json:"thread_id"`
+	OwnerID        string `json:"owner_id"`
+	CounterpartyID string `json:"counterparty_id"`
+}
+
+type OfferExpiredEvent struct {
+	OfferID        string `json:"offer_id"`
+	OwnerID        string `json:"owner_id"`
+	CounterpartyID string `json:"counterparty_id"`
+	ExpiredAt      string `json:"expired_at"`
+}
+
+// OfferCancelledEvent is published on exchange.cancelled when an offer is
+// closed because one of its users or books was deleted.
+type OfferCancelledEvent struct {
+	OfferID        string `json:"offer_id"`
+	OwnerID        string `json:"owner_id"`
+	CounterpartyID string `json:"counterparty_id"`
+}
+
+// UserDeletedEvent and BookDeletedEvent come from the user and book
+// services.
+type UserDeletedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+type BookDeletedEvent struct {
+	BookID string `json:"book_id"`
 }
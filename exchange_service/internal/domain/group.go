@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrGroupNotPending = errors.New("exchange group is no longer pending")
+	ErrGroupExpired    = errors.New("exchange group has expired")
+	ErrNotParticipant  = errors.New("user does not take part in the exchange group")
+)
+
+// Wishlist is a user's standing declaration for multi-party exchanges: the
+// books they are willing to give away and the books they would like to
+// get. There is one wishlist per user, keyed by the user ID.
+type Wishlist struct {
+	UserID    primitive.ObjectID   `bson:"_id"`
+	Have      []primitive.ObjectID `bson:"have"`
+	Want      []primitive.ObjectID `bson:"want"`
+	UpdatedAt primitive.DateTime   `bson:"updated_at"`
+}
+
+// GroupLeg is one book handed from one participant to the next.
+type GroupLeg struct {
+	GiverID    primitive.ObjectID `bson:"giver_id"`
+	ReceiverID primitive.ObjectID `bson:"receiver_id"`
+	BookID     primitive.ObjectID `bson:"book_id"`
+}
+
+// ExchangeGroup is a circular exchange proposed by the matcher, e.g.
+// A→B→C→A. Books only move once every participant has accepted; a single
+// decline cancels the whole group.
+type ExchangeGroup struct {
+	ID           primitive.ObjectID   `bson:"_id"`
+	Legs         []GroupLeg           `bson:"legs"`
+	Participants []primitive.ObjectID `bson:"participants"`
+	AcceptedBy   []primitive.ObjectID `bson:"accepted_by"`
+	Status       string               `bson:"status"`
+	CreatedAt    primitive.DateTime   `bson:"created_at"`
+	UpdatedAt    primitive.DateTime   `bson:"updated_at"`
+	ExpiresAt    primitive.DateTime   `bson:"expires_at,omitempty"`
+}
+
+// HasParticipant reports whether the user gives or receives a book in the
+// group.
+func (g *ExchangeGroup) HasParticipant(userID primitive.ObjectID) bool {
+	for _, p := range g.Participants {
+		if p == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptedByAll reports whether every participant has accepted.
+func (g *ExchangeGroup) AcceptedByAll() bool {
+	accepted := make(map[primitive.ObjectID]bool, len(g.AcceptedBy))
+	for _, id := range g.AcceptedBy {
+		accepted[id] = true
+	}
+	for _, p := range g.Participants {
+		if !accepted[p] {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *ExchangeGroup) Expired(now time.Time) bool {
+	return g.ExpiresAt != 0 && !g.ExpiresAt.Time().After(now)
+}
+
+type GroupLegEvent struct {
+	GiverID    string `json:"giver_id"`
+	ReceiverID string `json:"receiver_id"`
+	BookID     string `json:"book_id"`
+}
+
+// GroupEvent is published on exchange.group.* subjects.
+type GroupEvent struct {
+	GroupID      string          `json:"group_id"`
+	Status       string          `json:"status"`
+	Participants []string        `json:"participants"`
+	Legs         []GroupLegEvent `json:"legs"`
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -13,16 +14,18 @@ import (
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/usecase"
 	exchangepb "github.com/OshakbayAigerim/read_space/exchange_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
 )
 
 type ExchangeHandler struct {
 	exchangepb.UnimplementedExchangeServiceServer
-	uc usecase.ExchangeUseCase
-	nc *nats.Conn
+	uc     usecase.ExchangeUseCase
+	groups usecase.GroupUseCase
+	nc     *nats.Conn
 }
 
-func NewExchangeHandler(uc usecase.ExchangeUseCase, nc *nats.Conn) *ExchangeHandler {
-	return &ExchangeHandler{uc: uc, nc: nc}
+func NewExchangeHandler(uc usecase.ExchangeUseCase, groups usecase.GroupUseCase, nc *nats.Conn) *ExchangeHandler {
+	return &ExchangeHandler{uc: uc, groups: groups, nc: nc}
 }
 
 func (h *ExchangeHandler) CreateOffer(ctx context.Context, req *exchangepb.CreateOfferRequest) (*exchangepb.OfferResponse, error) {
@@ -30,6 +33,9 @@ func (h *ExchangeHandler) CreateOffer(ctx context.Context, req *exchangepb.Creat
 		len(req.OfferedBookIds) == 0 || len(req.RequestedBookIds) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "owner_id, counterparty_id, offered_book_ids and requested_book_ids are required")
 	}
+	if err := auth.AuthorizeUser(ctx, req.OwnerId); err != nil {
+		return nil, err
+	}
 
 	ownerOID, err := primitive.ObjectIDFromHex(req.OwnerId)
 	if err != nil {
@@ -39,9 +45,21 @@ func (h *ExchangeHandler) CreateOffer(ctx context.Context, req *exchangepb.Creat
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid counterparty_id")
 	}
-
-	offered := toObjectIDs(req.OfferedBookIds)
-	requested := toObjectIDs(req.RequestedBookIds)
+	if ownerOID == cpOID {
+		return nil, status.Error(codes.InvalidArgument, "cannot exchange books with yourself")
+	}
+	offered, err := toObjectIDs("offered_book_ids", req.OfferedBookIds)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := toObjectIDs("requested_book_ids", req.RequestedBookIds)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := parseExpiry(req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
 
 	now := primitive.NewDateTimeFromTime(time.Now())
 	offer := &domain.ExchangeOffer{
@@ -49,14 +67,15 @@ func (h *ExchangeHandler) CreateOffer(ctx context.Context, req *exchangepb.Creat
 		CounterpartyID:   cpOID,
 		OfferedBookIDs:   offered,
 		RequestedBookIDs: requested,
-		Status:           "PENDING",
+		Status:           domain.StatusPending,
 		CreatedAt:        now,
 		UpdatedAt:        now,
+		ExpiresAt:        expiresAt,
 	}
 
 	created, err := h.uc.CreateOffer(ctx, offer)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot create offer: %v", err)
+		return nil, offerError("cannot create offer", err)
 	}
 
 	evt := struct {
@@ -77,6 +96,9 @@ func (h *ExchangeHandler) GetOffer(ctx context.Context, req *exchangepb.OfferID)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "offer not found: %v", err)
 	}
+	if err := auth.AuthorizeAnyUser(ctx, offer.OwnerID.Hex(), offer.CounterpartyID.Hex()); err != nil {
+		return nil, err
+	}
 	return &exchangepb.OfferResponse{Offer: mapDomain(offer)}, nil
 }
 
@@ -84,6 +106,9 @@ func (h *ExchangeHandler) ListOffersByUser(ctx context.Context, req *exchangepb.
 	if req == nil || req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
 	offers, err := h.uc.ListOffersByUser(ctx, req.UserId)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot list offers: %v", err)
@@ -92,6 +117,9 @@ func (h *ExchangeHandler) ListOffersByUser(ctx context.Context, req *exchangepb.
 }
 
 func (h *ExchangeHandler) ListPendingOffers(ctx context.Context, _ *exchangepb.Empty) (*exchangepb.OfferList, error) {
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
 	offers, err := h.uc.ListPendingOffers(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot list pending offers: %v", err)
@@ -103,9 +131,30 @@ func (h *ExchangeHandler) AcceptOffer(ctx context.Context, req *exchangepb.Accep
 	if req == nil || req.OfferId == "" || req.RequesterId == "" {
 		return nil, status.Error(codes.InvalidArgument, "offer_id and requester_id are required")
 	}
+	if err := auth.AuthorizeUser(ctx, req.RequesterId); err != nil {
+		return nil, err
+	}
+	existing, err := h.loadOffer(ctx, req.OfferId)
+	if err != nil {
+		return nil, err
+	}
+	if existing.CounterpartyID.Hex() != req.RequesterId {
+		return nil, status.Error(codes.PermissionDenied, "only the counterparty can accept the offer")
+	}
 	offer, err := h.uc.AcceptOffer(ctx, req.OfferId, req.RequesterId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot accept offer: %v", err)
+		return nil, offerError("cannot accept offer", err)
+	}
+
+	evt := domain.OfferAcceptedEvent{
+		OfferID:          offer.ID.Hex(),
+		OwnerID:          offer.OwnerID.Hex(),
+		RequesterID:      req.RequesterId,
+		OfferedBookIDs:   toHexs(offer.OfferedBookIDs),
+		RequestedBookIDs: toHexs(offer.RequestedBookIDs),
+	}
+	if data, _ := json.Marshal(evt); data != nil {
+		h.nc.Publish("exchange.accepted", data)
 	}
 	return &exchangepb.OfferResponse{Offer: mapDomain(offer)}, nil
 }
@@ -114,9 +163,16 @@ func (h *ExchangeHandler) DeclineOffer(ctx context.Context, req *exchangepb.Offe
 	if req == nil || req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "offer id is required")
 	}
+	existing, err := h.loadOffer(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.AuthorizeUser(ctx, existing.CounterpartyID.Hex()); err != nil {
+		return nil, err
+	}
 	offer, err := h.uc.DeclineOffer(ctx, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot decline offer: %v", err)
+		return nil, offerError("cannot decline offer", err)
 	}
 	return &exchangepb.OfferResponse{Offer: mapDomain(offer)}, nil
 }
@@ -125,6 +181,9 @@ func (h *ExchangeHandler) DeleteOffer(ctx context.Context, req *exchangepb.Offer
 	if req == nil || req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "offer id is required")
 	}
+	if err := h.authorizeOwner(ctx, req.Id); err != nil {
+		return nil, err
+	}
 	if err := h.uc.DeleteOffer(ctx, req.Id); err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot delete offer: %v", err)
 	}
@@ -135,33 +194,47 @@ func (h *ExchangeHandler) UpdateOffer(ctx context.Context, req *exchangepb.Updat
 	if req == nil || req.Offer == nil || req.Offer.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "offer with id is required")
 	}
+	if len(req.Offer.OfferedBookIds) == 0 || len(req.Offer.RequestedBookIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "offered_book_ids and requested_book_ids are required")
+	}
 
 	oid, err := primitive.ObjectIDFromHex(req.Offer.Id)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid offer id")
 	}
-	ownerOID, err := primitive.ObjectIDFromHex(req.Offer.OwnerId)
+	// стороны оффера не меняются: owner_id и counterparty_id можно не передавать
+	ownerOID, err := optionalObjectID("owner_id", req.Offer.OwnerId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid owner_id")
+		return nil, err
 	}
-	cpOID, err := primitive.ObjectIDFromHex(req.Offer.CounterpartyId)
+	cpOID, err := optionalObjectID("counterparty_id", req.Offer.CounterpartyId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid counterparty_id")
+		return nil, err
+	}
+	offered, err := toObjectIDs("offered_book_ids", req.Offer.OfferedBookIds)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := toObjectIDs("requested_book_ids", req.Offer.RequestedBookIds)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.authorizeOwner(ctx, req.Offer.Id); err != nil {
+		return nil, err
 	}
 
 	dom := &domain.ExchangeOffer{
 		ID:               oid,
 		OwnerID:          ownerOID,
 		CounterpartyID:   cpOID,
-		OfferedBookIDs:   toObjectIDs(req.Offer.OfferedBookIds),
-		RequestedBookIDs: toObjectIDs(req.Offer.RequestedBookIds),
-		Status:           req.Offer.Status,
+		OfferedBookIDs:   offered,
+		RequestedBookIDs: requested,
 		UpdatedAt:        primitive.NewDateTimeFromTime(time.Now()),
 	}
 
 	updated, err := h.uc.UpdateOffer(ctx, dom)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot update offer: %v", err)
+		return nil, offerError("cannot update offer", err)
 	}
 	return &exchangepb.OfferResponse{Offer: mapDomain(updated)}, nil
 }
@@ -170,9 +243,15 @@ func (h *ExchangeHandler) AddOfferedBook(ctx context.Context, req *exchangepb.Bo
 	if req == nil || req.OfferId == "" || req.BookId == "" {
 		return nil, status.Error(codes.InvalidArgument, "offer_id and book_id are required")
 	}
+	if _, err := primitive.ObjectIDFromHex(req.BookId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid book_id")
+	}
+	if err := h.authorizeOwner(ctx, req.OfferId); err != nil {
+		return nil, err
+	}
 	offer, err := h.uc.AddOfferedBook(ctx, req.OfferId, req.BookId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot add offered book: %v", err)
+		return nil, offerError("cannot add offered book", err)
 	}
 	return &exchangepb.OfferResponse{Offer: mapDomain(offer)}, nil
 }
@@ -181,14 +260,20 @@ func (h *ExchangeHandler) RemoveOfferedBook(ctx context.Context, req *exchangepb
 	if req == nil || req.OfferId == "" || req.BookId == "" {
 		return nil, status.Error(codes.InvalidArgument, "offer_id and book_id are required")
 	}
+	if err := h.authorizeOwner(ctx, req.OfferId); err != nil {
+		return nil, err
+	}
 	offer, err := h.uc.RemoveOfferedBook(ctx, req.OfferId, req.BookId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot remove offered book: %v", err)
+		return nil, offerError("cannot remove offered book", err)
 	}
 	return &exchangepb.OfferResponse{Offer: mapDomain(offer)}, nil
 }
 
 func (h *ExchangeHandler) ListAllOffers(ctx context.Context, _ *exchangepb.Empty) (*exchangepb.OfferList, error) {
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
 	offers, err := h.uc.ListAllOffers(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot list all offers: %v", err)
@@ -200,6 +285,9 @@ func (h *ExchangeHandler) ListOffersByStatus(ctx context.Context, req *exchangep
 	if req == nil || req.Status == "" {
 		return nil, status.Error(codes.InvalidArgument, "status is required")
 	}
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
 	offers, err := h.uc.ListOffersByStatus(ctx, req.Status)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot list offers by status: %v", err)
@@ -207,7 +295,152 @@ func (h *ExchangeHandler) ListOffersByStatus(ctx context.Context, req *exchangep
 	return &exchangepb.OfferList{Offers: mapDomainList(offers)}, nil
 }
 
+// CounterOffer lets the counterparty of a pending offer propose a different
+// swap instead of declining it.
+func (h *ExchangeHandler) CounterOffer(ctx context.Context, req *exchangepb.CounterOfferRequest) (*exchangepb.OfferResponse, error) {
+	if req == nil || req.OfferId == "" || len(req.OfferedBookIds) == 0 || len(req.RequestedBookIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "offer_id, offered_book_ids and requested_book_ids are required")
+	}
+	offered, err := toObjectIDs("offered_book_ids", req.OfferedBookIds)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := toObjectIDs("requested_book_ids", req.RequestedBookIds)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := parseExpiry(req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := h.loadOffer(ctx, req.OfferId)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.AuthorizeUser(ctx, parent.CounterpartyID.Hex()); err != nil {
+		return nil, err
+	}
+
+	counter, err := h.uc.CounterOffer(ctx, &domain.ExchangeOffer{
+		ParentID:         parent.ID,
+		OfferedBookIDs:   offered,
+		RequestedBookIDs: requested,
+		ExpiresAt:        expiresAt,
+	})
+	if err != nil {
+		return nil, offerError("cannot counter offer", err)
+	}
+
+	evt := domain.OfferCounteredEvent{
+		OfferID:        counter.ID.Hex(),
+		ParentID:       counter.ParentID.Hex(),
+		ThreadID:       counter.ThreadID.Hex(),
+		OwnerID:        counter.OwnerID.Hex(),
+		CounterpartyID: counter.CounterpartyID.Hex(),
+	}
+	if data, _ := json.Marshal(evt); data != nil {
+		h.nc.Publish("exchange.countered", data)
+	}
+	return &exchangepb.OfferResponse{Offer: mapDomain(counter)}, nil
+}
+
+// GetOfferThread shows both parties every revision of the negotiation.
+func (h *ExchangeHandler) GetOfferThread(ctx context.Context, req *exchangepb.OfferID) (*exchangepb.OfferThread, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "offer id is required")
+	}
+	offer, err := h.loadOffer(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	// участники не меняются внутри ветки, меняются только роли
+	if err := auth.AuthorizeAnyUser(ctx, offer.OwnerID.Hex(), offer.CounterpartyID.Hex()); err != nil {
+		return nil, err
+	}
+	thread, err := h.uc.GetOfferThread(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot load offer thread: %v", err)
+	}
+	return &exchangepb.OfferThread{
+		ThreadId: offer.Thread().Hex(),
+		Offers:   mapDomainList(thread),
+	}, nil
+}
+
+// PublishExpired announces offers that timed out.
+func (h *ExchangeHandler) PublishExpired(offers []*domain.ExchangeOffer) {
+	for _, o := range offers {
+		evt := domain.OfferExpiredEvent{
+			OfferID:        o.ID.Hex(),
+			OwnerID:        o.OwnerID.Hex(),
+			CounterpartyID: o.CounterpartyID.Hex(),
+			ExpiredAt:      o.ExpiresAt.Time().String(),
+		}
+		if data, _ := json.Marshal(evt); data != nil {
+			h.nc.Publish("exchange.expired", data)
+		}
+	}
+}
+
+// PublishCancelled announces offers closed because a user or book was
+// deleted.
+func (h *ExchangeHandler) PublishCancelled(offers []*domain.ExchangeOffer) {
+	for _, o := range offers {
+		evt := domain.OfferCancelledEvent{
+			OfferID:        o.ID.Hex(),
+			OwnerID:        o.OwnerID.Hex(),
+			CounterpartyID: o.CounterpartyID.Hex(),
+		}
+		if data, _ := json.Marshal(evt); data != nil {
+			h.nc.Publish("exchange.cancelled", data)
+		}
+	}
+}
+
+func (h *ExchangeHandler) loadOffer(ctx context.Context, id string) (*domain.ExchangeOffer, error) {
+	offer, err := h.uc.GetOfferByID(ctx, id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "offer not found: %v", err)
+	}
+	return offer, nil
+}
+
+// authorizeOwner checks that the caller created the offer.
+func (h *ExchangeHandler) authorizeOwner(ctx context.Context, id string) error {
+	offer, err := h.loadOffer(ctx, id)
+	if err != nil {
+		return err
+	}
+	return auth.AuthorizeUser(ctx, offer.OwnerID.Hex())
+}
+
+// offerError maps domain failures to their gRPC codes; anything else is an
+// internal error.
+func offerError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOfferNotPending), errors.Is(err, domain.ErrBookNotOwned),
+		errors.Is(err, domain.ErrAlreadyOwned), errors.Is(err, domain.ErrOfferExpired),
+		errors.Is(err, domain.ErrBookLocked):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrUnknownBook), errors.Is(err, domain.ErrUnknownUser):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidExpiry), errors.Is(err, domain.ErrEmptyOffer), errors.Is(err, domain.ErrPartiesChanged):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrSwapFailed):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
 func mapDomain(o *domain.ExchangeOffer) *exchangepb.ExchangeOffer {
+	var parentID, expiresAt string
+	if !o.ParentID.IsZero() {
+		parentID = o.ParentID.Hex()
+	}
+	if o.ExpiresAt != 0 {
+		expiresAt = o.ExpiresAt.Time().String()
+	}
 	return &exchangepb.ExchangeOffer{
 		Id:               o.ID.Hex(),
 		OwnerId:          o.OwnerID.Hex(),
@@ -217,6 +450,10 @@ func mapDomain(o *domain.ExchangeOffer) *exchangepb.ExchangeOffer {
 		Status:           o.Status,
 		CreatedAt:        o.CreatedAt.Time().String(),
 		UpdatedAt:        o.UpdatedAt.Time().String(),
+		ThreadId:         o.Thread().Hex(),
+		ParentId:         parentID,
+		Revision:         int32(o.Revision),
+		ExpiresAt:        expiresAt,
 	}
 }
 
@@ -236,12 +473,37 @@ func toHexs(ids []primitive.ObjectID) []string {
 	return res
 }
 
-func toObjectIDs(strs []string) []primitive.ObjectID {
+// parseExpiry reads an optional RFC 3339 expiry; zero means the default.
+func parseExpiry(s string) (primitive.DateTime, error) {
+	if s == "" {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, "expires_at must be an RFC 3339 time")
+	}
+	return primitive.NewDateTimeFromTime(t), nil
+}
+
+func optionalObjectID(field, s string) (primitive.ObjectID, error) {
+	if s == "" {
+		return primitive.NilObjectID, nil
+	}
+	oid, err := primitive.ObjectIDFromHex(s)
+	if err != nil {
+		return primitive.NilObjectID, status.Errorf(codes.InvalidArgument, "invalid %s", field)
+	}
+	return oid, nil
+}
+
+func toObjectIDs(field string, strs []string) ([]primitive.ObjectID, error) {
 	out := make([]primitive.ObjectID, len(strs))
 	for i, s := range strs {
-		if oid, err := primitive.ObjectIDFromHex(s); err == nil {
-			out[i] = oid
+		oid, err := primitive.ObjectIDFromHex(s)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid id %q in %s", s, field)
 		}
+		out[i] = oid
 	}
-	return out
+	return out, nil
 }
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	exchangepb "github.com/OshakbayAigerim/read_space/exchange_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+)
+
+// groupSubjects maps a group status to the NATS subject announcing it.
+var groupSubjects = map[string]string{
+	domain.StatusPending:   "exchange.group.proposed",
+	domain.StatusAccepted:  "exchange.group.completed",
+	domain.StatusDeclined:  "exchange.group.declined",
+	domain.StatusExpired:   "exchange.group.expired",
+	domain.StatusCancelled: "exchange.group.cancelled",
+}
+
+func (h *ExchangeHandler) SetWishlist(ctx context.Context, req *exchangepb.SetWishlistRequest) (*exchangepb.WishlistResponse, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	uid, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	have, err := toObjectIDs("have_book_ids", req.HaveBookIds)
+	if err != nil {
+		return nil, err
+	}
+	want, err := toObjectIDs("want_book_ids", req.WantBookIds)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := h.groups.SetWishlist(ctx, &domain.Wishlist{UserID: uid, Have: have, Want: want})
+	if err != nil {
+		return nil, groupError("cannot save wishlist", err)
+	}
+	return &exchangepb.WishlistResponse{Wishlist: mapWishlist(w)}, nil
+}
+
+func (h *ExchangeHandler) GetWishlist(ctx context.Context, req *exchangepb.UserID) (*exchangepb.WishlistResponse, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	w, err := h.groups.GetWishlist(ctx, req.UserId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Error(codes.NotFound, "wishlist not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot load wishlist: %v", err)
+	}
+	return &exchangepb.WishlistResponse{Wishlist: mapWishlist(w)}, nil
+}
+
+// MatchGroups runs the matcher right away instead of waiting for the next
+// scheduled run.
+func (h *ExchangeHandler) MatchGroups(ctx context.Context, _ *exchangepb.Empty) (*exchangepb.GroupList, error) {
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	groups, err := h.groups.MatchGroups(ctx)
+	h.PublishGroups(groups)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot match exchange groups: %v", err)
+	}
+	return &exchangepb.GroupList{Groups: mapGroups(groups)}, nil
+}
+
+func (h *ExchangeHandler) GetGroup(ctx context.Context, req *exchangepb.GroupID) (*exchangepb.GroupResponse, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "group id is required")
+	}
+	g, err := h.groups.GetGroup(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "exchange group not found: %v", err)
+	}
+	if err := auth.AuthorizeAnyUser(ctx, toHexs(g.Participants)...); err != nil {
+		return nil, err
+	}
+	return &exchangepb.GroupResponse{Group: mapGroup(g)}, nil
+}
+
+func (h *ExchangeHandler) ListGroupsByUser(ctx context.Context, req *exchangepb.UserID) (*exchangepb.GroupList, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	groups, err := h.groups.ListGroupsByUser(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list exchange groups: %v", err)
+	}
+	return &exchangepb.GroupList{Groups: mapGroups(groups)}, nil
+}
+
+// AcceptGroup records the participant's consent; the last one completes
+// the exchange.
+func (h *ExchangeHandler) AcceptGroup(ctx context.Context, req *exchangepb.GroupActionRequest) (*exchangepb.GroupResponse, error) {
+	if req == nil || req.GroupId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "group_id and user_id are required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	g, err := h.groups.AcceptGroup(ctx, req.GroupId, req.UserId)
+	if err != nil {
+		return nil, groupError("cannot accept exchange group", err)
+	}
+	if g.Status == domain.StatusAccepted {
+		h.PublishGroups([]*domain.ExchangeGroup{g})
+	}
+	return &exchangepb.GroupResponse{Group: mapGroup(g)}, nil
+}
+
+func (h *ExchangeHandler) DeclineGroup(ctx context.Context, req *exchangepb.GroupActionRequest) (*exchangepb.GroupResponse, error) {
+	if req == nil || req.GroupId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "group_id and user_id are required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	g, err := h.groups.DeclineGroup(ctx, req.GroupId, req.UserId)
+	if err != nil {
+		return nil, groupError("cannot decline exchange group", err)
+	}
+	h.PublishGroups([]*domain.ExchangeGroup{g})
+	return &exchangepb.GroupResponse{Group: mapGroup(g)}, nil
+}
+
+// PublishGroups announces groups on the subject matching their status.
+func (h *ExchangeHandler) PublishGroups(groups []*domain.ExchangeGroup) {
+	for _, g := range groups {
+		subject, ok := groupSubjects[g.Status]
+		if !ok {
+			continue
+		}
+		evt := domain.GroupEvent{
+			GroupID:      g.ID.Hex(),
+			Status:       g.Status,
+			Participants: toHexs(g.Participants),
+		}
+		for _, l := range g.Legs {
+			evt.Legs = append(evt.Legs, domain.GroupLegEvent{
+				GiverID:    l.GiverID.Hex(),
+				ReceiverID: l.ReceiverID.Hex(),
+				BookID:     l.BookID.Hex(),
+			})
+		}
+		if data, _ := json.Marshal(evt); data != nil {
+			h.nc.Publish(subject, data)
+		}
+	}
+}
+
+func groupError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotParticipant):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrGroupNotPending), errors.Is(err, domain.ErrGroupExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, "exchange group not found")
+	default:
+		return offerError(msg, err)
+	}
+}
+
+func mapWishlist(w *domain.Wishlist) *exchangepb.Wishlist {
+	return &exchangepb.Wishlist{
+		UserId:      w.UserID.Hex(),
+		HaveBookIds: toHexs(w.Have),
+		WantBookIds: toHexs(w.Want),
+		UpdatedAt:   w.UpdatedAt.Time().String(),
+	}
+}
+
+func mapGroup(g *domain.ExchangeGroup) *exchangepb.ExchangeGroup {
+	legs := make([]*exchangepb.GroupLeg, len(g.Legs))
+	for i, l := range g.Legs {
+		legs[i] = &exchangepb.GroupLeg{
+			GiverId:    l.GiverID.Hex(),
+			ReceiverId: l.ReceiverID.Hex(),
+			BookId:     l.BookID.Hex(),
+		}
+	}
+	var expiresAt string
+	if g.ExpiresAt != 0 {
+		expiresAt = g.ExpiresAt.Time().String()
+	}
+	return &exchangepb.ExchangeGroup{
+		Id:           g.ID.Hex(),
+		Legs:         legs,
+		Participants: toHexs(g.Participants),
+		AcceptedBy:   toHexs(g.AcceptedBy),
+		Status:       g.Status,
+		CreatedAt:    g.CreatedAt.Time().String(),
+		UpdatedAt:    g.UpdatedAt.Time().String(),
+		ExpiresAt:    expiresAt,
+	}
+}
+
+func mapGroups(list []*domain.ExchangeGroup) []*exchangepb.ExchangeGroup {
+	out := make([]*exchangepb.ExchangeGroup, len(list))
+	for i, g := range list {
+		out[i] = mapGroup(g)
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
+)
+
+// SubscribeDeletions cancels the pending offers and groups that refer to
+// a deleted user or book and cleans the wishlists up.
+func (h *ExchangeHandler) SubscribeDeletions(refs *refcheck.Checker) error {
+	if _, err := h.nc.Subscribe("user.deleted", func(m *nats.Msg) {
+		var evt domain.UserDeletedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal user.deleted: %v", err)
+			return
+		}
+		refs.ForgetUser(evt.UserID)
+		ctx := context.Background()
+		offers, err := h.uc.CancelUserOffers(ctx, evt.UserID)
+		h.PublishCancelled(offers)
+		if err != nil {
+			log.Printf("⚠ cannot cancel offers of deleted user %s: %v", evt.UserID, err)
+		}
+		groups, err := h.groups.CancelUserGroups(ctx, evt.UserID)
+		h.PublishGroups(groups)
+		if err != nil {
+			log.Printf("⚠ cannot cancel exchange groups of deleted user %s: %v", evt.UserID, err)
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := h.nc.Subscribe("book.deleted", func(m *nats.Msg) {
+		var evt domain.BookDeletedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal book.deleted: %v", err)
+			return
+		}
+		refs.ForgetBook(evt.BookID)
+		ctx := context.Background()
+		offers, err := h.uc.CancelBookOffers(ctx, evt.BookID)
+		h.PublishCancelled(offers)
+		if err != nil {
+			log.Printf("⚠ cannot cancel offers with deleted book %s: %v", evt.BookID, err)
+		}
+		groups, err := h.groups.CancelBookGroups(ctx, evt.BookID)
+		h.PublishGroups(groups)
+		if err != nil {
+			log.Printf("⚠ cannot cancel exchange groups with deleted book %s: %v", evt.BookID, err)
+		}
+	}); err != nil {
+		return err
+	}
+	return nil
+}
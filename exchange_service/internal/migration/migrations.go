@@ -0,0 +1,142 @@
+package migrations
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+)
+
+// MigrateLegacyOffers rewrites offers stored with the driver's default
+// field names ("id", "ownerid", "offeredbookids", ...). Their IDs lived in
+// "id" next to a generated _id, so such offers are re-inserted under the ID
+// clients saw.
+func MigrateLegacyOffers(db *mongo.Database) {
+	collection := db.Collection("exchange_offers")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{"ownerid": bson.M{"$exists": true}})
+	if err != nil {
+		log.Fatalf("Failed to find legacy offers: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			log.Fatalf("Failed to decode offer: %v", err)
+		}
+
+		offer := domain.ExchangeOffer{
+			ID:               objectID(doc, "id", "_id"),
+			OwnerID:          objectID(doc, "ownerid"),
+			CounterpartyID:   objectID(doc, "counterpartyid"),
+			OfferedBookIDs:   objectIDs(doc, "offeredbookids"),
+			RequestedBookIDs: objectIDs(doc, "requestedbookids"),
+			Status:           stringField(doc, "status"),
+			CreatedAt:        dateField(doc, "createdat"),
+			UpdatedAt:        dateField(doc, "updatedat"),
+		}
+		if err := replaceLegacy(ctx, collection, doc["_id"], offer.ID, offer); err != nil {
+			log.Fatalf("Failed to migrate offer %s: %v", offer.ID.Hex(), err)
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("Migrated %d legacy exchange offers", migrated)
+	}
+}
+
+// BackfillThreads makes every offer created before negotiation threads the
+// first revision of its own thread.
+func BackfillThreads(db *mongo.Database) {
+	collection := db.Collection("exchange_offers")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateMany(ctx,
+		bson.M{"thread_id": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"thread_id": "$_id", "revision": 1}}}},
+	)
+	if err != nil {
+		log.Fatalf("Failed to backfill offer threads: %v", err)
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("Started threads for %d existing offers", res.ModifiedCount)
+	}
+}
+
+// BackfillExpiry gives pending offers created before offers expired the
+// default lifetime, counted from now so that they do not all time out at
+// once.
+func BackfillExpiry(db *mongo.Database, ttl time.Duration) {
+	collection := db.Collection("exchange_offers")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateMany(ctx,
+		bson.M{"status": domain.StatusPending, "expires_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"expires_at": primitive.NewDateTimeFromTime(time.Now().Add(ttl))}},
+	)
+	if err != nil {
+		log.Fatalf("Failed to backfill offer expiry: %v", err)
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("Set expiry on %d pending offers", res.ModifiedCount)
+	}
+}
+
+// replaceLegacy stores doc under id and removes the legacy document if it
+// had a different _id.
+func replaceLegacy(ctx context.Context, collection *mongo.Collection, legacyID interface{}, id primitive.ObjectID, doc interface{}) error {
+	if id == legacyID {
+		_, err := collection.ReplaceOne(ctx, bson.M{"_id": id}, doc)
+		return err
+	}
+	if _, err := collection.InsertOne(ctx, doc); err != nil {
+		return err
+	}
+	_, err := collection.DeleteOne(ctx, bson.M{"_id": legacyID})
+	return err
+}
+
+func objectID(doc bson.M, keys ...string) primitive.ObjectID {
+	for _, k := range keys {
+		if id, ok := doc[k].(primitive.ObjectID); ok && !id.IsZero() {
+			return id
+		}
+	}
+	return primitive.NilObjectID
+}
+
+func objectIDs(doc bson.M, key string) []primitive.ObjectID {
+	var out []primitive.ObjectID
+	ids, _ := doc[key].(bson.A)
+	for _, v := range ids {
+		if id, ok := v.(primitive.ObjectID); ok {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func stringField(doc bson.M, key string) string {
+	s, _ := doc[key].(string)
+	return s
+}
+
+func dateField(doc bson.M, key string) primitive.DateTime {
+	d, _ := doc[key].(primitive.DateTime)
+	return d
+}
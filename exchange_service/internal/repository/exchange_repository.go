@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
 )
@@ -20,4 +21,8 @@ type ExchangeRepository interface {
 	RemoveOfferedBook(ctx context.Context, offerID, bookID string) (*domain.ExchangeOffer, error)
 	ListAllOffers(ctx context.Context) ([]*domain.ExchangeOffer, error)
 	ListOffersByStatus(ctx context.Context, status string) ([]*domain.ExchangeOffer, error)
+
+	SetStatus(ctx context.Context, id, from, to string) (*domain.ExchangeOffer, error)
+	ListThread(ctx context.Context, threadID string) ([]*domain.ExchangeOffer, error)
+	ListExpired(ctx context.Context, now time.Time) ([]*domain.ExchangeOffer, error)
 }
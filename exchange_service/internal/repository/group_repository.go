@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+)
+
+// GroupRepository stores wishlists and the multi-party exchange groups
+// matched from them.
+type GroupRepository interface {
+	SaveWishlist(ctx context.Context, w *domain.Wishlist) (*domain.Wishlist, error)
+	GetWishlist(ctx context.Context, userID string) (*domain.Wishlist, error)
+	ListWishlists(ctx context.Context) ([]*domain.Wishlist, error)
+	DeleteWishlist(ctx context.Context, userID string) error
+	PullBookFromWishlists(ctx context.Context, bookID string) error
+
+	CreateGroup(ctx context.Context, g *domain.ExchangeGroup) (*domain.ExchangeGroup, error)
+	GetGroup(ctx context.Context, id string) (*domain.ExchangeGroup, error)
+	ListGroupsByUser(ctx context.Context, userID string) ([]*domain.ExchangeGroup, error)
+	ListGroupsByStatus(ctx context.Context, status string) ([]*domain.ExchangeGroup, error)
+	AddAcceptance(ctx context.Context, id, userID string) (*domain.ExchangeGroup, error)
+	SetGroupStatus(ctx context.Context, id, from, to string) (*domain.ExchangeGroup, error)
+	ListExpiredGroups(ctx context.Context, now time.Time) ([]*domain.ExchangeGroup, error)
+}
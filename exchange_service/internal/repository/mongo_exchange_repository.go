@@ -22,11 +22,17 @@ func NewMongoExchangeRepository(db *mongo.Database) ExchangeRepository {
 }
 
 func (r *mongoExchangeRepo) CreateOffer(ctx context.Context, offer *domain.ExchangeOffer) (*domain.ExchangeOffer, error) {
-	offer.ID = primitive.NewObjectID()
+	if offer.ID.IsZero() {
+		offer.ID = primitive.NewObjectID()
+	}
 	now := primitive.NewDateTimeFromTime(time.Now())
 	offer.CreatedAt = now
 	offer.UpdatedAt = now
-	offer.Status = "PENDING"
+	offer.Status = domain.StatusPending
+	if offer.ThreadID.IsZero() {
+		offer.ThreadID = offer.ID
+		offer.Revision = 1
+	}
 
 	if _, err := r.collection.InsertOne(ctx, offer); err != nil {
 		return nil, err
@@ -69,7 +75,7 @@ func (r *mongoExchangeRepo) ListOffersByUser(ctx context.Context, ownerID string
 }
 
 func (r *mongoExchangeRepo) ListPendingOffers(ctx context.Context) ([]*domain.ExchangeOffer, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"status": "PENDING"})
+	cursor, err := r.collection.Find(ctx, bson.M{"status": domain.StatusPending})
 	if err != nil {
 		return nil, err
 	}
@@ -87,14 +93,17 @@ func (r *mongoExchangeRepo) ListPendingOffers(ctx context.Context) ([]*domain.Ex
 }
 
 func (r *mongoExchangeRepo) AcceptOffer(ctx context.Context, id string) (*domain.ExchangeOffer, error) {
-	return r.updateStatus(ctx, id, "ACCEPTED")
+	return r.SetStatus(ctx, id, domain.StatusPending, domain.StatusAccepted)
 }
 
 func (r *mongoExchangeRepo) DeclineOffer(ctx context.Context, id string) (*domain.ExchangeOffer, error) {
-	return r.updateStatus(ctx, id, "DECLINED")
+	return r.SetStatus(ctx, id, domain.StatusPending, domain.StatusDeclined)
 }
 
-func (r *mongoExchangeRepo) updateStatus(ctx context.Context, id, status string) (*domain.ExchangeOffer, error) {
+// SetStatus moves the offer from one status to another. If the offer is no
+// longer in the from status it is left alone and mongo.ErrNoDocuments is
+// returned.
+func (r *mongoExchangeRepo) SetStatus(ctx context.Context, id, from, to string) (*domain.ExchangeOffer, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
@@ -103,9 +112,9 @@ func (r *mongoExchangeRepo) updateStatus(ctx context.Context, id, status string)
 	after := options.After
 	opts := options.FindOneAndUpdateOptions{ReturnDocument: &after}
 
-	filter := bson.M{"_id": objID}
+	filter := bson.M{"_id": objID, "status": from}
 	update := bson.M{"$set": bson.M{
-		"status":     status,
+		"status":     to,
 		"updated_at": now,
 	}}
 
@@ -127,18 +136,17 @@ func (r *mongoExchangeRepo) DeleteOffer(ctx context.Context, id string) error {
 
 // --- New methods ---
 
+// UpdateOffer replaces the books of an offer while it is still pending. The
+// status and the parties are never written here.
 func (r *mongoExchangeRepo) UpdateOffer(ctx context.Context, offer *domain.ExchangeOffer) (*domain.ExchangeOffer, error) {
 	now := primitive.NewDateTimeFromTime(time.Now())
 	after := options.After
 	opts := options.FindOneAndUpdateOptions{ReturnDocument: &after}
 
-	filter := bson.M{"_id": offer.ID}
+	filter := bson.M{"_id": offer.ID, "status": domain.StatusPending}
 	update := bson.M{"$set": bson.M{
-		"owner_id":           offer.OwnerID,
-		"counterparty_id":    offer.CounterpartyID,
 		"offered_book_ids":   offer.OfferedBookIDs,
 		"requested_book_ids": offer.RequestedBookIDs,
-		"status":             offer.Status,
 		"updated_at":         now,
 	}}
 
@@ -163,7 +171,7 @@ func (r *mongoExchangeRepo) AddOfferedBook(ctx context.Context, offerID, bookID
 	after := options.After
 	opts := options.FindOneAndUpdateOptions{ReturnDocument: &after}
 
-	filter := bson.M{"_id": objID}
+	filter := bson.M{"_id": objID, "status": domain.StatusPending}
 	update := bson.M{
 		"$push": bson.M{"offered_book_ids": bid},
 		"$set":  bson.M{"updated_at": now},
@@ -190,7 +198,12 @@ func (r *mongoExchangeRepo) RemoveOfferedBook(ctx context.Context, offerID, book
 	after := options.After
 	opts := options.FindOneAndUpdateOptions{ReturnDocument: &after}
 
-	filter := bson.M{"_id": objID}
+	// последнюю предложенную книгу не убираем: оффер без неё пуст
+	filter := bson.M{
+		"_id":                objID,
+		"status":             domain.StatusPending,
+		"offered_book_ids.1": bson.M{"$exists": true},
+	}
 	update := bson.M{
 		"$pull": bson.M{"offered_book_ids": bid},
 		"$set":  bson.M{"updated_at": now},
@@ -238,3 +251,50 @@ func (r *mongoExchangeRepo) ListOffersByStatus(ctx context.Context, status strin
 	}
 	return offers, nil
 }
+
+// ListThread returns every revision of a negotiation thread, oldest first.
+func (r *mongoExchangeRepo) ListThread(ctx context.Context, threadID string) ([]*domain.ExchangeOffer, error) {
+	tid, err := primitive.ObjectIDFromHex(threadID)
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"thread_id": tid}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var offers []*domain.ExchangeOffer
+	for cursor.Next(ctx) {
+		var o domain.ExchangeOffer
+		if err := cursor.Decode(&o); err != nil {
+			return nil, err
+		}
+		offers = append(offers, &o)
+	}
+	return offers, nil
+}
+
+// ListExpired returns pending offers whose expiry has passed.
+func (r *mongoExchangeRepo) ListExpired(ctx context.Context, now time.Time) ([]*domain.ExchangeOffer, error) {
+	filter := bson.M{
+		"status":     domain.StatusPending,
+		"expires_at": bson.M{"$lte": primitive.NewDateTimeFromTime(now)},
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var offers []*domain.ExchangeOffer
+	for cursor.Next(ctx) {
+		var o domain.ExchangeOffer
+		if err := cursor.Decode(&o); err != nil {
+			return nil, err
+		}
+		offers = append(offers, &o)
+	}
+	return offers, nil
+}
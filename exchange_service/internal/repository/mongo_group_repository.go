@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoGroupRepo struct {
+	wishlists *mongo.Collection
+	groups    *mongo.Collection
+}
+
+func NewMongoGroupRepository(db *mongo.Database) GroupRepository {
+	return &mongoGroupRepo{
+		wishlists: db.Collection("exchange_wishlists"),
+		groups:    db.Collection("exchange_groups"),
+	}
+}
+
+// SaveWishlist replaces the user's wishlist, creating it on first use.
+func (r *mongoGroupRepo) SaveWishlist(ctx context.Context, w *domain.Wishlist) (*domain.Wishlist, error) {
+	w.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.wishlists.ReplaceOne(ctx, bson.M{"_id": w.UserID}, w, opts); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *mongoGroupRepo) GetWishlist(ctx context.Context, userID string) (*domain.Wishlist, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	var w domain.Wishlist
+	if err := r.wishlists.FindOne(ctx, bson.M{"_id": uid}).Decode(&w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *mongoGroupRepo) ListWishlists(ctx context.Context) ([]*domain.Wishlist, error) {
+	cursor, err := r.wishlists.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var lists []*domain.Wishlist
+	for cursor.Next(ctx) {
+		var w domain.Wishlist
+		if err := cursor.Decode(&w); err != nil {
+			return nil, err
+		}
+		lists = append(lists, &w)
+	}
+	return lists, nil
+}
+
+func (r *mongoGroupRepo) DeleteWishlist(ctx context.Context, userID string) error {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	_, err = r.wishlists.DeleteOne(ctx, bson.M{"_id": uid})
+	return err
+}
+
+// PullBookFromWishlists removes the book from both sides of every wishlist.
+func (r *mongoGroupRepo) PullBookFromWishlists(ctx context.Context, bookID string) error {
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"$or": bson.A{bson.M{"have": bid}, bson.M{"want": bid}}}
+	update := bson.M{
+		"$pull": bson.M{"have": bid, "want": bid},
+		"$set":  bson.M{"updated_at": primitive.NewDateTimeFromTime(time.Now())},
+	}
+	_, err = r.wishlists.UpdateMany(ctx, filter, update)
+	return err
+}
+
+func (r *mongoGroupRepo) CreateGroup(ctx context.Context, g *domain.ExchangeGroup) (*domain.ExchangeGroup, error) {
+	if g.ID.IsZero() {
+		g.ID = primitive.NewObjectID()
+	}
+	now := primitive.NewDateTimeFromTime(time.Now())
+	g.CreatedAt = now
+	g.UpdatedAt = now
+	g.Status = domain.StatusPending
+	if g.AcceptedBy == nil {
+		g.AcceptedBy = []primitive.ObjectID{}
+	}
+
+	if _, err := r.groups.InsertOne(ctx, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (r *mongoGroupRepo) GetGroup(ctx context.Context, id string) (*domain.ExchangeGroup, error) {
+	gid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var g domain.ExchangeGroup
+	if err := r.groups.FindOne(ctx, bson.M{"_id": gid}).Decode(&g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *mongoGroupRepo) ListGroupsByUser(ctx context.Context, userID string) ([]*domain.ExchangeGroup, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.find(ctx, bson.M{"participants": uid})
+}
+
+func (r *mongoGroupRepo) ListGroupsByStatus(ctx context.Context, status string) ([]*domain.ExchangeGroup, error) {
+	return r.find(ctx, bson.M{"status": status})
+}
+
+// AddAcceptance records that a participant accepted a pending group and
+// returns the group as it is after the update. Concurrent callers may all
+// see every acceptance; the one that executes the group is decided by
+// SetGroupStatus.
+func (r *mongoGroupRepo) AddAcceptance(ctx context.Context, id, userID string) (*domain.ExchangeGroup, error) {
+	gid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	after := options.After
+	opts := options.FindOneAndUpdateOptions{ReturnDocument: &after}
+
+	filter := bson.M{"_id": gid, "status": domain.StatusPending, "participants": uid}
+	update := bson.M{
+		"$addToSet": bson.M{"accepted_by": uid},
+		"$set":      bson.M{"updated_at": primitive.NewDateTimeFromTime(time.Now())},
+	}
+
+	var g domain.ExchangeGroup
+	if err := r.groups.FindOneAndUpdate(ctx, filter, update, &opts).Decode(&g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// SetGroupStatus moves a group from one status to another; it fails with
+// mongo.ErrNoDocuments if the group is not in the expected status.
+func (r *mongoGroupRepo) SetGroupStatus(ctx context.Context, id, from, to string) (*domain.ExchangeGroup, error) {
+	gid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	after := options.After
+	opts := options.FindOneAndUpdateOptions{ReturnDocument: &after}
+
+	filter := bson.M{"_id": gid, "status": from}
+	update := bson.M{"$set": bson.M{
+		"status":     to,
+		"updated_at": primitive.NewDateTimeFromTime(time.Now()),
+	}}
+
+	var g domain.ExchangeGroup
+	if err := r.groups.FindOneAndUpdate(ctx, filter, update, &opts).Decode(&g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *mongoGroupRepo) ListExpiredGroups(ctx context.Context, now time.Time) ([]*domain.ExchangeGroup, error) {
+	return r.find(ctx, bson.M{
+		"status":     domain.StatusPending,
+		"expires_at": bson.M{"$lte": primitive.NewDateTimeFromTime(now)},
+	})
+}
+
+func (r *mongoGroupRepo) find(ctx context.Context, filter bson.M) ([]*domain.ExchangeGroup, error) {
+	cursor, err := r.groups.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []*domain.ExchangeGroup
+	for cursor.Next(ctx) {
+		var g domain.ExchangeGroup
+		if err := cursor.Decode(&g); err != nil {
+			return nil, err
+		}
+		groups = append(groups, &g)
+	}
+	return groups, nil
+}
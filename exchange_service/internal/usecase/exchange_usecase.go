@@ -2,6 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/cache"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
@@ -23,29 +30,61 @@ type ExchangeUseCase interface {
 	RemoveOfferedBook(ctx context.Context, offerID, bookID string) (*domain.ExchangeOffer, error)
 	ListAllOffers(ctx context.Context) ([]*domain.ExchangeOffer, error)
 	ListOffersByStatus(ctx context.Context, status string) ([]*domain.ExchangeOffer, error)
+
+	CounterOffer(ctx context.Context, counter *domain.ExchangeOffer) (*domain.ExchangeOffer, error)
+	GetOfferThread(ctx context.Context, offerID string) ([]*domain.ExchangeOffer, error)
+	ExpireStale(ctx context.Context, now time.Time) ([]*domain.ExchangeOffer, error)
+
+	CancelUserOffers(ctx context.Context, userID string) ([]*domain.ExchangeOffer, error)
+	CancelBookOffers(ctx context.Context, bookID string) ([]*domain.ExchangeOffer, error)
+}
+
+// RefChecker tells whether the users and books an offer refers to exist.
+type RefChecker interface {
+	UserExists(ctx context.Context, id string) error
+	BookExists(ctx context.Context, id string) error
 }
 
 type exchangeUseCase struct {
-	repo      repository.ExchangeRepository
-	cache     cache.ExchangeCache
-	libClient userlibpb.UserLibraryServiceClient
+	repo     repository.ExchangeRepository
+	cache    cache.ExchangeCache
+	lib      *library
+	offerTTL time.Duration
 }
 
+// NewExchangeUseCase builds the use case; offers without their own expiry
+// stay open for offerTTL.
 func NewExchangeUseCase(
 	r repository.ExchangeRepository,
 	c cache.ExchangeCache,
 	lc userlibpb.UserLibraryServiceClient,
+	refs RefChecker,
+	offerTTL time.Duration,
 ) ExchangeUseCase {
 	return &exchangeUseCase{
-		repo:      r,
-		cache:     c,
-		libClient: lc,
+		repo:     r,
+		cache:    c,
+		lib:      &library{client: lc, refs: refs},
+		offerTTL: offerTTL,
 	}
 }
 
 func (u *exchangeUseCase) CreateOffer(ctx context.Context, offer *domain.ExchangeOffer) (*domain.ExchangeOffer, error) {
+	if err := u.setExpiry(offer); err != nil {
+		return nil, err
+	}
+	if err := u.validateOffer(ctx, offer); err != nil {
+		return nil, err
+	}
+	if offer.ID.IsZero() {
+		offer.ID = primitive.NewObjectID()
+	}
+	if err := u.lockBooks(ctx, offer); err != nil {
+		return nil, err
+	}
 	created, err := u.repo.CreateOffer(ctx, offer)
 	if err != nil {
+		u.lib.releaseLocks(ctx, offer.ID)
 		return nil, err
 	}
 	u.cache.InvalidatePending(ctx)
@@ -65,22 +104,58 @@ func (u *exchangeUseCase) ListPendingOffers(ctx context.Context) ([]*domain.Exch
 	return u.cache.ListPending(ctx)
 }
 
+// AcceptOffer swaps the books of a pending offer between the two user
+// libraries and marks the offer accepted. Both parties must still own the
+// books; if the swap or the status change fails, the libraries are
+// restored.
 func (u *exchangeUseCase) AcceptOffer(ctx context.Context, offerID, requesterID string) (*domain.ExchangeOffer, error) {
-	offer, err := u.repo.AcceptOffer(ctx, offerID)
+	offer, err := u.repo.GetOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOpen(offer); err != nil {
+		return nil, err
+	}
+	if err := u.lib.verifyOwnership(ctx, offer.OwnerID.Hex(), offer.OfferedBookIDs); err != nil {
+		return nil, err
+	}
+	if err := u.lib.verifyOwnership(ctx, offer.CounterpartyID.Hex(), offer.RequestedBookIDs); err != nil {
+		return nil, err
+	}
+	if err := u.verifyReceivers(ctx, offer); err != nil {
+		return nil, err
+	}
+
+	done, err := u.swapBooks(ctx, offer)
+	if err != nil {
+		return nil, err
+	}
+	accepted, err := u.repo.AcceptOffer(ctx, offerID)
 	if err != nil {
+		// оффер успели закрыть, пока мы переносили книги
+		u.lib.rollback(ctx, offer.ID, done)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			u.lib.releaseLocks(ctx, offer.ID)
+			return nil, domain.ErrOfferNotPending
+		}
 		return nil, err
 	}
+
 	u.cache.InvalidatePending(ctx)
-	u.cache.InvalidateUser(ctx, offer.OwnerID.Hex())
+	u.cache.InvalidateUser(ctx, accepted.OwnerID.Hex())
 	u.cache.InvalidateUser(ctx, requesterID)
-	return offer, nil
+	return accepted, nil
 }
 
 func (u *exchangeUseCase) DeclineOffer(ctx context.Context, id string) (*domain.ExchangeOffer, error) {
 	o, err := u.repo.DeclineOffer(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrOfferNotPending
+	}
 	if err != nil {
 		return nil, err
 	}
+	u.lib.releaseLocks(ctx, o.ID)
 	u.cache.InvalidatePending(ctx)
 	u.cache.InvalidateUser(ctx, o.OwnerID.Hex())
 	return o, nil
@@ -94,14 +169,49 @@ func (u *exchangeUseCase) DeleteOffer(ctx context.Context, id string) error {
 	if err := u.repo.DeleteOffer(ctx, id); err != nil {
 		return err
 	}
+	if o.Status == domain.StatusPending {
+		u.lib.releaseLocks(ctx, o.ID)
+	}
 	u.cache.InvalidatePending(ctx)
 	u.cache.InvalidateUser(ctx, o.OwnerID.Hex())
 	return nil
 }
 
+// UpdateOffer replaces the books of a pending offer. The parties never
+// change, and the status only changes through AcceptOffer, DeclineOffer and
+// CounterOffer.
 func (u *exchangeUseCase) UpdateOffer(ctx context.Context, offer *domain.ExchangeOffer) (*domain.ExchangeOffer, error) {
+	existing, err := u.repo.GetOffer(ctx, offer.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOpen(existing); err != nil {
+		return nil, err
+	}
+	if (!offer.OwnerID.IsZero() && offer.OwnerID != existing.OwnerID) ||
+		(!offer.CounterpartyID.IsZero() && offer.CounterpartyID != existing.CounterpartyID) {
+		return nil, domain.ErrPartiesChanged
+	}
+	offer.OwnerID, offer.CounterpartyID = existing.OwnerID, existing.CounterpartyID
+	offer.Status = existing.Status
+	if err := u.validateOffer(ctx, offer); err != nil {
+		return nil, err
+	}
+
+	// переблокируем книги под новый состав предложения
+	u.lib.releaseLocks(ctx, existing.ID)
+	if err := u.lockBooks(ctx, offer); err != nil {
+		u.restoreLocks(ctx, existing)
+		return nil, err
+	}
 	updated, err := u.repo.UpdateOffer(ctx, offer)
 	if err != nil {
+		u.lib.releaseLocks(ctx, offer.ID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// оффер закрыли, пока мы его меняли: старые блокировки ему больше не нужны
+			return nil, domain.ErrOfferNotPending
+		}
+		u.restoreLocks(ctx, existing)
 		return nil, err
 	}
 	u.cache.InvalidatePending(ctx)
@@ -110,8 +220,35 @@ func (u *exchangeUseCase) UpdateOffer(ctx context.Context, offer *domain.Exchang
 }
 
 func (u *exchangeUseCase) AddOfferedBook(ctx context.Context, offerID, bookID string) (*domain.ExchangeOffer, error) {
+	offer, err := u.repo.GetOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOpen(offer); err != nil {
+		return nil, err
+	}
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnknownBook, bookID)
+	}
+	if err := u.lib.checkBooksExist(ctx, []primitive.ObjectID{bid}); err != nil {
+		return nil, err
+	}
+	if err := u.lib.verifyOwnership(ctx, offer.OwnerID.Hex(), append(offer.OfferedBookIDs, bid)); err != nil {
+		return nil, err
+	}
+	if err := u.lib.verifyNotOwned(ctx, offer.CounterpartyID.Hex(), []primitive.ObjectID{bid}); err != nil {
+		return nil, err
+	}
+	if err := u.lib.lockBook(ctx, offer.ID, offer.OwnerID, bid); err != nil {
+		return nil, err
+	}
 	updated, err := u.repo.AddOfferedBook(ctx, offerID, bookID)
 	if err != nil {
+		u.lib.unlockBook(ctx, offer.ID, offer.OwnerID, bid)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrOfferNotPending
+		}
 		return nil, err
 	}
 	u.cache.InvalidatePending(ctx)
@@ -119,11 +256,36 @@ func (u *exchangeUseCase) AddOfferedBook(ctx context.Context, offerID, bookID st
 	return updated, nil
 }
 
+// RemoveOfferedBook takes a book out of a pending offer and unlocks it. The
+// last offered book cannot be removed, the offer is declined or deleted
+// instead.
 func (u *exchangeUseCase) RemoveOfferedBook(ctx context.Context, offerID, bookID string) (*domain.ExchangeOffer, error) {
+	offer, err := u.repo.GetOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOpen(offer); err != nil {
+		return nil, err
+	}
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnknownBook, bookID)
+	}
+	if len(offer.OfferedBookIDs) < 2 {
+		return nil, domain.ErrEmptyOffer
+	}
 	updated, err := u.repo.RemoveOfferedBook(ctx, offerID, bookID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// оффер закрыли или из него убрали другие книги, пока мы проверяли
+		if current, err := u.repo.GetOffer(ctx, offerID); err == nil && current.Status != domain.StatusPending {
+			return nil, domain.ErrOfferNotPending
+		}
+		return nil, domain.ErrEmptyOffer
+	}
 	if err != nil {
 		return nil, err
 	}
+	u.lib.unlockBook(ctx, updated.ID, updated.OwnerID, bid)
 	u.cache.InvalidatePending(ctx)
 	u.cache.InvalidateUser(ctx, updated.OwnerID.Hex())
 	return updated, nil
@@ -136,3 +298,135 @@ func (u *exchangeUseCase) ListAllOffers(ctx context.Context) ([]*domain.Exchange
 func (u *exchangeUseCase) ListOffersByStatus(ctx context.Context, status string) ([]*domain.ExchangeOffer, error) {
 	return u.repo.ListOffersByStatus(ctx, status)
 }
+
+// CounterOffer answers the pending offer counter.ParentID with a new
+// revision from its counterparty: the parties swap roles, offered are the
+// books the counterparty now gives and requested the books it wants in
+// return. The answered revision is marked COUNTERED.
+func (u *exchangeUseCase) CounterOffer(ctx context.Context, counter *domain.ExchangeOffer) (*domain.ExchangeOffer, error) {
+	offerID := counter.ParentID.Hex()
+	parent, err := u.repo.GetOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOpen(parent); err != nil {
+		return nil, err
+	}
+
+	revision := parent.Revision
+	if revision == 0 {
+		revision = 1
+	}
+	counter.OwnerID = parent.CounterpartyID
+	counter.CounterpartyID = parent.OwnerID
+	counter.ThreadID = parent.Thread()
+	counter.Revision = revision + 1
+	if err := u.setExpiry(counter); err != nil {
+		return nil, err
+	}
+	if err := u.validateOffer(ctx, counter); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.repo.SetStatus(ctx, offerID, domain.StatusPending, domain.StatusCountered); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrOfferNotPending
+		}
+		return nil, err
+	}
+	// книги ответа часто те же, что и в исходном предложении, поэтому
+	// сначала освобождаем их
+	u.lib.releaseLocks(ctx, parent.ID)
+	reopen := func() {
+		u.restoreLocks(ctx, parent)
+		if _, rerr := u.repo.SetStatus(ctx, offerID, domain.StatusCountered, domain.StatusPending); rerr != nil {
+			log.Printf("⚠️ cannot reopen offer %s: %v", offerID, rerr)
+		}
+	}
+
+	counter.ID = primitive.NewObjectID()
+	if err := u.lockBooks(ctx, counter); err != nil {
+		reopen()
+		return nil, err
+	}
+	created, err := u.repo.CreateOffer(ctx, counter)
+	if err != nil {
+		u.lib.releaseLocks(ctx, counter.ID)
+		reopen()
+		return nil, err
+	}
+
+	u.cache.InvalidatePending(ctx)
+	u.cache.InvalidateUser(ctx, parent.OwnerID.Hex())
+	u.cache.InvalidateUser(ctx, parent.CounterpartyID.Hex())
+	return created, nil
+}
+
+// GetOfferThread returns every revision of the thread the offer belongs to,
+// oldest first.
+func (u *exchangeUseCase) GetOfferThread(ctx context.Context, offerID string) ([]*domain.ExchangeOffer, error) {
+	offer, err := u.repo.GetOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	thread, err := u.repo.ListThread(ctx, offer.Thread().Hex())
+	if err != nil {
+		return nil, err
+	}
+	if len(thread) == 0 {
+		thread = []*domain.ExchangeOffer{offer}
+	}
+	return thread, nil
+}
+
+// ExpireStale moves pending offers whose expiry has passed to EXPIRED and
+// returns them.
+func (u *exchangeUseCase) ExpireStale(ctx context.Context, now time.Time) ([]*domain.ExchangeOffer, error) {
+	stale, err := u.repo.ListExpired(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	var expired []*domain.ExchangeOffer
+	for _, o := range stale {
+		updated, err := u.repo.SetStatus(ctx, o.ID.Hex(), domain.StatusPending, domain.StatusExpired)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// оффер успели принять или отклонить
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		u.lib.releaseLocks(ctx, updated.ID)
+		u.cache.InvalidateUser(ctx, updated.OwnerID.Hex())
+		u.cache.InvalidateUser(ctx, updated.CounterpartyID.Hex())
+		expired = append(expired, updated)
+	}
+	if len(expired) > 0 {
+		u.cache.InvalidatePending(ctx)
+	}
+	return expired, nil
+}
+
+// setExpiry applies the default expiry to offers that do not set one.
+func (u *exchangeUseCase) setExpiry(offer *domain.ExchangeOffer) error {
+	now := time.Now()
+	if offer.ExpiresAt == 0 {
+		offer.ExpiresAt = primitive.NewDateTimeFromTime(now.Add(u.offerTTL))
+		return nil
+	}
+	if offer.Expired(now) {
+		return domain.ErrInvalidExpiry
+	}
+	return nil
+}
+
+// checkOpen reports whether the offer can still be answered or changed.
+func checkOpen(offer *domain.ExchangeOffer) error {
+	if offer.Status != domain.StatusPending {
+		return domain.ErrOfferNotPending
+	}
+	if offer.Expired(time.Now()) {
+		return domain.ErrOfferExpired
+	}
+	return nil
+}
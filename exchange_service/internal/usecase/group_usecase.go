@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/repository"
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+)
+
+// GroupUseCase runs multi-party exchanges: users declare what they have and
+// want, the matcher proposes rings of participants, and books move once
+// everyone in a ring has accepted.
+type GroupUseCase interface {
+	SetWishlist(ctx context.Context, w *domain.Wishlist) (*domain.Wishlist, error)
+	GetWishlist(ctx context.Context, userID string) (*domain.Wishlist, error)
+
+	MatchGroups(ctx context.Context) ([]*domain.ExchangeGroup, error)
+	GetGroup(ctx context.Context, id string) (*domain.ExchangeGroup, error)
+	ListGroupsByUser(ctx context.Context, userID string) ([]*domain.ExchangeGroup, error)
+	AcceptGroup(ctx context.Context, groupID, userID string) (*domain.ExchangeGroup, error)
+	DeclineGroup(ctx context.Context, groupID, userID string) (*domain.ExchangeGroup, error)
+	ExpireStaleGroups(ctx context.Context, now time.Time) ([]*domain.ExchangeGroup, error)
+
+	CancelUserGroups(ctx context.Context, userID string) ([]*domain.ExchangeGroup, error)
+	CancelBookGroups(ctx context.Context, bookID string) ([]*domain.ExchangeGroup, error)
+}
+
+type groupUseCase struct {
+	repo     repository.GroupRepository
+	lib      *library
+	groupTTL time.Duration
+}
+
+// NewGroupUseCase builds the use case; proposed groups stay open for
+// groupTTL.
+func NewGroupUseCase(
+	r repository.GroupRepository,
+	lc userlibpb.UserLibraryServiceClient,
+	refs RefChecker,
+	groupTTL time.Duration,
+) GroupUseCase {
+	return &groupUseCase{
+		repo:     r,
+		lib:      &library{client: lc, refs: refs},
+		groupTTL: groupTTL,
+	}
+}
+
+// SetWishlist replaces the user's wishlist. The user and every book must
+// exist, the books on offer must be in the user's library and the wanted
+// ones must not.
+func (u *groupUseCase) SetWishlist(ctx context.Context, w *domain.Wishlist) (*domain.Wishlist, error) {
+	w.Have = uniqueIDs(w.Have)
+	w.Want = uniqueIDs(w.Want)
+	if err := u.lib.checkUsersExist(ctx, w.UserID); err != nil {
+		return nil, err
+	}
+	if err := u.lib.checkBooksExist(ctx, append(append([]primitive.ObjectID(nil), w.Have...), w.Want...)); err != nil {
+		return nil, err
+	}
+	if err := u.lib.verifyOwnership(ctx, w.UserID.Hex(), w.Have); err != nil {
+		return nil, err
+	}
+	if err := u.lib.verifyNotOwned(ctx, w.UserID.Hex(), w.Want); err != nil {
+		return nil, err
+	}
+	return u.repo.SaveWishlist(ctx, w)
+}
+
+func (u *groupUseCase) GetWishlist(ctx context.Context, userID string) (*domain.Wishlist, error) {
+	return u.repo.GetWishlist(ctx, userID)
+}
+
+// MatchGroups proposes new exchange groups from the current wishlists.
+// Users already in a pending or executing group are left out, and so are rings that
+// were declined or expired before. Every book of a proposed group is
+// locked to it; rings whose books cannot be locked are skipped.
+func (u *groupUseCase) MatchGroups(ctx context.Context) ([]*domain.ExchangeGroup, error) {
+	lists, err := u.repo.ListWishlists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	busy := map[primitive.ObjectID]bool{}
+	for _, st := range []string{domain.StatusPending, domain.StatusExecuting} {
+		open, err := u.repo.ListGroupsByStatus(ctx, st)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range open {
+			for _, p := range g.Participants {
+				busy[p] = true
+			}
+		}
+	}
+	skip := map[string]bool{}
+	for _, st := range []string{domain.StatusDeclined, domain.StatusExpired} {
+		closed, err := u.repo.ListGroupsByStatus(ctx, st)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range closed {
+			skip[cycleKey(g.Legs)] = true
+		}
+	}
+
+	var candidates []*domain.Wishlist
+	for _, w := range lists {
+		if !busy[w.UserID] && len(w.Have) > 0 && len(w.Want) > 0 {
+			candidates = append(candidates, w)
+		}
+	}
+
+	var proposed []*domain.ExchangeGroup
+	for _, legs := range findCycles(candidates, maxGroupSize, skip) {
+		g := &domain.ExchangeGroup{
+			ID:        primitive.NewObjectID(),
+			Legs:      legs,
+			ExpiresAt: primitive.NewDateTimeFromTime(time.Now().Add(u.groupTTL)),
+		}
+		for _, l := range legs {
+			g.Participants = append(g.Participants, l.GiverID)
+		}
+		if err := u.lockLegs(ctx, g); err != nil {
+			log.Printf("skipping exchange group of %d users: %v", len(legs), err)
+			continue
+		}
+		created, err := u.repo.CreateGroup(ctx, g)
+		if err != nil {
+			u.lib.releaseLocks(ctx, g.ID)
+			return proposed, err
+		}
+		proposed = append(proposed, created)
+	}
+	return proposed, nil
+}
+
+func (u *groupUseCase) GetGroup(ctx context.Context, id string) (*domain.ExchangeGroup, error) {
+	return u.repo.GetGroup(ctx, id)
+}
+
+func (u *groupUseCase) ListGroupsByUser(ctx context.Context, userID string) ([]*domain.ExchangeGroup, error) {
+	return u.repo.ListGroupsByUser(ctx, userID)
+}
+
+// AcceptGroup records the user's acceptance. Once everyone has accepted,
+// the caller that claims the group for execution moves every book along
+// the ring and completes it; if that fails the libraries are restored and
+// the group goes back to pending, so accepting again retries the exchange.
+func (u *groupUseCase) AcceptGroup(ctx context.Context, groupID, userID string) (*domain.ExchangeGroup, error) {
+	g, err := u.openGroup(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	g, err = u.repo.AddAcceptance(ctx, g.ID.Hex(), userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrGroupNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !g.AcceptedByAll() {
+		return g, nil
+	}
+
+	// несколько последних принятий могут прийти одновременно — книги
+	// переносит только тот, кто перевёл группу в EXECUTING
+	g, err = u.repo.SetGroupStatus(ctx, g.ID.Hex(), domain.StatusPending, domain.StatusExecuting)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrGroupNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	moves := make([]bookMove, len(g.Legs))
+	for i, l := range g.Legs {
+		moves[i] = bookMove{bookID: l.BookID.Hex(), from: l.GiverID.Hex(), to: l.ReceiverID.Hex()}
+	}
+	if _, err := u.lib.moveBooks(ctx, g.ID, moves); err != nil {
+		if _, rerr := u.repo.SetGroupStatus(ctx, g.ID.Hex(), domain.StatusExecuting, domain.StatusPending); rerr != nil {
+			log.Printf("⚠️ group %s stuck in %s: %v", g.ID.Hex(), domain.StatusExecuting, rerr)
+		}
+		return nil, err
+	}
+	completed, err := u.repo.SetGroupStatus(ctx, g.ID.Hex(), domain.StatusExecuting, domain.StatusAccepted)
+	if err != nil {
+		// книги уже у новых владельцев, откатывать обмен нельзя
+		log.Printf("⚠️ group %s exchanged but not marked %s: %v", g.ID.Hex(), domain.StatusAccepted, err)
+		return nil, err
+	}
+	u.settleWishlists(ctx, completed)
+	return completed, nil
+}
+
+// DeclineGroup cancels the whole group and frees its books.
+func (u *groupUseCase) DeclineGroup(ctx context.Context, groupID, userID string) (*domain.ExchangeGroup, error) {
+	g, err := u.openGroup(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	declined, err := u.repo.SetGroupStatus(ctx, g.ID.Hex(), domain.StatusPending, domain.StatusDeclined)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrGroupNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.lib.releaseLocks(ctx, declined.ID)
+	return declined, nil
+}
+
+// ExpireStaleGroups moves pending groups past their expiry to EXPIRED and
+// returns them.
+func (u *groupUseCase) ExpireStaleGroups(ctx context.Context, now time.Time) ([]*domain.ExchangeGroup, error) {
+	stale, err := u.repo.ListExpiredGroups(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	var expired []*domain.ExchangeGroup
+	for _, g := range stale {
+		updated, err := u.repo.SetGroupStatus(ctx, g.ID.Hex(), domain.StatusPending, domain.StatusExpired)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		u.lib.releaseLocks(ctx, updated.ID)
+		expired = append(expired, updated)
+	}
+	return expired, nil
+}
+
+// openGroup loads a group the user takes part in and checks that it can
+// still be answered.
+func (u *groupUseCase) openGroup(ctx context.Context, groupID, userID string) (*domain.ExchangeGroup, error) {
+	g, err := u.repo.GetGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil || !g.HasParticipant(uid) {
+		return nil, domain.ErrNotParticipant
+	}
+	if g.Status != domain.StatusPending {
+		return nil, domain.ErrGroupNotPending
+	}
+	if g.Expired(time.Now()) {
+		return nil, domain.ErrGroupExpired
+	}
+	return g, nil
+}
+
+// lockLegs locks every book of the group in its giver's library. On
+// failure the locks taken so far are released.
+func (u *groupUseCase) lockLegs(ctx context.Context, g *domain.ExchangeGroup) error {
+	for _, l := range g.Legs {
+		if err := u.lib.lockBook(ctx, g.ID, l.GiverID, l.BookID); err != nil {
+			u.lib.releaseLocks(ctx, g.ID)
+			return err
+		}
+	}
+	return nil
+}
+
+// settleWishlists drops the books that changed hands from the wishlists so
+// they are not matched again. Failures are only logged.
+func (u *groupUseCase) settleWishlists(ctx context.Context, g *domain.ExchangeGroup) {
+	for _, l := range g.Legs {
+		if err := u.updateWishlist(ctx, l.GiverID, func(w *domain.Wishlist) {
+			w.Have = removeID(w.Have, l.BookID)
+		}); err != nil {
+			log.Printf("⚠️ cannot update wishlist of user %s: %v", l.GiverID.Hex(), err)
+		}
+		if err := u.updateWishlist(ctx, l.ReceiverID, func(w *domain.Wishlist) {
+			w.Want = removeID(w.Want, l.BookID)
+		}); err != nil {
+			log.Printf("⚠️ cannot update wishlist of user %s: %v", l.ReceiverID.Hex(), err)
+		}
+	}
+}
+
+func (u *groupUseCase) updateWishlist(ctx context.Context, userID primitive.ObjectID, change func(*domain.Wishlist)) error {
+	w, err := u.repo.GetWishlist(ctx, userID.Hex())
+	if err != nil {
+		return err
+	}
+	change(w)
+	_, err = u.repo.SaveWishlist(ctx, w)
+	return err
+}
+
+func uniqueIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	out := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func removeID(ids []primitive.ObjectID, id primitive.ObjectID) []primitive.ObjectID {
+	out := ids[:0]
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/repository"
+)
+
+type fakeGroupRepo struct {
+	repository.GroupRepository
+	wishlists map[primitive.ObjectID]*domain.Wishlist
+	groups    map[primitive.ObjectID]*domain.ExchangeGroup
+}
+
+func newFakeGroupRepo(lists ...*domain.Wishlist) *fakeGroupRepo {
+	r := &fakeGroupRepo{
+		wishlists: map[primitive.ObjectID]*domain.Wishlist{},
+		groups:    map[primitive.ObjectID]*domain.ExchangeGroup{},
+	}
+	for _, w := range lists {
+		r.wishlists[w.UserID] = w
+	}
+	return r
+}
+
+func (r *fakeGroupRepo) SaveWishlist(ctx context.Context, w *domain.Wishlist) (*domain.Wishlist, error) {
+	r.wishlists[w.UserID] = w
+	return w, nil
+}
+func (r *fakeGroupRepo) GetWishlist(ctx context.Context, userID string) (*domain.Wishlist, error) {
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	if w, ok := r.wishlists[uid]; ok {
+		return w, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+func (r *fakeGroupRepo) ListWishlists(ctx context.Context) ([]*domain.Wishlist, error) {
+	var out []*domain.Wishlist
+	for _, w := range r.wishlists {
+		out = append(out, w)
+	}
+	return out, nil
+}
+func (r *fakeGroupRepo) CreateGroup(ctx context.Context, g *domain.ExchangeGroup) (*domain.ExchangeGroup, error) {
+	g.Status = domain.StatusPending
+	r.groups[g.ID] = g
+	cp := *g
+	return &cp, nil
+}
+func (r *fakeGroupRepo) GetGroup(ctx context.Context, id string) (*domain.ExchangeGroup, error) {
+	gid, _ := primitive.ObjectIDFromHex(id)
+	g, ok := r.groups[gid]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	cp := *g
+	return &cp, nil
+}
+func (r *fakeGroupRepo) ListGroupsByStatus(ctx context.Context, status string) ([]*domain.ExchangeGroup, error) {
+	var out []*domain.ExchangeGroup
+	for _, g := range r.groups {
+		if g.Status == status {
+			out = append(out, g)
+		}
+	}
+	return out, nil
+}
+func (r *fakeGroupRepo) AddAcceptance(ctx context.Context, id, userID string) (*domain.ExchangeGroup, error) {
+	gid, _ := primitive.ObjectIDFromHex(id)
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	g, ok := r.groups[gid]
+	if !ok || g.Status != domain.StatusPending {
+		return nil, mongo.ErrNoDocuments
+	}
+	g.AcceptedBy = append(g.AcceptedBy, uid)
+	return r.GetGroup(ctx, id)
+}
+func (r *fakeGroupRepo) SetGroupStatus(ctx context.Context, id, from, to string) (*domain.ExchangeGroup, error) {
+	gid, _ := primitive.ObjectIDFromHex(id)
+	g, ok := r.groups[gid]
+	if !ok || g.Status != from {
+		return nil, mongo.ErrNoDocuments
+	}
+	g.Status = to
+	return r.GetGroup(ctx, id)
+}
+
+// ring gives every user one book that the next user wants: u0→u1→…→u0.
+func ring(n int) ([]*domain.Wishlist, *fakeLib) {
+	lib := &fakeLib{books: map[string][]string{}}
+	lists := make([]*domain.Wishlist, n)
+	books := make([]primitive.ObjectID, n)
+	for i := range lists {
+		books[i] = primitive.NewObjectID()
+		lists[i] = &domain.Wishlist{UserID: primitive.NewObjectID(), Have: []primitive.ObjectID{books[i]}}
+		lib.books[lists[i].UserID.Hex()] = []string{books[i].Hex()}
+	}
+	for i := range lists {
+		lists[(i+1)%n].Want = []primitive.ObjectID{books[i]}
+	}
+	return lists, lib
+}
+
+func TestFindCycles(t *testing.T) {
+	lists, _ := ring(3)
+	cycles := findCycles(lists, maxGroupSize, nil)
+	if len(cycles) != 1 || len(cycles[0]) != 3 {
+		t.Fatalf("expected one ring of 3, got %v", cycles)
+	}
+	for _, l := range cycles[0] {
+		if l.GiverID == l.ReceiverID {
+			t.Errorf("user gives a book to themselves: %+v", l)
+		}
+	}
+	if again := findCycles(lists, maxGroupSize, map[string]bool{cycleKey(cycles[0]): true}); len(again) != 0 {
+		t.Errorf("skipped ring proposed again: %v", again)
+	}
+	if short := findCycles(lists, 2, nil); len(short) != 0 {
+		t.Errorf("ring longer than the limit proposed: %v", short)
+	}
+
+	// прямой обмен двоих предпочтительнее длинного круга через них
+	pair, _ := ring(2)
+	extra := primitive.NewObjectID()
+	pair[0].Want = append(pair[0].Want, extra)
+	third := &domain.Wishlist{
+		UserID: primitive.NewObjectID(),
+		Have:   []primitive.ObjectID{extra},
+		Want:   []primitive.ObjectID{pair[1].Have[0]},
+	}
+	cycles = findCycles(append(pair, third), maxGroupSize, nil)
+	if len(cycles) != 1 || len(cycles[0]) != 2 {
+		t.Fatalf("expected the two-way swap only, got %v", cycles)
+	}
+
+	// отклонённый обмен предлагается снова с другой подходящей книгой
+	pair, _ = ring(2)
+	other := primitive.NewObjectID()
+	pair[0].Have = append(pair[0].Have, other)
+	pair[1].Want = append(pair[1].Want, other)
+	first := findCycles(pair, maxGroupSize, nil)
+	if len(first) != 1 {
+		t.Fatalf("expected one swap, got %v", first)
+	}
+	second := findCycles(pair, maxGroupSize, map[string]bool{cycleKey(first[0]): true})
+	if len(second) != 1 || cycleKey(second[0]) == cycleKey(first[0]) {
+		t.Errorf("expected the swap with the other book, got %v", second)
+	}
+}
+
+func TestGroupExchange(t *testing.T) {
+	ctx := context.Background()
+	lists, lib := ring(3)
+	repo := newFakeGroupRepo(lists...)
+	uc := NewGroupUseCase(repo, lib, nil, time.Hour)
+
+	groups, err := uc.MatchGroups(ctx)
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("expected one group, got %v, %v", groups, err)
+	}
+	g := groups[0]
+	if len(lib.locks) != 3 {
+		t.Errorf("expected every book locked, got %v", lib.locks)
+	}
+	if again, _ := uc.MatchGroups(ctx); len(again) != 0 {
+		t.Errorf("users of a pending group matched again: %v", again)
+	}
+
+	outsider := primitive.NewObjectID().Hex()
+	if _, err := uc.AcceptGroup(ctx, g.ID.Hex(), outsider); !errors.Is(err, domain.ErrNotParticipant) {
+		t.Errorf("expected ErrNotParticipant, got %v", err)
+	}
+
+	for i, p := range g.Participants[:2] {
+		got, err := uc.AcceptGroup(ctx, g.ID.Hex(), p.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != domain.StatusPending || lib.assignCalls != 0 {
+			t.Fatalf("books moved after %d of 3 acceptances", i+1)
+		}
+	}
+	done, err := uc.AcceptGroup(ctx, g.ID.Hex(), g.Participants[2].Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Status != domain.StatusAccepted {
+		t.Errorf("expected ACCEPTED, got %s", done.Status)
+	}
+	for _, l := range g.Legs {
+		got := lib.books[l.ReceiverID.Hex()]
+		if len(got) != 1 || got[0] != l.BookID.Hex() {
+			t.Errorf("user %s should hold %s, has %v", l.ReceiverID.Hex(), l.BookID.Hex(), got)
+		}
+		if w := repo.wishlists[l.ReceiverID]; len(w.Want) != 0 {
+			t.Errorf("received book still wanted: %v", w.Want)
+		}
+	}
+	if len(lib.locks) != 0 {
+		t.Errorf("locks left after the exchange: %v", lib.locks)
+	}
+	calls := lib.assignCalls
+	if _, err := uc.AcceptGroup(ctx, g.ID.Hex(), g.Participants[0].Hex()); !errors.Is(err, domain.ErrGroupNotPending) {
+		t.Errorf("expected ErrGroupNotPending, got %v", err)
+	}
+	if lib.assignCalls != calls {
+		t.Errorf("books moved again by a repeated acceptance")
+	}
+}
+
+// claimedGroupRepo lets another caller claim the group right after the
+// acceptance is recorded.
+type claimedGroupRepo struct {
+	*fakeGroupRepo
+}
+
+func (r claimedGroupRepo) AddAcceptance(ctx context.Context, id, userID string) (*domain.ExchangeGroup, error) {
+	g, err := r.fakeGroupRepo.AddAcceptance(ctx, id, userID)
+	if err == nil && g.AcceptedByAll() {
+		r.groups[g.ID].Status = domain.StatusExecuting
+	}
+	return g, err
+}
+
+func TestAcceptGroup_OnlyOneExecutes(t *testing.T) {
+	ctx := context.Background()
+	lists, lib := ring(2)
+	repo := newFakeGroupRepo(lists...)
+	uc := NewGroupUseCase(claimedGroupRepo{repo}, lib, nil, time.Hour)
+
+	groups, _ := uc.MatchGroups(ctx)
+	if len(groups) != 1 {
+		t.Fatalf("expected one group, got %d", len(groups))
+	}
+	g := groups[0]
+	if _, err := uc.AcceptGroup(ctx, g.ID.Hex(), g.Participants[0].Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.AcceptGroup(ctx, g.ID.Hex(), g.Participants[1].Hex()); !errors.Is(err, domain.ErrGroupNotPending) {
+		t.Errorf("expected ErrGroupNotPending, got %v", err)
+	}
+	if lib.assignCalls != 0 {
+		t.Errorf("books moved by a caller that lost the claim")
+	}
+	if again, _ := uc.MatchGroups(ctx); len(again) != 0 {
+		t.Errorf("users of an executing group matched again: %v", again)
+	}
+}
+
+func TestDeclineGroup(t *testing.T) {
+	ctx := context.Background()
+	lists, lib := ring(2)
+	repo := newFakeGroupRepo(lists...)
+	uc := NewGroupUseCase(repo, lib, nil, time.Hour)
+
+	groups, _ := uc.MatchGroups(ctx)
+	if len(groups) != 1 {
+		t.Fatalf("expected one group, got %d", len(groups))
+	}
+	g := groups[0]
+	declined, err := uc.DeclineGroup(ctx, g.ID.Hex(), g.Participants[1].Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if declined.Status != domain.StatusDeclined || len(lib.locks) != 0 {
+		t.Errorf("decline should close the group and free its books: %s, %v", declined.Status, lib.locks)
+	}
+	if _, err := uc.AcceptGroup(ctx, g.ID.Hex(), g.Participants[0].Hex()); !errors.Is(err, domain.ErrGroupNotPending) {
+		t.Errorf("expected ErrGroupNotPending, got %v", err)
+	}
+	if again, _ := uc.MatchGroups(ctx); len(again) != 0 {
+		t.Errorf("declined ring proposed again: %v", again)
+	}
+}
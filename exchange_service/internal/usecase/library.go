@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+)
+
+// library moves, locks and checks books in users' libraries on behalf of
+// both offers and exchange groups.
+type library struct {
+	client userlibpb.UserLibraryServiceClient
+	refs   RefChecker
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+)
+
+// lockBooks commits the offered books in the owner's library and the
+// requested books in the counterparty's library to the offer, so that they
+// cannot be used by another offer or removed while it is pending. If a
+// book cannot be locked, the locks taken so far are released.
+func (u *exchangeUseCase) lockBooks(ctx context.Context, offer *domain.ExchangeOffer) error {
+	lock := func(userID primitive.ObjectID, bookIDs []primitive.ObjectID) error {
+		for _, id := range bookIDs {
+			if err := u.lib.lockBook(ctx, offer.ID, userID, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := lock(offer.OwnerID, offer.OfferedBookIDs); err != nil {
+		u.lib.releaseLocks(ctx, offer.ID)
+		return err
+	}
+	if err := lock(offer.CounterpartyID, offer.RequestedBookIDs); err != nil {
+		u.lib.releaseLocks(ctx, offer.ID)
+		return err
+	}
+	return nil
+}
+
+// restoreLocks takes the offer's locks back after a failed change. Books
+// that were meanwhile committed elsewhere stay unlocked; that is logged.
+func (u *exchangeUseCase) restoreLocks(ctx context.Context, offer *domain.ExchangeOffer) {
+	if err := u.lockBooks(ctx, offer); err != nil {
+		log.Printf("⚠️ cannot restore locks of offer %s: %v", offer.ID.Hex(), err)
+	}
+}
+
+func (l *library) lockBook(ctx context.Context, offerID, userID, bookID primitive.ObjectID) error {
+	_, err := l.client.LockBook(ctx, &userlibpb.LockBookRequest{
+		UserId:  userID.Hex(),
+		BookId:  bookID.Hex(),
+		OfferId: offerID.Hex(),
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: book %s of user %s", domain.ErrBookLocked, bookID.Hex(), userID.Hex())
+	case codes.NotFound:
+		return fmt.Errorf("%w: user %s does not own book %s", domain.ErrBookNotOwned, userID.Hex(), bookID.Hex())
+	default:
+		return fmt.Errorf("cannot lock book %s: %w", bookID.Hex(), err)
+	}
+}
+
+func (l *library) unlockBook(ctx context.Context, offerID, userID, bookID primitive.ObjectID) {
+	_, err := l.client.UnlockBook(ctx, &userlibpb.LockBookRequest{
+		UserId:  userID.Hex(),
+		BookId:  bookID.Hex(),
+		OfferId: offerID.Hex(),
+	})
+	if err != nil {
+		log.Printf("⚠️ cannot unlock book %s for offer %s: %v", bookID.Hex(), offerID.Hex(), err)
+	}
+}
+
+// releaseLocks frees every book held by an offer that is no longer
+// pending. Failures are only logged: the offer itself is already closed.
+func (l *library) releaseLocks(ctx context.Context, offerID primitive.ObjectID) {
+	if _, err := l.client.ReleaseLocks(ctx, &userlibpb.ReleaseLocksRequest{OfferId: offerID.Hex()}); err != nil {
+		log.Printf("⚠️ cannot release books of offer %s: %v", offerID.Hex(), err)
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+)
+
+// maxGroupSize bounds the number of participants in one exchange group;
+// longer rings are unlikely to be accepted by everyone.
+const maxGroupSize = 5
+
+// findCycles matches wishlists into exchange rings. There is an edge from
+// user A to user B when A has a book that B wants; every cycle in that
+// graph is an exchange where each participant gives one book and gets one
+// they asked for.
+//
+// Shorter cycles are preferred and each user takes part in at most one
+// cycle per run, so the result is a set of disjoint groups. Cycles whose
+// key is in skip (rings that were already declined or let expire) are not
+// proposed again. The result is deterministic for the same input.
+func findCycles(lists []*domain.Wishlist, maxLen int, skip map[string]bool) [][]domain.GroupLeg {
+	if maxLen > maxGroupSize {
+		maxLen = maxGroupSize
+	}
+	lists = append([]*domain.Wishlist(nil), lists...)
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].UserID.Hex() < lists[j].UserID.Hex()
+	})
+
+	n := len(lists)
+	wantedBy := map[primitive.ObjectID][]int{}
+	for i, w := range lists {
+		for _, id := range w.Want {
+			wantedBy[id] = append(wantedBy[id], i)
+		}
+	}
+	// gives[i][j] lists every book user i can hand to user j; next[i] are
+	// the users i can give to, in index order
+	gives := make([]map[int][]primitive.ObjectID, n)
+	next := make([][]int, n)
+	for i, w := range lists {
+		gives[i] = map[int][]primitive.ObjectID{}
+		for _, book := range w.Have {
+			for _, j := range wantedBy[book] {
+				if j != i {
+					gives[i][j] = append(gives[i][j], book)
+				}
+			}
+		}
+		for j, books := range gives[i] {
+			sort.Slice(books, func(a, b int) bool { return books[a].Hex() < books[b].Hex() })
+			next[i] = append(next[i], j)
+		}
+		sort.Ints(next[i])
+	}
+
+	// pickBooks chooses a book for every leg of the ring, trying the
+	// candidates in order until the ring is not one to skip.
+	pickBooks := func(path []int) []domain.GroupLeg {
+		legs := make([]domain.GroupLeg, len(path))
+		var pick func(k int) bool
+		pick = func(k int) bool {
+			if k == len(path) {
+				return !skip[cycleKey(legs)]
+			}
+			from, to := path[k], path[(k+1)%len(path)]
+			for _, book := range gives[from][to] {
+				legs[k] = domain.GroupLeg{
+					GiverID:    lists[from].UserID,
+					ReceiverID: lists[to].UserID,
+					BookID:     book,
+				}
+				if pick(k + 1) {
+					return true
+				}
+			}
+			return false
+		}
+		if pick(0) {
+			return legs
+		}
+		return nil
+	}
+
+	used := make([]bool, n)
+	// search extends path to exactly size users. The first user has the
+	// lowest index in the cycle, so every ring is found once.
+	var search func(path []int, size int) ([]int, []domain.GroupLeg)
+	search = func(path []int, size int) ([]int, []domain.GroupLeg) {
+		last := path[len(path)-1]
+		if len(path) == size {
+			if _, ok := gives[last][path[0]]; ok {
+				if legs := pickBooks(path); legs != nil {
+					return path, legs
+				}
+			}
+			return nil, nil
+		}
+		for _, to := range next[last] {
+			if to <= path[0] || used[to] || contains(path, to) {
+				continue
+			}
+			if found, legs := search(append(path, to), size); legs != nil {
+				return found, legs
+			}
+		}
+		return nil, nil
+	}
+
+	var cycles [][]domain.GroupLeg
+	for size := 2; size <= maxLen; size++ {
+		for start := 0; start < n; start++ {
+			if used[start] || len(next[start]) == 0 {
+				continue
+			}
+			path, legs := search([]int{start}, size)
+			if legs == nil {
+				continue
+			}
+			for _, i := range path {
+				used[i] = true
+			}
+			cycles = append(cycles, legs)
+		}
+	}
+	return cycles
+}
+
+// cycleKey identifies a ring by its legs regardless of where it starts.
+func cycleKey(legs []domain.GroupLeg) string {
+	parts := make([]string, len(legs))
+	for i, l := range legs {
+		parts[i] = l.GiverID.Hex() + ">" + l.ReceiverID.Hex() + ":" + l.BookID.Hex()
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+func contains(path []int, v int) bool {
+	for _, p := range path {
+		if p == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+)
+
+// CancelUserOffers closes the pending offers made by or to a deleted user
+// and frees the books they locked.
+func (u *exchangeUseCase) CancelUserOffers(ctx context.Context, userID string) ([]*domain.ExchangeOffer, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.cancelPending(ctx, func(o *domain.ExchangeOffer) bool {
+		return o.OwnerID == uid || o.CounterpartyID == uid
+	})
+}
+
+// CancelBookOffers closes the pending offers that include a deleted book.
+func (u *exchangeUseCase) CancelBookOffers(ctx context.Context, bookID string) ([]*domain.ExchangeOffer, error) {
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, err
+	}
+	return u.cancelPending(ctx, func(o *domain.ExchangeOffer) bool {
+		return hasID(o.OfferedBookIDs, bid) || hasID(o.RequestedBookIDs, bid)
+	})
+}
+
+func (u *exchangeUseCase) cancelPending(ctx context.Context, match func(*domain.ExchangeOffer) bool) ([]*domain.ExchangeOffer, error) {
+	pending, err := u.repo.ListOffersByStatus(ctx, domain.StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	var cancelled []*domain.ExchangeOffer
+	for _, o := range pending {
+		if !match(o) {
+			continue
+		}
+		updated, err := u.repo.SetStatus(ctx, o.ID.Hex(), domain.StatusPending, domain.StatusCancelled)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return cancelled, err
+		}
+		u.lib.releaseLocks(ctx, updated.ID)
+		u.cache.InvalidateUser(ctx, updated.OwnerID.Hex())
+		u.cache.InvalidateUser(ctx, updated.CounterpartyID.Hex())
+		cancelled = append(cancelled, updated)
+	}
+	if len(cancelled) > 0 {
+		u.cache.InvalidatePending(ctx)
+	}
+	return cancelled, nil
+}
+
+// CancelUserGroups closes the pending groups a deleted user takes part in
+// and drops the user's wishlist.
+func (u *groupUseCase) CancelUserGroups(ctx context.Context, userID string) ([]*domain.ExchangeGroup, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	cancelled, err := u.cancelPending(ctx, func(g *domain.ExchangeGroup) bool {
+		return g.HasParticipant(uid)
+	})
+	if err != nil {
+		return cancelled, err
+	}
+	return cancelled, u.repo.DeleteWishlist(ctx, userID)
+}
+
+// CancelBookGroups closes the pending groups moving a deleted book and
+// removes the book from every wishlist.
+func (u *groupUseCase) CancelBookGroups(ctx context.Context, bookID string) ([]*domain.ExchangeGroup, error) {
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, err
+	}
+	cancelled, err := u.cancelPending(ctx, func(g *domain.ExchangeGroup) bool {
+		for _, l := range g.Legs {
+			if l.BookID == bid {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return cancelled, err
+	}
+	return cancelled, u.repo.PullBookFromWishlists(ctx, bookID)
+}
+
+func (u *groupUseCase) cancelPending(ctx context.Context, match func(*domain.ExchangeGroup) bool) ([]*domain.ExchangeGroup, error) {
+	pending, err := u.repo.ListGroupsByStatus(ctx, domain.StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	var cancelled []*domain.ExchangeGroup
+	for _, g := range pending {
+		if !match(g) {
+			continue
+		}
+		updated, err := u.repo.SetGroupStatus(ctx, g.ID.Hex(), domain.StatusPending, domain.StatusCancelled)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return cancelled, err
+		}
+		u.lib.releaseLocks(ctx, updated.ID)
+		cancelled = append(cancelled, updated)
+	}
+	return cancelled, nil
+}
+
+func hasID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+)
+
+// libraryStep is one completed change to a user library, kept so that a
+// failed swap can be undone.
+type libraryStep struct {
+	assign bool
+	userID string
+	bookID string
+}
+
+// verifyOwnership checks that the user's library holds every book, counting
+// copies when a book is listed more than once.
+func (l *library) verifyOwnership(ctx context.Context, userID string, bookIDs []primitive.ObjectID) error {
+	resp, err := l.client.ListUserBooks(ctx, &userlibpb.ListUserBooksRequest{UserId: userID})
+	if err != nil {
+		return fmt.Errorf("cannot list books of user %s: %w", userID, err)
+	}
+	owned := make(map[string]int, len(resp.Entries))
+	for _, e := range resp.Entries {
+		owned[e.BookId]++
+	}
+	for _, id := range bookIDs {
+		if owned[id.Hex()] == 0 {
+			return fmt.Errorf("%w: user %s does not own book %s", domain.ErrBookNotOwned, userID, id.Hex())
+		}
+		owned[id.Hex()]--
+	}
+	return nil
+}
+
+// verifyNotOwned checks that the user's library holds none of the books.
+// A library keeps one entry per book, so a user cannot receive a book they
+// already have.
+func (l *library) verifyNotOwned(ctx context.Context, userID string, bookIDs []primitive.ObjectID) error {
+	resp, err := l.client.ListUserBooks(ctx, &userlibpb.ListUserBooksRequest{UserId: userID})
+	if err != nil {
+		return fmt.Errorf("cannot list books of user %s: %w", userID, err)
+	}
+	owned := make(map[string]bool, len(resp.Entries))
+	for _, e := range resp.Entries {
+		owned[e.BookId] = true
+	}
+	for _, id := range bookIDs {
+		if owned[id.Hex()] {
+			return fmt.Errorf("%w: user %s already has book %s", domain.ErrAlreadyOwned, userID, id.Hex())
+		}
+	}
+	return nil
+}
+
+// bookMove hands one copy of a book from one library to another.
+type bookMove struct {
+	bookID, from, to string
+}
+
+// swapBooks moves the offered books to the counterparty and the requested
+// books to the owner. If any call fails, the steps done so far are undone
+// and ErrSwapFailed is returned.
+func (u *exchangeUseCase) swapBooks(ctx context.Context, offer *domain.ExchangeOffer) ([]libraryStep, error) {
+	owner, counterparty := offer.OwnerID.Hex(), offer.CounterpartyID.Hex()
+
+	moves := make([]bookMove, 0, len(offer.OfferedBookIDs)+len(offer.RequestedBookIDs))
+	for _, id := range offer.OfferedBookIDs {
+		moves = append(moves, bookMove{bookID: id.Hex(), from: owner, to: counterparty})
+	}
+	for _, id := range offer.RequestedBookIDs {
+		moves = append(moves, bookMove{bookID: id.Hex(), from: counterparty, to: owner})
+	}
+	return u.lib.moveBooks(ctx, offer.ID, moves)
+}
+
+// moveBooks performs the moves in order, taking the copies locked by
+// holdID. On failure the steps done so far are undone and ErrSwapFailed is
+// returned.
+func (l *library) moveBooks(ctx context.Context, holdID primitive.ObjectID, moves []bookMove) ([]libraryStep, error) {
+	var done []libraryStep
+	move := func(m bookMove) error {
+		req := &userlibpb.UnassignBookRequest{UserId: m.from, BookId: m.bookID, OfferId: holdID.Hex()}
+		if _, err := l.client.UnassignBook(ctx, req); err != nil {
+			return fmt.Errorf("%w: unassign book %s from %s: %v", domain.ErrSwapFailed, m.bookID, m.from, err)
+		}
+		done = append(done, libraryStep{userID: m.from, bookID: m.bookID})
+		// offers and wishlists never send a book to a user who has it; if
+		// the receiver got one since, the exchange fails rather than a
+		// rollback removing the receiver's own entry
+		assign := &userlibpb.AssignBookRequest{UserId: m.to, BookId: m.bookID, FailIfExists: true}
+		if _, err := l.client.AssignBook(ctx, assign); err != nil {
+			return fmt.Errorf("%w: assign book %s to %s: %v", domain.ErrSwapFailed, m.bookID, m.to, err)
+		}
+		done = append(done, libraryStep{assign: true, userID: m.to, bookID: m.bookID})
+		return nil
+	}
+
+	for _, m := range moves {
+		if err := move(m); err != nil {
+			l.rollback(ctx, holdID, done)
+			return nil, err
+		}
+	}
+	return done, nil
+}
+
+// rollback undoes completed library changes in reverse order; copies put
+// back are locked to the offer or group again. It keeps going on errors so
+// that as much as possible is restored.
+func (l *library) rollback(ctx context.Context, offerID primitive.ObjectID, done []libraryStep) {
+	for i := len(done) - 1; i >= 0; i-- {
+		s := done[i]
+		var err error
+		if s.assign {
+			_, err = l.client.UnassignBook(ctx, &userlibpb.UnassignBookRequest{UserId: s.userID, BookId: s.bookID})
+		} else {
+			_, err = l.client.AssignBook(ctx, &userlibpb.AssignBookRequest{UserId: s.userID, BookId: s.bookID})
+			if err == nil {
+				_, err = l.client.LockBook(ctx, &userlibpb.LockBookRequest{UserId: s.userID, BookId: s.bookID, OfferId: offerID.Hex()})
+			}
+		}
+		if err != nil {
+			log.Printf("⚠️ rollback of book %s for user %s failed: %v", s.bookID, s.userID, err)
+		}
+	}
+}
@@ -3,48 +3,119 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"testing"
+	"time"
+
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/cache"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
 	"github.com/OshakbayAigerim/read_space/exchange_service/internal/repository"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
 	userlibpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"testing"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type fakeRepo struct {
 	repository.ExchangeRepository
 	acceptCalled, declineCalled, deleteCalled bool
+	offer                                     *domain.ExchangeOffer
+	created                                   []*domain.ExchangeOffer
+}
+
+func newPendingOffer() *domain.ExchangeOffer {
+	return &domain.ExchangeOffer{
+		ID:               primitive.NewObjectID(),
+		OwnerID:          primitive.NewObjectID(),
+		CounterpartyID:   primitive.NewObjectID(),
+		OfferedBookIDs:   []primitive.ObjectID{primitive.NewObjectID()},
+		RequestedBookIDs: []primitive.ObjectID{primitive.NewObjectID()},
+		Status:           domain.StatusPending,
+	}
 }
 
 func (r *fakeRepo) CreateOffer(ctx context.Context, offer *domain.ExchangeOffer) (*domain.ExchangeOffer, error) {
+	if offer.ID.IsZero() {
+		offer.ID = primitive.NewObjectID()
+	}
+	offer.Status = domain.StatusPending
+	r.created = append(r.created, offer)
 	return offer, nil
 }
+func (r *fakeRepo) ListExpired(ctx context.Context, now time.Time) ([]*domain.ExchangeOffer, error) {
+	if r.offer != nil && r.offer.Status == domain.StatusPending && r.offer.Expired(now) {
+		cp := *r.offer
+		return []*domain.ExchangeOffer{&cp}, nil
+	}
+	return nil, nil
+}
+func (r *fakeRepo) SetStatus(ctx context.Context, id, from, to string) (*domain.ExchangeOffer, error) {
+	if r.offer.Status != from {
+		return nil, mongo.ErrNoDocuments
+	}
+	r.offer.Status = to
+	return r.GetOffer(ctx, id)
+}
 func (r *fakeRepo) GetOffer(ctx context.Context, id string) (*domain.ExchangeOffer, error) {
 	if id == "err" {
 		return nil, errors.New("not found")
 	}
+	if r.offer != nil {
+		cp := *r.offer
+		return &cp, nil
+	}
 	return &domain.ExchangeOffer{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID()}, nil
 }
 func (r *fakeRepo) ListOffersByUser(ctx context.Context, ownerID string) ([]*domain.ExchangeOffer, error) {
 	return []*domain.ExchangeOffer{{ID: primitive.NewObjectID()}}, nil
 }
+func (r *fakeRepo) ListOffersByStatus(ctx context.Context, status string) ([]*domain.ExchangeOffer, error) {
+	if r.offer == nil || r.offer.Status != status {
+		return nil, nil
+	}
+	cp := *r.offer
+	return []*domain.ExchangeOffer{&cp}, nil
+}
 func (r *fakeRepo) ListPendingOffers(ctx context.Context) ([]*domain.ExchangeOffer, error) {
 	return []*domain.ExchangeOffer{{ID: primitive.NewObjectID()}}, nil
 }
 func (r *fakeRepo) AcceptOffer(ctx context.Context, id string) (*domain.ExchangeOffer, error) {
 	r.acceptCalled = true
-	owner := primitive.NewObjectID()
-	return &domain.ExchangeOffer{
-		ID:               primitive.NewObjectID(),
-		OwnerID:          owner,
-		OfferedBookIDs:   []primitive.ObjectID{primitive.NewObjectID()},
-		RequestedBookIDs: []primitive.ObjectID{primitive.NewObjectID()},
-	}, nil
+	cp := *r.offer
+	cp.Status = domain.StatusAccepted
+	return &cp, nil
 }
 func (r *fakeRepo) DeclineOffer(ctx context.Context, id string) (*domain.ExchangeOffer, error) {
 	r.declineCalled = true
+	if r.offer != nil {
+		return r.SetStatus(ctx, id, domain.StatusPending, domain.StatusDeclined)
+	}
 	return &domain.ExchangeOffer{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID()}, nil
 }
+func (r *fakeRepo) AddOfferedBook(ctx context.Context, id, bookID string) (*domain.ExchangeOffer, error) {
+	if r.offer.Status != domain.StatusPending {
+		return nil, mongo.ErrNoDocuments
+	}
+	bid, _ := primitive.ObjectIDFromHex(bookID)
+	r.offer.OfferedBookIDs = append(r.offer.OfferedBookIDs, bid)
+	return r.GetOffer(ctx, id)
+}
+func (r *fakeRepo) RemoveOfferedBook(ctx context.Context, id, bookID string) (*domain.ExchangeOffer, error) {
+	if r.offer.Status != domain.StatusPending || len(r.offer.OfferedBookIDs) < 2 {
+		return nil, mongo.ErrNoDocuments
+	}
+	var kept []primitive.ObjectID
+	for _, b := range r.offer.OfferedBookIDs {
+		if b.Hex() != bookID {
+			kept = append(kept, b)
+		}
+	}
+	r.offer.OfferedBookIDs = kept
+	return r.GetOffer(ctx, id)
+}
 func (r *fakeRepo) DeleteOffer(ctx context.Context, id string) error {
 	r.deleteCalled = true
 	return nil
@@ -74,38 +145,136 @@ func (c *fakeCache) InvalidatePending(ctx context.Context) error {
 	return nil
 }
 
+// fakeLib keeps user libraries in memory: user ID → book IDs. Locks are
+// tracked per user and book, one copy each.
 type fakeLib struct {
 	userlibpb.UserLibraryServiceClient
-	unassignErr, assignErr     bool
+	books                      map[string][]string
+	locks                      map[string]string // "user/book" → offer ID
+	failAssignTo               string            // the next assign to this user fails
 	unassignCalls, assignCalls int
 }
 
-func (f *fakeLib) UnassignBook(ctx context.Context, req *userlibpb.UnassignBookRequest) (*userlibpb.UnassignBookResponse, error) {
+func (f *fakeLib) UnassignBook(ctx context.Context, req *userlibpb.UnassignBookRequest, opts ...grpc.CallOption) (*userlibpb.UnassignBookResponse, error) {
 	f.unassignCalls++
-	if f.unassignErr {
-		return nil, errors.New("unassign error")
+	key := req.UserId + "/" + req.BookId
+	if held, ok := f.locks[key]; ok && held != req.OfferId {
+		return nil, status.Error(codes.FailedPrecondition, "book is locked")
+	}
+	books := f.books[req.UserId]
+	for i, b := range books {
+		if b == req.BookId {
+			f.books[req.UserId] = append(books[:i:i], books[i+1:]...)
+			delete(f.locks, key)
+			return &userlibpb.UnassignBookResponse{Success: true}, nil
+		}
+	}
+	return nil, errors.New("book not in library")
+}
+
+func (f *fakeLib) LockBook(ctx context.Context, req *userlibpb.LockBookRequest, opts ...grpc.CallOption) (*userlibpb.AssignBookResponse, error) {
+	owned := false
+	for _, b := range f.books[req.UserId] {
+		owned = owned || b == req.BookId
+	}
+	if !owned {
+		return nil, status.Error(codes.NotFound, "book not in library")
+	}
+	key := req.UserId + "/" + req.BookId
+	if held, ok := f.locks[key]; ok && held != req.OfferId {
+		return nil, status.Error(codes.FailedPrecondition, "book is locked")
+	}
+	if f.locks == nil {
+		f.locks = map[string]string{}
+	}
+	f.locks[key] = req.OfferId
+	return &userlibpb.AssignBookResponse{Entry: &userlibpb.UserBook{UserId: req.UserId, BookId: req.BookId, LockedBy: req.OfferId}}, nil
+}
+
+func (f *fakeLib) UnlockBook(ctx context.Context, req *userlibpb.LockBookRequest, opts ...grpc.CallOption) (*userlibpb.UnassignBookResponse, error) {
+	key := req.UserId + "/" + req.BookId
+	if f.locks[key] == req.OfferId {
+		delete(f.locks, key)
 	}
 	return &userlibpb.UnassignBookResponse{Success: true}, nil
 }
-func (f *fakeLib) AssignBook(ctx context.Context, req *userlibpb.AssignBookRequest) (*userlibpb.AssignBookResponse, error) {
+
+func (f *fakeLib) ReleaseLocks(ctx context.Context, req *userlibpb.ReleaseLocksRequest, opts ...grpc.CallOption) (*userlibpb.ReleaseLocksResponse, error) {
+	for key, held := range f.locks {
+		if held == req.OfferId {
+			delete(f.locks, key)
+		}
+	}
+	return &userlibpb.ReleaseLocksResponse{}, nil
+}
+func (f *fakeLib) AssignBook(ctx context.Context, req *userlibpb.AssignBookRequest, opts ...grpc.CallOption) (*userlibpb.AssignBookResponse, error) {
 	f.assignCalls++
-	if f.assignErr {
+	if req.UserId == f.failAssignTo {
+		f.failAssignTo = ""
 		return nil, errors.New("assign error")
 	}
-	return &userlibpb.AssignBookResponse{Entry: &userlibpb.UserBook{}}, nil
+	for _, b := range f.books[req.UserId] {
+		if b == req.BookId && req.FailIfExists {
+			return nil, status.Error(codes.AlreadyExists, "book is already in the user's library")
+		}
+	}
+	f.books[req.UserId] = append(f.books[req.UserId], req.BookId)
+	return &userlibpb.AssignBookResponse{Entry: &userlibpb.UserBook{UserId: req.UserId, BookId: req.BookId}}, nil
+}
+
+func (f *fakeLib) ListUserBooks(ctx context.Context, req *userlibpb.ListUserBooksRequest, opts ...grpc.CallOption) (*userlibpb.ListUserBooksResponse, error) {
+	var entries []*userlibpb.UserBook
+	for _, b := range f.books[req.UserId] {
+		entries = append(entries, &userlibpb.UserBook{UserId: req.UserId, BookId: b})
+	}
+	return &userlibpb.ListUserBooksResponse{Entries: entries}, nil
+}
+
+// fakeRefs knows the listed books and every user except the deleted ones.
+type fakeRefs struct {
+	known   map[string]bool
+	deleted map[string]bool
+}
+
+func (f *fakeRefs) UserExists(ctx context.Context, id string) error {
+	if f.deleted[id] {
+		return fmt.Errorf("%w: %s", refcheck.ErrUnknownUser, id)
+	}
+	return nil
+}
+
+func (f *fakeRefs) BookExists(ctx context.Context, id string) error {
+	if !f.known[id] {
+		return fmt.Errorf("%w: %s", refcheck.ErrUnknownBook, id)
+	}
+	return nil
+}
+
+// catalogFor knows every book of the offer.
+func catalogFor(o *domain.ExchangeOffer) *fakeRefs {
+	known := map[string]bool{}
+	for _, id := range append(o.OfferedBookIDs, o.RequestedBookIDs...) {
+		known[id.Hex()] = true
+	}
+	return &fakeRefs{known: known, deleted: map[string]bool{}}
 }
 
-func (f *fakeLib) ListUserBooks(ctx context.Context, req *userlibpb.ListUserBooksRequest) (*userlibpb.ListUserBooksResponse, error) {
-	return &userlibpb.ListUserBooksResponse{}, nil
+// libraryFor gives both parties of the offer the books they put in it.
+func libraryFor(o *domain.ExchangeOffer) *fakeLib {
+	return &fakeLib{books: map[string][]string{
+		o.OwnerID.Hex():        {o.OfferedBookIDs[0].Hex()},
+		o.CounterpartyID.Hex(): {o.RequestedBookIDs[0].Hex()},
+	}}
 }
 
 func TestCreateOffer_InvalidatesCache(t *testing.T) {
 	repo := &fakeRepo{}
 	cache := &fakeCache{}
-	uc := NewExchangeUseCase(repo, cache, nil)
+	offer := newPendingOffer()
+	uc := NewExchangeUseCase(repo, cache, libraryFor(offer), catalogFor(offer), time.Hour)
 
-	owner := primitive.NewObjectID()
-	off, err := uc.CreateOffer(context.Background(), &domain.ExchangeOffer{OwnerID: owner})
+	owner := offer.OwnerID
+	off, err := uc.CreateOffer(context.Background(), offer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -120,8 +289,92 @@ func TestCreateOffer_InvalidatesCache(t *testing.T) {
 	}
 }
 
+func TestCreateOffer_ValidatesBooks(t *testing.T) {
+	ctx := context.Background()
+
+	offer := newPendingOffer()
+	books := catalogFor(offer)
+	delete(books.known, offer.RequestedBookIDs[0].Hex())
+	uc := NewExchangeUseCase(&fakeRepo{}, &fakeCache{}, libraryFor(offer), books, time.Hour)
+	if _, err := uc.CreateOffer(ctx, offer); !errors.Is(err, domain.ErrUnknownBook) {
+		t.Errorf("unknown book: expected ErrUnknownBook, got %v", err)
+	}
+
+	offer = newPendingOffer()
+	books = catalogFor(offer)
+	books.deleted[offer.CounterpartyID.Hex()] = true
+	uc = NewExchangeUseCase(&fakeRepo{}, &fakeCache{}, libraryFor(offer), books, time.Hour)
+	if _, err := uc.CreateOffer(ctx, offer); !errors.Is(err, domain.ErrUnknownUser) {
+		t.Errorf("deleted counterparty: expected ErrUnknownUser, got %v", err)
+	}
+
+	offer = newPendingOffer()
+	lib := libraryFor(offer)
+	lib.books[offer.OwnerID.Hex()] = nil
+	uc = NewExchangeUseCase(&fakeRepo{}, &fakeCache{}, lib, catalogFor(offer), time.Hour)
+	if _, err := uc.CreateOffer(ctx, offer); !errors.Is(err, domain.ErrBookNotOwned) {
+		t.Errorf("offered book not owned: expected ErrBookNotOwned, got %v", err)
+	}
+
+	offer = newPendingOffer()
+	lib = libraryFor(offer)
+	cp := offer.CounterpartyID.Hex()
+	lib.books[cp] = append(lib.books[cp], offer.OfferedBookIDs[0].Hex())
+	uc = NewExchangeUseCase(&fakeRepo{}, &fakeCache{}, lib, catalogFor(offer), time.Hour)
+	if _, err := uc.CreateOffer(ctx, offer); !errors.Is(err, domain.ErrAlreadyOwned) {
+		t.Errorf("counterparty has the offered book: expected ErrAlreadyOwned, got %v", err)
+	}
+
+	// одну и ту же книгу нельзя предложить дважды, если экземпляр один
+	offer = newPendingOffer()
+	repo := &fakeRepo{offer: offer}
+	uc = NewExchangeUseCase(repo, &fakeCache{}, libraryFor(offer), catalogFor(offer), time.Hour)
+	if _, err := uc.AddOfferedBook(ctx, offer.ID.Hex(), offer.OfferedBookIDs[0].Hex()); !errors.Is(err, domain.ErrBookNotOwned) {
+		t.Errorf("second copy: expected ErrBookNotOwned, got %v", err)
+	}
+}
+
+func TestCreateOffer_LocksBooks(t *testing.T) {
+	ctx := context.Background()
+	first := newPendingOffer()
+	lib := libraryFor(first)
+	repo := &fakeRepo{}
+	uc := NewExchangeUseCase(repo, &fakeCache{}, lib, catalogFor(first), time.Hour)
+	if _, err := uc.CreateOffer(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if len(lib.locks) != 2 {
+		t.Fatalf("expected both books locked, got %v", lib.locks)
+	}
+
+	// та же книга во втором предложении занята первым
+	second := newPendingOffer()
+	second.OwnerID = first.OwnerID
+	second.OfferedBookIDs = first.OfferedBookIDs
+	lib.books[second.CounterpartyID.Hex()] = []string{second.RequestedBookIDs[0].Hex()}
+	uc = NewExchangeUseCase(repo, &fakeCache{}, lib, catalogFor(second), time.Hour)
+	if _, err := uc.CreateOffer(ctx, second); !errors.Is(err, domain.ErrBookLocked) {
+		t.Fatalf("expected ErrBookLocked, got %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Errorf("locked offer must not be stored, got %d offers", len(repo.created))
+	}
+	if held := lib.locks[second.CounterpartyID.Hex()+"/"+second.RequestedBookIDs[0].Hex()]; held != "" {
+		t.Errorf("failed offer left a lock behind: %s", held)
+	}
+
+	repo.offer = first
+	if _, err := uc.DeclineOffer(ctx, first.ID.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	second.ID = primitive.NilObjectID
+	if _, err := uc.CreateOffer(ctx, second); err != nil {
+		t.Errorf("books should be free after decline, got %v", err)
+	}
+}
+
 func TestListMethods_UseCache(t *testing.T) {
-	uc := NewExchangeUseCase(&fakeRepo{}, &fakeCache{}, nil)
+	uc := NewExchangeUseCase(&fakeRepo{}, &fakeCache{}, nil, nil, time.Hour)
 
 	uc.ListOffersByUser(context.Background(), "u1")
 	fc := uc.(*exchangeUseCase).cache.(*fakeCache)
@@ -136,12 +389,13 @@ func TestListMethods_UseCache(t *testing.T) {
 }
 
 func TestAcceptOffer_FlowAndInvalidate(t *testing.T) {
-	repo := &fakeRepo{}
+	offer := newPendingOffer()
+	repo := &fakeRepo{offer: offer}
 	cache := &fakeCache{}
-	lib := &fakeLib{}
-	uc := NewExchangeUseCase(repo, cache, lib)
+	lib := libraryFor(offer)
+	uc := NewExchangeUseCase(repo, cache, lib, nil, time.Hour)
 
-	_, err := uc.AcceptOffer(context.Background(), "id", "req")
+	_, err := uc.AcceptOffer(context.Background(), offer.ID.Hex(), offer.CounterpartyID.Hex())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -154,12 +408,220 @@ func TestAcceptOffer_FlowAndInvalidate(t *testing.T) {
 	if !cache.invalPending || len(cache.invalUsers) != 2 {
 		t.Errorf("expected pending+2 users invalidated, got %v/%v", cache.invalPending, cache.invalUsers)
 	}
+	owner, cp := lib.books[offer.OwnerID.Hex()], lib.books[offer.CounterpartyID.Hex()]
+	if len(owner) != 1 || owner[0] != offer.RequestedBookIDs[0].Hex() ||
+		len(cp) != 1 || cp[0] != offer.OfferedBookIDs[0].Hex() {
+		t.Errorf("books not swapped: owner=%v counterparty=%v", owner, cp)
+	}
+}
+
+func TestAcceptOffer_BooksNoLongerOwned(t *testing.T) {
+	offer := newPendingOffer()
+	repo := &fakeRepo{offer: offer}
+	lib := libraryFor(offer)
+	lib.books[offer.CounterpartyID.Hex()] = nil
+	uc := NewExchangeUseCase(repo, &fakeCache{}, lib, nil, time.Hour)
+
+	_, err := uc.AcceptOffer(context.Background(), offer.ID.Hex(), offer.CounterpartyID.Hex())
+	if !errors.Is(err, domain.ErrBookNotOwned) {
+		t.Fatalf("expected ErrBookNotOwned, got %v", err)
+	}
+	if repo.acceptCalled || lib.unassignCalls != 0 {
+		t.Error("offer accepted or books moved despite failed ownership check")
+	}
+}
+
+func TestAcceptOffer_RollsBackOnFailure(t *testing.T) {
+	offer := newPendingOffer()
+	repo := &fakeRepo{offer: offer}
+	lib := libraryFor(offer)
+	// второй перенос (книга владельцу) падает
+	lib.failAssignTo = offer.OwnerID.Hex()
+	uc := NewExchangeUseCase(repo, &fakeCache{}, lib, nil, time.Hour)
+
+	_, err := uc.AcceptOffer(context.Background(), offer.ID.Hex(), offer.CounterpartyID.Hex())
+	if !errors.Is(err, domain.ErrSwapFailed) {
+		t.Fatalf("expected ErrSwapFailed, got %v", err)
+	}
+	if repo.acceptCalled {
+		t.Error("offer accepted despite failed swap")
+	}
+	owner, cp := lib.books[offer.OwnerID.Hex()], lib.books[offer.CounterpartyID.Hex()]
+	if len(owner) != 1 || owner[0] != offer.OfferedBookIDs[0].Hex() ||
+		len(cp) != 1 || cp[0] != offer.RequestedBookIDs[0].Hex() {
+		t.Errorf("libraries not restored: owner=%v counterparty=%v", owner, cp)
+	}
+}
+
+func TestAcceptOffer_ReceiverAlreadyOwnsBook(t *testing.T) {
+	offer := newPendingOffer()
+	repo := &fakeRepo{offer: offer}
+	lib := libraryFor(offer)
+	cp := offer.CounterpartyID.Hex()
+	lib.books[cp] = append(lib.books[cp], offer.OfferedBookIDs[0].Hex())
+	uc := NewExchangeUseCase(repo, &fakeCache{}, lib, nil, time.Hour)
+
+	if _, err := uc.AcceptOffer(context.Background(), offer.ID.Hex(), cp); !errors.Is(err, domain.ErrAlreadyOwned) {
+		t.Fatalf("expected ErrAlreadyOwned, got %v", err)
+	}
+	if lib.assignCalls != 0 {
+		t.Errorf("books moved for an offer that cannot be executed")
+	}
+	if got := lib.books[cp]; len(got) != 2 {
+		t.Errorf("counterparty library changed: %v", got)
+	}
+}
+
+func TestAcceptOffer_NotPending(t *testing.T) {
+	offer := newPendingOffer()
+	offer.Status = domain.StatusDeclined
+	lib := libraryFor(offer)
+	uc := NewExchangeUseCase(&fakeRepo{offer: offer}, &fakeCache{}, lib, nil, time.Hour)
+
+	if _, err := uc.AcceptOffer(context.Background(), offer.ID.Hex(), offer.CounterpartyID.Hex()); !errors.Is(err, domain.ErrOfferNotPending) {
+		t.Errorf("expected ErrOfferNotPending, got %v", err)
+	}
+}
+
+func TestCounterOffer(t *testing.T) {
+	ctx := context.Background()
+	offer := newPendingOffer()
+	repo := &fakeRepo{offer: offer}
+	cache := &fakeCache{}
+	// встречное предложение: вместо запрошенной книги другая
+	other := primitive.NewObjectID()
+	lib := libraryFor(offer)
+	lib.books[offer.CounterpartyID.Hex()] = append(lib.books[offer.CounterpartyID.Hex()], other.Hex())
+	books := catalogFor(offer)
+	books.known[other.Hex()] = true
+	uc := NewExchangeUseCase(repo, cache, lib, books, time.Hour)
+
+	counter, err := uc.CounterOffer(ctx, &domain.ExchangeOffer{
+		ParentID:         offer.ID,
+		OfferedBookIDs:   []primitive.ObjectID{other},
+		RequestedBookIDs: offer.OfferedBookIDs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter.OwnerID != offer.CounterpartyID || counter.CounterpartyID != offer.OwnerID {
+		t.Errorf("parties not swapped: owner=%v counterparty=%v", counter.OwnerID, counter.CounterpartyID)
+	}
+	if counter.ThreadID != offer.ID || counter.ParentID != offer.ID || counter.Revision != 2 {
+		t.Errorf("counter not linked: thread=%v parent=%v revision=%d", counter.ThreadID, counter.ParentID, counter.Revision)
+	}
+	if repo.offer.Status != domain.StatusCountered {
+		t.Errorf("parent status = %q, want COUNTERED", repo.offer.Status)
+	}
+	if len(cache.invalUsers) != 2 {
+		t.Errorf("expected both users invalidated, got %v", cache.invalUsers)
+	}
+
+	if _, err := uc.CounterOffer(ctx, &domain.ExchangeOffer{ParentID: offer.ID}); !errors.Is(err, domain.ErrOfferNotPending) {
+		t.Errorf("countering twice: expected ErrOfferNotPending, got %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Errorf("created %d revisions, want 1", len(repo.created))
+	}
+}
+
+func TestOfferExpiry(t *testing.T) {
+	ctx := context.Background()
+	offer := newPendingOffer()
+	repo := &fakeRepo{}
+	uc := NewExchangeUseCase(repo, &fakeCache{}, libraryFor(offer), catalogFor(offer), time.Hour)
+
+	created, err := uc.CreateOffer(ctx, offer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left := time.Until(created.ExpiresAt.Time()); left <= 59*time.Minute || left > time.Hour {
+		t.Errorf("default expiry in %v, want about an hour", left)
+	}
+	past := newPendingOffer()
+	past.ExpiresAt = primitive.NewDateTimeFromTime(time.Now().Add(-time.Minute))
+	if _, err := uc.CreateOffer(ctx, past); !errors.Is(err, domain.ErrInvalidExpiry) {
+		t.Errorf("expiry in the past: expected ErrInvalidExpiry, got %v", err)
+	}
+
+	// срок истёк, но свипер ещё не успел пройти
+	offer.ExpiresAt = primitive.NewDateTimeFromTime(time.Now().Add(-time.Second))
+	cache := &fakeCache{}
+	repo = &fakeRepo{offer: offer}
+	uc = NewExchangeUseCase(repo, cache, libraryFor(offer), catalogFor(offer), time.Hour)
+	if _, err := uc.AcceptOffer(ctx, offer.ID.Hex(), offer.CounterpartyID.Hex()); !errors.Is(err, domain.ErrOfferExpired) {
+		t.Errorf("accepting expired offer: expected ErrOfferExpired, got %v", err)
+	}
+
+	expired, err := uc.ExpireStale(ctx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || repo.offer.Status != domain.StatusExpired {
+		t.Errorf("expired %d offers, status %q", len(expired), repo.offer.Status)
+	}
+	if !cache.invalPending || len(cache.invalUsers) != 2 {
+		t.Errorf("expected pending+2 users invalidated, got %v/%v", cache.invalPending, cache.invalUsers)
+	}
+	if again, _ := uc.ExpireStale(ctx, time.Now()); len(again) != 0 {
+		t.Errorf("second sweep expired %d offers", len(again))
+	}
+}
+
+// closingRepo declines the offer just before a book is added, as a
+// concurrent decline between the checks and the write would.
+type closingRepo struct {
+	*fakeRepo
+}
+
+func (r *closingRepo) AddOfferedBook(ctx context.Context, id, bookID string) (*domain.ExchangeOffer, error) {
+	r.offer.Status = domain.StatusDeclined
+	return r.fakeRepo.AddOfferedBook(ctx, id, bookID)
+}
+
+func TestOfferedBooks_OnlyWhilePending(t *testing.T) {
+	ctx := context.Background()
+	offer := newPendingOffer()
+	first, second := offer.OfferedBookIDs[0], primitive.NewObjectID()
+	lib := libraryFor(offer)
+	lib.books[offer.OwnerID.Hex()] = append(lib.books[offer.OwnerID.Hex()], second.Hex())
+	refs := catalogFor(offer)
+	refs.known[second.Hex()] = true
+	repo := &fakeRepo{offer: offer}
+	uc := NewExchangeUseCase(repo, &fakeCache{}, lib, refs, time.Hour)
+	if _, err := uc.AddOfferedBook(ctx, offer.ID.Hex(), second.Hex()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.RemoveOfferedBook(ctx, offer.ID.Hex(), second.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if _, locked := lib.locks[offer.OwnerID.Hex()+"/"+second.Hex()]; locked {
+		t.Error("removed book is still locked")
+	}
+	if _, err := uc.RemoveOfferedBook(ctx, offer.ID.Hex(), first.Hex()); !errors.Is(err, domain.ErrEmptyOffer) {
+		t.Errorf("last book: expected ErrEmptyOffer, got %v", err)
+	}
+	if len(offer.OfferedBookIDs) != 1 {
+		t.Errorf("offer emptied: %v", offer.OfferedBookIDs)
+	}
+
+	closing := NewExchangeUseCase(&closingRepo{repo}, &fakeCache{}, lib, refs, time.Hour)
+	if _, err := closing.AddOfferedBook(ctx, offer.ID.Hex(), second.Hex()); !errors.Is(err, domain.ErrOfferNotPending) {
+		t.Errorf("add to an offer declined meanwhile: expected ErrOfferNotPending, got %v", err)
+	}
+	if _, locked := lib.locks[offer.OwnerID.Hex()+"/"+second.Hex()]; locked {
+		t.Error("book added to a closed offer stays locked")
+	}
+	if _, err := uc.RemoveOfferedBook(ctx, offer.ID.Hex(), first.Hex()); !errors.Is(err, domain.ErrOfferNotPending) {
+		t.Errorf("remove from a declined offer: expected ErrOfferNotPending, got %v", err)
+	}
 }
 
 func TestDeclineAndDelete_Invalidation(t *testing.T) {
 	repo := &fakeRepo{}
 	cache := &fakeCache{}
-	uc := NewExchangeUseCase(repo, cache, nil)
+	uc := NewExchangeUseCase(repo, cache, &fakeLib{}, nil, time.Hour)
 
 	uc.DeclineOffer(context.Background(), "id")
 	if !repo.declineCalled || !cache.invalPending || len(cache.invalUsers) != 1 {
@@ -168,7 +630,7 @@ func TestDeclineAndDelete_Invalidation(t *testing.T) {
 
 	repo = &fakeRepo{}
 	cache = &fakeCache{}
-	uc = NewExchangeUseCase(repo, cache, nil)
+	uc = NewExchangeUseCase(repo, cache, &fakeLib{}, nil, time.Hour)
 
 	uc.DeleteOffer(context.Background(), "id")
 	if !repo.deleteCalled || !cache.invalPending || len(cache.invalUsers) != 1 {
@@ -179,10 +641,36 @@ func TestDeclineAndDelete_Invalidation(t *testing.T) {
 func TestGetOffer_Error(t *testing.T) {
 	repo := &fakeRepo{}
 	cache := &fakeCache{}
-	uc := NewExchangeUseCase(repo, cache, nil)
+	uc := NewExchangeUseCase(repo, cache, nil, nil, time.Hour)
 
 	_, err := uc.GetOfferByID(context.Background(), "err")
 	if err == nil {
 		t.Error("expected error from GetOfferByID")
 	}
 }
+
+func TestCancelBookOffers(t *testing.T) {
+	ctx := context.Background()
+	offer := newPendingOffer()
+	lib := libraryFor(offer)
+	repo := &fakeRepo{}
+	uc := NewExchangeUseCase(repo, &fakeCache{}, lib, catalogFor(offer), time.Hour)
+	if _, err := uc.CreateOffer(ctx, offer); err != nil {
+		t.Fatal(err)
+	}
+	repo.offer = offer
+
+	if none, _ := uc.CancelBookOffers(ctx, primitive.NewObjectID().Hex()); len(none) != 0 {
+		t.Errorf("offer without the book cancelled: %v", none)
+	}
+	cancelled, err := uc.CancelBookOffers(ctx, offer.RequestedBookIDs[0].Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cancelled) != 1 || cancelled[0].Status != domain.StatusCancelled {
+		t.Fatalf("expected the offer cancelled, got %v", cancelled)
+	}
+	if len(lib.locks) != 0 {
+		t.Errorf("cancelled offer kept its locks: %v", lib.locks)
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
+)
+
+// validateOffer checks that both sides of the offer list books, that both
+// parties and every book exist, that the owner holds the offered books and
+// the counterparty the requested ones, and that neither party already has
+// a book they would receive.
+func (u *exchangeUseCase) validateOffer(ctx context.Context, offer *domain.ExchangeOffer) error {
+	if len(offer.OfferedBookIDs) == 0 || len(offer.RequestedBookIDs) == 0 {
+		return domain.ErrEmptyOffer
+	}
+	if err := u.lib.checkUsersExist(ctx, offer.OwnerID, offer.CounterpartyID); err != nil {
+		return err
+	}
+	if err := u.lib.checkBooksExist(ctx, offer.OfferedBookIDs); err != nil {
+		return err
+	}
+	if err := u.lib.checkBooksExist(ctx, offer.RequestedBookIDs); err != nil {
+		return err
+	}
+	if err := u.lib.verifyOwnership(ctx, offer.OwnerID.Hex(), offer.OfferedBookIDs); err != nil {
+		return err
+	}
+	if err := u.lib.verifyOwnership(ctx, offer.CounterpartyID.Hex(), offer.RequestedBookIDs); err != nil {
+		return err
+	}
+	return u.verifyReceivers(ctx, offer)
+}
+
+// verifyReceivers checks that neither party already has a book the offer
+// would hand to them.
+func (u *exchangeUseCase) verifyReceivers(ctx context.Context, offer *domain.ExchangeOffer) error {
+	if err := u.lib.verifyNotOwned(ctx, offer.CounterpartyID.Hex(), offer.OfferedBookIDs); err != nil {
+		return err
+	}
+	return u.lib.verifyNotOwned(ctx, offer.OwnerID.Hex(), offer.RequestedBookIDs)
+}
+
+func (l *library) checkUsersExist(ctx context.Context, userIDs ...primitive.ObjectID) error {
+	for _, id := range userIDs {
+		err := l.refs.UserExists(ctx, id.Hex())
+		if errors.Is(err, refcheck.ErrUnknownUser) {
+			return fmt.Errorf("%w: %s", domain.ErrUnknownUser, id.Hex())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *library) checkBooksExist(ctx context.Context, bookIDs []primitive.ObjectID) error {
+	seen := make(map[primitive.ObjectID]bool, len(bookIDs))
+	for _, id := range bookIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		err := l.refs.BookExists(ctx, id.Hex())
+		if errors.Is(err, refcheck.ErrUnknownBook) {
+			return fmt.Errorf("%w: %s", domain.ErrUnknownBook, id.Hex())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/usecase"
+)
+
+// ExpirySweeper periodically moves pending offers past their expiry to
+// EXPIRED and hands them to publish.
+type ExpirySweeper struct {
+	uc       usecase.ExchangeUseCase
+	publish  func([]*domain.ExchangeOffer)
+	interval time.Duration
+}
+
+func NewExpirySweeper(uc usecase.ExchangeUseCase, publish func([]*domain.ExchangeOffer), interval time.Duration) *ExpirySweeper {
+	return &ExpirySweeper{uc: uc, publish: publish, interval: interval}
+}
+
+// Run sweeps until ctx is cancelled.
+func (s *ExpirySweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ExpirySweeper) sweep(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+
+	expired, err := s.uc.ExpireStale(ctx, time.Now())
+	// даже при ошибке публикуем то, что уже успели закрыть
+	if len(expired) > 0 {
+		log.Printf("Expired %d exchange offers", len(expired))
+		s.publish(expired)
+	}
+	if err != nil {
+		log.Printf("offer expiry sweep failed: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/exchange_service/internal/usecase"
+)
+
+// GroupMatcher periodically expires stale exchange groups and matches the
+// wishlists into new ones. Both expired and proposed groups are handed to
+// publish.
+type GroupMatcher struct {
+	uc       usecase.GroupUseCase
+	publish  func([]*domain.ExchangeGroup)
+	interval time.Duration
+}
+
+func NewGroupMatcher(uc usecase.GroupUseCase, publish func([]*domain.ExchangeGroup), interval time.Duration) *GroupMatcher {
+	return &GroupMatcher{uc: uc, publish: publish, interval: interval}
+}
+
+// Run matches until ctx is cancelled.
+func (m *GroupMatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.match(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *GroupMatcher) match(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	// сначала освобождаем участников просроченных групп, чтобы они
+	// попали в новый подбор
+	expired, err := m.uc.ExpireStaleGroups(ctx, time.Now())
+	if len(expired) > 0 {
+		log.Printf("Expired %d exchange groups", len(expired))
+		m.publish(expired)
+	}
+	if err != nil {
+		log.Printf("exchange group expiry failed: %v", err)
+	}
+
+	proposed, err := m.uc.MatchGroups(ctx)
+	if len(proposed) > 0 {
+		log.Printf("Proposed %d exchange groups", len(proposed))
+		m.publish(proposed)
+	}
+	if err != nil {
+		log.Printf("exchange group matching failed: %v", err)
+	}
+}
@@ -31,6 +31,10 @@ type ExchangeOffer struct {
 	Status           string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
 	CreatedAt        string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt        string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ThreadId         string                 `protobuf:"bytes,9,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	ParentId         string                 `protobuf:"bytes,10,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Revision         int32                  `protobuf:"varint,11,opt,name=revision,proto3" json:"revision,omitempty"`
+	ExpiresAt        string                 `protobuf:"bytes,12,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -121,12 +125,43 @@ func (x *ExchangeOffer) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *ExchangeOffer) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *ExchangeOffer) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *ExchangeOffer) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *ExchangeOffer) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+// expires_at is an optional RFC 3339 time; offers without it use the
+// service default.
 type CreateOfferRequest struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	OwnerId          string                 `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
 	CounterpartyId   string                 `protobuf:"bytes,2,opt,name=counterparty_id,json=counterpartyId,proto3" json:"counterparty_id,omitempty"`
 	OfferedBookIds   []string               `protobuf:"bytes,3,rep,name=offered_book_ids,json=offeredBookIds,proto3" json:"offered_book_ids,omitempty"`
 	RequestedBookIds []string               `protobuf:"bytes,4,rep,name=requested_book_ids,json=requestedBookIds,proto3" json:"requested_book_ids,omitempty"`
+	ExpiresAt        string                 `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -189,6 +224,13 @@ func (x *CreateOfferRequest) GetRequestedBookIds() []string {
 	return nil
 }
 
+func (x *CreateOfferRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
 type AcceptOfferRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OfferId       string                 `protobuf:"bytes,1,opt,name=offer_id,json=offerId,proto3" json:"offer_id,omitempty"`
@@ -241,6 +283,129 @@ func (x *AcceptOfferRequest) GetRequesterId() string {
 	return ""
 }
 
+// CounterOfferRequest answers offer_id on behalf of its counterparty:
+// offered_book_ids are the books the counterparty gives, requested_book_ids
+// the books it wants from the original owner.
+type CounterOfferRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OfferId          string                 `protobuf:"bytes,1,opt,name=offer_id,json=offerId,proto3" json:"offer_id,omitempty"`
+	OfferedBookIds   []string               `protobuf:"bytes,2,rep,name=offered_book_ids,json=offeredBookIds,proto3" json:"offered_book_ids,omitempty"`
+	RequestedBookIds []string               `protobuf:"bytes,3,rep,name=requested_book_ids,json=requestedBookIds,proto3" json:"requested_book_ids,omitempty"`
+	ExpiresAt        string                 `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CounterOfferRequest) Reset() {
+	*x = CounterOfferRequest{}
+	mi := &file_exchange_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CounterOfferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CounterOfferRequest) ProtoMessage() {}
+
+func (x *CounterOfferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CounterOfferRequest.ProtoReflect.Descriptor instead.
+func (*CounterOfferRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CounterOfferRequest) GetOfferId() string {
+	if x != nil {
+		return x.OfferId
+	}
+	return ""
+}
+
+func (x *CounterOfferRequest) GetOfferedBookIds() []string {
+	if x != nil {
+		return x.OfferedBookIds
+	}
+	return nil
+}
+
+func (x *CounterOfferRequest) GetRequestedBookIds() []string {
+	if x != nil {
+		return x.RequestedBookIds
+	}
+	return nil
+}
+
+func (x *CounterOfferRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type OfferThread struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId      string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Offers        []*ExchangeOffer       `protobuf:"bytes,2,rep,name=offers,proto3" json:"offers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OfferThread) Reset() {
+	*x = OfferThread{}
+	mi := &file_exchange_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OfferThread) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OfferThread) ProtoMessage() {}
+
+func (x *OfferThread) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OfferThread.ProtoReflect.Descriptor instead.
+func (*OfferThread) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OfferThread) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *OfferThread) GetOffers() []*ExchangeOffer {
+	if x != nil {
+		return x.Offers
+	}
+	return nil
+}
+
 type UpdateOfferRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Offer         *ExchangeOffer         `protobuf:"bytes,1,opt,name=offer,proto3" json:"offer,omitempty"`
@@ -250,7 +415,7 @@ type UpdateOfferRequest struct {
 
 func (x *UpdateOfferRequest) Reset() {
 	*x = UpdateOfferRequest{}
-	mi := &file_exchange_proto_msgTypes[3]
+	mi := &file_exchange_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -262,7 +427,7 @@ func (x *UpdateOfferRequest) String() string {
 func (*UpdateOfferRequest) ProtoMessage() {}
 
 func (x *UpdateOfferRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[3]
+	mi := &file_exchange_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -275,7 +440,7 @@ func (x *UpdateOfferRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOfferRequest.ProtoReflect.Descriptor instead.
 func (*UpdateOfferRequest) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{3}
+	return file_exchange_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UpdateOfferRequest) GetOffer() *ExchangeOffer {
@@ -295,7 +460,7 @@ type BookOpRequest struct {
 
 func (x *BookOpRequest) Reset() {
 	*x = BookOpRequest{}
-	mi := &file_exchange_proto_msgTypes[4]
+	mi := &file_exchange_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -307,7 +472,7 @@ func (x *BookOpRequest) String() string {
 func (*BookOpRequest) ProtoMessage() {}
 
 func (x *BookOpRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[4]
+	mi := &file_exchange_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -320,7 +485,7 @@ func (x *BookOpRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BookOpRequest.ProtoReflect.Descriptor instead.
 func (*BookOpRequest) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{4}
+	return file_exchange_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BookOpRequest) GetOfferId() string {
@@ -346,7 +511,7 @@ type StatusRequest struct {
 
 func (x *StatusRequest) Reset() {
 	*x = StatusRequest{}
-	mi := &file_exchange_proto_msgTypes[5]
+	mi := &file_exchange_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -358,7 +523,7 @@ func (x *StatusRequest) String() string {
 func (*StatusRequest) ProtoMessage() {}
 
 func (x *StatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[5]
+	mi := &file_exchange_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +536,7 @@ func (x *StatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
 func (*StatusRequest) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{5}
+	return file_exchange_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StatusRequest) GetStatus() string {
@@ -390,7 +555,7 @@ type OfferID struct {
 
 func (x *OfferID) Reset() {
 	*x = OfferID{}
-	mi := &file_exchange_proto_msgTypes[6]
+	mi := &file_exchange_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -402,7 +567,7 @@ func (x *OfferID) String() string {
 func (*OfferID) ProtoMessage() {}
 
 func (x *OfferID) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[6]
+	mi := &file_exchange_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -415,7 +580,7 @@ func (x *OfferID) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OfferID.ProtoReflect.Descriptor instead.
 func (*OfferID) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{6}
+	return file_exchange_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *OfferID) GetId() string {
@@ -434,7 +599,7 @@ type UserID struct {
 
 func (x *UserID) Reset() {
 	*x = UserID{}
-	mi := &file_exchange_proto_msgTypes[7]
+	mi := &file_exchange_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -446,7 +611,7 @@ func (x *UserID) String() string {
 func (*UserID) ProtoMessage() {}
 
 func (x *UserID) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[7]
+	mi := &file_exchange_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -459,7 +624,7 @@ func (x *UserID) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserID.ProtoReflect.Descriptor instead.
 func (*UserID) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{7}
+	return file_exchange_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UserID) GetUserId() string {
@@ -478,7 +643,7 @@ type OfferResponse struct {
 
 func (x *OfferResponse) Reset() {
 	*x = OfferResponse{}
-	mi := &file_exchange_proto_msgTypes[8]
+	mi := &file_exchange_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -490,7 +655,7 @@ func (x *OfferResponse) String() string {
 func (*OfferResponse) ProtoMessage() {}
 
 func (x *OfferResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[8]
+	mi := &file_exchange_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -503,7 +668,7 @@ func (x *OfferResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OfferResponse.ProtoReflect.Descriptor instead.
 func (*OfferResponse) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{8}
+	return file_exchange_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *OfferResponse) GetOffer() *ExchangeOffer {
@@ -522,7 +687,7 @@ type OfferList struct {
 
 func (x *OfferList) Reset() {
 	*x = OfferList{}
-	mi := &file_exchange_proto_msgTypes[9]
+	mi := &file_exchange_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -534,7 +699,7 @@ func (x *OfferList) String() string {
 func (*OfferList) ProtoMessage() {}
 
 func (x *OfferList) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[9]
+	mi := &file_exchange_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -547,7 +712,7 @@ func (x *OfferList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OfferList.ProtoReflect.Descriptor instead.
 func (*OfferList) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{9}
+	return file_exchange_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *OfferList) GetOffers() []*ExchangeOffer {
@@ -557,27 +722,33 @@ func (x *OfferList) GetOffers() []*ExchangeOffer {
 	return nil
 }
 
-type Empty struct {
+// Wishlist declares the books a user gives away and the books they want
+// for multi-party exchanges.
+type Wishlist struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	HaveBookIds   []string               `protobuf:"bytes,2,rep,name=have_book_ids,json=haveBookIds,proto3" json:"have_book_ids,omitempty"`
+	WantBookIds   []string               `protobuf:"bytes,3,rep,name=want_book_ids,json=wantBookIds,proto3" json:"want_book_ids,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
-	mi := &file_exchange_proto_msgTypes[10]
+func (x *Wishlist) Reset() {
+	*x = Wishlist{}
+	mi := &file_exchange_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *Wishlist) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*Wishlist) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_exchange_proto_msgTypes[10]
+func (x *Wishlist) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -588,51 +759,620 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
-	return file_exchange_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use Wishlist.ProtoReflect.Descriptor instead.
+func (*Wishlist) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{12}
 }
 
-var File_exchange_proto protoreflect.FileDescriptor
+func (x *Wishlist) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
 
-const file_exchange_proto_rawDesc = "" +
-	"\n" +
-	"\x0eexchange.proto\x12\bexchange\"\x91\x02\n" +
-	"\rExchangeOffer\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
-	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12'\n" +
-	"\x0fcounterparty_id\x18\x03 \x01(\tR\x0ecounterpartyId\x12(\n" +
-	"\x10offered_book_ids\x18\x04 \x03(\tR\x0eofferedBookIds\x12,\n" +
-	"\x12requested_book_ids\x18\x05 \x03(\tR\x10requestedBookIds\x12\x16\n" +
-	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\b \x01(\tR\tupdatedAt\"\xb0\x01\n" +
-	"\x12CreateOfferRequest\x12\x19\n" +
-	"\bowner_id\x18\x01 \x01(\tR\aownerId\x12'\n" +
-	"\x0fcounterparty_id\x18\x02 \x01(\tR\x0ecounterpartyId\x12(\n" +
-	"\x10offered_book_ids\x18\x03 \x03(\tR\x0eofferedBookIds\x12,\n" +
-	"\x12requested_book_ids\x18\x04 \x03(\tR\x10requestedBookIds\"R\n" +
-	"\x12AcceptOfferRequest\x12\x19\n" +
-	"\boffer_id\x18\x01 \x01(\tR\aofferId\x12!\n" +
-	"\frequester_id\x18\x02 \x01(\tR\vrequesterId\"C\n" +
-	"\x12UpdateOfferRequest\x12-\n" +
-	"\x05offer\x18\x01 \x01(\v2\x17.exchange.ExchangeOfferR\x05offer\"C\n" +
-	"\rBookOpRequest\x12\x19\n" +
-	"\boffer_id\x18\x01 \x01(\tR\aofferId\x12\x17\n" +
-	"\abook_id\x18\x02 \x01(\tR\x06bookId\"'\n" +
-	"\rStatusRequest\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"\x19\n" +
-	"\aOfferID\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"!\n" +
-	"\x06UserID\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\">\n" +
-	"\rOfferResponse\x12-\n" +
-	"\x05offer\x18\x01 \x01(\v2\x17.exchange.ExchangeOfferR\x05offer\"<\n" +
-	"\tOfferList\x12/\n" +
-	"\x06offers\x18\x01 \x03(\v2\x17.exchange.ExchangeOfferR\x06offers\"\a\n" +
-	"\x05Empty2\x86\x06\n" +
+func (x *Wishlist) GetHaveBookIds() []string {
+	if x != nil {
+		return x.HaveBookIds
+	}
+	return nil
+}
+
+func (x *Wishlist) GetWantBookIds() []string {
+	if x != nil {
+		return x.WantBookIds
+	}
+	return nil
+}
+
+func (x *Wishlist) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type SetWishlistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	HaveBookIds   []string               `protobuf:"bytes,2,rep,name=have_book_ids,json=haveBookIds,proto3" json:"have_book_ids,omitempty"`
+	WantBookIds   []string               `protobuf:"bytes,3,rep,name=want_book_ids,json=wantBookIds,proto3" json:"want_book_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetWishlistRequest) Reset() {
+	*x = SetWishlistRequest{}
+	mi := &file_exchange_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetWishlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetWishlistRequest) ProtoMessage() {}
+
+func (x *SetWishlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetWishlistRequest.ProtoReflect.Descriptor instead.
+func (*SetWishlistRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SetWishlistRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetWishlistRequest) GetHaveBookIds() []string {
+	if x != nil {
+		return x.HaveBookIds
+	}
+	return nil
+}
+
+func (x *SetWishlistRequest) GetWantBookIds() []string {
+	if x != nil {
+		return x.WantBookIds
+	}
+	return nil
+}
+
+type WishlistResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wishlist      *Wishlist              `protobuf:"bytes,1,opt,name=wishlist,proto3" json:"wishlist,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WishlistResponse) Reset() {
+	*x = WishlistResponse{}
+	mi := &file_exchange_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WishlistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WishlistResponse) ProtoMessage() {}
+
+func (x *WishlistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WishlistResponse.ProtoReflect.Descriptor instead.
+func (*WishlistResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WishlistResponse) GetWishlist() *Wishlist {
+	if x != nil {
+		return x.Wishlist
+	}
+	return nil
+}
+
+type GroupLeg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GiverId       string                 `protobuf:"bytes,1,opt,name=giver_id,json=giverId,proto3" json:"giver_id,omitempty"`
+	ReceiverId    string                 `protobuf:"bytes,2,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`
+	BookId        string                 `protobuf:"bytes,3,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupLeg) Reset() {
+	*x = GroupLeg{}
+	mi := &file_exchange_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupLeg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupLeg) ProtoMessage() {}
+
+func (x *GroupLeg) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupLeg.ProtoReflect.Descriptor instead.
+func (*GroupLeg) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GroupLeg) GetGiverId() string {
+	if x != nil {
+		return x.GiverId
+	}
+	return ""
+}
+
+func (x *GroupLeg) GetReceiverId() string {
+	if x != nil {
+		return x.ReceiverId
+	}
+	return ""
+}
+
+func (x *GroupLeg) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+type ExchangeGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Legs          []*GroupLeg            `protobuf:"bytes,2,rep,name=legs,proto3" json:"legs,omitempty"`
+	Participants  []string               `protobuf:"bytes,3,rep,name=participants,proto3" json:"participants,omitempty"`
+	AcceptedBy    []string               `protobuf:"bytes,4,rep,name=accepted_by,json=acceptedBy,proto3" json:"accepted_by,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeGroup) Reset() {
+	*x = ExchangeGroup{}
+	mi := &file_exchange_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeGroup) ProtoMessage() {}
+
+func (x *ExchangeGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeGroup.ProtoReflect.Descriptor instead.
+func (*ExchangeGroup) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExchangeGroup) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExchangeGroup) GetLegs() []*GroupLeg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *ExchangeGroup) GetParticipants() []string {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+func (x *ExchangeGroup) GetAcceptedBy() []string {
+	if x != nil {
+		return x.AcceptedBy
+	}
+	return nil
+}
+
+func (x *ExchangeGroup) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExchangeGroup) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ExchangeGroup) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *ExchangeGroup) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type GroupID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupID) Reset() {
+	*x = GroupID{}
+	mi := &file_exchange_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupID) ProtoMessage() {}
+
+func (x *GroupID) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupID.ProtoReflect.Descriptor instead.
+func (*GroupID) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GroupID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GroupActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupActionRequest) Reset() {
+	*x = GroupActionRequest{}
+	mi := &file_exchange_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupActionRequest) ProtoMessage() {}
+
+func (x *GroupActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupActionRequest.ProtoReflect.Descriptor instead.
+func (*GroupActionRequest) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GroupActionRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GroupActionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *ExchangeGroup         `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupResponse) Reset() {
+	*x = GroupResponse{}
+	mi := &file_exchange_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupResponse) ProtoMessage() {}
+
+func (x *GroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupResponse.ProtoReflect.Descriptor instead.
+func (*GroupResponse) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GroupResponse) GetGroup() *ExchangeGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type GroupList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*ExchangeGroup       `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupList) Reset() {
+	*x = GroupList{}
+	mi := &file_exchange_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupList) ProtoMessage() {}
+
+func (x *GroupList) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupList.ProtoReflect.Descriptor instead.
+func (*GroupList) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GroupList) GetGroups() []*ExchangeGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_exchange_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_exchange_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_exchange_proto_rawDescGZIP(), []int{21}
+}
+
+var File_exchange_proto protoreflect.FileDescriptor
+
+const file_exchange_proto_rawDesc = "" +
+	"\n" +
+	"\x0eexchange.proto\x12\bexchange\"\x86\x03\n" +
+	"\rExchangeOffer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12'\n" +
+	"\x0fcounterparty_id\x18\x03 \x01(\tR\x0ecounterpartyId\x12(\n" +
+	"\x10offered_book_ids\x18\x04 \x03(\tR\x0eofferedBookIds\x12,\n" +
+	"\x12requested_book_ids\x18\x05 \x03(\tR\x10requestedBookIds\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\x12\x1b\n" +
+	"\tthread_id\x18\t \x01(\tR\bthreadId\x12\x1b\n" +
+	"\tparent_id\x18\n" +
+	" \x01(\tR\bparentId\x12\x1a\n" +
+	"\brevision\x18\v \x01(\x05R\brevision\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\f \x01(\tR\texpiresAt\"\xcf\x01\n" +
+	"\x12CreateOfferRequest\x12\x19\n" +
+	"\bowner_id\x18\x01 \x01(\tR\aownerId\x12'\n" +
+	"\x0fcounterparty_id\x18\x02 \x01(\tR\x0ecounterpartyId\x12(\n" +
+	"\x10offered_book_ids\x18\x03 \x03(\tR\x0eofferedBookIds\x12,\n" +
+	"\x12requested_book_ids\x18\x04 \x03(\tR\x10requestedBookIds\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\tR\texpiresAt\"R\n" +
+	"\x12AcceptOfferRequest\x12\x19\n" +
+	"\boffer_id\x18\x01 \x01(\tR\aofferId\x12!\n" +
+	"\frequester_id\x18\x02 \x01(\tR\vrequesterId\"\xa7\x01\n" +
+	"\x13CounterOfferRequest\x12\x19\n" +
+	"\boffer_id\x18\x01 \x01(\tR\aofferId\x12(\n" +
+	"\x10offered_book_ids\x18\x02 \x03(\tR\x0eofferedBookIds\x12,\n" +
+	"\x12requested_book_ids\x18\x03 \x03(\tR\x10requestedBookIds\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\"[\n" +
+	"\vOfferThread\x12\x1b\n" +
+	"\tthread_id\x18\x01 \x01(\tR\bthreadId\x12/\n" +
+	"\x06offers\x18\x02 \x03(\v2\x17.exchange.ExchangeOfferR\x06offers\"C\n" +
+	"\x12UpdateOfferRequest\x12-\n" +
+	"\x05offer\x18\x01 \x01(\v2\x17.exchange.ExchangeOfferR\x05offer\"C\n" +
+	"\rBookOpRequest\x12\x19\n" +
+	"\boffer_id\x18\x01 \x01(\tR\aofferId\x12\x17\n" +
+	"\abook_id\x18\x02 \x01(\tR\x06bookId\"'\n" +
+	"\rStatusRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"\x19\n" +
+	"\aOfferID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"!\n" +
+	"\x06UserID\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\">\n" +
+	"\rOfferResponse\x12-\n" +
+	"\x05offer\x18\x01 \x01(\v2\x17.exchange.ExchangeOfferR\x05offer\"<\n" +
+	"\tOfferList\x12/\n" +
+	"\x06offers\x18\x01 \x03(\v2\x17.exchange.ExchangeOfferR\x06offers\"\x8a\x01\n" +
+	"\bWishlist\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\"\n" +
+	"\rhave_book_ids\x18\x02 \x03(\tR\vhaveBookIds\x12\"\n" +
+	"\rwant_book_ids\x18\x03 \x03(\tR\vwantBookIds\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\"u\n" +
+	"\x12SetWishlistRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\"\n" +
+	"\rhave_book_ids\x18\x02 \x03(\tR\vhaveBookIds\x12\"\n" +
+	"\rwant_book_ids\x18\x03 \x03(\tR\vwantBookIds\"B\n" +
+	"\x10WishlistResponse\x12.\n" +
+	"\bwishlist\x18\x01 \x01(\v2\x12.exchange.WishlistR\bwishlist\"_\n" +
+	"\bGroupLeg\x12\x19\n" +
+	"\bgiver_id\x18\x01 \x01(\tR\agiverId\x12\x1f\n" +
+	"\vreceiver_id\x18\x02 \x01(\tR\n" +
+	"receiverId\x12\x17\n" +
+	"\abook_id\x18\x03 \x01(\tR\x06bookId\"\x81\x02\n" +
+	"\rExchangeGroup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\x04legs\x18\x02 \x03(\v2\x12.exchange.GroupLegR\x04legs\x12\"\n" +
+	"\fparticipants\x18\x03 \x03(\tR\fparticipants\x12\x1f\n" +
+	"\vaccepted_by\x18\x04 \x03(\tR\n" +
+	"acceptedBy\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\tR\texpiresAt\"\x19\n" +
+	"\aGroupID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"H\n" +
+	"\x12GroupActionRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\">\n" +
+	"\rGroupResponse\x12-\n" +
+	"\x05group\x18\x01 \x01(\v2\x17.exchange.ExchangeGroupR\x05group\"<\n" +
+	"\tGroupList\x12/\n" +
+	"\x06groups\x18\x01 \x03(\v2\x17.exchange.ExchangeGroupR\x06groups\"\a\n" +
+	"\x05Empty2\xc5\n" +
+	"\n" +
 	"\x0fExchangeService\x12D\n" +
 	"\vCreateOffer\x12\x1c.exchange.CreateOfferRequest\x1a\x17.exchange.OfferResponse\x126\n" +
 	"\bGetOffer\x12\x11.exchange.OfferID\x1a\x17.exchange.OfferResponse\x129\n" +
@@ -645,7 +1385,16 @@ const file_exchange_proto_rawDesc = "" +
 	"\x0eAddOfferedBook\x12\x17.exchange.BookOpRequest\x1a\x17.exchange.OfferResponse\x12E\n" +
 	"\x11RemoveOfferedBook\x12\x17.exchange.BookOpRequest\x1a\x17.exchange.OfferResponse\x125\n" +
 	"\rListAllOffers\x12\x0f.exchange.Empty\x1a\x13.exchange.OfferList\x12B\n" +
-	"\x12ListOffersByStatus\x12\x17.exchange.StatusRequest\x1a\x13.exchange.OfferListBTZRgithub.com/OshakbayAigerim/read_space/exchange_service/proto/exchangepb;exchangepbb\x06proto3"
+	"\x12ListOffersByStatus\x12\x17.exchange.StatusRequest\x1a\x13.exchange.OfferList\x12F\n" +
+	"\fCounterOffer\x12\x1d.exchange.CounterOfferRequest\x1a\x17.exchange.OfferResponse\x12:\n" +
+	"\x0eGetOfferThread\x12\x11.exchange.OfferID\x1a\x15.exchange.OfferThread\x12G\n" +
+	"\vSetWishlist\x12\x1c.exchange.SetWishlistRequest\x1a\x1a.exchange.WishlistResponse\x12;\n" +
+	"\vGetWishlist\x12\x10.exchange.UserID\x1a\x1a.exchange.WishlistResponse\x123\n" +
+	"\vMatchGroups\x12\x0f.exchange.Empty\x1a\x13.exchange.GroupList\x126\n" +
+	"\bGetGroup\x12\x11.exchange.GroupID\x1a\x17.exchange.GroupResponse\x129\n" +
+	"\x10ListGroupsByUser\x12\x10.exchange.UserID\x1a\x13.exchange.GroupList\x12D\n" +
+	"\vAcceptGroup\x12\x1c.exchange.GroupActionRequest\x1a\x17.exchange.GroupResponse\x12E\n" +
+	"\fDeclineGroup\x12\x1c.exchange.GroupActionRequest\x1a\x17.exchange.GroupResponseBTZRgithub.com/OshakbayAigerim/read_space/exchange_service/proto/exchangepb;exchangepbb\x06proto3"
 
 var (
 	file_exchange_proto_rawDescOnce sync.Once
@@ -659,53 +1408,87 @@ func file_exchange_proto_rawDescGZIP() []byte {
 	return file_exchange_proto_rawDescData
 }
 
-var file_exchange_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_exchange_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_exchange_proto_goTypes = []any{
-	(*ExchangeOffer)(nil),      // 0: exchange.ExchangeOffer
-	(*CreateOfferRequest)(nil), // 1: exchange.CreateOfferRequest
-	(*AcceptOfferRequest)(nil), // 2: exchange.AcceptOfferRequest
-	(*UpdateOfferRequest)(nil), // 3: exchange.UpdateOfferRequest
-	(*BookOpRequest)(nil),      // 4: exchange.BookOpRequest
-	(*StatusRequest)(nil),      // 5: exchange.StatusRequest
-	(*OfferID)(nil),            // 6: exchange.OfferID
-	(*UserID)(nil),             // 7: exchange.UserID
-	(*OfferResponse)(nil),      // 8: exchange.OfferResponse
-	(*OfferList)(nil),          // 9: exchange.OfferList
-	(*Empty)(nil),              // 10: exchange.Empty
+	(*ExchangeOffer)(nil),       // 0: exchange.ExchangeOffer
+	(*CreateOfferRequest)(nil),  // 1: exchange.CreateOfferRequest
+	(*AcceptOfferRequest)(nil),  // 2: exchange.AcceptOfferRequest
+	(*CounterOfferRequest)(nil), // 3: exchange.CounterOfferRequest
+	(*OfferThread)(nil),         // 4: exchange.OfferThread
+	(*UpdateOfferRequest)(nil),  // 5: exchange.UpdateOfferRequest
+	(*BookOpRequest)(nil),       // 6: exchange.BookOpRequest
+	(*StatusRequest)(nil),       // 7: exchange.StatusRequest
+	(*OfferID)(nil),             // 8: exchange.OfferID
+	(*UserID)(nil),              // 9: exchange.UserID
+	(*OfferResponse)(nil),       // 10: exchange.OfferResponse
+	(*OfferList)(nil),           // 11: exchange.OfferList
+	(*Wishlist)(nil),            // 12: exchange.Wishlist
+	(*SetWishlistRequest)(nil),  // 13: exchange.SetWishlistRequest
+	(*WishlistResponse)(nil),    // 14: exchange.WishlistResponse
+	(*GroupLeg)(nil),            // 15: exchange.GroupLeg
+	(*ExchangeGroup)(nil),       // 16: exchange.ExchangeGroup
+	(*GroupID)(nil),             // 17: exchange.GroupID
+	(*GroupActionRequest)(nil),  // 18: exchange.GroupActionRequest
+	(*GroupResponse)(nil),       // 19: exchange.GroupResponse
+	(*GroupList)(nil),           // 20: exchange.GroupList
+	(*Empty)(nil),               // 21: exchange.Empty
 }
 var file_exchange_proto_depIdxs = []int32{
-	0,  // 0: exchange.UpdateOfferRequest.offer:type_name -> exchange.ExchangeOffer
-	0,  // 1: exchange.OfferResponse.offer:type_name -> exchange.ExchangeOffer
-	0,  // 2: exchange.OfferList.offers:type_name -> exchange.ExchangeOffer
-	1,  // 3: exchange.ExchangeService.CreateOffer:input_type -> exchange.CreateOfferRequest
-	6,  // 4: exchange.ExchangeService.GetOffer:input_type -> exchange.OfferID
-	7,  // 5: exchange.ExchangeService.ListOffersByUser:input_type -> exchange.UserID
-	10, // 6: exchange.ExchangeService.ListPendingOffers:input_type -> exchange.Empty
-	2,  // 7: exchange.ExchangeService.AcceptOffer:input_type -> exchange.AcceptOfferRequest
-	6,  // 8: exchange.ExchangeService.DeclineOffer:input_type -> exchange.OfferID
-	6,  // 9: exchange.ExchangeService.DeleteOffer:input_type -> exchange.OfferID
-	3,  // 10: exchange.ExchangeService.UpdateOffer:input_type -> exchange.UpdateOfferRequest
-	4,  // 11: exchange.ExchangeService.AddOfferedBook:input_type -> exchange.BookOpRequest
-	4,  // 12: exchange.ExchangeService.RemoveOfferedBook:input_type -> exchange.BookOpRequest
-	10, // 13: exchange.ExchangeService.ListAllOffers:input_type -> exchange.Empty
-	5,  // 14: exchange.ExchangeService.ListOffersByStatus:input_type -> exchange.StatusRequest
-	8,  // 15: exchange.ExchangeService.CreateOffer:output_type -> exchange.OfferResponse
-	8,  // 16: exchange.ExchangeService.GetOffer:output_type -> exchange.OfferResponse
-	9,  // 17: exchange.ExchangeService.ListOffersByUser:output_type -> exchange.OfferList
-	9,  // 18: exchange.ExchangeService.ListPendingOffers:output_type -> exchange.OfferList
-	8,  // 19: exchange.ExchangeService.AcceptOffer:output_type -> exchange.OfferResponse
-	8,  // 20: exchange.ExchangeService.DeclineOffer:output_type -> exchange.OfferResponse
-	10, // 21: exchange.ExchangeService.DeleteOffer:output_type -> exchange.Empty
-	8,  // 22: exchange.ExchangeService.UpdateOffer:output_type -> exchange.OfferResponse
-	8,  // 23: exchange.ExchangeService.AddOfferedBook:output_type -> exchange.OfferResponse
-	8,  // 24: exchange.ExchangeService.RemoveOfferedBook:output_type -> exchange.OfferResponse
-	9,  // 25: exchange.ExchangeService.ListAllOffers:output_type -> exchange.OfferList
-	9,  // 26: exchange.ExchangeService.ListOffersByStatus:output_type -> exchange.OfferList
-	15, // [15:27] is the sub-list for method output_type
-	3,  // [3:15] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	0,  // 0: exchange.OfferThread.offers:type_name -> exchange.ExchangeOffer
+	0,  // 1: exchange.UpdateOfferRequest.offer:type_name -> exchange.ExchangeOffer
+	0,  // 2: exchange.OfferResponse.offer:type_name -> exchange.ExchangeOffer
+	0,  // 3: exchange.OfferList.offers:type_name -> exchange.ExchangeOffer
+	12, // 4: exchange.WishlistResponse.wishlist:type_name -> exchange.Wishlist
+	15, // 5: exchange.ExchangeGroup.legs:type_name -> exchange.GroupLeg
+	16, // 6: exchange.GroupResponse.group:type_name -> exchange.ExchangeGroup
+	16, // 7: exchange.GroupList.groups:type_name -> exchange.ExchangeGroup
+	1,  // 8: exchange.ExchangeService.CreateOffer:input_type -> exchange.CreateOfferRequest
+	8,  // 9: exchange.ExchangeService.GetOffer:input_type -> exchange.OfferID
+	9,  // 10: exchange.ExchangeService.ListOffersByUser:input_type -> exchange.UserID
+	21, // 11: exchange.ExchangeService.ListPendingOffers:input_type -> exchange.Empty
+	2,  // 12: exchange.ExchangeService.AcceptOffer:input_type -> exchange.AcceptOfferRequest
+	8,  // 13: exchange.ExchangeService.DeclineOffer:input_type -> exchange.OfferID
+	8,  // 14: exchange.ExchangeService.DeleteOffer:input_type -> exchange.OfferID
+	5,  // 15: exchange.ExchangeService.UpdateOffer:input_type -> exchange.UpdateOfferRequest
+	6,  // 16: exchange.ExchangeService.AddOfferedBook:input_type -> exchange.BookOpRequest
+	6,  // 17: exchange.ExchangeService.RemoveOfferedBook:input_type -> exchange.BookOpRequest
+	21, // 18: exchange.ExchangeService.ListAllOffers:input_type -> exchange.Empty
+	7,  // 19: exchange.ExchangeService.ListOffersByStatus:input_type -> exchange.StatusRequest
+	3,  // 20: exchange.ExchangeService.CounterOffer:input_type -> exchange.CounterOfferRequest
+	8,  // 21: exchange.ExchangeService.GetOfferThread:input_type -> exchange.OfferID
+	13, // 22: exchange.ExchangeService.SetWishlist:input_type -> exchange.SetWishlistRequest
+	9,  // 23: exchange.ExchangeService.GetWishlist:input_type -> exchange.UserID
+	21, // 24: exchange.ExchangeService.MatchGroups:input_type -> exchange.Empty
+	17, // 25: exchange.ExchangeService.GetGroup:input_type -> exchange.GroupID
+	9,  // 26: exchange.ExchangeService.ListGroupsByUser:input_type -> exchange.UserID
+	18, // 27: exchange.ExchangeService.AcceptGroup:input_type -> exchange.GroupActionRequest
+	18, // 28: exchange.ExchangeService.DeclineGroup:input_type -> exchange.GroupActionRequest
+	10, // 29: exchange.ExchangeService.CreateOffer:output_type -> exchange.OfferResponse
+	10, // 30: exchange.ExchangeService.GetOffer:output_type -> exchange.OfferResponse
+	11, // 31: exchange.ExchangeService.ListOffersByUser:output_type -> exchange.OfferList
+	11, // 32: exchange.ExchangeService.ListPendingOffers:output_type -> exchange.OfferList
+	10, // 33: exchange.ExchangeService.AcceptOffer:output_type -> exchange.OfferResponse
+	10, // 34: exchange.ExchangeService.DeclineOffer:output_type -> exchange.OfferResponse
+	21, // 35: exchange.ExchangeService.DeleteOffer:output_type -> exchange.Empty
+	10, // 36: exchange.ExchangeService.UpdateOffer:output_type -> exchange.OfferResponse
+	10, // 37: exchange.ExchangeService.AddOfferedBook:output_type -> exchange.OfferResponse
+	10, // 38: exchange.ExchangeService.RemoveOfferedBook:output_type -> exchange.OfferResponse
+	11, // 39: exchange.ExchangeService.ListAllOffers:output_type -> exchange.OfferList
+	11, // 40: exchange.ExchangeService.ListOffersByStatus:output_type -> exchange.OfferList
+	10, // 41: exchange.ExchangeService.CounterOffer:output_type -> exchange.OfferResponse
+	4,  // 42: exchange.ExchangeService.GetOfferThread:output_type -> exchange.OfferThread
+	14, // 43: exchange.ExchangeService.SetWishlist:output_type -> exchange.WishlistResponse
+	14, // 44: exchange.ExchangeService.GetWishlist:output_type -> exchange.WishlistResponse
+	20, // 45: exchange.ExchangeService.MatchGroups:output_type -> exchange.GroupList
+	19, // 46: exchange.ExchangeService.GetGroup:output_type -> exchange.GroupResponse
+	20, // 47: exchange.ExchangeService.ListGroupsByUser:output_type -> exchange.GroupList
+	19, // 48: exchange.ExchangeService.AcceptGroup:output_type -> exchange.GroupResponse
+	19, // 49: exchange.ExchangeService.DeclineGroup:output_type -> exchange.GroupResponse
+	29, // [29:50] is the sub-list for method output_type
+	8,  // [8:29] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_exchange_proto_init() }
@@ -719,7 +1502,7 @@ func file_exchange_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_exchange_proto_rawDesc), len(file_exchange_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
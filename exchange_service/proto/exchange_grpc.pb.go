@@ -31,6 +31,15 @@ const (
 	ExchangeService_RemoveOfferedBook_FullMethodName  = "/exchange.ExchangeService/RemoveOfferedBook"
 	ExchangeService_ListAllOffers_FullMethodName      = "/exchange.ExchangeService/ListAllOffers"
 	ExchangeService_ListOffersByStatus_FullMethodName = "/exchange.ExchangeService/ListOffersByStatus"
+	ExchangeService_CounterOffer_FullMethodName       = "/exchange.ExchangeService/CounterOffer"
+	ExchangeService_GetOfferThread_FullMethodName     = "/exchange.ExchangeService/GetOfferThread"
+	ExchangeService_SetWishlist_FullMethodName        = "/exchange.ExchangeService/SetWishlist"
+	ExchangeService_GetWishlist_FullMethodName        = "/exchange.ExchangeService/GetWishlist"
+	ExchangeService_MatchGroups_FullMethodName        = "/exchange.ExchangeService/MatchGroups"
+	ExchangeService_GetGroup_FullMethodName           = "/exchange.ExchangeService/GetGroup"
+	ExchangeService_ListGroupsByUser_FullMethodName   = "/exchange.ExchangeService/ListGroupsByUser"
+	ExchangeService_AcceptGroup_FullMethodName        = "/exchange.ExchangeService/AcceptGroup"
+	ExchangeService_DeclineGroup_FullMethodName       = "/exchange.ExchangeService/DeclineGroup"
 )
 
 // ExchangeServiceClient is the client API for ExchangeService service.
@@ -49,6 +58,15 @@ type ExchangeServiceClient interface {
 	RemoveOfferedBook(ctx context.Context, in *BookOpRequest, opts ...grpc.CallOption) (*OfferResponse, error)
 	ListAllOffers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OfferList, error)
 	ListOffersByStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*OfferList, error)
+	CounterOffer(ctx context.Context, in *CounterOfferRequest, opts ...grpc.CallOption) (*OfferResponse, error)
+	GetOfferThread(ctx context.Context, in *OfferID, opts ...grpc.CallOption) (*OfferThread, error)
+	SetWishlist(ctx context.Context, in *SetWishlistRequest, opts ...grpc.CallOption) (*WishlistResponse, error)
+	GetWishlist(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*WishlistResponse, error)
+	MatchGroups(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GroupList, error)
+	GetGroup(ctx context.Context, in *GroupID, opts ...grpc.CallOption) (*GroupResponse, error)
+	ListGroupsByUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*GroupList, error)
+	AcceptGroup(ctx context.Context, in *GroupActionRequest, opts ...grpc.CallOption) (*GroupResponse, error)
+	DeclineGroup(ctx context.Context, in *GroupActionRequest, opts ...grpc.CallOption) (*GroupResponse, error)
 }
 
 type exchangeServiceClient struct {
@@ -179,6 +197,96 @@ func (c *exchangeServiceClient) ListOffersByStatus(ctx context.Context, in *Stat
 	return out, nil
 }
 
+func (c *exchangeServiceClient) CounterOffer(ctx context.Context, in *CounterOfferRequest, opts ...grpc.CallOption) (*OfferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OfferResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_CounterOffer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetOfferThread(ctx context.Context, in *OfferID, opts ...grpc.CallOption) (*OfferThread, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OfferThread)
+	err := c.cc.Invoke(ctx, ExchangeService_GetOfferThread_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) SetWishlist(ctx context.Context, in *SetWishlistRequest, opts ...grpc.CallOption) (*WishlistResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_SetWishlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetWishlist(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*WishlistResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetWishlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) MatchGroups(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GroupList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupList)
+	err := c.cc.Invoke(ctx, ExchangeService_MatchGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) GetGroup(ctx context.Context, in *GroupID, opts ...grpc.CallOption) (*GroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_GetGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) ListGroupsByUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*GroupList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupList)
+	err := c.cc.Invoke(ctx, ExchangeService_ListGroupsByUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) AcceptGroup(ctx context.Context, in *GroupActionRequest, opts ...grpc.CallOption) (*GroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_AcceptGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeServiceClient) DeclineGroup(ctx context.Context, in *GroupActionRequest, opts ...grpc.CallOption) (*GroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, ExchangeService_DeclineGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExchangeServiceServer is the server API for ExchangeService service.
 // All implementations must embed UnimplementedExchangeServiceServer
 // for forward compatibility.
@@ -195,6 +303,15 @@ type ExchangeServiceServer interface {
 	RemoveOfferedBook(context.Context, *BookOpRequest) (*OfferResponse, error)
 	ListAllOffers(context.Context, *Empty) (*OfferList, error)
 	ListOffersByStatus(context.Context, *StatusRequest) (*OfferList, error)
+	CounterOffer(context.Context, *CounterOfferRequest) (*OfferResponse, error)
+	GetOfferThread(context.Context, *OfferID) (*OfferThread, error)
+	SetWishlist(context.Context, *SetWishlistRequest) (*WishlistResponse, error)
+	GetWishlist(context.Context, *UserID) (*WishlistResponse, error)
+	MatchGroups(context.Context, *Empty) (*GroupList, error)
+	GetGroup(context.Context, *GroupID) (*GroupResponse, error)
+	ListGroupsByUser(context.Context, *UserID) (*GroupList, error)
+	AcceptGroup(context.Context, *GroupActionRequest) (*GroupResponse, error)
+	DeclineGroup(context.Context, *GroupActionRequest) (*GroupResponse, error)
 	mustEmbedUnimplementedExchangeServiceServer()
 }
 
@@ -241,6 +358,33 @@ func (UnimplementedExchangeServiceServer) ListAllOffers(context.Context, *Empty)
 func (UnimplementedExchangeServiceServer) ListOffersByStatus(context.Context, *StatusRequest) (*OfferList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListOffersByStatus not implemented")
 }
+func (UnimplementedExchangeServiceServer) CounterOffer(context.Context, *CounterOfferRequest) (*OfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CounterOffer not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetOfferThread(context.Context, *OfferID) (*OfferThread, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOfferThread not implemented")
+}
+func (UnimplementedExchangeServiceServer) SetWishlist(context.Context, *SetWishlistRequest) (*WishlistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetWishlist not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetWishlist(context.Context, *UserID) (*WishlistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWishlist not implemented")
+}
+func (UnimplementedExchangeServiceServer) MatchGroups(context.Context, *Empty) (*GroupList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatchGroups not implemented")
+}
+func (UnimplementedExchangeServiceServer) GetGroup(context.Context, *GroupID) (*GroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedExchangeServiceServer) ListGroupsByUser(context.Context, *UserID) (*GroupList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroupsByUser not implemented")
+}
+func (UnimplementedExchangeServiceServer) AcceptGroup(context.Context, *GroupActionRequest) (*GroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptGroup not implemented")
+}
+func (UnimplementedExchangeServiceServer) DeclineGroup(context.Context, *GroupActionRequest) (*GroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclineGroup not implemented")
+}
 func (UnimplementedExchangeServiceServer) mustEmbedUnimplementedExchangeServiceServer() {}
 func (UnimplementedExchangeServiceServer) testEmbeddedByValue()                         {}
 
@@ -478,6 +622,168 @@ func _ExchangeService_ListOffersByStatus_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ExchangeService_CounterOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CounterOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).CounterOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_CounterOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).CounterOffer(ctx, req.(*CounterOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetOfferThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OfferID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetOfferThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetOfferThread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetOfferThread(ctx, req.(*OfferID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_SetWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetWishlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).SetWishlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_SetWishlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).SetWishlist(ctx, req.(*SetWishlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetWishlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetWishlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetWishlist(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_MatchGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).MatchGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_MatchGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).MatchGroups(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_GetGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).GetGroup(ctx, req.(*GroupID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_ListGroupsByUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).ListGroupsByUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_ListGroupsByUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).ListGroupsByUser(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_AcceptGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).AcceptGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_AcceptGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).AcceptGroup(ctx, req.(*GroupActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeService_DeclineGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServiceServer).DeclineGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeService_DeclineGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServiceServer).DeclineGroup(ctx, req.(*GroupActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ExchangeService_ServiceDesc is the grpc.ServiceDesc for ExchangeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -533,6 +839,42 @@ var ExchangeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListOffersByStatus",
 			Handler:    _ExchangeService_ListOffersByStatus_Handler,
 		},
+		{
+			MethodName: "CounterOffer",
+			Handler:    _ExchangeService_CounterOffer_Handler,
+		},
+		{
+			MethodName: "GetOfferThread",
+			Handler:    _ExchangeService_GetOfferThread_Handler,
+		},
+		{
+			MethodName: "SetWishlist",
+			Handler:    _ExchangeService_SetWishlist_Handler,
+		},
+		{
+			MethodName: "GetWishlist",
+			Handler:    _ExchangeService_GetWishlist_Handler,
+		},
+		{
+			MethodName: "MatchGroups",
+			Handler:    _ExchangeService_MatchGroups_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _ExchangeService_GetGroup_Handler,
+		},
+		{
+			MethodName: "ListGroupsByUser",
+			Handler:    _ExchangeService_ListGroupsByUser_Handler,
+		},
+		{
+			MethodName: "AcceptGroup",
+			Handler:    _ExchangeService_AcceptGroup_Handler,
+		},
+		{
+			MethodName: "DeclineGroup",
+			Handler:    _ExchangeService_DeclineGroup_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "exchange.proto",
@@ -12,6 +12,7 @@ import (
 	"github.com/OshakbayAigerim/read_space/notification_service/internal/config"
 	"github.com/OshakbayAigerim/read_space/notification_service/internal/handler"
 	"github.com/OshakbayAigerim/read_space/notification_service/internal/usecase"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
 	userpb "github.com/OshakbayAigerim/read_space/user_service/proto"
 )
 
@@ -22,7 +23,11 @@ func main() {
 	}
 	defer nc.Close()
 
-	conn, err := grpc.Dial("localhost:50052", grpc.WithInsecure())
+	// адреса видны пользователю только ему самому, поэтому ходим с сервисным токеном
+	conn, err := grpc.Dial("localhost:50052",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(auth.SecretFromEnv(), "notification_service")),
+	)
 	if err != nil {
 		log.Fatalf("failed to dial UserService: %v", err)
 	}
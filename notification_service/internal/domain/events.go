@@ -12,6 +12,12 @@ type UserCreatedEvent struct {
 	Email string `json:"email"`
 }
 
+type PasswordResetRequestedEvent struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	Token  string `json:"token"`
+}
+
 type OrderCompletedEvent struct {
 	OrderID string   `json:"order_id"`
 	UserID  string   `json:"user_id"`
@@ -39,6 +45,26 @@ type OfferAcceptedEvent struct {
 	Counterparty string `json:"requester_id"`
 }
 
+type OfferExpiredEvent struct {
+	OfferID        string `json:"offer_id"`
+	OwnerID        string `json:"owner_id"`
+	CounterpartyID string `json:"counterparty_id"`
+	ExpiredAt      string `json:"expired_at"`
+}
+
+type GroupLegEvent struct {
+	GiverID    string `json:"giver_id"`
+	ReceiverID string `json:"receiver_id"`
+	BookID     string `json:"book_id"`
+}
+
+type ExchangeGroupEvent struct {
+	GroupID      string          `json:"group_id"`
+	Status       string          `json:"status"`
+	Participants []string        `json:"participants"`
+	Legs         []GroupLegEvent `json:"legs"`
+}
+
 type BookAssignedEvent struct {
 	UserID string `json:"user_id"`
 	BookID string `json:"book_id"`
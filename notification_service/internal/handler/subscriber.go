@@ -33,6 +33,17 @@ func SubscribeAll(nc *nats.Conn, notifier *usecase.Notifier) error {
 		return err
 	}
 
+	if _, err := nc.Subscribe("user.password_reset_requested", func(m *nats.Msg) {
+		var evt domain.PasswordResetRequestedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal user.password_reset_requested: %v", err)
+			return
+		}
+		notifier.SendPasswordReset(context.Background(), evt)
+	}); err != nil {
+		return err
+	}
+
 	if _, err := nc.Subscribe("order.completed", func(m *nats.Msg) {
 		var evt domain.OrderCompletedEvent
 		if err := json.Unmarshal(m.Data, &evt); err != nil {
@@ -88,6 +99,28 @@ func SubscribeAll(nc *nats.Conn, notifier *usecase.Notifier) error {
 		return err
 	}
 
+	if _, err := nc.Subscribe("exchange.expired", func(m *nats.Msg) {
+		var evt domain.OfferExpiredEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal exchange.expired: %v", err)
+			return
+		}
+		notifier.SendOfferExpired(context.Background(), evt)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := nc.Subscribe("exchange.group.*", func(m *nats.Msg) {
+		var evt domain.ExchangeGroupEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal %s: %v", m.Subject, err)
+			return
+		}
+		notifier.SendExchangeGroup(context.Background(), evt)
+	}); err != nil {
+		return err
+	}
+
 	if _, err := nc.Subscribe("userlibrary.book.assigned", func(m *nats.Msg) {
 		var evt domain.BookAssignedEvent
 		if err := json.Unmarshal(m.Data, &evt); err == nil {
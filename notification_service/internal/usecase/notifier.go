@@ -47,6 +47,13 @@ func (n *Notifier) SendWelcome(ctx context.Context, evt domain.UserCreatedEvent)
 	log.Printf(" Welcome email sent to %s", evt.Email)
 }
 
+func (n *Notifier) SendPasswordReset(ctx context.Context, evt domain.PasswordResetRequestedEvent) {
+	subject := "Сброс пароля ReadSpace"
+	body := fmt.Sprintf("Код для сброса пароля: %s\n\nКод действует 30 минут. Если вы не запрашивали сброс, просто проигнорируйте это письмо.", evt.Token)
+	n.sendEmail(evt.Email, subject, body)
+	log.Printf(" Password reset email sent to %s", evt.Email)
+}
+
 func (n *Notifier) SendOrderCompleted(ctx context.Context, evt domain.OrderCompletedEvent) {
 	email, err := n.getEmail(ctx, evt.UserID)
 	if err != nil {
@@ -106,6 +113,55 @@ func (n *Notifier) SendOfferAccepted(ctx context.Context, evt domain.OfferAccept
 	log.Printf(" Email sent to %s", email)
 }
 
+// SendOfferExpired tells both parties that nobody answered the offer in time.
+func (n *Notifier) SendOfferExpired(ctx context.Context, evt domain.OfferExpiredEvent) {
+	subject := "Срок предложения обмена истёк"
+	body := fmt.Sprintf("Предложение %s не было принято вовремя и закрыто.", evt.OfferID)
+	for _, userID := range []string{evt.OwnerID, evt.CounterpartyID} {
+		email, err := n.getEmail(ctx, userID)
+		if err != nil {
+			log.Printf("cannot fetch email for %s: %v", userID, err)
+			continue
+		}
+		n.sendEmail(email, subject, body)
+		log.Printf(" Email sent to %s", email)
+	}
+}
+
+// SendExchangeGroup tells every participant of a multi-party exchange what
+// happened to it.
+func (n *Notifier) SendExchangeGroup(ctx context.Context, evt domain.ExchangeGroupEvent) {
+	var subject, body string
+	switch evt.Status {
+	case "PENDING":
+		subject = "Найден обмен по кругу"
+		body = fmt.Sprintf("Мы нашли обмен %s на %d участников. Примите его, чтобы книги переехали.", evt.GroupID, len(evt.Participants))
+	case "ACCEPTED":
+		subject = "Обмен по кругу состоялся"
+		body = fmt.Sprintf("Все участники приняли обмен %s, книги уже в ваших библиотеках.", evt.GroupID)
+	case "DECLINED":
+		subject = "Обмен по кругу отменён"
+		body = fmt.Sprintf("Один из участников отклонил обмен %s.", evt.GroupID)
+	case "EXPIRED":
+		subject = "Срок обмена по кругу истёк"
+		body = fmt.Sprintf("Обмен %s не приняли вовремя, и он закрыт.", evt.GroupID)
+	case "CANCELLED":
+		subject = "Обмен по кругу отменён"
+		body = fmt.Sprintf("Обмен %s закрыт: один из участников или одна из книг удалены.", evt.GroupID)
+	default:
+		return
+	}
+	for _, userID := range evt.Participants {
+		email, err := n.getEmail(ctx, userID)
+		if err != nil {
+			log.Printf("cannot fetch email for %s: %v", userID, err)
+			continue
+		}
+		n.sendEmail(email, subject, body)
+		log.Printf(" Email sent to %s", email)
+	}
+}
+
 func (n *Notifier) SendBookAssigned(ctx context.Context, evt domain.BookAssignedEvent) {
 	email, err := n.getEmail(ctx, evt.UserID)
 	if err != nil {
@@ -5,12 +5,18 @@ import (
 	"net"
 	"net/http"
 
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
 	"github.com/OshakbayAigerim/read_space/order_service/internal/cache"
 	"github.com/OshakbayAigerim/read_space/order_service/internal/config"
 	"github.com/OshakbayAigerim/read_space/order_service/internal/handler"
+	"github.com/OshakbayAigerim/read_space/order_service/internal/migration"
+	"github.com/OshakbayAigerim/read_space/order_service/internal/payment"
 	"github.com/OshakbayAigerim/read_space/order_service/internal/repository"
 	"github.com/OshakbayAigerim/read_space/order_service/internal/usecase"
 	pb "github.com/OshakbayAigerim/read_space/order_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
+	userpb "github.com/OshakbayAigerim/read_space/user_service/proto"
 	"github.com/nats-io/nats.go"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
@@ -20,6 +26,9 @@ func main() {
 	client := config.ConnectMongo()
 	db := client.Database("readspace")
 
+	migrations.MigrateLegacyOrders(db)
+	migrations.RenameCreatedStatus(db)
+
 	redisClient := config.ConnectRedis()
 	defer redisClient.Close()
 
@@ -35,17 +44,42 @@ func main() {
 		log.Fatal(http.ListenAndServe(":9091", nil))
 	}()
 
+	jwtSecret := auth.SecretFromEnv()
+
 	orderCache := cache.NewOrderCache(redisClient)
 	orderRepo := repository.NewMongoOrderRepository(db, orderCache)
-	orderUC := usecase.NewOrderUseCase(orderRepo)
+	bookConn, err := grpc.Dial("localhost:50051",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "order_service")),
+	)
+	if err != nil {
+		log.Fatalf("cannot dial BookService: %v", err)
+	}
+	defer bookConn.Close()
+	bookClient := bookpb.NewBookServiceClient(bookConn)
+
+	userConn, err := grpc.Dial("localhost:50052",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "order_service")),
+	)
+	if err != nil {
+		log.Fatalf("cannot dial UserService: %v", err)
+	}
+	defer userConn.Close()
+	refs := refcheck.New(userpb.NewUserServiceClient(userConn), bookClient, config.RefCacheTTL())
+
+	orderUC := usecase.NewOrderUseCase(orderRepo, bookClient, refs, payment.NewFakeProvider())
 
 	h := handler.NewOrderHandler(orderUC, nc)
+	if err := h.SubscribeDeletions(refs); err != nil {
+		log.Fatalf("NATS subscribe error: %v", err)
+	}
 
 	lis, err := net.Listen("tcp", ":50053")
 	if err != nil {
 		log.Fatalf(" failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(auth.UnaryServerInterceptor(jwtSecret)))
 	pb.RegisterOrderServiceServer(grpcServer, h)
 
 	log.Println("OrderService started on :50053")
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -43,3 +44,22 @@ func ConnectRedis() *redis.Client {
 	log.Println("Connected to Redis")
 	return client
 }
+
+// RefCacheTTL is how long the existence of a user or book is trusted
+// before it is checked again.
+func RefCacheTTL() time.Duration {
+	return durationEnv("REF_CACHE_TTL", 30*time.Second)
+}
+
+func durationEnv(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("invalid %s=%q, using %s", key, v, def)
+		return def
+	}
+	return d
+}
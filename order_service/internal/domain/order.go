@@ -1,12 +1,254 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Order lifecycle: Pending → Paid → Shipped → Delivered → Returned.
+// An order can be cancelled while it is Pending or Paid.
+const (
+	StatusPending   = "Pending"
+	StatusPaid      = "Paid"
+	StatusShipped   = "Shipped"
+	StatusDelivered = "Delivered"
+	StatusReturned  = "Returned"
+	StatusCancelled = "Cancelled"
+)
+
+var transitions = map[string][]string{
+	StatusPending:   {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusShipped, StatusCancelled},
+	StatusShipped:   {StatusDelivered},
+	StatusDelivered: {StatusReturned},
+}
+
+func IsValidStatus(s string) bool {
+	if _, ok := transitions[s]; ok {
+		return true
+	}
+	return s == StatusReturned || s == StatusCancelled
+}
+
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TaxRate is the VAT applied on top of the order subtotal.
+const TaxRate = 0.12
+
+var (
+	ErrOutOfStock        = errors.New("book is out of stock")
+	ErrBookNotFound      = errors.New("book not found")
+	ErrInvalidQuantity   = errors.New("quantity must be positive")
+	ErrBookNotInOrder    = errors.New("book is not in the order")
+	ErrUnknownStatus     = errors.New("unknown order status")
+	ErrNotEditable       = errors.New("only pending orders can be changed")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrPaymentDeclined   = errors.New("payment declined")
+	ErrNotPaid           = errors.New("order has no captured payment")
+)
+
+const (
+	PaymentCaptured = "Captured"
+	PaymentFailed   = "Failed"
+	// PaymentRefunding marks a captured payment claimed for a refund while
+	// the provider is called, so that it is never refunded twice.
+	PaymentRefunding = "Refunding"
+	PaymentRefunded  = "Refunded"
+)
+
+// Payment records the charge made for an order through a payment provider.
+type Payment struct {
+	Amount          float64            `bson:"amount"`
+	Status          string             `bson:"status"`
+	Provider        string             `bson:"provider"`
+	Reference       string             `bson:"reference,omitempty"`
+	RefundReference string             `bson:"refund_reference,omitempty"`
+	PaidAt          primitive.DateTime `bson:"paid_at,omitempty"`
+	RefundedAt      primitive.DateTime `bson:"refunded_at,omitempty"`
+}
+
+// StatusChange is one entry of an order's status history.
+type StatusChange struct {
+	Status    string             `bson:"status"`
+	ChangedAt primitive.DateTime `bson:"changed_at"`
+}
+
+// LineItem is one book in an order. UnitPrice is the book's price at the
+// moment it was added, so later catalog price changes do not affect
+// existing orders.
+type LineItem struct {
+	BookID    primitive.ObjectID `bson:"book_id"`
+	Quantity  int                `bson:"quantity"`
+	UnitPrice float64            `bson:"unit_price"`
+}
+
+func (li LineItem) Total() float64 {
+	return roundMoney(li.UnitPrice * float64(li.Quantity))
+}
 
 type Order struct {
-	ID        primitive.ObjectID
-	UserID    primitive.ObjectID
-	BookIDs   []primitive.ObjectID
-	Status    string
-	CreatedAt primitive.DateTime
-	UpdatedAt primitive.DateTime
+	ID        primitive.ObjectID `bson:"_id"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	Items     []LineItem         `bson:"items"`
+	Subtotal  float64            `bson:"subtotal"`
+	Tax       float64            `bson:"tax"`
+	Total     float64            `bson:"total"`
+	Status    string             `bson:"status"`
+	History   []StatusChange     `bson:"status_history"`
+	Payment   *Payment           `bson:"payment,omitempty"`
+	CreatedAt primitive.DateTime `bson:"created_at"`
+	UpdatedAt primitive.DateTime `bson:"updated_at"`
+	// Version counts the changes to the items and status. Such changes
+	// only apply to the version they were read at, so that two of them
+	// cannot overwrite each other.
+	Version int `bson:"version"`
+	// Orphaned names why the order points at a user or book that no
+	// longer exists; empty for ordinary orders.
+	Orphaned string `bson:"orphaned,omitempty"`
+}
+
+// Reasons recorded in Order.Orphaned.
+const (
+	OrphanUserDeleted = "user_deleted"
+	OrphanBookDeleted = "book_deleted"
+)
+
+// HoldsStock reports whether the order's books are still taken out of the
+// book service's stock.
+func (o *Order) HoldsStock() bool {
+	return o.Status != StatusCancelled && o.Status != StatusReturned
+}
+
+// Transition moves the order to the given status and records it in the
+// history.
+func (o *Order) Transition(to string, at time.Time) error {
+	if !IsValidStatus(to) {
+		return ErrUnknownStatus
+	}
+	if !CanTransition(o.Status, to) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, o.Status, to)
+	}
+	o.Status = to
+	o.History = append(o.History, StatusChange{Status: to, ChangedAt: primitive.NewDateTimeFromTime(at)})
+	return nil
+}
+
+// IsPaid reports whether a captured payment is waiting to be refunded if
+// the order is cancelled or returned.
+func (o *Order) IsPaid() bool {
+	return o.Payment != nil && o.Payment.Status == PaymentCaptured
+}
+
+// PaymentStatus is the status of the order's payment, "" without one.
+func (o *Order) PaymentStatus() string {
+	if o.Payment == nil {
+		return ""
+	}
+	return o.Payment.Status
+}
+
+// BookIDs lists the distinct books in the order.
+func (o *Order) BookIDs() []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(o.Items))
+	for _, it := range o.Items {
+		ids = append(ids, it.BookID)
+	}
+	return ids
+}
+
+// AddItem adds quantity copies of a book, merging with an existing line.
+// The unit price of an existing line is kept.
+func (o *Order) AddItem(bookID primitive.ObjectID, quantity int, unitPrice float64) {
+	for i := range o.Items {
+		if o.Items[i].BookID == bookID {
+			o.Items[i].Quantity += quantity
+			o.Recalculate()
+			return
+		}
+	}
+	o.Items = append(o.Items, LineItem{BookID: bookID, Quantity: quantity, UnitPrice: unitPrice})
+	o.Recalculate()
+}
+
+// RemoveItem takes quantity copies of a book out of the order; a quantity
+// of zero or more than ordered drops the whole line. It returns the number
+// of copies removed.
+func (o *Order) RemoveItem(bookID primitive.ObjectID, quantity int) (int, error) {
+	for i := range o.Items {
+		if o.Items[i].BookID != bookID {
+			continue
+		}
+		removed := o.Items[i].Quantity
+		if quantity > 0 && quantity < removed {
+			o.Items[i].Quantity -= quantity
+			removed = quantity
+		} else {
+			o.Items = append(o.Items[:i], o.Items[i+1:]...)
+		}
+		o.Recalculate()
+		return removed, nil
+	}
+	return 0, ErrBookNotInOrder
+}
+
+// Recalculate refreshes subtotal, tax and total from the line items.
+func (o *Order) Recalculate() {
+	var subtotal float64
+	for _, it := range o.Items {
+		subtotal += it.Total()
+	}
+	o.Subtotal = roundMoney(subtotal)
+	o.Tax = roundMoney(o.Subtotal * TaxRate)
+	o.Total = roundMoney(o.Subtotal + o.Tax)
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// RoundPrice converts a catalog price to an amount in whole cents.
+func RoundPrice(p float32) float64 {
+	return roundMoney(float64(p))
+}
+
+// UserDeletedEvent and BookDeletedEvent come from the user and book
+// services; orders referring to them are cancelled where possible and
+// flagged as orphaned.
+type UserDeletedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+type BookDeletedEvent struct {
+	BookID string `json:"book_id"`
+}
+
+// StatusChangedEvent is published on orders.<status> for every transition,
+// e.g. orders.paid or orders.cancelled.
+type StatusChangedEvent struct {
+	OrderID string   `json:"order_id"`
+	UserID  string   `json:"user_id"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	BookIDs []string `json:"book_ids"`
+	Total   float64  `json:"total"`
+}
+
+// PaymentEvent is published on orders.payment_failed and orders.refunded.
+type PaymentEvent struct {
+	OrderID   string  `json:"order_id"`
+	UserID    string  `json:"user_id"`
+	Amount    float64 `json:"amount"`
+	Status    string  `json:"status"`
+	Reference string  `json:"reference"`
 }
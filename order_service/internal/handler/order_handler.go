@@ -3,11 +3,15 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"strings"
 
 	"github.com/OshakbayAigerim/read_space/order_service/internal/domain"
 	"github.com/OshakbayAigerim/read_space/order_service/internal/usecase"
 	pb "github.com/OshakbayAigerim/read_space/order_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
 	"github.com/nats-io/nats.go"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc/codes"
@@ -25,42 +29,42 @@ func NewOrderHandler(u usecase.OrderUseCase, nc *nats.Conn) *OrderHandler {
 }
 
 func (h *OrderHandler) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.OrderResponse, error) {
-	if req == nil || req.UserId == "" || len(req.BookIds) == 0 {
-		return nil, status.Error(codes.InvalidArgument, "user_id and book_ids are required")
+	if req == nil || req.UserId == "" || len(req.BookIds)+len(req.Items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id and book_ids or items are required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
 	}
 
 	uid, err := primitive.ObjectIDFromHex(req.UserId)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
 	}
-	var bids []primitive.ObjectID
-	for _, id := range req.BookIds {
-		oid, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "invalid book_id %q", id)
-		}
-		bids = append(bids, oid)
+	items, err := toLineItems(req.BookIds, req.Items)
+	if err != nil {
+		return nil, err
 	}
 
 	ord := &domain.Order{
-		ID:      primitive.NewObjectID(),
-		UserID:  uid,
-		BookIDs: bids,
-		Status:  "Created",
+		ID:     primitive.NewObjectID(),
+		UserID: uid,
+		Items:  items,
 	}
 	created, err := h.uc.CreateOrder(ctx, ord)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot create order: %v", err)
+		return nil, orderError("cannot create order", err)
 	}
 
 	evt := struct {
 		OrderID string   `json:"order_id"`
 		UserID  string   `json:"user_id"`
 		BookIDs []string `json:"book_ids"`
+		Total   float64  `json:"total"`
 	}{
 		OrderID: created.ID.Hex(),
 		UserID:  created.UserID.Hex(),
-		BookIDs: req.BookIds,
+		BookIDs: toHexs(created.BookIDs()),
+		Total:   created.Total,
 	}
 	if raw, err := json.Marshal(evt); err == nil {
 		if err := h.nc.Publish("orders.created", raw); err != nil {
@@ -79,6 +83,9 @@ func (h *OrderHandler) GetOrder(ctx context.Context, req *pb.OrderID) (*pb.Order
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "order not found: %v", err)
 	}
+	if err := auth.AuthorizeUser(ctx, ord.UserID.Hex()); err != nil {
+		return nil, err
+	}
 	return &pb.OrderResponse{Order: mapDomain(ord)}, nil
 }
 
@@ -86,6 +93,9 @@ func (h *OrderHandler) ListOrdersByUser(ctx context.Context, req *pb.ListOrdersB
 	if req == nil || req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
 	orders, err := h.uc.ListOrdersByUser(ctx, req.UserId)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot list orders: %v", err)
@@ -93,34 +103,147 @@ func (h *OrderHandler) ListOrdersByUser(ctx context.Context, req *pb.ListOrdersB
 	return &pb.OrderList{Orders: mapDomainList(orders)}, nil
 }
 
+// ListOrdersByBook returns every order that contains the book. It feeds the
+// catalog's recommendations, so only staff and services may call it.
+func (h *OrderHandler) ListOrdersByBook(ctx context.Context, req *pb.ListOrdersByBookRequest) (*pb.OrderList, error) {
+	if req == nil || req.BookId == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id is required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	orders, err := h.uc.ListOrdersByBook(ctx, req.BookId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list orders: %v", err)
+	}
+	return &pb.OrderList{Orders: mapDomainList(orders)}, nil
+}
+
 func (h *OrderHandler) CancelOrder(ctx context.Context, req *pb.OrderID) (*pb.OrderResponse, error) {
 	if req == nil || req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "order id is required")
 	}
-	o, err := h.uc.CancelOrder(ctx, req.Id)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot cancel order: %v", err)
+	if err := h.authorizeOrder(ctx, req.Id); err != nil {
+		return nil, err
 	}
-	return &pb.OrderResponse{Order: mapDomain(o)}, nil
+	return h.changeStatus(ctx, req.Id, domain.StatusCancelled)
 }
 
 func (h *OrderHandler) ReturnBook(ctx context.Context, req *pb.OrderID) (*pb.OrderResponse, error) {
 	if req == nil || req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "order id is required")
 	}
-	o, err := h.uc.ReturnBook(ctx, req.Id)
+	if err := h.authorizeOrder(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	return h.changeStatus(ctx, req.Id, domain.StatusReturned)
+}
+
+// UpdateOrderStatus lets staff move orders through payment and delivery.
+func (h *OrderHandler) UpdateOrderStatus(ctx context.Context, req *pb.OrderStatusRequest) (*pb.OrderResponse, error) {
+	if req == nil || req.OrderId == "" || req.Status == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id and status are required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return h.changeStatus(ctx, req.OrderId, req.Status)
+}
+
+// PayOrder charges the owner for a pending order.
+func (h *OrderHandler) PayOrder(ctx context.Context, req *pb.PayOrderRequest) (*pb.OrderResponse, error) {
+	if req == nil || req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	ord, err := h.uc.GetOrderByID(ctx, req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "order not found: %v", err)
+	}
+	if err := auth.AuthorizeUser(ctx, ord.UserID.Hex()); err != nil {
+		return nil, err
+	}
+
+	paid, err := h.uc.PayOrder(ctx, req.OrderId, req.PaymentMethod)
+	if errors.Is(err, domain.ErrPaymentDeclined) {
+		h.publishPayment("orders.payment_failed", ord, domain.PaymentFailed, "")
+	}
+	if err != nil {
+		return nil, orderError("cannot pay order", err)
+	}
+	h.publishStatus(paid, ord.Status)
+	return &pb.OrderResponse{Order: mapDomain(paid)}, nil
+}
+
+// RefundOrder refunds a paid order without changing its status.
+func (h *OrderHandler) RefundOrder(ctx context.Context, req *pb.OrderID) (*pb.OrderResponse, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleLibrarian, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	o, err := h.uc.RefundOrder(ctx, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot return order: %v", err)
+		return nil, orderError("cannot refund order", err)
 	}
+	h.publishPayment("orders.refunded", o, o.Payment.Status, o.Payment.RefundReference)
 	return &pb.OrderResponse{Order: mapDomain(o)}, nil
 }
 
+func (h *OrderHandler) changeStatus(ctx context.Context, id, to string) (*pb.OrderResponse, error) {
+	t, err := h.uc.ChangeStatus(ctx, id, to)
+	if err != nil {
+		return nil, orderError("cannot change order status", err)
+	}
+	h.publishStatus(t.Order, t.From)
+	if t.Refunded {
+		h.publishPayment("orders.refunded", t.Order, t.Order.Payment.Status, t.Order.Payment.RefundReference)
+	}
+	return &pb.OrderResponse{Order: mapDomain(t.Order)}, nil
+}
+
+func (h *OrderHandler) publishStatus(o *domain.Order, from string) {
+	evt := domain.StatusChangedEvent{
+		OrderID: o.ID.Hex(),
+		UserID:  o.UserID.Hex(),
+		From:    from,
+		To:      o.Status,
+		BookIDs: toHexs(o.BookIDs()),
+		Total:   o.Total,
+	}
+	h.publish("orders."+strings.ToLower(o.Status), evt)
+}
+
+func (h *OrderHandler) publishPayment(subject string, o *domain.Order, paymentStatus, reference string) {
+	evt := domain.PaymentEvent{
+		OrderID:   o.ID.Hex(),
+		UserID:    o.UserID.Hex(),
+		Amount:    o.Total,
+		Status:    paymentStatus,
+		Reference: reference,
+	}
+	h.publish(subject, evt)
+}
+
+func (h *OrderHandler) publish(subject string, evt interface{}) {
+	raw, err := json.Marshal(evt)
+	if err != nil {
+		return
+	}
+	if err := h.nc.Publish(subject, raw); err != nil {
+		log.Printf("⚠️ publish %s: %v", subject, err)
+	}
+}
+
 func (h *OrderHandler) DeleteOrder(ctx context.Context, req *pb.OrderID) (*pb.Empty, error) {
 	if req == nil || req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "order id is required")
 	}
+	if err := h.authorizeOrder(ctx, req.Id); err != nil {
+		return nil, err
+	}
 	if err := h.uc.DeleteOrder(ctx, req.Id); err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot delete order: %v", err)
+		return nil, orderError("cannot delete order", err)
 	}
 	return &pb.Empty{}, nil
 }
@@ -137,24 +260,31 @@ func (h *OrderHandler) UpdateOrder(ctx context.Context, req *pb.UpdateOrderReque
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
 	}
-	var bids []primitive.ObjectID
-	for _, id := range req.Order.BookIds {
-		bid, err := primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "invalid book_id %q", id)
-		}
-		bids = append(bids, bid)
+	if err := h.authorizeOrder(ctx, req.Order.Id); err != nil {
+		return nil, err
+	}
+	if err := auth.AuthorizeUser(ctx, req.Order.UserId); err != nil {
+		return nil, err
+	}
+	// items with quantities win over the plain book_ids list
+	bookIDs := req.Order.BookIds
+	if len(req.Order.Items) > 0 {
+		bookIDs = nil
+	}
+	items, err := toLineItems(bookIDs, req.Order.Items)
+	if err != nil {
+		return nil, err
 	}
 
 	dom := &domain.Order{
-		ID:      oid,
-		UserID:  uid,
-		BookIDs: bids,
-		Status:  req.Order.Status,
+		ID:     oid,
+		UserID: uid,
+		Items:  items,
+		Status: req.Order.Status,
 	}
 	updated, err := h.uc.UpdateOrder(ctx, dom)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot update order: %v", err)
+		return nil, orderError("cannot update order", err)
 	}
 	return &pb.OrderResponse{Order: mapDomain(updated)}, nil
 }
@@ -163,9 +293,16 @@ func (h *OrderHandler) AddBookToOrder(ctx context.Context, req *pb.BookOperation
 	if req == nil || req.OrderId == "" || req.BookId == "" {
 		return nil, status.Error(codes.InvalidArgument, "order_id and book_id are required")
 	}
-	o, err := h.uc.AddBook(ctx, req.OrderId, req.BookId)
+	if err := h.authorizeOrder(ctx, req.OrderId); err != nil {
+		return nil, err
+	}
+	quantity := int(req.Quantity)
+	if quantity == 0 {
+		quantity = 1
+	}
+	o, err := h.uc.AddBook(ctx, req.OrderId, req.BookId, quantity)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot add book to order: %v", err)
+		return nil, orderError("cannot add book to order", err)
 	}
 	return &pb.OrderResponse{Order: mapDomain(o)}, nil
 }
@@ -174,14 +311,20 @@ func (h *OrderHandler) RemoveBookFromOrder(ctx context.Context, req *pb.BookOper
 	if req == nil || req.OrderId == "" || req.BookId == "" {
 		return nil, status.Error(codes.InvalidArgument, "order_id and book_id are required")
 	}
-	o, err := h.uc.RemoveBook(ctx, req.OrderId, req.BookId)
+	if err := h.authorizeOrder(ctx, req.OrderId); err != nil {
+		return nil, err
+	}
+	o, err := h.uc.RemoveBook(ctx, req.OrderId, req.BookId, int(req.Quantity))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot remove book from order: %v", err)
+		return nil, orderError("cannot remove book from order", err)
 	}
 	return &pb.OrderResponse{Order: mapDomain(o)}, nil
 }
 
 func (h *OrderHandler) ListAllOrders(ctx context.Context, _ *pb.Empty) (*pb.OrderList, error) {
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
 	all, err := h.uc.ListAll(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot list all orders: %v", err)
@@ -193,6 +336,12 @@ func (h *OrderHandler) ListOrdersByStatus(ctx context.Context, req *pb.StatusReq
 	if req == nil || req.Status == "" {
 		return nil, status.Error(codes.InvalidArgument, "status is required")
 	}
+	if !domain.IsValidStatus(req.Status) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown status %q", req.Status)
+	}
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
 	filtered, err := h.uc.ListByStatus(ctx, req.Status)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot list orders by status: %v", err)
@@ -200,19 +349,118 @@ func (h *OrderHandler) ListOrdersByStatus(ctx context.Context, req *pb.StatusReq
 	return &pb.OrderList{Orders: mapDomainList(filtered)}, nil
 }
 
+// authorizeOrder checks that the caller owns the order.
+func (h *OrderHandler) authorizeOrder(ctx context.Context, id string) error {
+	ord, err := h.uc.GetOrderByID(ctx, id)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "order not found: %v", err)
+	}
+	return auth.AuthorizeUser(ctx, ord.UserID.Hex())
+}
+
+// orderError maps domain and stock failures to their gRPC codes; anything
+// else is an internal error.
+func orderError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOutOfStock):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrBookNotFound), errors.Is(err, domain.ErrBookNotInOrder),
+		errors.Is(err, refcheck.ErrUnknownUser):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidQuantity), errors.Is(err, domain.ErrUnknownStatus):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrNotEditable),
+		errors.Is(err, domain.ErrPaymentDeclined), errors.Is(err, domain.ErrNotPaid):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+// toLineItems combines plain book IDs (one copy each) and explicit items
+// into unpriced line items.
+func toLineItems(bookIDs []string, items []*pb.LineItem) ([]domain.LineItem, error) {
+	var out []domain.LineItem
+	for _, id := range bookIDs {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid book_id %q", id)
+		}
+		out = append(out, domain.LineItem{BookID: oid, Quantity: 1})
+	}
+	for _, it := range items {
+		oid, err := primitive.ObjectIDFromHex(it.BookId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid book_id %q", it.BookId)
+		}
+		if it.Quantity <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "quantity of book %s must be positive", it.BookId)
+		}
+		out = append(out, domain.LineItem{BookID: oid, Quantity: int(it.Quantity)})
+	}
+	return out, nil
+}
+
 func mapDomain(o *domain.Order) *pb.Order {
-	var bookIDs []string
-	for _, id := range o.BookIDs {
-		bookIDs = append(bookIDs, id.Hex())
+	items := make([]*pb.LineItem, 0, len(o.Items))
+	for _, it := range o.Items {
+		items = append(items, &pb.LineItem{
+			BookId:    it.BookID.Hex(),
+			Quantity:  int32(it.Quantity),
+			UnitPrice: it.UnitPrice,
+			LineTotal: it.Total(),
+		})
+	}
+	history := make([]*pb.StatusChange, 0, len(o.History))
+	for _, c := range o.History {
+		history = append(history, &pb.StatusChange{
+			Status:    c.Status,
+			ChangedAt: c.ChangedAt.Time().String(),
+		})
 	}
 	return &pb.Order{
-		Id:        o.ID.Hex(),
-		UserId:    o.UserID.Hex(),
-		BookIds:   bookIDs,
-		Status:    o.Status,
-		CreatedAt: o.CreatedAt.Time().String(),
-		UpdatedAt: o.UpdatedAt.Time().String(),
+		Id:            o.ID.Hex(),
+		UserId:        o.UserID.Hex(),
+		BookIds:       toHexs(o.BookIDs()),
+		Status:        o.Status,
+		CreatedAt:     o.CreatedAt.Time().String(),
+		UpdatedAt:     o.UpdatedAt.Time().String(),
+		Items:         items,
+		Subtotal:      o.Subtotal,
+		Tax:           o.Tax,
+		Total:         o.Total,
+		StatusHistory: history,
+		Payment:       mapPayment(o.Payment),
+		Orphaned:      o.Orphaned,
+	}
+}
+
+func mapPayment(p *domain.Payment) *pb.Payment {
+	if p == nil {
+		return nil
+	}
+	out := &pb.Payment{
+		Amount:          p.Amount,
+		Status:          p.Status,
+		Provider:        p.Provider,
+		Reference:       p.Reference,
+		RefundReference: p.RefundReference,
+	}
+	if p.PaidAt != 0 {
+		out.PaidAt = p.PaidAt.Time().String()
 	}
+	if p.RefundedAt != 0 {
+		out.RefundedAt = p.RefundedAt.Time().String()
+	}
+	return out
+}
+
+func toHexs(ids []primitive.ObjectID) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.Hex())
+	}
+	return out
 }
 
 func mapDomainList(list []*domain.Order) []*pb.Order {
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/OshakbayAigerim/read_space/order_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/order_service/internal/usecase"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
+)
+
+// SubscribeDeletions cancels and flags the orders of deleted users and
+// flags the orders containing deleted books.
+func (h *OrderHandler) SubscribeDeletions(refs *refcheck.Checker) error {
+	if _, err := h.nc.Subscribe("user.deleted", func(m *nats.Msg) {
+		var evt domain.UserDeletedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal user.deleted: %v", err)
+			return
+		}
+		refs.ForgetUser(evt.UserID)
+		cancelled, err := h.uc.OrphanUserOrders(context.Background(), evt.UserID)
+		h.publishOrphaned(cancelled, err, "user "+evt.UserID)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := h.nc.Subscribe("book.deleted", func(m *nats.Msg) {
+		var evt domain.BookDeletedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal book.deleted: %v", err)
+			return
+		}
+		open, err := h.uc.OrphanBookOrders(context.Background(), evt.BookID)
+		if err != nil {
+			log.Printf("⚠️ cannot process orders of deleted book %s: %v", evt.BookID, err)
+			return
+		}
+		for _, o := range open {
+			log.Printf("⚠️ order %s (%s) contains deleted book %s and needs manual handling", o.ID.Hex(), o.Status, evt.BookID)
+		}
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (h *OrderHandler) publishOrphaned(cancelled []*usecase.Transition, err error, what string) {
+	if err != nil {
+		log.Printf("⚠️ cannot process orders of deleted %s: %v", what, err)
+		return
+	}
+	for _, t := range cancelled {
+		h.publishStatus(t.Order, t.From)
+		if t.Refunded {
+			h.publishPayment("orders.refunded", t.Order, t.Order.Payment.Status, t.Order.Payment.RefundReference)
+		}
+	}
+	if len(cancelled) > 0 {
+		log.Printf("Cancelled %d orders of deleted %s", len(cancelled), what)
+	}
+}
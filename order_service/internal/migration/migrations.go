@@ -0,0 +1,119 @@
+package migrations
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/order_service/internal/domain"
+)
+
+// MigrateLegacyOrders rewrites orders stored before line items were
+// introduced. Those documents used the driver's default field names
+// ("id", "userid", "bookids", ...) and only listed book IDs; each book
+// becomes a line item with its copies counted. Prices were never captured
+// for them, so unit prices are left at zero.
+func MigrateLegacyOrders(db *mongo.Database) {
+	collection := db.Collection("orders")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{"items": bson.M{"$exists": false}})
+	if err != nil {
+		log.Fatalf("Failed to find legacy orders: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			log.Fatalf("Failed to decode order: %v", err)
+		}
+
+		order := domain.Order{
+			ID:        objectID(doc, "id", "_id"),
+			UserID:    objectID(doc, "userid", "user_id"),
+			Status:    stringField(doc, "status"),
+			CreatedAt: dateField(doc, "createdat", "created_at"),
+			UpdatedAt: dateField(doc, "updatedat", "updated_at"),
+		}
+		for _, key := range []string{"bookids", "book_ids"} {
+			ids, _ := doc[key].(bson.A)
+			for _, v := range ids {
+				if id, ok := v.(primitive.ObjectID); ok {
+					order.AddItem(id, 1, 0)
+				}
+			}
+		}
+		order.Recalculate()
+
+		// старые документы хранили ID заказа в поле "id" отдельно от _id;
+		// пересоздаём их, чтобы _id совпадал с ID, который видели клиенты
+		if order.ID == doc["_id"] {
+			if _, err := collection.ReplaceOne(ctx, bson.M{"_id": order.ID}, order); err != nil {
+				log.Fatalf("Failed to migrate order %s: %v", order.ID.Hex(), err)
+			}
+		} else {
+			if _, err := collection.InsertOne(ctx, order); err != nil {
+				log.Fatalf("Failed to store migrated order %s: %v", order.ID.Hex(), err)
+			}
+			if _, err := collection.DeleteOne(ctx, bson.M{"_id": doc["_id"]}); err != nil {
+				log.Fatalf("Failed to remove legacy order %v: %v", doc["_id"], err)
+			}
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("Migrated %d legacy orders to line items", migrated)
+	}
+}
+
+// RenameCreatedStatus moves orders from the old "Created" status to
+// "Pending", the first state of the order lifecycle.
+func RenameCreatedStatus(db *mongo.Database) {
+	collection := db.Collection("orders")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateMany(ctx,
+		bson.M{"status": "Created"},
+		bson.M{"$set": bson.M{"status": domain.StatusPending}},
+	)
+	if err != nil {
+		log.Fatalf("Failed to rename order status: %v", err)
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("Moved %d orders from Created to Pending", res.ModifiedCount)
+	}
+}
+
+func objectID(doc bson.M, keys ...string) primitive.ObjectID {
+	for _, k := range keys {
+		if id, ok := doc[k].(primitive.ObjectID); ok && !id.IsZero() {
+			return id
+		}
+	}
+	return primitive.NilObjectID
+}
+
+func stringField(doc bson.M, key string) string {
+	s, _ := doc[key].(string)
+	return s
+}
+
+func dateField(doc bson.M, keys ...string) primitive.DateTime {
+	for _, k := range keys {
+		if d, ok := doc[k].(primitive.DateTime); ok {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DeclineMethod makes FakeProvider refuse the charge.
+const DeclineMethod = "fake_decline"
+
+const fakeChargePrefix = "fake_ch_"
+
+// FakeProvider is an in-process provider for development and tests. It is
+// deterministic: every charge succeeds unless the method is DeclineMethod,
+// and references are derived from the order ID and a counter. It keeps no
+// state about charges, so refunds keep working across restarts.
+type FakeProvider struct {
+	mu  sync.Mutex
+	seq int
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Charge(_ context.Context, req ChargeRequest) (string, error) {
+	if req.Method == DeclineMethod {
+		return "", ErrDeclined
+	}
+	if req.Amount <= 0 {
+		return "", fmt.Errorf("%w: amount must be positive", ErrDeclined)
+	}
+	return fmt.Sprintf("%s%s_%d", fakeChargePrefix, req.OrderID, p.next()), nil
+}
+
+func (p *FakeProvider) Refund(_ context.Context, reference string, amount float64) (string, error) {
+	if !strings.HasPrefix(reference, fakeChargePrefix) {
+		return "", fmt.Errorf("unknown charge %q", reference)
+	}
+	if amount <= 0 {
+		return "", fmt.Errorf("refund amount must be positive")
+	}
+	return fmt.Sprintf("fake_re_%s_%d", strings.TrimPrefix(reference, fakeChargePrefix), p.next()), nil
+}
+
+func (p *FakeProvider) next() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seq++
+	return p.seq
+}
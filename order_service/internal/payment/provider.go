@@ -0,0 +1,26 @@
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDeclined is returned by providers when the charge was refused, as
+// opposed to the provider being unreachable.
+var ErrDeclined = errors.New("payment declined")
+
+type ChargeRequest struct {
+	OrderID string
+	UserID  string
+	Amount  float64
+	// Method is the provider-specific payment method token.
+	Method string
+}
+
+// Provider is implemented by every payment backend order_service can charge
+// through. References returned by Charge are what Refund expects.
+type Provider interface {
+	Name() string
+	Charge(ctx context.Context, req ChargeRequest) (reference string, err error)
+	Refund(ctx context.Context, reference string, amount float64) (refundReference string, err error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/OshakbayAigerim/read_space/order_service/internal/cache"
@@ -88,53 +89,50 @@ func (r *mongoOrderRepo) ListByUser(ctx context.Context, userID string) ([]*doma
 	return orders, nil
 }
 
-func (r *mongoOrderRepo) Cancel(ctx context.Context, id string) (*domain.Order, error) {
-	return r.updateStatusWithCache(ctx, id, "Cancelled")
-}
-
-func (r *mongoOrderRepo) Return(ctx context.Context, id string) (*domain.Order, error) {
-	return r.updateStatusWithCache(ctx, id, "Returned")
-}
-
-func (r *mongoOrderRepo) updateStatusWithCache(ctx context.Context, id, status string) (*domain.Order, error) {
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return nil, err
-	}
-	var existing domain.Order
-	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&existing); err != nil {
-		return nil, err
-	}
-
+// UpdateStatus stores the order's new status, history and payment. The update only
+// applies while the stored status is still from, the stored payment status
+// is still paymentFrom ("" without a payment) and the order is still at the
+// version it was read at, so two concurrent transitions, a transition and a
+// refund, or a payment and an edit of the items, cannot both succeed.
+func (r *mongoOrderRepo) UpdateStatus(ctx context.Context, order *domain.Order, from, paymentFrom string) (*domain.Order, error) {
 	now := primitive.NewDateTimeFromTime(time.Now())
 	after := options.After
 	opt := options.FindOneAndUpdateOptions{ReturnDocument: &after}
-	filter := bson.M{"_id": objID}
-	update := bson.M{"$set": bson.M{"status": status, "updated_at": now}}
+	filter := paymentFilter(versionFilter(bson.M{"_id": order.ID, "status": from}, order.Version), paymentFrom)
+	update := bson.M{
+		"$set": bson.M{
+			"status":         order.Status,
+			"status_history": order.History,
+			"payment":        order.Payment,
+			"updated_at":     now,
+		},
+		"$inc": bson.M{"version": 1},
+	}
 
 	var updated domain.Order
 	if err := r.collection.FindOneAndUpdate(ctx, filter, update, &opt).Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			r.cache.Delete(ctx, order.ID.Hex())
+		}
 		return nil, err
 	}
 
-	r.cache.Delete(ctx, id)
-	r.cache.DeleteByUser(ctx, existing.UserID.Hex())
+	r.cache.Delete(ctx, order.ID.Hex())
+	r.cache.DeleteByUser(ctx, order.UserID.Hex())
 	return &updated, nil
 }
 
-func (r *mongoOrderRepo) Update(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+// SavePayment stores the order's payment while the stored payment status is
+// still from ("" without a payment). Refunds claim the payment this way
+// before the provider is called.
+func (r *mongoOrderRepo) SavePayment(ctx context.Context, order *domain.Order, from string) (*domain.Order, error) {
 	now := primitive.NewDateTimeFromTime(time.Now())
 	after := options.After
 	opt := options.FindOneAndUpdateOptions{ReturnDocument: &after}
-	filter := bson.M{"_id": order.ID}
-	update := bson.M{"$set": bson.M{
-		"user_id":    order.UserID,
-		"book_ids":   order.BookIDs,
-		"status":     order.Status,
-		"updated_at": now,
-	}}
+	update := bson.M{"$set": bson.M{"payment": order.Payment, "updated_at": now}}
 
 	var updated domain.Order
+	filter := paymentFilter(bson.M{"_id": order.ID}, from)
 	if err := r.collection.FindOneAndUpdate(ctx, filter, update, &opt).Decode(&updated); err != nil {
 		return nil, err
 	}
@@ -144,66 +142,85 @@ func (r *mongoOrderRepo) Update(ctx context.Context, order *domain.Order) (*doma
 	return &updated, nil
 }
 
-func (r *mongoOrderRepo) AddBook(ctx context.Context, orderID, bookID string) (*domain.Order, error) {
-	objID, err := primitive.ObjectIDFromHex(orderID)
-	if err != nil {
-		return nil, err
+// versionFilter matches the order at version; orders stored before versions
+// were counted have none and are at version 0.
+func versionFilter(filter bson.M, version int) bson.M {
+	if version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["version"] = version
 	}
-	bid, err := primitive.ObjectIDFromHex(bookID)
-	if err != nil {
-		return nil, err
+	return filter
+}
+
+func paymentFilter(filter bson.M, status string) bson.M {
+	if status == "" {
+		filter["payment"] = nil
+	} else {
+		filter["payment.status"] = status
 	}
+	return filter
+}
 
+// Update stores the order's items and totals. It only applies while the
+// order is pending and still at the version it was read at; otherwise it
+// fails with domain.ErrNotEditable. The status is never written here.
+func (r *mongoOrderRepo) Update(ctx context.Context, order *domain.Order) (*domain.Order, error) {
 	now := primitive.NewDateTimeFromTime(time.Now())
 	after := options.After
 	opt := options.FindOneAndUpdateOptions{ReturnDocument: &after}
-	filter := bson.M{"_id": objID}
+	filter := versionFilter(bson.M{"_id": order.ID, "status": domain.StatusPending}, order.Version)
 	update := bson.M{
-		"$push": bson.M{"book_ids": bid},
-		"$set":  bson.M{"updated_at": now},
+		"$set": bson.M{
+			"user_id":    order.UserID,
+			"items":      order.Items,
+			"subtotal":   order.Subtotal,
+			"tax":        order.Tax,
+			"total":      order.Total,
+			"updated_at": now,
+		},
+		"$inc": bson.M{"version": 1},
 	}
 
 	var updated domain.Order
-	if err := r.collection.FindOneAndUpdate(ctx, filter, update, &opt).Decode(&updated); err != nil {
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, &opt).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// в кэше могла остаться старая версия заказа
+		r.cache.Delete(ctx, order.ID.Hex())
+		if _, err := r.GetByID(ctx, order.ID.Hex()); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrNotEditable
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	r.cache.Delete(ctx, orderID)
-	r.cache.DeleteByUser(ctx, updated.UserID.Hex())
+	r.cache.Delete(ctx, order.ID.Hex())
+	r.cache.DeleteByUser(ctx, order.UserID.Hex())
 	return &updated, nil
 }
 
-func (r *mongoOrderRepo) RemoveBook(ctx context.Context, orderID, bookID string) (*domain.Order, error) {
-	objID, err := primitive.ObjectIDFromHex(orderID)
-	if err != nil {
-		return nil, err
-	}
-	bid, err := primitive.ObjectIDFromHex(bookID)
+func (r *mongoOrderRepo) ListAll(ctx context.Context) ([]*domain.Order, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	now := primitive.NewDateTimeFromTime(time.Now())
-	after := options.After
-	opt := options.FindOneAndUpdateOptions{ReturnDocument: &after}
-	filter := bson.M{"_id": objID}
-	update := bson.M{
-		"$pull": bson.M{"book_ids": bid},
-		"$set":  bson.M{"updated_at": now},
-	}
-
-	var updated domain.Order
-	if err := r.collection.FindOneAndUpdate(ctx, filter, update, &opt).Decode(&updated); err != nil {
-		return nil, err
+	var orders []*domain.Order
+	for cursor.Next(ctx) {
+		var o domain.Order
+		if err := cursor.Decode(&o); err != nil {
+			return nil, err
+		}
+		orders = append(orders, &o)
 	}
-
-	r.cache.Delete(ctx, orderID)
-	r.cache.DeleteByUser(ctx, updated.UserID.Hex())
-	return &updated, nil
+	return orders, nil
 }
 
-func (r *mongoOrderRepo) ListAll(ctx context.Context) ([]*domain.Order, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+func (r *mongoOrderRepo) ListByStatus(ctx context.Context, status string) ([]*domain.Order, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"status": status})
 	if err != nil {
 		return nil, err
 	}
@@ -220,8 +237,12 @@ func (r *mongoOrderRepo) ListAll(ctx context.Context) ([]*domain.Order, error) {
 	return orders, nil
 }
 
-func (r *mongoOrderRepo) ListByStatus(ctx context.Context, status string) ([]*domain.Order, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"status": status})
+func (r *mongoOrderRepo) ListByBook(ctx context.Context, bookID string) ([]*domain.Order, error) {
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{"items.book_id": bid})
 	if err != nil {
 		return nil, err
 	}
@@ -238,6 +259,25 @@ func (r *mongoOrderRepo) ListByStatus(ctx context.Context, status string) ([]*do
 	return orders, nil
 }
 
+// MarkOrphaned records why the order lost its user or one of its books.
+func (r *mongoOrderRepo) MarkOrphaned(ctx context.Context, order *domain.Order, reason string) (*domain.Order, error) {
+	after := options.After
+	opt := options.FindOneAndUpdateOptions{ReturnDocument: &after}
+	update := bson.M{"$set": bson.M{
+		"orphaned":   reason,
+		"updated_at": primitive.NewDateTimeFromTime(time.Now()),
+	}}
+
+	var updated domain.Order
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": order.ID}, update, &opt).Decode(&updated); err != nil {
+		return nil, err
+	}
+
+	r.cache.Delete(ctx, order.ID.Hex())
+	r.cache.DeleteByUser(ctx, order.UserID.Hex())
+	return &updated, nil
+}
+
 func (r *mongoOrderRepo) Delete(ctx context.Context, id string) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
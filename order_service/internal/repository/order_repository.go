@@ -10,12 +10,12 @@ type OrderRepository interface {
 	Create(ctx context.Context, order *domain.Order) (*domain.Order, error)
 	GetByID(ctx context.Context, id string) (*domain.Order, error)
 	ListByUser(ctx context.Context, userID string) ([]*domain.Order, error)
-	Cancel(ctx context.Context, id string) (*domain.Order, error)
-	Return(ctx context.Context, id string) (*domain.Order, error)
+	UpdateStatus(ctx context.Context, order *domain.Order, from, paymentFrom string) (*domain.Order, error)
+	SavePayment(ctx context.Context, order *domain.Order, from string) (*domain.Order, error)
 	Update(ctx context.Context, order *domain.Order) (*domain.Order, error)
-	AddBook(ctx context.Context, orderID, bookID string) (*domain.Order, error)
-	RemoveBook(ctx context.Context, orderID, bookID string) (*domain.Order, error)
 	ListAll(ctx context.Context) ([]*domain.Order, error)
 	ListByStatus(ctx context.Context, status string) ([]*domain.Order, error)
+	ListByBook(ctx context.Context, bookID string) ([]*domain.Order, error)
+	MarkOrphaned(ctx context.Context, order *domain.Order, reason string) (*domain.Order, error)
 	Delete(ctx context.Context, id string) error
 }
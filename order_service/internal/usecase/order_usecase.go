@@ -2,8 +2,17 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
 	"github.com/OshakbayAigerim/read_space/order_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/order_service/internal/payment"
 	"github.com/OshakbayAigerim/read_space/order_service/internal/repository"
 )
 
@@ -11,26 +20,72 @@ type OrderUseCase interface {
 	CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error)
 	GetOrderByID(ctx context.Context, id string) (*domain.Order, error)
 	ListOrdersByUser(ctx context.Context, userID string) ([]*domain.Order, error)
-	CancelOrder(ctx context.Context, id string) (*domain.Order, error)
-	ReturnBook(ctx context.Context, id string) (*domain.Order, error)
+	ListOrdersByBook(ctx context.Context, bookID string) ([]*domain.Order, error)
+	ChangeStatus(ctx context.Context, id, status string) (*Transition, error)
+	PayOrder(ctx context.Context, id, method string) (*domain.Order, error)
+	RefundOrder(ctx context.Context, id string) (*domain.Order, error)
 	DeleteOrder(ctx context.Context, id string) error
 	UpdateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error)
-	AddBook(ctx context.Context, orderID, bookID string) (*domain.Order, error)
-	RemoveBook(ctx context.Context, orderID, bookID string) (*domain.Order, error)
+	AddBook(ctx context.Context, orderID, bookID string, quantity int) (*domain.Order, error)
+	RemoveBook(ctx context.Context, orderID, bookID string, quantity int) (*domain.Order, error)
 	ListAll(ctx context.Context) ([]*domain.Order, error)
 	ListByStatus(ctx context.Context, status string) ([]*domain.Order, error)
+
+	OrphanUserOrders(ctx context.Context, userID string) ([]*Transition, error)
+	OrphanBookOrders(ctx context.Context, bookID string) ([]*domain.Order, error)
+}
+
+// UserChecker tells whether an order's buyer exists.
+type UserChecker interface {
+	UserExists(ctx context.Context, id string) error
+}
+
+// Transition is the outcome of a status change.
+type Transition struct {
+	Order *domain.Order
+	From  string
+	// Refunded is set when the change refunded the order's payment.
+	Refunded bool
 }
 
 type orderUseCase struct {
-	repo repository.OrderRepository
+	repo       repository.OrderRepository
+	bookClient bookpb.BookServiceClient
+	users      UserChecker
+	payments   payment.Provider
 }
 
-func NewOrderUseCase(r repository.OrderRepository) OrderUseCase {
-	return &orderUseCase{repo: r}
+func NewOrderUseCase(r repository.OrderRepository, bc bookpb.BookServiceClient, users UserChecker, p payment.Provider) OrderUseCase {
+	return &orderUseCase{repo: r, bookClient: bc, users: users, payments: p}
 }
 
+// CreateOrder prices the requested items from the catalog and reserves
+// them before the order is stored.
 func (u *orderUseCase) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
-	return u.repo.Create(ctx, order)
+	if err := u.users.UserExists(ctx, order.UserID.Hex()); err != nil {
+		return nil, err
+	}
+	order.Status = domain.StatusPending
+	order.History = []domain.StatusChange{{
+		Status:    domain.StatusPending,
+		ChangedAt: primitive.NewDateTimeFromTime(time.Now()),
+	}}
+	items, err := u.priceItems(ctx, order.Items, nil)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+	order.Recalculate()
+
+	if err := u.reserveItems(ctx, order.Items); err != nil {
+		return nil, err
+	}
+	created, err := u.repo.Create(ctx, order)
+	if err != nil {
+		u.releaseItems(ctx, order.Items)
+		return nil, err
+	}
+	return created, nil
 }
 
 func (u *orderUseCase) GetOrderByID(ctx context.Context, id string) (*domain.Order, error) {
@@ -41,28 +96,267 @@ func (u *orderUseCase) ListOrdersByUser(ctx context.Context, userID string) ([]*
 	return u.repo.ListByUser(ctx, userID)
 }
 
-func (u *orderUseCase) CancelOrder(ctx context.Context, id string) (*domain.Order, error) {
-	return u.repo.Cancel(ctx, id)
+func (u *orderUseCase) ListOrdersByBook(ctx context.Context, bookID string) ([]*domain.Order, error) {
+	return u.repo.ListByBook(ctx, bookID)
 }
 
-func (u *orderUseCase) ReturnBook(ctx context.Context, id string) (*domain.Order, error) {
-	return u.repo.Return(ctx, id)
+// ChangeStatus moves the order along its lifecycle. Copies go back to
+// stock and a captured payment is refunded when an order is cancelled or
+// returned; the payment is claimed together with the status change, so only
+// the caller that wins the transition refunds it. Orders become Paid only
+// through PayOrder.
+func (u *orderUseCase) ChangeStatus(ctx context.Context, id, status string) (*Transition, error) {
+	if status == domain.StatusPaid {
+		return nil, fmt.Errorf("%w: orders are paid through PayOrder", domain.ErrInvalidTransition)
+	}
+	order, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	from, paidFrom := order.Status, order.PaymentStatus()
+	if err := order.Transition(status, time.Now()); err != nil {
+		return nil, err
+	}
+	refund := !order.HoldsStock() && order.IsPaid()
+	if refund {
+		order.Payment.Status = domain.PaymentRefunding
+	}
+
+	updated, err := u.repo.UpdateStatus(ctx, order, from, paidFrom)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("%w: order was changed concurrently", domain.ErrInvalidTransition)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !updated.HoldsStock() {
+		u.releaseItems(ctx, updated.Items)
+	}
+
+	t := &Transition{Order: updated, From: from}
+	if refund {
+		// статус уже сменён: неудачный возврат не откатываем, его повторит RefundOrder
+		if refunded, err := u.refund(ctx, updated); err != nil {
+			log.Printf("⚠️ order %s is %s but its payment was not refunded: %v", id, status, err)
+		} else {
+			t.Order, t.Refunded = refunded, true
+		}
+	}
+	return t, nil
 }
 
+// PayOrder charges the order total and moves a pending order to Paid. A
+// declined charge is recorded on the order and the order stays pending.
+func (u *orderUseCase) PayOrder(ctx context.Context, id, method string) (*domain.Order, error) {
+	order, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	from, paidFrom := order.Status, order.PaymentStatus()
+	if !domain.CanTransition(from, domain.StatusPaid) {
+		return nil, fmt.Errorf("%w: cannot pay a %s order", domain.ErrInvalidTransition, from)
+	}
+
+	ref, err := u.payments.Charge(ctx, payment.ChargeRequest{
+		OrderID: id,
+		UserID:  order.UserID.Hex(),
+		Amount:  order.Total,
+		Method:  method,
+	})
+	if errors.Is(err, payment.ErrDeclined) {
+		order.Payment = &domain.Payment{
+			Amount:   order.Total,
+			Status:   domain.PaymentFailed,
+			Provider: u.payments.Name(),
+		}
+		if _, err := u.repo.SavePayment(ctx, order, paidFrom); err != nil {
+			log.Printf("⚠️ cannot record failed payment for order %s: %v", id, err)
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrPaymentDeclined, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order.Payment = &domain.Payment{
+		Amount:    order.Total,
+		Status:    domain.PaymentCaptured,
+		Provider:  u.payments.Name(),
+		Reference: ref,
+		PaidAt:    primitive.NewDateTimeFromTime(now),
+	}
+	if err := order.Transition(domain.StatusPaid, now); err != nil {
+		return nil, err
+	}
+	updated, err := u.repo.UpdateStatus(ctx, order, from, paidFrom)
+	if err != nil {
+		// заказ изменился во время оплаты — возвращаем деньги
+		if _, rerr := u.payments.Refund(ctx, ref, order.Total); rerr != nil {
+			log.Printf("⚠️ cannot refund charge %s of order %s: %v", ref, id, rerr)
+		}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: order was changed concurrently", domain.ErrInvalidTransition)
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// RefundOrder refunds the captured payment without changing the order
+// status, e.g. as a goodwill gesture, or after a refund on cancellation
+// failed.
+func (u *orderUseCase) RefundOrder(ctx context.Context, id string) (*domain.Order, error) {
+	order, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsPaid() {
+		return nil, domain.ErrNotPaid
+	}
+	order.Payment.Status = domain.PaymentRefunding
+	claimed, err := u.repo.SavePayment(ctx, order, domain.PaymentCaptured)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("%w: payment was changed concurrently", domain.ErrNotPaid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.refund(ctx, claimed)
+}
+
+// refund refunds a payment already claimed as Refunding with the provider
+// and stores the outcome. A failed refund puts the payment back to Captured
+// so that it can be retried.
+func (u *orderUseCase) refund(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	ref, err := u.payments.Refund(ctx, order.Payment.Reference, order.Payment.Amount)
+	if err != nil {
+		order.Payment.Status = domain.PaymentCaptured
+		if _, serr := u.repo.SavePayment(ctx, order, domain.PaymentRefunding); serr != nil {
+			log.Printf("⚠️ cannot release refund claim of order %s: %v", order.ID.Hex(), serr)
+		}
+		return nil, fmt.Errorf("cannot refund payment %s: %w", order.Payment.Reference, err)
+	}
+	order.Payment.Status = domain.PaymentRefunded
+	order.Payment.RefundReference = ref
+	order.Payment.RefundedAt = primitive.NewDateTimeFromTime(time.Now())
+	return u.repo.SavePayment(ctx, order, domain.PaymentRefunding)
+}
+
+// DeleteOrder removes pending orders and orders that are already closed;
+// paid or shipped orders have to be cancelled or returned first.
 func (u *orderUseCase) DeleteOrder(ctx context.Context, id string) error {
-	return u.repo.Delete(ctx, id)
+	existing, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.Status != domain.StatusPending && existing.HoldsStock() {
+		return fmt.Errorf("%w: cannot delete a %s order", domain.ErrInvalidTransition, existing.Status)
+	}
+	if err := u.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if existing.HoldsStock() {
+		u.releaseItems(ctx, existing.Items)
+	}
+	return nil
 }
 
+// UpdateOrder replaces the order's items. Lines for books already in the
+// order keep their price snapshot; new books are priced from the catalog.
+// If the order was paid or edited meanwhile, the new copies are released
+// and ErrNotEditable is returned.
 func (u *orderUseCase) UpdateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
-	return u.repo.Update(ctx, order)
+	existing, err := u.repo.GetByID(ctx, order.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "" && order.Status != existing.Status {
+		return nil, fmt.Errorf("%w: status is changed through the status RPCs", domain.ErrInvalidTransition)
+	}
+	if existing.Status != domain.StatusPending {
+		return nil, domain.ErrNotEditable
+	}
+	items, err := u.priceItems(ctx, order.Items, existing.Items)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+	order.Status, order.Version = existing.Status, existing.Version
+	order.Recalculate()
+
+	added, removed := diffItems(existing.Items, order.Items)
+	if err := u.reserveItems(ctx, added); err != nil {
+		return nil, err
+	}
+	updated, err := u.repo.Update(ctx, order)
+	if err != nil {
+		u.releaseItems(ctx, added)
+		return nil, err
+	}
+	u.releaseItems(ctx, removed)
+	return updated, nil
 }
 
-func (u *orderUseCase) AddBook(ctx context.Context, orderID, bookID string) (*domain.Order, error) {
-	return u.repo.AddBook(ctx, orderID, bookID)
+func (u *orderUseCase) AddBook(ctx context.Context, orderID, bookID string, quantity int) (*domain.Order, error) {
+	if quantity <= 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := u.repo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.StatusPending {
+		return nil, domain.ErrNotEditable
+	}
+	items, err := u.priceItems(ctx, []domain.LineItem{{BookID: bid, Quantity: quantity}}, order.Items)
+	if err != nil {
+		return nil, err
+	}
+	order.AddItem(bid, quantity, items[0].UnitPrice)
+
+	if err := u.reserveItems(ctx, items); err != nil {
+		return nil, err
+	}
+	updated, err := u.repo.Update(ctx, order)
+	if err != nil {
+		u.releaseItems(ctx, items)
+		return nil, err
+	}
+	return updated, nil
 }
 
-func (u *orderUseCase) RemoveBook(ctx context.Context, orderID, bookID string) (*domain.Order, error) {
-	return u.repo.RemoveBook(ctx, orderID, bookID)
+// RemoveBook takes quantity copies of the book out of the order; zero
+// removes the whole line.
+func (u *orderUseCase) RemoveBook(ctx context.Context, orderID, bookID string, quantity int) (*domain.Order, error) {
+	if quantity < 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	bid, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := u.repo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.StatusPending {
+		return nil, domain.ErrNotEditable
+	}
+	removed, err := order.RemoveItem(bid, quantity)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := u.repo.Update(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	u.releaseItems(ctx, []domain.LineItem{{BookID: bid, Quantity: removed}})
+	return updated, nil
 }
 
 func (u *orderUseCase) ListAll(ctx context.Context) ([]*domain.Order, error) {
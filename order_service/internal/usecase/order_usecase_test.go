@@ -0,0 +1,557 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	"github.com/OshakbayAigerim/read_space/order_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/order_service/internal/payment"
+	"github.com/OshakbayAigerim/read_space/order_service/internal/repository"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
+)
+
+type fakeRepo struct {
+	repository.OrderRepository
+	orders map[string]*domain.Order
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{orders: map[string]*domain.Order{}}
+}
+
+func (r *fakeRepo) Create(ctx context.Context, o *domain.Order) (*domain.Order, error) {
+	o.ID = primitive.NewObjectID()
+	r.orders[o.ID.Hex()] = o
+	return o, nil
+}
+func (r *fakeRepo) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	o, ok := r.orders[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	cp := *o
+	if o.Payment != nil {
+		pay := *o.Payment
+		cp.Payment = &pay
+	}
+	return &cp, nil
+}
+func (r *fakeRepo) Update(ctx context.Context, o *domain.Order) (*domain.Order, error) {
+	stored := r.orders[o.ID.Hex()]
+	if stored.Status != domain.StatusPending || stored.Version != o.Version {
+		return nil, domain.ErrNotEditable
+	}
+	stored.UserID = o.UserID
+	stored.Items = append([]domain.LineItem(nil), o.Items...)
+	stored.Subtotal, stored.Tax, stored.Total = o.Subtotal, o.Tax, o.Total
+	stored.Version++
+	return r.GetByID(ctx, o.ID.Hex())
+}
+func (r *fakeRepo) UpdateStatus(ctx context.Context, o *domain.Order, from, paymentFrom string) (*domain.Order, error) {
+	stored := r.orders[o.ID.Hex()]
+	if stored.Status != from || stored.PaymentStatus() != paymentFrom || stored.Version != o.Version {
+		return nil, mongo.ErrNoDocuments
+	}
+	stored.Version++
+	stored.Status = o.Status
+	stored.History = o.History
+	if o.Payment != nil {
+		pay := *o.Payment
+		stored.Payment = &pay
+	}
+	return r.GetByID(ctx, o.ID.Hex())
+}
+func (r *fakeRepo) SavePayment(ctx context.Context, o *domain.Order, from string) (*domain.Order, error) {
+	stored := r.orders[o.ID.Hex()]
+	if stored.PaymentStatus() != from {
+		return nil, mongo.ErrNoDocuments
+	}
+	cp := *o.Payment
+	stored.Payment = &cp
+	return r.GetByID(ctx, o.ID.Hex())
+}
+
+func (r *fakeRepo) ListByUser(ctx context.Context, userID string) ([]*domain.Order, error) {
+	var out []*domain.Order
+	for _, o := range r.orders {
+		if o.UserID.Hex() == userID {
+			cp := *o
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+func (r *fakeRepo) ListByBook(ctx context.Context, bookID string) ([]*domain.Order, error) {
+	var out []*domain.Order
+	for _, o := range r.orders {
+		for _, it := range o.Items {
+			if it.BookID.Hex() == bookID {
+				cp := *o
+				out = append(out, &cp)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+func (r *fakeRepo) MarkOrphaned(ctx context.Context, o *domain.Order, reason string) (*domain.Order, error) {
+	r.orders[o.ID.Hex()].Orphaned = reason
+	return r.GetByID(ctx, o.ID.Hex())
+}
+
+// fakeUsers knows every user except the listed ones.
+type fakeUsers map[string]bool
+
+func (f fakeUsers) UserExists(ctx context.Context, id string) error {
+	if f[id] {
+		return fmt.Errorf("%w: %s", refcheck.ErrUnknownUser, id)
+	}
+	return nil
+}
+
+// countingProvider wraps the fake provider and counts refunds.
+type countingProvider struct {
+	*payment.FakeProvider
+	refunds int
+}
+
+func (p *countingProvider) Refund(ctx context.Context, reference string, amount float64) (string, error) {
+	p.refunds++
+	return p.FakeProvider.Refund(ctx, reference, amount)
+}
+
+type fakeBookClient struct {
+	bookpb.BookServiceClient
+	stock  map[string]int32
+	prices map[string]float32
+}
+
+func (c *fakeBookClient) GetBook(ctx context.Context, in *bookpb.BookID, opts ...grpc.CallOption) (*bookpb.BookResponse, error) {
+	if _, ok := c.stock[in.Id]; !ok {
+		return nil, status.Error(codes.NotFound, "book not found")
+	}
+	return &bookpb.BookResponse{Book: &bookpb.Book{Id: in.Id, Price: c.prices[in.Id]}}, nil
+}
+
+func (c *fakeBookClient) ReserveStock(ctx context.Context, in *bookpb.StockRequest, opts ...grpc.CallOption) (*bookpb.BookResponse, error) {
+	left, ok := c.stock[in.BookId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "book not found")
+	}
+	if left < in.Quantity {
+		return nil, status.Error(codes.FailedPrecondition, "not enough copies in stock")
+	}
+	c.stock[in.BookId] = left - in.Quantity
+	return &bookpb.BookResponse{}, nil
+}
+func (c *fakeBookClient) ReleaseStock(ctx context.Context, in *bookpb.StockRequest, opts ...grpc.CallOption) (*bookpb.BookResponse, error) {
+	c.stock[in.BookId] += in.Quantity
+	return &bookpb.BookResponse{}, nil
+}
+
+func TestCreateOrder_ReservesStock(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	books := &fakeBookClient{stock: map[string]int32{a.Hex(): 2, b.Hex(): 1}}
+	uc := NewOrderUseCase(newFakeRepo(), books, fakeUsers{}, payment.NewFakeProvider())
+
+	_, err := uc.CreateOrder(context.Background(), &domain.Order{
+		Items: []domain.LineItem{{BookID: a, Quantity: 1}, {BookID: a, Quantity: 1}, {BookID: b, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if books.stock[a.Hex()] != 0 || books.stock[b.Hex()] != 0 {
+		t.Errorf("stock after order = %v, want all zero", books.stock)
+	}
+}
+
+func TestCreateOrder_OutOfStockRollsBack(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	books := &fakeBookClient{stock: map[string]int32{a.Hex(): 1, b.Hex(): 0}}
+	repo := newFakeRepo()
+	uc := NewOrderUseCase(repo, books, fakeUsers{}, payment.NewFakeProvider())
+
+	_, err := uc.CreateOrder(context.Background(), &domain.Order{
+		Items: []domain.LineItem{{BookID: a, Quantity: 1}, {BookID: b, Quantity: 1}},
+	})
+	if !errors.Is(err, domain.ErrOutOfStock) {
+		t.Fatalf("expected ErrOutOfStock, got %v", err)
+	}
+	if books.stock[a.Hex()] != 1 {
+		t.Errorf("reservation of %s not released, stock = %d", a.Hex(), books.stock[a.Hex()])
+	}
+	if len(repo.orders) != 0 {
+		t.Error("order stored despite failed reservation")
+	}
+}
+
+func TestCancelOrder_ReleasesStockOnce(t *testing.T) {
+	a := primitive.NewObjectID()
+	books := &fakeBookClient{stock: map[string]int32{a.Hex(): 1}}
+	uc := NewOrderUseCase(newFakeRepo(), books, fakeUsers{}, payment.NewFakeProvider())
+	ctx := context.Background()
+
+	o, err := uc.CreateOrder(ctx, &domain.Order{Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc.ChangeStatus(ctx, o.ID.Hex(), domain.StatusCancelled)
+	if _, err := uc.ChangeStatus(ctx, o.ID.Hex(), domain.StatusCancelled); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("second cancel: expected ErrInvalidTransition, got %v", err)
+	}
+	if books.stock[a.Hex()] != 1 {
+		t.Errorf("stock after cancel = %d, want 1", books.stock[a.Hex()])
+	}
+}
+
+func TestOrderTotals(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	books := &fakeBookClient{
+		stock:  map[string]int32{a.Hex(): 10, b.Hex(): 10},
+		prices: map[string]float32{a.Hex(): 10, b.Hex(): 2.5},
+	}
+	uc := NewOrderUseCase(newFakeRepo(), books, fakeUsers{}, payment.NewFakeProvider())
+	ctx := context.Background()
+
+	o, err := uc.CreateOrder(ctx, &domain.Order{
+		Items: []domain.LineItem{{BookID: a, Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Subtotal != 20 || o.Tax != 2.4 || o.Total != 22.4 {
+		t.Errorf("totals = %v/%v/%v, want 20/2.4/22.4", o.Subtotal, o.Tax, o.Total)
+	}
+
+	// цена в каталоге меняется, но уже добавленная позиция хранит старую
+	books.prices[a.Hex()] = 99
+	o, err = uc.AddBook(ctx, o.ID.Hex(), a.Hex(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err = uc.AddBook(ctx, o.ID.Hex(), b.Hex(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(o.Items) != 2 || o.Items[0].Quantity != 3 || o.Items[0].UnitPrice != 10 {
+		t.Fatalf("items after add = %+v", o.Items)
+	}
+	if o.Subtotal != 35 || o.Total != 39.2 {
+		t.Errorf("after add subtotal/total = %v/%v, want 35/39.2", o.Subtotal, o.Total)
+	}
+
+	o, err = uc.RemoveBook(ctx, o.ID.Hex(), a.Hex(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Subtotal != 15 {
+		t.Errorf("after remove subtotal = %v, want 15", o.Subtotal)
+	}
+	if books.stock[a.Hex()] != 9 || books.stock[b.Hex()] != 8 {
+		t.Errorf("stock = %v, want a=9 b=8", books.stock)
+	}
+	if _, err := uc.RemoveBook(ctx, o.ID.Hex(), primitive.NewObjectID().Hex(), 0); !errors.Is(err, domain.ErrBookNotInOrder) {
+		t.Errorf("expected ErrBookNotInOrder, got %v", err)
+	}
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	a := primitive.NewObjectID()
+	books := &fakeBookClient{
+		stock:  map[string]int32{a.Hex(): 1},
+		prices: map[string]float32{a.Hex(): 10},
+	}
+	payments := &countingProvider{FakeProvider: payment.NewFakeProvider()}
+	uc := NewOrderUseCase(newFakeRepo(), books, fakeUsers{}, payments)
+	ctx := context.Background()
+
+	o, err := uc.CreateOrder(ctx, &domain.Order{Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Status != domain.StatusPending {
+		t.Fatalf("new order status = %q, want Pending", o.Status)
+	}
+	id := o.ID.Hex()
+
+	if _, err := uc.ChangeStatus(ctx, id, domain.StatusReturned); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Pending → Returned: expected ErrInvalidTransition, got %v", err)
+	}
+	if _, err := uc.PayOrder(ctx, id, "card"); err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+	if _, err := uc.ChangeStatus(ctx, id, domain.StatusShipped); err != nil {
+		t.Fatalf("→ Shipped: %v", err)
+	}
+	if _, err := uc.ChangeStatus(ctx, id, domain.StatusCancelled); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Shipped → Cancelled: expected ErrInvalidTransition, got %v", err)
+	}
+	if _, err := uc.AddBook(ctx, id, a.Hex(), 1); !errors.Is(err, domain.ErrNotEditable) {
+		t.Errorf("editing shipped order: expected ErrNotEditable, got %v", err)
+	}
+	uc.ChangeStatus(ctx, id, domain.StatusDelivered)
+	tr, err := uc.ChangeStatus(ctx, id, domain.StatusReturned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.From != domain.StatusDelivered {
+		t.Errorf("from = %q, want Delivered", tr.From)
+	}
+	if len(tr.Order.History) != 5 {
+		t.Errorf("history has %d entries, want 5", len(tr.Order.History))
+	}
+	if !tr.Refunded || tr.Order.Payment.Status != domain.PaymentRefunded || payments.refunds != 1 {
+		t.Errorf("return of paid order: refunded=%v payment=%+v refunds=%d", tr.Refunded, tr.Order.Payment, payments.refunds)
+	}
+	if books.stock[a.Hex()] != 1 {
+		t.Errorf("stock after return = %d, want 1", books.stock[a.Hex()])
+	}
+}
+
+func TestPayOrder(t *testing.T) {
+	a := primitive.NewObjectID()
+	books := &fakeBookClient{
+		stock:  map[string]int32{a.Hex(): 2},
+		prices: map[string]float32{a.Hex(): 10},
+	}
+	payments := &countingProvider{FakeProvider: payment.NewFakeProvider()}
+	uc := NewOrderUseCase(newFakeRepo(), books, fakeUsers{}, payments)
+	ctx := context.Background()
+
+	o, err := uc.CreateOrder(ctx, &domain.Order{Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := o.ID.Hex()
+
+	if _, err := uc.ChangeStatus(ctx, id, domain.StatusPaid); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Paid via ChangeStatus: expected ErrInvalidTransition, got %v", err)
+	}
+	if _, err := uc.RefundOrder(ctx, id); !errors.Is(err, domain.ErrNotPaid) {
+		t.Errorf("refund of unpaid order: expected ErrNotPaid, got %v", err)
+	}
+
+	if _, err := uc.PayOrder(ctx, id, payment.DeclineMethod); !errors.Is(err, domain.ErrPaymentDeclined) {
+		t.Fatalf("expected ErrPaymentDeclined, got %v", err)
+	}
+	o, _ = uc.GetOrderByID(ctx, id)
+	if o.Status != domain.StatusPending || o.Payment == nil || o.Payment.Status != domain.PaymentFailed {
+		t.Errorf("after decline status=%q payment=%+v", o.Status, o.Payment)
+	}
+
+	o, err = uc.PayOrder(ctx, id, "card")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Status != domain.StatusPaid || o.Payment.Status != domain.PaymentCaptured || o.Payment.Amount != 11.2 || o.Payment.Reference == "" {
+		t.Errorf("after pay status=%q payment=%+v", o.Status, o.Payment)
+	}
+	if _, err := uc.PayOrder(ctx, id, "card"); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("paying twice: expected ErrInvalidTransition, got %v", err)
+	}
+
+	tr, err := uc.ChangeStatus(ctx, id, domain.StatusCancelled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tr.Refunded || tr.Order.Payment.RefundReference == "" || payments.refunds != 1 {
+		t.Errorf("cancel of paid order: refunded=%v payment=%+v", tr.Refunded, tr.Order.Payment)
+	}
+	if books.stock[a.Hex()] != 2 {
+		t.Errorf("stock after cancel = %d, want 2", books.stock[a.Hex()])
+	}
+	if _, err := uc.RefundOrder(ctx, id); !errors.Is(err, domain.ErrNotPaid) {
+		t.Errorf("second refund: expected ErrNotPaid, got %v", err)
+	}
+}
+
+// staleRepo serves reads from a snapshot, as a caller that read the order
+// just before a concurrent change would see it.
+type staleRepo struct {
+	*fakeRepo
+	snapshot *domain.Order
+}
+
+func (r *staleRepo) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	cp := *r.snapshot
+	cp.Items = append([]domain.LineItem(nil), r.snapshot.Items...)
+	if r.snapshot.Payment != nil {
+		pay := *r.snapshot.Payment
+		cp.Payment = &pay
+	}
+	return &cp, nil
+}
+
+func TestRefund_Concurrent(t *testing.T) {
+	a := primitive.NewObjectID()
+	books := &fakeBookClient{
+		stock:  map[string]int32{a.Hex(): 2},
+		prices: map[string]float32{a.Hex(): 10},
+	}
+	payments := &countingProvider{FakeProvider: payment.NewFakeProvider()}
+	repo := newFakeRepo()
+	uc := NewOrderUseCase(repo, books, fakeUsers{}, payments)
+	ctx := context.Background()
+
+	o, err := uc.CreateOrder(ctx, &domain.Order{Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paid, err := uc.PayOrder(ctx, o.ID.Hex(), "card")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.RefundOrder(ctx, paid.ID.Hex()); err != nil {
+		t.Fatal(err)
+	}
+
+	// оба вызова прочитали заказ до возврата и видят платёж Captured
+	stale := NewOrderUseCase(&staleRepo{fakeRepo: repo, snapshot: paid}, books, fakeUsers{}, payments)
+	if _, err := stale.RefundOrder(ctx, paid.ID.Hex()); !errors.Is(err, domain.ErrNotPaid) {
+		t.Errorf("stale refund: expected ErrNotPaid, got %v", err)
+	}
+	if _, err := stale.ChangeStatus(ctx, paid.ID.Hex(), domain.StatusCancelled); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("stale cancel: expected ErrInvalidTransition, got %v", err)
+	}
+	if payments.refunds != 1 {
+		t.Errorf("payment refunded %d times, want 1", payments.refunds)
+	}
+	if stored := repo.orders[paid.ID.Hex()]; stored.Status != domain.StatusPaid || stored.Payment.Status != domain.PaymentRefunded {
+		t.Errorf("stored order status=%q payment=%+v", stored.Status, stored.Payment)
+	}
+}
+
+func TestEditOrder_Concurrent(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	books := &fakeBookClient{
+		stock:  map[string]int32{a.Hex(): 5, b.Hex(): 5},
+		prices: map[string]float32{a.Hex(): 10, b.Hex(): 4},
+	}
+	repo := newFakeRepo()
+	uc := NewOrderUseCase(repo, books, fakeUsers{}, payment.NewFakeProvider())
+	ctx := context.Background()
+
+	o, err := uc.CreateOrder(ctx, &domain.Order{Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, _ := repo.GetByID(ctx, o.ID.Hex())
+	stale := NewOrderUseCase(&staleRepo{fakeRepo: repo, snapshot: snapshot}, books, fakeUsers{}, payment.NewFakeProvider())
+
+	// два добавления прочитали одну и ту же версию заказа
+	if _, err := uc.AddBook(ctx, o.ID.Hex(), b.Hex(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stale.AddBook(ctx, o.ID.Hex(), b.Hex(), 2); !errors.Is(err, domain.ErrNotEditable) {
+		t.Errorf("stale add: expected ErrNotEditable, got %v", err)
+	}
+	if books.stock[b.Hex()] != 4 {
+		t.Errorf("stock of the lost add not released: %d, want 4", books.stock[b.Hex()])
+	}
+
+	paid, err := uc.PayOrder(ctx, o.ID.Hex(), "card")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stale.RemoveBook(ctx, o.ID.Hex(), a.Hex(), 0); !errors.Is(err, domain.ErrNotEditable) {
+		t.Errorf("edit of a paid order: expected ErrNotEditable, got %v", err)
+	}
+	stored := repo.orders[o.ID.Hex()]
+	if stored.Status != domain.StatusPaid || stored.Total != paid.Total || len(stored.Items) != 2 {
+		t.Errorf("paid order changed by a stale edit: %+v", stored)
+	}
+	if books.stock[a.Hex()] != 4 {
+		t.Errorf("copy of a paid order released: stock %d, want 4", books.stock[a.Hex()])
+	}
+}
+
+func TestCreateOrder_UnknownUser(t *testing.T) {
+	a := primitive.NewObjectID()
+	ghost := primitive.NewObjectID()
+	books := &fakeBookClient{stock: map[string]int32{a.Hex(): 1}}
+	repo := newFakeRepo()
+	uc := NewOrderUseCase(repo, books, fakeUsers{ghost.Hex(): true}, payment.NewFakeProvider())
+
+	_, err := uc.CreateOrder(context.Background(), &domain.Order{UserID: ghost, Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	if !errors.Is(err, refcheck.ErrUnknownUser) {
+		t.Fatalf("expected ErrUnknownUser, got %v", err)
+	}
+	if books.stock[a.Hex()] != 1 || len(repo.orders) != 0 {
+		t.Error("order for a missing user reserved stock or was stored")
+	}
+}
+
+func TestOrphanUserOrders(t *testing.T) {
+	ctx := context.Background()
+	a := primitive.NewObjectID()
+	user := primitive.NewObjectID()
+	books := &fakeBookClient{stock: map[string]int32{a.Hex(): 3}, prices: map[string]float32{a.Hex(): 5}}
+	repo := newFakeRepo()
+	uc := NewOrderUseCase(repo, books, fakeUsers{}, payment.NewFakeProvider())
+
+	open, _ := uc.CreateOrder(ctx, &domain.Order{UserID: user, Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	shipped, _ := uc.CreateOrder(ctx, &domain.Order{UserID: user, Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	if _, err := uc.PayOrder(ctx, shipped.ID.Hex(), "card"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.ChangeStatus(ctx, shipped.ID.Hex(), domain.StatusShipped); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled, err := uc.OrphanUserOrders(ctx, user.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cancelled) != 1 || cancelled[0].Order.ID != open.ID {
+		t.Fatalf("expected only the pending order cancelled, got %v", cancelled)
+	}
+	if books.stock[a.Hex()] != 2 {
+		t.Errorf("stock of the cancelled order not released: %d", books.stock[a.Hex()])
+	}
+	for _, o := range repo.orders {
+		if o.Orphaned != domain.OrphanUserDeleted {
+			t.Errorf("order %s in status %s not flagged", o.ID.Hex(), o.Status)
+		}
+	}
+	if repo.orders[shipped.ID.Hex()].Status != domain.StatusShipped {
+		t.Error("shipped order must not be cancelled")
+	}
+}
+
+func TestOrphanBookOrders(t *testing.T) {
+	ctx := context.Background()
+	a := primitive.NewObjectID()
+	books := &fakeBookClient{stock: map[string]int32{a.Hex(): 3}, prices: map[string]float32{a.Hex(): 5}}
+	repo := newFakeRepo()
+	uc := NewOrderUseCase(repo, books, fakeUsers{}, payment.NewFakeProvider())
+
+	paid, _ := uc.CreateOrder(ctx, &domain.Order{UserID: primitive.NewObjectID(), Items: []domain.LineItem{{BookID: a, Quantity: 1}}})
+	if _, err := uc.PayOrder(ctx, paid.ID.Hex(), "card"); err != nil {
+		t.Fatal(err)
+	}
+
+	open, err := uc.OrphanBookOrders(ctx, a.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].ID != paid.ID {
+		t.Fatalf("expected the paid order reported, got %v", open)
+	}
+	got := repo.orders[paid.ID.Hex()]
+	if got.Orphaned != domain.OrphanBookDeleted {
+		t.Error("order with a deleted book not flagged")
+	}
+	// удаление книги не должно отменять заказ и возвращать деньги
+	if got.Status != domain.StatusPaid || got.Payment.Status != domain.PaymentCaptured || books.stock[a.Hex()] != 2 {
+		t.Errorf("order changed by a book deletion: %s, payment %s, stock %d", got.Status, got.Payment.Status, books.stock[a.Hex()])
+	}
+}
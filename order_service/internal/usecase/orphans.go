@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/OshakbayAigerim/read_space/order_service/internal/domain"
+)
+
+// OrphanUserOrders handles a deleted user: orders that can still be
+// cancelled are cancelled (releasing stock and refunding payments) and
+// every order of the user is flagged as orphaned.
+func (u *orderUseCase) OrphanUserOrders(ctx context.Context, userID string) ([]*Transition, error) {
+	orders, err := u.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.orphan(ctx, orders, domain.OrphanUserDeleted), nil
+}
+
+// OrphanBookOrders flags the orders containing a deleted book. Unlike a
+// deleted user, a deleted book does not cancel or refund anything: open
+// orders are left for manual handling and returned so they can be
+// reported.
+func (u *orderUseCase) OrphanBookOrders(ctx context.Context, bookID string) ([]*domain.Order, error) {
+	orders, err := u.repo.ListByBook(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	var open []*domain.Order
+	for _, o := range orders {
+		flagged, err := u.repo.MarkOrphaned(ctx, o, domain.OrphanBookDeleted)
+		if err != nil {
+			log.Printf("⚠️ cannot flag orphaned order %s: %v", o.ID.Hex(), err)
+			continue
+		}
+		if domain.CanTransition(flagged.Status, domain.StatusCancelled) {
+			open = append(open, flagged)
+		}
+	}
+	return open, nil
+}
+
+func (u *orderUseCase) orphan(ctx context.Context, orders []*domain.Order, reason string) []*Transition {
+	var cancelled []*Transition
+	for _, o := range orders {
+		if domain.CanTransition(o.Status, domain.StatusCancelled) {
+			t, err := u.ChangeStatus(ctx, o.ID.Hex(), domain.StatusCancelled)
+			if err != nil {
+				log.Printf("⚠️ cannot cancel orphaned order %s: %v", o.ID.Hex(), err)
+			} else {
+				cancelled = append(cancelled, t)
+			}
+		}
+		if _, err := u.repo.MarkOrphaned(ctx, o, reason); err != nil {
+			log.Printf("⚠️ cannot flag orphaned order %s: %v", o.ID.Hex(), err)
+		}
+	}
+	return cancelled
+}
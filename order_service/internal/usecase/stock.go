@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	"github.com/OshakbayAigerim/read_space/order_service/internal/domain"
+)
+
+// priceItems merges repeated books and snapshots the current catalog price
+// of every line that does not have one in known.
+func (u *orderUseCase) priceItems(ctx context.Context, items []domain.LineItem, known []domain.LineItem) ([]domain.LineItem, error) {
+	prices := make(map[primitive.ObjectID]float64, len(known))
+	for _, it := range known {
+		prices[it.BookID] = it.UnitPrice
+	}
+	var order domain.Order
+	for _, it := range items {
+		if it.Quantity <= 0 {
+			return nil, domain.ErrInvalidQuantity
+		}
+		price, ok := prices[it.BookID]
+		if !ok {
+			var err error
+			if price, err = u.bookPrice(ctx, it.BookID); err != nil {
+				return nil, err
+			}
+			prices[it.BookID] = price
+		}
+		order.AddItem(it.BookID, it.Quantity, price)
+	}
+	return order.Items, nil
+}
+
+func (u *orderUseCase) bookPrice(ctx context.Context, bookID primitive.ObjectID) (float64, error) {
+	resp, err := u.bookClient.GetBook(ctx, &bookpb.BookID{Id: bookID.Hex()})
+	if err != nil {
+		return 0, stockError(bookID, err)
+	}
+	return domain.RoundPrice(resp.Book.GetPrice()), nil
+}
+
+// reserveItems takes the ordered copies out of stock. If any reservation
+// fails, the copies reserved so far are released again.
+func (u *orderUseCase) reserveItems(ctx context.Context, items []domain.LineItem) error {
+	var reserved []domain.LineItem
+	for _, it := range items {
+		_, err := u.bookClient.ReserveStock(ctx, &bookpb.StockRequest{
+			BookId:   it.BookID.Hex(),
+			Quantity: int32(it.Quantity),
+		})
+		if err != nil {
+			u.releaseItems(ctx, reserved)
+			return stockError(it.BookID, err)
+		}
+		reserved = append(reserved, it)
+	}
+	return nil
+}
+
+// releaseItems returns copies to stock. Failures are only logged: the order
+// change has already happened and stock can be corrected by a librarian.
+func (u *orderUseCase) releaseItems(ctx context.Context, items []domain.LineItem) {
+	for _, it := range items {
+		_, err := u.bookClient.ReleaseStock(ctx, &bookpb.StockRequest{
+			BookId:   it.BookID.Hex(),
+			Quantity: int32(it.Quantity),
+		})
+		if err != nil {
+			log.Printf("⚠️ cannot release %d copies of book %s: %v", it.Quantity, it.BookID.Hex(), err)
+		}
+	}
+}
+
+// diffItems returns the copies present in after but not in before and vice
+// versa.
+func diffItems(before, after []domain.LineItem) (added, removed []domain.LineItem) {
+	qty := make(map[primitive.ObjectID]int)
+	for _, it := range before {
+		qty[it.BookID] -= it.Quantity
+	}
+	for _, it := range after {
+		qty[it.BookID] += it.Quantity
+	}
+	for _, it := range after {
+		if d := qty[it.BookID]; d > 0 {
+			added = append(added, domain.LineItem{BookID: it.BookID, Quantity: d})
+			qty[it.BookID] = 0
+		}
+	}
+	for _, it := range before {
+		if d := qty[it.BookID]; d < 0 {
+			removed = append(removed, domain.LineItem{BookID: it.BookID, Quantity: -d})
+			qty[it.BookID] = 0
+		}
+	}
+	return added, removed
+}
+
+func stockError(bookID primitive.ObjectID, err error) error {
+	switch status.Code(err) {
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: %s", domain.ErrOutOfStock, bookID.Hex())
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", domain.ErrBookNotFound, bookID.Hex())
+	default:
+		return err
+	}
+}
@@ -21,6 +21,218 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type LineItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice     float64                `protobuf:"fixed64,3,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	LineTotal     float64                `protobuf:"fixed64,4,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineItem) Reset() {
+	*x = LineItem{}
+	mi := &file_order_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineItem) ProtoMessage() {}
+
+func (x *LineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineItem.ProtoReflect.Descriptor instead.
+func (*LineItem) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LineItem) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *LineItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *LineItem) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *LineItem) GetLineTotal() float64 {
+	if x != nil {
+		return x.LineTotal
+	}
+	return 0
+}
+
+type StatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	ChangedAt     string                 `protobuf:"bytes,2,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusChange) Reset() {
+	*x = StatusChange{}
+	mi := &file_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusChange) ProtoMessage() {}
+
+func (x *StatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusChange.ProtoReflect.Descriptor instead.
+func (*StatusChange) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StatusChange) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StatusChange) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+type Payment struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Amount          float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status          string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Provider        string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Reference       string                 `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	RefundReference string                 `protobuf:"bytes,5,opt,name=refund_reference,json=refundReference,proto3" json:"refund_reference,omitempty"`
+	PaidAt          string                 `protobuf:"bytes,6,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	RefundedAt      string                 `protobuf:"bytes,7,opt,name=refunded_at,json=refundedAt,proto3" json:"refunded_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Payment) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Payment) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Payment) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Payment) GetRefundReference() string {
+	if x != nil {
+		return x.RefundReference
+	}
+	return ""
+}
+
+func (x *Payment) GetPaidAt() string {
+	if x != nil {
+		return x.PaidAt
+	}
+	return ""
+}
+
+func (x *Payment) GetRefundedAt() string {
+	if x != nil {
+		return x.RefundedAt
+	}
+	return ""
+}
+
 type Order struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -29,13 +241,22 @@ type Order struct {
 	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
 	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Items         []*LineItem            `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
+	Subtotal      float64                `protobuf:"fixed64,8,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Tax           float64                `protobuf:"fixed64,9,opt,name=tax,proto3" json:"tax,omitempty"`
+	Total         float64                `protobuf:"fixed64,10,opt,name=total,proto3" json:"total,omitempty"`
+	StatusHistory []*StatusChange        `protobuf:"bytes,11,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"`
+	Payment       *Payment               `protobuf:"bytes,12,opt,name=payment,proto3" json:"payment,omitempty"`
+	// user_deleted or book_deleted when the order refers to a removed user
+	// or book.
+	Orphaned      string `protobuf:"bytes,13,opt,name=orphaned,proto3" json:"orphaned,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
 	*x = Order{}
-	mi := &file_order_proto_msgTypes[0]
+	mi := &file_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -47,7 +268,7 @@ func (x *Order) String() string {
 func (*Order) ProtoMessage() {}
 
 func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[0]
+	mi := &file_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -60,7 +281,7 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Order.ProtoReflect.Descriptor instead.
 func (*Order) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{0}
+	return file_order_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Order) GetId() string {
@@ -105,17 +326,69 @@ func (x *Order) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *Order) GetItems() []*LineItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetSubtotal() float64 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+func (x *Order) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *Order) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Order) GetStatusHistory() []*StatusChange {
+	if x != nil {
+		return x.StatusHistory
+	}
+	return nil
+}
+
+func (x *Order) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *Order) GetOrphaned() string {
+	if x != nil {
+		return x.Orphaned
+	}
+	return ""
+}
+
+// Books can be given either as book_ids (one copy each) or as items with
+// quantities; prices are always taken from the catalog.
 type CreateOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	BookIds       []string               `protobuf:"bytes,2,rep,name=book_ids,json=bookIds,proto3" json:"book_ids,omitempty"`
+	Items         []*LineItem            `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateOrderRequest) Reset() {
 	*x = CreateOrderRequest{}
-	mi := &file_order_proto_msgTypes[1]
+	mi := &file_order_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -127,7 +400,7 @@ func (x *CreateOrderRequest) String() string {
 func (*CreateOrderRequest) ProtoMessage() {}
 
 func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[1]
+	mi := &file_order_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -140,7 +413,7 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{1}
+	return file_order_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateOrderRequest) GetUserId() string {
@@ -157,6 +430,13 @@ func (x *CreateOrderRequest) GetBookIds() []string {
 	return nil
 }
 
+func (x *CreateOrderRequest) GetItems() []*LineItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
 type UpdateOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
@@ -166,7 +446,7 @@ type UpdateOrderRequest struct {
 
 func (x *UpdateOrderRequest) Reset() {
 	*x = UpdateOrderRequest{}
-	mi := &file_order_proto_msgTypes[2]
+	mi := &file_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -178,7 +458,7 @@ func (x *UpdateOrderRequest) String() string {
 func (*UpdateOrderRequest) ProtoMessage() {}
 
 func (x *UpdateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[2]
+	mi := &file_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,7 +471,7 @@ func (x *UpdateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOrderRequest.ProtoReflect.Descriptor instead.
 func (*UpdateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{2}
+	return file_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UpdateOrderRequest) GetOrder() *Order {
@@ -205,13 +485,14 @@ type BookOperationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
 	BookId        string                 `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BookOperationRequest) Reset() {
 	*x = BookOperationRequest{}
-	mi := &file_order_proto_msgTypes[3]
+	mi := &file_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -223,7 +504,7 @@ func (x *BookOperationRequest) String() string {
 func (*BookOperationRequest) ProtoMessage() {}
 
 func (x *BookOperationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[3]
+	mi := &file_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -236,7 +517,7 @@ func (x *BookOperationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BookOperationRequest.ProtoReflect.Descriptor instead.
 func (*BookOperationRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{3}
+	return file_order_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BookOperationRequest) GetOrderId() string {
@@ -253,6 +534,13 @@ func (x *BookOperationRequest) GetBookId() string {
 	return ""
 }
 
+func (x *BookOperationRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
 type StatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -262,7 +550,7 @@ type StatusRequest struct {
 
 func (x *StatusRequest) Reset() {
 	*x = StatusRequest{}
-	mi := &file_order_proto_msgTypes[4]
+	mi := &file_order_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -274,7 +562,7 @@ func (x *StatusRequest) String() string {
 func (*StatusRequest) ProtoMessage() {}
 
 func (x *StatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[4]
+	mi := &file_order_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -287,7 +575,7 @@ func (x *StatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
 func (*StatusRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{4}
+	return file_order_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StatusRequest) GetStatus() string {
@@ -297,6 +585,110 @@ func (x *StatusRequest) GetStatus() string {
 	return ""
 }
 
+type OrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusRequest) Reset() {
+	*x = OrderStatusRequest{}
+	mi := &file_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusRequest) ProtoMessage() {}
+
+func (x *OrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*OrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OrderStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type PayOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,2,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayOrderRequest) Reset() {
+	*x = PayOrderRequest{}
+	mi := &file_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayOrderRequest) ProtoMessage() {}
+
+func (x *PayOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayOrderRequest.ProtoReflect.Descriptor instead.
+func (*PayOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PayOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PayOrderRequest) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
 type OrderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
@@ -306,7 +698,7 @@ type OrderResponse struct {
 
 func (x *OrderResponse) Reset() {
 	*x = OrderResponse{}
-	mi := &file_order_proto_msgTypes[5]
+	mi := &file_order_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -318,7 +710,7 @@ func (x *OrderResponse) String() string {
 func (*OrderResponse) ProtoMessage() {}
 
 func (x *OrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[5]
+	mi := &file_order_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -331,7 +723,7 @@ func (x *OrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
 func (*OrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{5}
+	return file_order_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *OrderResponse) GetOrder() *Order {
@@ -350,7 +742,7 @@ type OrderID struct {
 
 func (x *OrderID) Reset() {
 	*x = OrderID{}
-	mi := &file_order_proto_msgTypes[6]
+	mi := &file_order_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -362,7 +754,7 @@ func (x *OrderID) String() string {
 func (*OrderID) ProtoMessage() {}
 
 func (x *OrderID) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[6]
+	mi := &file_order_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -375,7 +767,7 @@ func (x *OrderID) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderID.ProtoReflect.Descriptor instead.
 func (*OrderID) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{6}
+	return file_order_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *OrderID) GetId() string {
@@ -394,7 +786,7 @@ type ListOrdersByUserRequest struct {
 
 func (x *ListOrdersByUserRequest) Reset() {
 	*x = ListOrdersByUserRequest{}
-	mi := &file_order_proto_msgTypes[7]
+	mi := &file_order_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -406,7 +798,7 @@ func (x *ListOrdersByUserRequest) String() string {
 func (*ListOrdersByUserRequest) ProtoMessage() {}
 
 func (x *ListOrdersByUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[7]
+	mi := &file_order_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -419,7 +811,7 @@ func (x *ListOrdersByUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersByUserRequest.ProtoReflect.Descriptor instead.
 func (*ListOrdersByUserRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{7}
+	return file_order_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ListOrdersByUserRequest) GetUserId() string {
@@ -429,6 +821,50 @@ func (x *ListOrdersByUserRequest) GetUserId() string {
 	return ""
 }
 
+type ListOrdersByBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersByBookRequest) Reset() {
+	*x = ListOrdersByBookRequest{}
+	mi := &file_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersByBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersByBookRequest) ProtoMessage() {}
+
+func (x *ListOrdersByBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersByBookRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersByBookRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListOrdersByBookRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
 type OrderList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
@@ -438,7 +874,7 @@ type OrderList struct {
 
 func (x *OrderList) Reset() {
 	*x = OrderList{}
-	mi := &file_order_proto_msgTypes[8]
+	mi := &file_order_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -450,7 +886,7 @@ func (x *OrderList) String() string {
 func (*OrderList) ProtoMessage() {}
 
 func (x *OrderList) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[8]
+	mi := &file_order_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -463,7 +899,7 @@ func (x *OrderList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderList.ProtoReflect.Descriptor instead.
 func (*OrderList) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{8}
+	return file_order_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *OrderList) GetOrders() []*Order {
@@ -481,7 +917,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_order_proto_msgTypes[9]
+	mi := &file_order_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -493,7 +929,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[9]
+	mi := &file_order_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -506,14 +942,34 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{9}
+	return file_order_proto_rawDescGZIP(), []int{15}
 }
 
 var File_order_proto protoreflect.FileDescriptor
 
 const file_order_proto_rawDesc = "" +
 	"\n" +
-	"\vorder.proto\x12\x05order\"\xa1\x01\n" +
+	"\vorder.proto\x12\x05order\"}\n" +
+	"\bLineItem\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x03 \x01(\x01R\tunitPrice\x12\x1d\n" +
+	"\n" +
+	"line_total\x18\x04 \x01(\x01R\tlineTotal\"E\n" +
+	"\fStatusChange\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x02 \x01(\tR\tchangedAt\"\xd8\x01\n" +
+	"\aPayment\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x1c\n" +
+	"\treference\x18\x04 \x01(\tR\treference\x12)\n" +
+	"\x10refund_reference\x18\x05 \x01(\tR\x0frefundReference\x12\x17\n" +
+	"\apaid_at\x18\x06 \x01(\tR\x06paidAt\x12\x1f\n" +
+	"\vrefunded_at\x18\a \x01(\tR\n" +
+	"refundedAt\"\x8e\x03\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
@@ -522,30 +978,49 @@ const file_order_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\tR\tupdatedAt\"H\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\x12%\n" +
+	"\x05items\x18\a \x03(\v2\x0f.order.LineItemR\x05items\x12\x1a\n" +
+	"\bsubtotal\x18\b \x01(\x01R\bsubtotal\x12\x10\n" +
+	"\x03tax\x18\t \x01(\x01R\x03tax\x12\x14\n" +
+	"\x05total\x18\n" +
+	" \x01(\x01R\x05total\x12:\n" +
+	"\x0estatus_history\x18\v \x03(\v2\x13.order.StatusChangeR\rstatusHistory\x12(\n" +
+	"\apayment\x18\f \x01(\v2\x0e.order.PaymentR\apayment\x12\x1a\n" +
+	"\borphaned\x18\r \x01(\tR\borphaned\"o\n" +
 	"\x12CreateOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
-	"\bbook_ids\x18\x02 \x03(\tR\abookIds\"8\n" +
+	"\bbook_ids\x18\x02 \x03(\tR\abookIds\x12%\n" +
+	"\x05items\x18\x03 \x03(\v2\x0f.order.LineItemR\x05items\"8\n" +
 	"\x12UpdateOrderRequest\x12\"\n" +
-	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"J\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"f\n" +
 	"\x14BookOperationRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
-	"\abook_id\x18\x02 \x01(\tR\x06bookId\"'\n" +
+	"\abook_id\x18\x02 \x01(\tR\x06bookId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"'\n" +
 	"\rStatusRequest\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"3\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"G\n" +
+	"\x12OrderStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"S\n" +
+	"\x0fPayOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12%\n" +
+	"\x0epayment_method\x18\x02 \x01(\tR\rpaymentMethod\"3\n" +
 	"\rOrderResponse\x12\"\n" +
 	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"\x19\n" +
 	"\aOrderID\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"2\n" +
 	"\x17ListOrdersByUserRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"1\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"2\n" +
+	"\x17ListOrdersByBookRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\"1\n" +
 	"\tOrderList\x12$\n" +
 	"\x06orders\x18\x01 \x03(\v2\f.order.OrderR\x06orders\"\a\n" +
-	"\x05Empty2\x9a\x05\n" +
+	"\x05Empty2\x95\a\n" +
 	"\fOrderService\x12>\n" +
 	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\x14.order.OrderResponse\x120\n" +
 	"\bGetOrder\x12\x0e.order.OrderID\x1a\x14.order.OrderResponse\x12D\n" +
-	"\x10ListOrdersByUser\x12\x1e.order.ListOrdersByUserRequest\x1a\x10.order.OrderList\x123\n" +
+	"\x10ListOrdersByUser\x12\x1e.order.ListOrdersByUserRequest\x1a\x10.order.OrderList\x12D\n" +
+	"\x10ListOrdersByBook\x12\x1e.order.ListOrdersByBookRequest\x1a\x10.order.OrderList\x123\n" +
 	"\vCancelOrder\x12\x0e.order.OrderID\x1a\x14.order.OrderResponse\x122\n" +
 	"\n" +
 	"ReturnBook\x12\x0e.order.OrderID\x1a\x14.order.OrderResponse\x12+\n" +
@@ -554,7 +1029,10 @@ const file_order_proto_rawDesc = "" +
 	"\x0eAddBookToOrder\x12\x1b.order.BookOperationRequest\x1a\x14.order.OrderResponse\x12H\n" +
 	"\x13RemoveBookFromOrder\x12\x1b.order.BookOperationRequest\x1a\x14.order.OrderResponse\x12/\n" +
 	"\rListAllOrders\x12\f.order.Empty\x1a\x10.order.OrderList\x12<\n" +
-	"\x12ListOrdersByStatus\x12\x14.order.StatusRequest\x1a\x10.order.OrderListBJZHgithub.com/OshakbayAigerim/readspace/order_service/proto/orderpb;orderpbb\x06proto3"
+	"\x12ListOrdersByStatus\x12\x14.order.StatusRequest\x1a\x10.order.OrderList\x12D\n" +
+	"\x11UpdateOrderStatus\x12\x19.order.OrderStatusRequest\x1a\x14.order.OrderResponse\x128\n" +
+	"\bPayOrder\x12\x16.order.PayOrderRequest\x1a\x14.order.OrderResponse\x123\n" +
+	"\vRefundOrder\x12\x0e.order.OrderID\x1a\x14.order.OrderResponseBJZHgithub.com/OshakbayAigerim/readspace/order_service/proto/orderpb;orderpbb\x06proto3"
 
 var (
 	file_order_proto_rawDescOnce sync.Once
@@ -568,50 +1046,68 @@ func file_order_proto_rawDescGZIP() []byte {
 	return file_order_proto_rawDescData
 }
 
-var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_order_proto_goTypes = []any{
-	(*Order)(nil),                   // 0: order.Order
-	(*CreateOrderRequest)(nil),      // 1: order.CreateOrderRequest
-	(*UpdateOrderRequest)(nil),      // 2: order.UpdateOrderRequest
-	(*BookOperationRequest)(nil),    // 3: order.BookOperationRequest
-	(*StatusRequest)(nil),           // 4: order.StatusRequest
-	(*OrderResponse)(nil),           // 5: order.OrderResponse
-	(*OrderID)(nil),                 // 6: order.OrderID
-	(*ListOrdersByUserRequest)(nil), // 7: order.ListOrdersByUserRequest
-	(*OrderList)(nil),               // 8: order.OrderList
-	(*Empty)(nil),                   // 9: order.Empty
+	(*LineItem)(nil),                // 0: order.LineItem
+	(*StatusChange)(nil),            // 1: order.StatusChange
+	(*Payment)(nil),                 // 2: order.Payment
+	(*Order)(nil),                   // 3: order.Order
+	(*CreateOrderRequest)(nil),      // 4: order.CreateOrderRequest
+	(*UpdateOrderRequest)(nil),      // 5: order.UpdateOrderRequest
+	(*BookOperationRequest)(nil),    // 6: order.BookOperationRequest
+	(*StatusRequest)(nil),           // 7: order.StatusRequest
+	(*OrderStatusRequest)(nil),      // 8: order.OrderStatusRequest
+	(*PayOrderRequest)(nil),         // 9: order.PayOrderRequest
+	(*OrderResponse)(nil),           // 10: order.OrderResponse
+	(*OrderID)(nil),                 // 11: order.OrderID
+	(*ListOrdersByUserRequest)(nil), // 12: order.ListOrdersByUserRequest
+	(*ListOrdersByBookRequest)(nil), // 13: order.ListOrdersByBookRequest
+	(*OrderList)(nil),               // 14: order.OrderList
+	(*Empty)(nil),                   // 15: order.Empty
 }
 var file_order_proto_depIdxs = []int32{
-	0,  // 0: order.UpdateOrderRequest.order:type_name -> order.Order
-	0,  // 1: order.OrderResponse.order:type_name -> order.Order
-	0,  // 2: order.OrderList.orders:type_name -> order.Order
-	1,  // 3: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
-	6,  // 4: order.OrderService.GetOrder:input_type -> order.OrderID
-	7,  // 5: order.OrderService.ListOrdersByUser:input_type -> order.ListOrdersByUserRequest
-	6,  // 6: order.OrderService.CancelOrder:input_type -> order.OrderID
-	6,  // 7: order.OrderService.ReturnBook:input_type -> order.OrderID
-	6,  // 8: order.OrderService.DeleteOrder:input_type -> order.OrderID
-	2,  // 9: order.OrderService.UpdateOrder:input_type -> order.UpdateOrderRequest
-	3,  // 10: order.OrderService.AddBookToOrder:input_type -> order.BookOperationRequest
-	3,  // 11: order.OrderService.RemoveBookFromOrder:input_type -> order.BookOperationRequest
-	9,  // 12: order.OrderService.ListAllOrders:input_type -> order.Empty
-	4,  // 13: order.OrderService.ListOrdersByStatus:input_type -> order.StatusRequest
-	5,  // 14: order.OrderService.CreateOrder:output_type -> order.OrderResponse
-	5,  // 15: order.OrderService.GetOrder:output_type -> order.OrderResponse
-	8,  // 16: order.OrderService.ListOrdersByUser:output_type -> order.OrderList
-	5,  // 17: order.OrderService.CancelOrder:output_type -> order.OrderResponse
-	5,  // 18: order.OrderService.ReturnBook:output_type -> order.OrderResponse
-	9,  // 19: order.OrderService.DeleteOrder:output_type -> order.Empty
-	5,  // 20: order.OrderService.UpdateOrder:output_type -> order.OrderResponse
-	5,  // 21: order.OrderService.AddBookToOrder:output_type -> order.OrderResponse
-	5,  // 22: order.OrderService.RemoveBookFromOrder:output_type -> order.OrderResponse
-	8,  // 23: order.OrderService.ListAllOrders:output_type -> order.OrderList
-	8,  // 24: order.OrderService.ListOrdersByStatus:output_type -> order.OrderList
-	14, // [14:25] is the sub-list for method output_type
-	3,  // [3:14] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	0,  // 0: order.Order.items:type_name -> order.LineItem
+	1,  // 1: order.Order.status_history:type_name -> order.StatusChange
+	2,  // 2: order.Order.payment:type_name -> order.Payment
+	0,  // 3: order.CreateOrderRequest.items:type_name -> order.LineItem
+	3,  // 4: order.UpdateOrderRequest.order:type_name -> order.Order
+	3,  // 5: order.OrderResponse.order:type_name -> order.Order
+	3,  // 6: order.OrderList.orders:type_name -> order.Order
+	4,  // 7: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
+	11, // 8: order.OrderService.GetOrder:input_type -> order.OrderID
+	12, // 9: order.OrderService.ListOrdersByUser:input_type -> order.ListOrdersByUserRequest
+	13, // 10: order.OrderService.ListOrdersByBook:input_type -> order.ListOrdersByBookRequest
+	11, // 11: order.OrderService.CancelOrder:input_type -> order.OrderID
+	11, // 12: order.OrderService.ReturnBook:input_type -> order.OrderID
+	11, // 13: order.OrderService.DeleteOrder:input_type -> order.OrderID
+	5,  // 14: order.OrderService.UpdateOrder:input_type -> order.UpdateOrderRequest
+	6,  // 15: order.OrderService.AddBookToOrder:input_type -> order.BookOperationRequest
+	6,  // 16: order.OrderService.RemoveBookFromOrder:input_type -> order.BookOperationRequest
+	15, // 17: order.OrderService.ListAllOrders:input_type -> order.Empty
+	7,  // 18: order.OrderService.ListOrdersByStatus:input_type -> order.StatusRequest
+	8,  // 19: order.OrderService.UpdateOrderStatus:input_type -> order.OrderStatusRequest
+	9,  // 20: order.OrderService.PayOrder:input_type -> order.PayOrderRequest
+	11, // 21: order.OrderService.RefundOrder:input_type -> order.OrderID
+	10, // 22: order.OrderService.CreateOrder:output_type -> order.OrderResponse
+	10, // 23: order.OrderService.GetOrder:output_type -> order.OrderResponse
+	14, // 24: order.OrderService.ListOrdersByUser:output_type -> order.OrderList
+	14, // 25: order.OrderService.ListOrdersByBook:output_type -> order.OrderList
+	10, // 26: order.OrderService.CancelOrder:output_type -> order.OrderResponse
+	10, // 27: order.OrderService.ReturnBook:output_type -> order.OrderResponse
+	15, // 28: order.OrderService.DeleteOrder:output_type -> order.Empty
+	10, // 29: order.OrderService.UpdateOrder:output_type -> order.OrderResponse
+	10, // 30: order.OrderService.AddBookToOrder:output_type -> order.OrderResponse
+	10, // 31: order.OrderService.RemoveBookFromOrder:output_type -> order.OrderResponse
+	14, // 32: order.OrderService.ListAllOrders:output_type -> order.OrderList
+	14, // 33: order.OrderService.ListOrdersByStatus:output_type -> order.OrderList
+	10, // 34: order.OrderService.UpdateOrderStatus:output_type -> order.OrderResponse
+	10, // 35: order.OrderService.PayOrder:output_type -> order.OrderResponse
+	10, // 36: order.OrderService.RefundOrder:output_type -> order.OrderResponse
+	22, // [22:37] is the sub-list for method output_type
+	7,  // [7:22] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_order_proto_init() }
@@ -625,7 +1121,7 @@ func file_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_proto_rawDesc), len(file_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,7 @@ const (
 	OrderService_CreateOrder_FullMethodName         = "/order.OrderService/CreateOrder"
 	OrderService_GetOrder_FullMethodName            = "/order.OrderService/GetOrder"
 	OrderService_ListOrdersByUser_FullMethodName    = "/order.OrderService/ListOrdersByUser"
+	OrderService_ListOrdersByBook_FullMethodName    = "/order.OrderService/ListOrdersByBook"
 	OrderService_CancelOrder_FullMethodName         = "/order.OrderService/CancelOrder"
 	OrderService_ReturnBook_FullMethodName          = "/order.OrderService/ReturnBook"
 	OrderService_DeleteOrder_FullMethodName         = "/order.OrderService/DeleteOrder"
@@ -30,6 +31,9 @@ const (
 	OrderService_RemoveBookFromOrder_FullMethodName = "/order.OrderService/RemoveBookFromOrder"
 	OrderService_ListAllOrders_FullMethodName       = "/order.OrderService/ListAllOrders"
 	OrderService_ListOrdersByStatus_FullMethodName  = "/order.OrderService/ListOrdersByStatus"
+	OrderService_UpdateOrderStatus_FullMethodName   = "/order.OrderService/UpdateOrderStatus"
+	OrderService_PayOrder_FullMethodName            = "/order.OrderService/PayOrder"
+	OrderService_RefundOrder_FullMethodName         = "/order.OrderService/RefundOrder"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -39,6 +43,7 @@ type OrderServiceClient interface {
 	CreateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	GetOrder(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*OrderResponse, error)
 	ListOrdersByUser(ctx context.Context, in *ListOrdersByUserRequest, opts ...grpc.CallOption) (*OrderList, error)
+	ListOrdersByBook(ctx context.Context, in *ListOrdersByBookRequest, opts ...grpc.CallOption) (*OrderList, error)
 	CancelOrder(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*OrderResponse, error)
 	ReturnBook(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*OrderResponse, error)
 	DeleteOrder(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*Empty, error)
@@ -47,6 +52,9 @@ type OrderServiceClient interface {
 	RemoveBookFromOrder(ctx context.Context, in *BookOperationRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	ListAllOrders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OrderList, error)
 	ListOrdersByStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*OrderList, error)
+	UpdateOrderStatus(ctx context.Context, in *OrderStatusRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	PayOrder(ctx context.Context, in *PayOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	RefundOrder(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*OrderResponse, error)
 }
 
 type orderServiceClient struct {
@@ -87,6 +95,16 @@ func (c *orderServiceClient) ListOrdersByUser(ctx context.Context, in *ListOrder
 	return out, nil
 }
 
+func (c *orderServiceClient) ListOrdersByBook(ctx context.Context, in *ListOrdersByBookRequest, opts ...grpc.CallOption) (*OrderList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderList)
+	err := c.cc.Invoke(ctx, OrderService_ListOrdersByBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) CancelOrder(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*OrderResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(OrderResponse)
@@ -167,6 +185,36 @@ func (c *orderServiceClient) ListOrdersByStatus(ctx context.Context, in *StatusR
 	return out, nil
 }
 
+func (c *orderServiceClient) UpdateOrderStatus(ctx context.Context, in *OrderStatusRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_UpdateOrderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) PayOrder(ctx context.Context, in *PayOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_PayOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RefundOrder(ctx context.Context, in *OrderID, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_RefundOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility.
@@ -174,6 +222,7 @@ type OrderServiceServer interface {
 	CreateOrder(context.Context, *CreateOrderRequest) (*OrderResponse, error)
 	GetOrder(context.Context, *OrderID) (*OrderResponse, error)
 	ListOrdersByUser(context.Context, *ListOrdersByUserRequest) (*OrderList, error)
+	ListOrdersByBook(context.Context, *ListOrdersByBookRequest) (*OrderList, error)
 	CancelOrder(context.Context, *OrderID) (*OrderResponse, error)
 	ReturnBook(context.Context, *OrderID) (*OrderResponse, error)
 	DeleteOrder(context.Context, *OrderID) (*Empty, error)
@@ -182,6 +231,9 @@ type OrderServiceServer interface {
 	RemoveBookFromOrder(context.Context, *BookOperationRequest) (*OrderResponse, error)
 	ListAllOrders(context.Context, *Empty) (*OrderList, error)
 	ListOrdersByStatus(context.Context, *StatusRequest) (*OrderList, error)
+	UpdateOrderStatus(context.Context, *OrderStatusRequest) (*OrderResponse, error)
+	PayOrder(context.Context, *PayOrderRequest) (*OrderResponse, error)
+	RefundOrder(context.Context, *OrderID) (*OrderResponse, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -201,6 +253,9 @@ func (UnimplementedOrderServiceServer) GetOrder(context.Context, *OrderID) (*Ord
 func (UnimplementedOrderServiceServer) ListOrdersByUser(context.Context, *ListOrdersByUserRequest) (*OrderList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListOrdersByUser not implemented")
 }
+func (UnimplementedOrderServiceServer) ListOrdersByBook(context.Context, *ListOrdersByBookRequest) (*OrderList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrdersByBook not implemented")
+}
 func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *OrderID) (*OrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
 }
@@ -225,6 +280,15 @@ func (UnimplementedOrderServiceServer) ListAllOrders(context.Context, *Empty) (*
 func (UnimplementedOrderServiceServer) ListOrdersByStatus(context.Context, *StatusRequest) (*OrderList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListOrdersByStatus not implemented")
 }
+func (UnimplementedOrderServiceServer) UpdateOrderStatus(context.Context, *OrderStatusRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrderStatus not implemented")
+}
+func (UnimplementedOrderServiceServer) PayOrder(context.Context, *PayOrderRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PayOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) RefundOrder(context.Context, *OrderID) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundOrder not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
 
@@ -300,6 +364,24 @@ func _OrderService_ListOrdersByUser_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_ListOrdersByBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersByBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListOrdersByBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListOrdersByBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListOrdersByBook(ctx, req.(*ListOrdersByBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(OrderID)
 	if err := dec(in); err != nil {
@@ -444,6 +526,60 @@ func _OrderService_ListOrdersByStatus_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).UpdateOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_UpdateOrderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).UpdateOrderStatus(ctx, req.(*OrderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_PayOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).PayOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_PayOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).PayOrder(ctx, req.(*PayOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_RefundOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).RefundOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_RefundOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).RefundOrder(ctx, req.(*OrderID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -463,6 +599,10 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListOrdersByUser",
 			Handler:    _OrderService_ListOrdersByUser_Handler,
 		},
+		{
+			MethodName: "ListOrdersByBook",
+			Handler:    _OrderService_ListOrdersByBook_Handler,
+		},
 		{
 			MethodName: "CancelOrder",
 			Handler:    _OrderService_CancelOrder_Handler,
@@ -495,6 +635,18 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListOrdersByStatus",
 			Handler:    _OrderService_ListOrdersByStatus_Handler,
 		},
+		{
+			MethodName: "UpdateOrderStatus",
+			Handler:    _OrderService_UpdateOrderStatus_Handler,
+		},
+		{
+			MethodName: "PayOrder",
+			Handler:    _OrderService_PayOrder_Handler,
+		},
+		{
+			MethodName: "RefundOrder",
+			Handler:    _OrderService_RefundOrder_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "order.proto",
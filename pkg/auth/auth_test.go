@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testSecret = []byte("test-secret")
+
+func TestIssueAndParseToken(t *testing.T) {
+	token, _, err := IssueToken(testSecret, "u1", []string{RoleReader}, time.Minute)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	id, err := ParseToken(testSecret, token)
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+	if id.UserID != "u1" || id.IsService() || !id.HasRole(RoleReader) {
+		t.Fatalf("unexpected identity %+v", id)
+	}
+}
+
+func TestParseTokenRejectsInvalid(t *testing.T) {
+	expired, _, _ := IssueToken(testSecret, "u1", nil, -time.Minute)
+	if _, err := ParseToken(testSecret, expired); err == nil {
+		t.Error("expected error for expired token")
+	}
+	token, _, _ := IssueToken(testSecret, "u1", []string{RoleReader}, time.Minute)
+	if _, err := ParseToken([]byte("other"), token); err == nil {
+		t.Error("expected error for wrong secret")
+	}
+}
+
+func TestAuthorizeUser(t *testing.T) {
+	if code := status.Code(AuthorizeUser(context.Background(), "u1")); code != codes.Unauthenticated {
+		t.Errorf("anonymous: got %v, want Unauthenticated", code)
+	}
+	ctx := WithIdentity(context.Background(), &Identity{UserID: "u1"})
+	if err := AuthorizeUser(ctx, "u1"); err != nil {
+		t.Errorf("owner: %v", err)
+	}
+	if code := status.Code(AuthorizeUser(ctx, "u2")); code != codes.PermissionDenied {
+		t.Errorf("other user: got %v, want PermissionDenied", code)
+	}
+	svc := WithIdentity(context.Background(), &Identity{Service: "exchange_service"})
+	if err := AuthorizeUser(svc, "u2"); err != nil {
+		t.Errorf("service: %v", err)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	reader := WithIdentity(context.Background(), &Identity{UserID: "u1", Roles: []string{RoleReader}})
+	if code := status.Code(RequireRole(reader, RoleLibrarian, RoleAdmin)); code != codes.PermissionDenied {
+		t.Errorf("reader: got %v, want PermissionDenied", code)
+	}
+	librarian := WithIdentity(context.Background(), &Identity{UserID: "u2", Roles: []string{RoleReader, RoleLibrarian}})
+	if err := RequireRole(librarian, RoleLibrarian, RoleAdmin); err != nil {
+		t.Errorf("librarian: %v", err)
+	}
+	admin := WithIdentity(context.Background(), &Identity{UserID: "u3", Roles: []string{RoleAdmin}})
+	if err := AuthorizeUser(admin, "u1"); err != nil {
+		t.Errorf("admin acting for another user: %v", err)
+	}
+}
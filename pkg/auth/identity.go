@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	RoleReader    = "reader"
+	RoleLibrarian = "librarian"
+	RoleAdmin     = "admin"
+)
+
+// Identity describes who is making a call: either an end user or one of
+// the internal services.
+type Identity struct {
+	UserID  string
+	Service string
+	Roles   []string
+}
+
+func (i *Identity) IsService() bool {
+	return i.Service != ""
+}
+
+func (i *Identity) HasRole(roles ...string) bool {
+	for _, have := range i.Roles {
+		for _, want := range roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok && id != nil
+}
+
+// RequireIdentity returns the caller or an Unauthenticated status error.
+func RequireIdentity(ctx context.Context) (*Identity, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	return id, nil
+}
+
+// RequireRole allows the call only if the caller holds one of the given
+// roles. Internal services are always allowed.
+func RequireRole(ctx context.Context, roles ...string) error {
+	id, err := RequireIdentity(ctx)
+	if err != nil {
+		return err
+	}
+	if id.IsService() || id.HasRole(roles...) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "requires role %s", strings.Join(roles, " or "))
+}
+
+// AuthorizeUser allows the call only if it is made by the user with the
+// given ID, by an admin or by an internal service.
+func AuthorizeUser(ctx context.Context, userID string) error {
+	id, err := RequireIdentity(ctx)
+	if err != nil {
+		return err
+	}
+	if id.IsService() || id.HasRole(RoleAdmin) || id.UserID == userID {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "not allowed to act on behalf of another user")
+}
+
+// AuthorizeAnyUser allows the call if it is made by one of the given users,
+// by an admin or by an internal service.
+func AuthorizeAnyUser(ctx context.Context, userIDs ...string) error {
+	id, err := RequireIdentity(ctx)
+	if err != nil {
+		return err
+	}
+	if id.IsService() || id.HasRole(RoleAdmin) {
+		return nil
+	}
+	for _, u := range userIDs {
+		if id.UserID == u {
+			return nil
+		}
+	}
+	return status.Error(codes.PermissionDenied, "not allowed to access this resource")
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	issuer          = "readspace"
+	devSecret       = "readspace-dev-secret"
+	AccessTokenTTL  = 24 * time.Hour
+	serviceTokenTTL = time.Hour
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims is the payload of tokens issued by user_service. Service is set
+// instead of the subject for tokens that internal services use when they
+// call each other. Roles are captured at login, so role changes take effect
+// with the next token.
+type Claims struct {
+	Service string   `json:"svc,omitempty"`
+	Roles   []string `json:"roles,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// SecretFromEnv returns the shared signing key from JWT_SECRET and exits
+// when it is not set, since a known key would let anyone mint tokens. For
+// local runs AUTH_DEV_MODE=1 falls back to a fixed development key.
+func SecretFromEnv() []byte {
+	if s := os.Getenv("JWT_SECRET"); s != "" {
+		return []byte(s)
+	}
+	if os.Getenv("AUTH_DEV_MODE") != "1" {
+		log.Fatal("JWT_SECRET is not set (AUTH_DEV_MODE=1 allows the development key)")
+	}
+	log.Println("⚠ JWT_SECRET is not set, using development secret")
+	return []byte(devSecret)
+}
+
+// IssueToken signs an access token for the given user.
+func IssueToken(secret []byte, userID string, roles []string, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	claims := Claims{
+		Roles: roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// IssueServiceToken signs a short-lived token identifying an internal service.
+func IssueServiceToken(secret []byte, service string) (string, error) {
+	claims := Claims{
+		Service: service,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(serviceTokenTTL)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+// ParseToken validates the signature and expiry of a token and returns the
+// identity it carries.
+func ParseToken(secret []byte, token string) (*Identity, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Subject == "" && claims.Service == "" {
+		return nil, ErrInvalidToken
+	}
+	return &Identity{UserID: claims.Subject, Service: claims.Service, Roles: claims.Roles}, nil
+}
@@ -0,0 +1,100 @@
+// Package refcheck verifies that users and books referenced by ID exist in
+// the services that own them. Positive answers are cached for a short
+// while so that a burst of requests for the same IDs does not turn into a
+// burst of gRPC calls; negative answers are never cached, so a freshly
+// created user or book can be referenced right away.
+package refcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	userpb "github.com/OshakbayAigerim/read_space/user_service/proto"
+)
+
+var (
+	ErrUnknownUser = errors.New("user does not exist")
+	ErrUnknownBook = errors.New("book does not exist")
+)
+
+// Checker looks IDs up through UserService.GetUser and BookService.GetBook.
+type Checker struct {
+	users userpb.UserServiceClient
+	books bookpb.BookServiceClient
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func New(users userpb.UserServiceClient, books bookpb.BookServiceClient, ttl time.Duration) *Checker {
+	return &Checker{
+		users: users,
+		books: books,
+		ttl:   ttl,
+		now:   time.Now,
+		seen:  make(map[string]time.Time),
+	}
+}
+
+// UserExists returns ErrUnknownUser if the user service does not know id.
+func (c *Checker) UserExists(ctx context.Context, id string) error {
+	return c.check(ctx, "user", id, ErrUnknownUser, func() error {
+		_, err := c.users.GetUser(ctx, &userpb.UserID{Id: id})
+		return err
+	})
+}
+
+// BookExists returns ErrUnknownBook if the catalog does not know id.
+func (c *Checker) BookExists(ctx context.Context, id string) error {
+	return c.check(ctx, "book", id, ErrUnknownBook, func() error {
+		_, err := c.books.GetBook(ctx, &bookpb.BookID{Id: id})
+		return err
+	})
+}
+
+// ForgetUser drops a cached answer, e.g. after a user.deleted event.
+func (c *Checker) ForgetUser(id string) { c.forget("user:" + id) }
+
+// ForgetBook drops a cached answer, e.g. after a book.deleted event.
+func (c *Checker) ForgetBook(id string) { c.forget("book:" + id) }
+
+func (c *Checker) check(ctx context.Context, kind, id string, notFound error, lookup func() error) error {
+	key := kind + ":" + id
+	c.mu.Lock()
+	until, ok := c.seen[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(until) {
+		return nil
+	}
+
+	err := lookup()
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.NotFound, codes.InvalidArgument:
+		// такого ID не может быть ни у кого
+		c.forget(key)
+		return fmt.Errorf("%w: %s", notFound, id)
+	default:
+		return fmt.Errorf("cannot look up %s %s: %w", kind, id, err)
+	}
+
+	c.mu.Lock()
+	c.seen[key] = c.now().Add(c.ttl)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Checker) forget(key string) {
+	c.mu.Lock()
+	delete(c.seen, key)
+	c.mu.Unlock()
+}
@@ -0,0 +1,75 @@
+package refcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	userpb "github.com/OshakbayAigerim/read_space/user_service/proto"
+)
+
+type fakeUsers struct {
+	userpb.UserServiceClient
+	known map[string]bool
+	calls int
+}
+
+func (f *fakeUsers) GetUser(ctx context.Context, in *userpb.UserID, _ ...grpc.CallOption) (*userpb.UserResponse, error) {
+	f.calls++
+	if in.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "user ID is required")
+	}
+	if !f.known[in.Id] {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &userpb.UserResponse{User: &userpb.User{Id: in.Id}}, nil
+}
+
+func TestUserExists(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeUsers{known: map[string]bool{"u1": true}}
+	c := New(users, nil, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if err := c.UserExists(ctx, "u1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if users.calls != 1 {
+		t.Errorf("expected one lookup while cached, got %d", users.calls)
+	}
+
+	if err := c.UserExists(ctx, "ghost"); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("expected ErrUnknownUser, got %v", err)
+	}
+	if err := c.UserExists(ctx, ""); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("malformed ID: expected ErrUnknownUser, got %v", err)
+	}
+	users.known["ghost"] = true
+	if err := c.UserExists(ctx, "ghost"); err != nil {
+		t.Errorf("missing users must not be cached: %v", err)
+	}
+
+	// удалённый пользователь перестаёт считаться существующим сразу
+	delete(users.known, "u1")
+	c.ForgetUser("u1")
+	if err := c.UserExists(ctx, "u1"); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("expected ErrUnknownUser after ForgetUser, got %v", err)
+	}
+
+	users.known["u1"] = true
+	c.UserExists(ctx, "u1")
+	calls := users.calls
+	now = now.Add(2 * time.Minute)
+	c.UserExists(ctx, "u1")
+	if users.calls != calls+1 {
+		t.Error("expired answer was not looked up again")
+	}
+}
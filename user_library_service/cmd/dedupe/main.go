@@ -0,0 +1,46 @@
+// Command dedupe removes duplicate user_books entries left from before
+// AssignBook became idempotent, so that the service can build its unique
+// (user_id, book_id) index. Run it once, ideally with -dry-run first:
+//
+//	go run ./user_library_service/cmd/dedupe -dry-run
+//	go run ./user_library_service/cmd/dedupe
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/config"
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/migration"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report duplicates without deleting them")
+	flag.Parse()
+
+	client := config.ConnectMongo()
+	defer client.Disconnect(context.Background())
+	db := client.Database("readspace")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	n, skipped, err := migrations.DedupeEntries(ctx, db, *dryRun)
+	if err != nil {
+		log.Fatalf("🔴 dedupe failed after %d duplicates: %v", n, err)
+	}
+	if *dryRun {
+		log.Printf("🟢 found %d duplicate entries, nothing deleted", n)
+	} else {
+		log.Printf("🟢 removed %d duplicate entries", n)
+	}
+	if skipped > 0 {
+		log.Printf("⚠️ %d books have several copies locked by pending offers and were left as they are", skipped)
+		return
+	}
+	if !*dryRun {
+		migrations.CreateEntryIndexes(db)
+	}
+}
@@ -11,12 +11,17 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"google.golang.org/grpc"
 
+	bookpb "github.com/OshakbayAigerim/read_space/book_service/proto"
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/cache"
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/config"
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/handler"
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/migration"
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/repository"
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/usecase"
-	userpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+	libpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
+	userpb "github.com/OshakbayAigerim/read_space/user_service/proto"
 )
 
 func main() {
@@ -32,6 +37,10 @@ func main() {
 	log.Printf("🟢 [DEBUG] user_books collection has %d documents", count)
 	// —————————————————————————————————————————————————————
 
+	migrations.MigrateLegacyEntries(db)
+	migrations.BackfillReadingStatus(db)
+	migrations.CreateEntryIndexes(db)
+
 	// ——— Подключаемся к Redis ———
 	rdb := redis.NewClient(&redis.Options{
 		Addr: "localhost:6379",
@@ -51,19 +60,43 @@ func main() {
 	defer nc.Close()
 	log.Println("🟢 Connected to NATS")
 
+	jwtSecret := auth.SecretFromEnv()
+
+	// ——— Клиенты UserService и BookService для проверки ссылок ———
+	userConn, err := grpc.Dial("localhost:50052",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "user_library_service")),
+	)
+	if err != nil {
+		log.Fatalf("🔴 cannot dial UserService: %v", err)
+	}
+	defer userConn.Close()
+	bookConn, err := grpc.Dial("localhost:50051",
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(auth.ServiceClientInterceptor(jwtSecret, "user_library_service")),
+	)
+	if err != nil {
+		log.Fatalf("🔴 cannot dial BookService: %v", err)
+	}
+	defer bookConn.Close()
+	refs := refcheck.New(userpb.NewUserServiceClient(userConn), bookpb.NewBookServiceClient(bookConn), config.RefCacheTTL())
+
 	// ——— Инициализируем слои ———
 	repo := repository.NewMongoUserBookRepo(db)
 	redisCache := cache.NewRedisUserLibraryCache(repo, rdb, 5*time.Minute)
-	uc := usecase.NewUserLibraryUseCase(repo, redisCache)
+	uc := usecase.NewUserLibraryUseCase(repo, redisCache, refs)
 	h := handler.NewUserLibraryHandler(uc, nc)
+	if err := handler.SubscribeDeletions(nc, uc, refs); err != nil {
+		log.Fatalf("🔴 NATS subscribe error: %v", err)
+	}
 
 	// ——— Запускаем gRPC-сервер ———
 	lis, err := net.Listen("tcp", ":50055")
 	if err != nil {
 		log.Fatalf("🔴 failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
-	userpb.RegisterUserLibraryServiceServer(grpcServer, h)
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(auth.UnaryServerInterceptor(jwtSecret)))
+	libpb.RegisterUserLibraryServiceServer(grpcServer, h)
 
 	log.Println("🟢 UserLibraryService listening on :50055")
 	if err := grpcServer.Serve(lis); err != nil {
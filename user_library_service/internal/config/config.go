@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -24,3 +25,22 @@ func ConnectMongo() *mongo.Client {
 	log.Println("Connected to MongoDB for UserLibraryService")
 	return client
 }
+
+// RefCacheTTL is how long the existence of a user or book is trusted
+// before it is checked again.
+func RefCacheTTL() time.Duration {
+	return durationEnv("REF_CACHE_TTL", 30*time.Second)
+}
+
+func durationEnv(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("invalid %s=%q, using %s", key, v, def)
+		return def
+	}
+	return d
+}
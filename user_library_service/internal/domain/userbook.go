@@ -1,11 +1,132 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"errors"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reading statuses of a library entry.
+const (
+	StatusWantToRead = "WANT_TO_READ"
+	StatusReading    = "READING"
+	StatusFinished   = "FINISHED"
+	StatusAbandoned  = "ABANDONED"
+)
+
+var (
+	ErrBookLocked      = errors.New("book is committed to a pending exchange")
+	ErrNotInLibrary    = errors.New("book is not in the user's library")
+	ErrNotLocked       = errors.New("no copy of the book is committed to this exchange")
+	ErrAlreadyAssigned = errors.New("book is already in the user's library")
+	ErrInvalidStatus   = errors.New("unknown reading status")
+	ErrInvalidProgress = errors.New("invalid reading progress")
+	ErrBookChanged     = errors.New("the book of a library entry cannot change")
+)
+
+// ValidStatus reports whether s is one of the reading statuses.
+func ValidStatus(s string) bool {
+	switch s {
+	case StatusWantToRead, StatusReading, StatusFinished, StatusAbandoned:
+		return true
+	}
+	return false
+}
+
+// UserBook is one copy of a book in a user's library. LockedBy holds the
+// pending exchange offer the copy is committed to, if any. The remaining
+// fields track how far the owner got with the book.
 type UserBook struct {
-	ID     primitive.ObjectID
-	UserID primitive.ObjectID
-	BookID primitive.ObjectID
+	ID          primitive.ObjectID `bson:"_id"`
+	UserID      primitive.ObjectID `bson:"user_id"`
+	BookID      primitive.ObjectID `bson:"book_id"`
+	LockedBy    primitive.ObjectID `bson:"locked_by,omitempty"`
+	Status      string             `bson:"status"`
+	CurrentPage int                `bson:"current_page"`
+	StartedAt   primitive.DateTime `bson:"started_at,omitempty"`
+	FinishedAt  primitive.DateTime `bson:"finished_at,omitempty"`
+	Note        string             `bson:"note,omitempty"`
+	UpdatedAt   primitive.DateTime `bson:"updated_at,omitempty"`
+}
+
+func (u *UserBook) Locked() bool {
+	return !u.LockedBy.IsZero()
+}
+
+// Progress is a change to the reading state of an entry. Nil fields are
+// left as they are.
+type Progress struct {
+	Status      string
+	CurrentPage *int
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	Note        *string
+}
+
+// ApplyProgress updates the entry and keeps its dates consistent with the
+// status: starting to read records the start date, finishing records the
+// finish date, and moving back to want-to-read clears both. Reporting a
+// page on an unread book starts it.
+func (u *UserBook) ApplyProgress(p Progress, now time.Time) error {
+	status := p.Status
+	if status == "" {
+		status = u.Status
+	}
+	if status == "" {
+		status = StatusWantToRead
+	}
+	if !ValidStatus(status) {
+		return ErrInvalidStatus
+	}
+	if p.CurrentPage != nil {
+		if *p.CurrentPage < 0 {
+			return ErrInvalidProgress
+		}
+		u.CurrentPage = *p.CurrentPage
+		if p.Status == "" && status == StatusWantToRead && u.CurrentPage > 0 {
+			status = StatusReading
+		}
+	}
+
+	switch status {
+	case StatusWantToRead:
+		u.CurrentPage, u.StartedAt, u.FinishedAt = 0, 0, 0
+	case StatusReading, StatusAbandoned:
+		if u.StartedAt == 0 {
+			u.StartedAt = primitive.NewDateTimeFromTime(now)
+		}
+		u.FinishedAt = 0
+	case StatusFinished:
+		if u.StartedAt == 0 {
+			u.StartedAt = primitive.NewDateTimeFromTime(now)
+		}
+		if u.FinishedAt == 0 {
+			u.FinishedAt = primitive.NewDateTimeFromTime(now)
+		}
+	}
+	u.Status = status
+
+	if p.StartedAt != nil {
+		if status == StatusWantToRead {
+			return ErrInvalidProgress
+		}
+		u.StartedAt = primitive.NewDateTimeFromTime(*p.StartedAt)
+	}
+	if p.FinishedAt != nil {
+		if status != StatusFinished {
+			return ErrInvalidProgress
+		}
+		u.FinishedAt = primitive.NewDateTimeFromTime(*p.FinishedAt)
+	}
+	if u.StartedAt != 0 && u.FinishedAt != 0 && u.FinishedAt < u.StartedAt {
+		return ErrInvalidProgress
+	}
+	if p.Note != nil {
+		u.Note = *p.Note
+	}
+	u.UpdatedAt = primitive.NewDateTimeFromTime(now)
+	return nil
 }
 
 type BookAssignedEvent struct {
@@ -28,3 +149,23 @@ type EntryUpdatedEvent struct {
 	UserID  string `json:"user_id"`
 	BookID  string `json:"book_id"`
 }
+
+type ProgressUpdatedEvent struct {
+	EntryID        string `json:"id"`
+	UserID         string `json:"user_id"`
+	BookID         string `json:"book_id"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
+	CurrentPage    int    `json:"current_page"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+// UserDeletedEvent and BookDeletedEvent come from the user and book
+// services; the library drops the entries they leave dangling.
+type UserDeletedEvent struct {
+	UserID string `json:"user_id"`
+}
+
+type BookDeletedEvent struct {
+	BookID string `json:"book_id"`
+}
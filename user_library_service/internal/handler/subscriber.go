@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/usecase"
+)
+
+// SubscribeDeletions drops the library entries left dangling when a user
+// or a book is deleted.
+func SubscribeDeletions(nc *nats.Conn, uc usecase.UserLibraryUseCase, refs *refcheck.Checker) error {
+	if _, err := nc.Subscribe("user.deleted", func(m *nats.Msg) {
+		var evt domain.UserDeletedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal user.deleted: %v", err)
+			return
+		}
+		refs.ForgetUser(evt.UserID)
+		n, err := uc.RemoveUser(context.Background(), evt.UserID)
+		if err != nil {
+			log.Printf("⚠ cannot remove library of user %s: %v", evt.UserID, err)
+			return
+		}
+		log.Printf("Removed %d library entries of deleted user %s", n, evt.UserID)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := nc.Subscribe("book.deleted", func(m *nats.Msg) {
+		var evt domain.BookDeletedEvent
+		if err := json.Unmarshal(m.Data, &evt); err != nil {
+			log.Printf("unmarshal book.deleted: %v", err)
+			return
+		}
+		refs.ForgetBook(evt.BookID)
+		n, err := uc.RemoveBook(context.Background(), evt.BookID)
+		if err != nil {
+			log.Printf("⚠ cannot remove deleted book %s from libraries: %v", evt.BookID, err)
+			return
+		}
+		log.Printf("Removed deleted book %s from %d libraries", evt.BookID, n)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
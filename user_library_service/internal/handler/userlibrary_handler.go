@@ -3,6 +3,8 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,6 +12,8 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/domain"
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/usecase"
 	userpb "github.com/OshakbayAigerim/read_space/user_library_service/proto"
@@ -31,10 +35,58 @@ func mustMarshal(v interface{}) []byte {
 }
 
 func toProto(u *domain.UserBook) *userpb.UserBook {
-	return &userpb.UserBook{
-		Id:     u.ID.Hex(),
-		UserId: u.UserID.Hex(),
-		BookId: u.BookID.Hex(),
+	pb := &userpb.UserBook{
+		Id:          u.ID.Hex(),
+		UserId:      u.UserID.Hex(),
+		BookId:      u.BookID.Hex(),
+		Status:      u.Status,
+		CurrentPage: int32(u.CurrentPage),
+		StartedAt:   formatDate(u.StartedAt),
+		FinishedAt:  formatDate(u.FinishedAt),
+		Note:        u.Note,
+		UpdatedAt:   formatDate(u.UpdatedAt),
+	}
+	if u.Locked() {
+		pb.LockedBy = u.LockedBy.Hex()
+	}
+	return pb
+}
+
+func formatDate(d primitive.DateTime) string {
+	if d == 0 {
+		return ""
+	}
+	return d.Time().UTC().Format(time.RFC3339)
+}
+
+// parseDate reads an optional RFC 3339 time.
+func parseDate(field, s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s must be an RFC 3339 time", field)
+	}
+	return &t, nil
+}
+
+// libraryError maps lock and ownership failures to their gRPC codes;
+// anything else is an internal error.
+func libraryError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrBookLocked), errors.Is(err, domain.ErrNotLocked):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrNotInLibrary),
+		errors.Is(err, refcheck.ErrUnknownUser), errors.Is(err, refcheck.ErrUnknownBook):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrAlreadyAssigned):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrInvalidStatus), errors.Is(err, domain.ErrInvalidProgress),
+		errors.Is(err, domain.ErrBookChanged):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
 	}
 }
 
@@ -50,21 +102,39 @@ func (h *UserLibraryHandler) AssignBook(ctx context.Context, req *userpb.AssignB
 	if req.UserId == "" || req.BookId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id and book_id are required")
 	}
-	entry, err := h.uc.AssignBook(ctx, req.UserId, req.BookId)
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	entry, created, err := h.uc.AssignBook(ctx, req.UserId, req.BookId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot assign book: %v", err)
+		return nil, libraryError("cannot assign book", err)
+	}
+	if !created {
+		if req.FailIfExists {
+			return nil, status.Error(codes.AlreadyExists, domain.ErrAlreadyAssigned.Error())
+		}
+		return &userpb.AssignBookResponse{Entry: toProto(entry)}, nil
 	}
 	evt := domain.BookAssignedEvent{UserID: req.UserId, BookID: req.BookId}
 	h.nc.Publish("userlibrary.book.assigned", mustMarshal(evt))
-	return &userpb.AssignBookResponse{Entry: toProto(entry)}, nil
+	return &userpb.AssignBookResponse{Entry: toProto(entry), Created: true}, nil
 }
 
 func (h *UserLibraryHandler) UnassignBook(ctx context.Context, req *userpb.UnassignBookRequest) (*userpb.UnassignBookResponse, error) {
 	if req.UserId == "" || req.BookId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id and book_id are required")
 	}
-	if err := h.uc.UnassignBook(ctx, req.UserId, req.BookId); err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot unassign book: %v", err)
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	// снимать заблокированные экземпляры может только сервис обмена
+	if req.OfferId != "" {
+		if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.uc.UnassignBook(ctx, req.UserId, req.BookId, req.OfferId); err != nil {
+		return nil, libraryError("cannot unassign book", err)
 	}
 	evt := domain.BookUnassignedEvent{UserID: req.UserId, BookID: req.BookId}
 	h.nc.Publish("userlibrary.book.unassigned", mustMarshal(evt))
@@ -82,6 +152,59 @@ func (h *UserLibraryHandler) ListUserBooks(ctx context.Context, req *userpb.List
 	return &userpb.ListUserBooksResponse{Entries: toProtoList(list)}, nil
 }
 
+func (h *UserLibraryHandler) ListUserBooksByStatus(ctx context.Context, req *userpb.ListByStatusRequest) (*userpb.ListUserBooksResponse, error) {
+	if req.UserId == "" || req.Status == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and status are required")
+	}
+	list, err := h.uc.ListUserBooksByStatus(ctx, req.UserId, req.Status)
+	if err != nil {
+		return nil, libraryError("cannot list user books", err)
+	}
+	return &userpb.ListUserBooksResponse{Entries: toProtoList(list)}, nil
+}
+
+// UpdateProgress records how far the owner got with a book.
+func (h *UserLibraryHandler) UpdateProgress(ctx context.Context, req *userpb.UpdateProgressRequest) (*userpb.AssignBookResponse, error) {
+	if req.EntryId == "" {
+		return nil, status.Error(codes.InvalidArgument, "entry_id is required")
+	}
+	progress := domain.Progress{Status: req.Status, Note: req.Note}
+	if req.CurrentPage != nil {
+		page := int(*req.CurrentPage)
+		progress.CurrentPage = &page
+	}
+	var err error
+	if progress.StartedAt, err = parseDate("started_at", req.StartedAt); err != nil {
+		return nil, err
+	}
+	if progress.FinishedAt, err = parseDate("finished_at", req.FinishedAt); err != nil {
+		return nil, err
+	}
+
+	e, err := h.uc.GetEntry(ctx, req.EntryId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "entry not found: %v", err)
+	}
+	if err := auth.AuthorizeUser(ctx, e.UserID.Hex()); err != nil {
+		return nil, err
+	}
+	updated, previous, err := h.uc.UpdateProgress(ctx, req.EntryId, progress)
+	if err != nil {
+		return nil, libraryError("cannot update progress", err)
+	}
+	evt := domain.ProgressUpdatedEvent{
+		EntryID:        updated.ID.Hex(),
+		UserID:         updated.UserID.Hex(),
+		BookID:         updated.BookID.Hex(),
+		Status:         updated.Status,
+		PreviousStatus: previous,
+		CurrentPage:    updated.CurrentPage,
+		UpdatedAt:      formatDate(updated.UpdatedAt),
+	}
+	h.nc.Publish("userlibrary.progress.updated", mustMarshal(evt))
+	return &userpb.AssignBookResponse{Entry: toProto(updated)}, nil
+}
+
 func (h *UserLibraryHandler) GetEntry(ctx context.Context, req *userpb.GetEntryRequest) (*userpb.AssignBookResponse, error) {
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
@@ -102,8 +225,11 @@ func (h *UserLibraryHandler) DeleteEntry(ctx context.Context, req *userpb.Delete
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "entry not found: %v", err)
 	}
+	if err := auth.AuthorizeUser(ctx, e.UserID.Hex()); err != nil {
+		return nil, err
+	}
 	if err := h.uc.DeleteEntry(ctx, req.Id); err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot delete entry: %v", err)
+		return nil, libraryError("cannot delete entry", err)
 	}
 	evt := domain.EntryDeletedEvent{EntryID: req.Id, UserID: e.UserID.Hex()}
 	h.nc.Publish("userlibrary.entry.deleted", mustMarshal(evt))
@@ -126,10 +252,20 @@ func (h *UserLibraryHandler) UpdateEntry(ctx context.Context, req *userpb.Update
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid book_id")
 	}
+	existing, err := h.uc.GetEntry(ctx, req.Entry.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "entry not found: %v", err)
+	}
+	if err := auth.AuthorizeUser(ctx, existing.UserID.Hex()); err != nil {
+		return nil, err
+	}
+	if err := auth.AuthorizeUser(ctx, req.Entry.UserId); err != nil {
+		return nil, err
+	}
 	dom := &domain.UserBook{ID: oid, UserID: uo, BookID: bo}
 	updated, err := h.uc.UpdateEntry(ctx, dom)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot update entry: %v", err)
+		return nil, libraryError("cannot update entry", err)
 	}
 	evt := domain.EntryUpdatedEvent{EntryID: req.Entry.Id, UserID: req.Entry.UserId, BookID: req.Entry.BookId}
 	h.nc.Publish("userlibrary.entry.updated", mustMarshal(evt))
@@ -137,6 +273,9 @@ func (h *UserLibraryHandler) UpdateEntry(ctx context.Context, req *userpb.Update
 }
 
 func (h *UserLibraryHandler) ListAllEntries(ctx context.Context, _ *emptypb.Empty) (*userpb.ListUserBooksResponse, error) {
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
 	all, err := h.uc.ListAllEntries(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot list all entries: %v", err)
@@ -154,3 +293,48 @@ func (h *UserLibraryHandler) ListByBook(ctx context.Context, req *userpb.ListByB
 	}
 	return &userpb.ListUserBooksResponse{Entries: toProtoList(list)}, nil
 }
+
+// LockBook commits a copy of the book to a pending exchange offer. Only
+// internal services and admins manage locks.
+func (h *UserLibraryHandler) LockBook(ctx context.Context, req *userpb.LockBookRequest) (*userpb.AssignBookResponse, error) {
+	if req.UserId == "" || req.BookId == "" || req.OfferId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id, book_id and offer_id are required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	entry, err := h.uc.LockBook(ctx, req.UserId, req.BookId, req.OfferId)
+	if err != nil {
+		return nil, libraryError("cannot lock book", err)
+	}
+	return &userpb.AssignBookResponse{Entry: toProto(entry)}, nil
+}
+
+func (h *UserLibraryHandler) UnlockBook(ctx context.Context, req *userpb.LockBookRequest) (*userpb.UnassignBookResponse, error) {
+	if req.UserId == "" || req.BookId == "" || req.OfferId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id, book_id and offer_id are required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := h.uc.UnlockBook(ctx, req.UserId, req.BookId, req.OfferId); err != nil {
+		return nil, libraryError("cannot unlock book", err)
+	}
+	return &userpb.UnassignBookResponse{Success: true}, nil
+}
+
+// ReleaseLocks frees every copy committed to an offer that is no longer
+// pending.
+func (h *UserLibraryHandler) ReleaseLocks(ctx context.Context, req *userpb.ReleaseLocksRequest) (*userpb.ReleaseLocksResponse, error) {
+	if req.OfferId == "" {
+		return nil, status.Error(codes.InvalidArgument, "offer_id is required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	n, err := h.uc.ReleaseLocks(ctx, req.OfferId)
+	if err != nil {
+		return nil, libraryError("cannot release locks", err)
+	}
+	return &userpb.ReleaseLocksResponse{Libraries: int32(n)}, nil
+}
@@ -0,0 +1,209 @@
+package migrations
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/domain"
+)
+
+// MigrateLegacyEntries rewrites library entries stored with the driver's
+// default field names ("id", "userid", "bookid"), which the user_id and
+// book_id queries never matched. Entries are re-inserted under the ID
+// clients saw.
+func MigrateLegacyEntries(db *mongo.Database) {
+	collection := db.Collection("user_books")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{"userid": bson.M{"$exists": true}})
+	if err != nil {
+		log.Fatalf("🔴 failed to find legacy entries: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			log.Fatalf("🔴 failed to decode entry: %v", err)
+		}
+		entry := domain.UserBook{
+			ID:     objectID(doc, "id", "_id"),
+			UserID: objectID(doc, "userid"),
+			BookID: objectID(doc, "bookid"),
+		}
+
+		if entry.ID == doc["_id"] {
+			_, err = collection.ReplaceOne(ctx, bson.M{"_id": entry.ID}, entry)
+		} else if _, err = collection.InsertOne(ctx, entry); err == nil {
+			_, err = collection.DeleteOne(ctx, bson.M{"_id": doc["_id"]})
+		}
+		if err != nil {
+			log.Fatalf("🔴 failed to migrate entry %s: %v", entry.ID.Hex(), err)
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("🟢 migrated %d legacy library entries", migrated)
+	}
+}
+
+// BackfillReadingStatus gives entries created before reading progress
+// existed the want-to-read status, so they show up in status listings.
+func BackfillReadingStatus(db *mongo.Database) {
+	collection := db.Collection("user_books")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	res, err := collection.UpdateMany(ctx,
+		bson.M{"status": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"$set": bson.M{"status": domain.StatusWantToRead, "current_page": 0}},
+	)
+	if err != nil {
+		log.Fatalf("🔴 failed to backfill reading status: %v", err)
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("🟢 set reading status on %d library entries", res.ModifiedCount)
+	}
+}
+
+// CreateEntryIndexes builds the user_books indexes. The unique
+// (user_id, book_id) index cannot be built while duplicates remain; the
+// service then keeps running without it and cmd/dedupe must be run once.
+func CreateEntryIndexes(db *mongo.Database) {
+	collection := db.Collection("user_books")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "updated_at", Value: -1}},
+	})
+	if err != nil {
+		log.Fatalf("🔴 failed to create indexes: %v", err)
+	}
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "book_id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName(uniqueEntryIndex),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		log.Printf("🔴 user_books has duplicate entries, unique index not created; run cmd/dedupe: %v", err)
+		return
+	}
+	if err != nil {
+		log.Fatalf("🔴 failed to create unique entry index: %v", err)
+	}
+}
+
+const uniqueEntryIndex = "user_id_book_id_unique"
+
+// DedupeEntries collapses entries that hold the same book for the same
+// user into one. The entry kept is the one locked by an exchange, then the
+// one furthest along in reading, then the oldest. Locked entries are never
+// deleted: a group with several of them backs several pending offers and is
+// skipped until those offers are settled. With dryRun nothing is deleted.
+// It returns the number of surplus entries found and of groups skipped.
+func DedupeEntries(ctx context.Context, db *mongo.Database, dryRun bool) (surplus, skipped int, err error) {
+	collection := db.Collection("user_books")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"user_id": "$user_id", "book_id": "$book_id"},
+			"entries": bson.M{"$push": "$$ROOT"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var group struct {
+			Entries []domain.UserBook `bson:"entries"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return surplus, skipped, err
+		}
+		keep := keeper(group.Entries)
+		kept := group.Entries[keep]
+		var drop, offers []primitive.ObjectID
+		for i, e := range group.Entries {
+			if e.Locked() {
+				offers = append(offers, e.LockedBy)
+			}
+			if i != keep {
+				drop = append(drop, e.ID)
+			}
+		}
+		if len(offers) > 1 {
+			log.Printf("⚠️ user %s, book %s: %d copies are locked by offers %v; settle the offers and run again", kept.UserID.Hex(), kept.BookID.Hex(), len(offers), offers)
+			skipped++
+			continue
+		}
+		surplus += len(drop)
+		log.Printf("user %s, book %s: keeping %s, dropping %d", kept.UserID.Hex(), kept.BookID.Hex(), kept.ID.Hex(), len(drop))
+		if dryRun {
+			continue
+		}
+		// копия могла заблокироваться после агрегации — такие не трогаем
+		filter := bson.M{"_id": bson.M{"$in": drop}, "locked_by": bson.M{"$exists": false}}
+		if _, err := collection.DeleteMany(ctx, filter); err != nil {
+			return surplus, skipped, err
+		}
+	}
+	return surplus, skipped, cursor.Err()
+}
+
+var statusRank = map[string]int{
+	domain.StatusFinished:  3,
+	domain.StatusReading:   2,
+	domain.StatusAbandoned: 1,
+}
+
+// keeper picks the entry to keep out of duplicates.
+func keeper(entries []domain.UserBook) int {
+	best := 0
+	for i := 1; i < len(entries); i++ {
+		a, b := entries[i], entries[best]
+		switch {
+		case a.Locked() != b.Locked():
+			if a.Locked() {
+				best = i
+			}
+		case statusRank[a.Status] != statusRank[b.Status]:
+			if statusRank[a.Status] > statusRank[b.Status] {
+				best = i
+			}
+		case a.CurrentPage != b.CurrentPage:
+			if a.CurrentPage > b.CurrentPage {
+				best = i
+			}
+		case a.ID.Timestamp().Before(b.ID.Timestamp()):
+			best = i
+		}
+	}
+	return best
+}
+
+func objectID(doc bson.M, keys ...string) primitive.ObjectID {
+	for _, k := range keys {
+		if id, ok := doc[k].(primitive.ObjectID); ok && !id.IsZero() {
+			return id
+		}
+	}
+	return primitive.NilObjectID
+}
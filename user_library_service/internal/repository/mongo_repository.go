@@ -18,28 +18,206 @@ func NewMongoUserBookRepo(db *mongo.Database) UserBookRepo {
 	return &mongoUserBookRepo{coll: db.Collection("user_books")}
 }
 
-func (r *mongoUserBookRepo) AssignBook(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, error) {
+// AssignBook adds the book to the user's library. A user holds a book at
+// most once: if it is already there, the existing entry is returned and
+// created is false.
+func (r *mongoUserBookRepo) AssignBook(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, bool, error) {
+	filter := bson.M{"user_id": entry.UserID, "book_id": entry.BookID}
+	var existing domain.UserBook
+	err := r.coll.FindOne(ctx, filter).Decode(&existing)
+	if err == nil {
+		return &existing, false, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, false, err
+	}
+
 	entry.ID = primitive.NewObjectID()
-	// insert into Mongo
+	if entry.Status == "" {
+		entry.Status = domain.StatusWantToRead
+	}
 	if _, err := r.coll.InsertOne(ctx, entry); err != nil {
+		// параллельный вызов успел вставить ту же книгу
+		if mongo.IsDuplicateKeyError(err) {
+			if err := r.coll.FindOne(ctx, filter).Decode(&existing); err != nil {
+				return nil, false, err
+			}
+			return &existing, false, nil
+		}
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+// UnassignBook removes one copy of the book from the library. With an
+// offerID only the copy locked by that offer is removed: if its lock was
+// released meanwhile, ErrNotLocked is returned rather than taking a copy the
+// offer never held. With an empty offerID only unlocked copies are
+// considered.
+func (r *mongoUserBookRepo) UnassignBook(ctx context.Context, userID, bookID, offerID string) error {
+	filter, err := entryFilter(userID, bookID)
+	if err != nil {
+		return err
+	}
+	if offerID != "" {
+		oo, err := primitive.ObjectIDFromHex(offerID)
+		if err != nil {
+			return err
+		}
+		res, err := r.coll.DeleteOne(ctx, bson.M{"user_id": filter["user_id"], "book_id": filter["book_id"], "locked_by": oo})
+		if err != nil {
+			return err
+		}
+		if res.DeletedCount == 0 {
+			return domain.ErrNotLocked
+		}
+		return nil
+	}
+
+	res, err := r.coll.DeleteOne(ctx, withUnlocked(filter))
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return r.missingCopyError(ctx, filter)
+	}
+	return nil
+}
+
+// LockBook commits one unlocked copy of the book to the offer.
+func (r *mongoUserBookRepo) LockBook(ctx context.Context, userID, bookID, offerID string) (*domain.UserBook, error) {
+	filter, err := entryFilter(userID, bookID)
+	if err != nil {
+		return nil, err
+	}
+	oo, err := primitive.ObjectIDFromHex(offerID)
+	if err != nil {
 		return nil, err
 	}
-	return entry, nil
+	after := options.After
+	opt := options.FindOneAndUpdateOptions{ReturnDocument: &after}
+
+	var locked domain.UserBook
+	err = r.coll.FindOneAndUpdate(ctx, withUnlocked(filter), bson.M{"$set": bson.M{"locked_by": oo}}, &opt).Decode(&locked)
+	if err == mongo.ErrNoDocuments {
+		return nil, r.missingCopyError(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &locked, nil
 }
 
-func (r *mongoUserBookRepo) UnassignBook(ctx context.Context, userID, bookID string) error {
-	uo, err := primitive.ObjectIDFromHex(userID)
+// UnlockBook releases the copies of the book committed to the offer.
+func (r *mongoUserBookRepo) UnlockBook(ctx context.Context, userID, bookID, offerID string) error {
+	filter, err := entryFilter(userID, bookID)
 	if err != nil {
 		return err
 	}
-	bo, err := primitive.ObjectIDFromHex(bookID)
+	oo, err := primitive.ObjectIDFromHex(offerID)
 	if err != nil {
 		return err
 	}
-	_, err = r.coll.DeleteOne(ctx, bson.M{"user_id": uo, "book_id": bo})
+	filter["locked_by"] = oo
+	_, err = r.coll.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"locked_by": ""}})
 	return err
 }
 
+// ReleaseLocks unlocks every copy committed to the offer and returns the
+// owners of those copies.
+func (r *mongoUserBookRepo) ReleaseLocks(ctx context.Context, offerID string) ([]string, error) {
+	oo, err := primitive.ObjectIDFromHex(offerID)
+	if err != nil {
+		return nil, err
+	}
+	owners, err := r.coll.Distinct(ctx, "user_id", bson.M{"locked_by": oo})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.coll.UpdateMany(ctx, bson.M{"locked_by": oo}, bson.M{"$unset": bson.M{"locked_by": ""}}); err != nil {
+		return nil, err
+	}
+
+	var users []string
+	for _, o := range owners {
+		if id, ok := o.(primitive.ObjectID); ok {
+			users = append(users, id.Hex())
+		}
+	}
+	return users, nil
+}
+
+// DeleteByUser removes the whole library of a deleted user, locked copies
+// included.
+func (r *mongoUserBookRepo) DeleteByUser(ctx context.Context, userID string) (int, error) {
+	uo, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+	res, err := r.coll.DeleteMany(ctx, bson.M{"user_id": uo})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
+
+// DeleteByBook removes every entry of a deleted book and returns the users
+// who had it.
+func (r *mongoUserBookRepo) DeleteByBook(ctx context.Context, bookID string) ([]string, error) {
+	bo, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, err
+	}
+	holders, err := r.coll.Distinct(ctx, "user_id", bson.M{"book_id": bo})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.coll.DeleteMany(ctx, bson.M{"book_id": bo}); err != nil {
+		return nil, err
+	}
+
+	var users []string
+	for _, h := range holders {
+		if id, ok := h.(primitive.ObjectID); ok {
+			users = append(users, id.Hex())
+		}
+	}
+	return users, nil
+}
+
+// missingCopyError tells apart a book that is not in the library from one
+// whose copies are all locked.
+func (r *mongoUserBookRepo) missingCopyError(ctx context.Context, filter bson.M) error {
+	n, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return domain.ErrBookLocked
+	}
+	return domain.ErrNotInLibrary
+}
+
+func entryFilter(userID, bookID string) (bson.M, error) {
+	uo, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	bo, err := primitive.ObjectIDFromHex(bookID)
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{"user_id": uo, "book_id": bo}, nil
+}
+
+func withUnlocked(filter bson.M) bson.M {
+	out := bson.M{"locked_by": bson.M{"$exists": false}}
+	for k, v := range filter {
+		out[k] = v
+	}
+	return out
+}
+
 func (r *mongoUserBookRepo) ListUserBooks(ctx context.Context, userID string) ([]*domain.UserBook, error) {
 	uo, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -62,6 +240,60 @@ func (r *mongoUserBookRepo) ListUserBooks(ctx context.Context, userID string) ([
 	return out, nil
 }
 
+func (r *mongoUserBookRepo) ListUserBooksByStatus(ctx context.Context, userID, status string) ([]*domain.UserBook, error) {
+	uo, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+	cur, err := r.coll.Find(ctx, bson.M{"user_id": uo, "status": status}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []*domain.UserBook
+	for cur.Next(ctx) {
+		var u domain.UserBook
+		if err := cur.Decode(&u); err != nil {
+			return nil, err
+		}
+		out = append(out, &u)
+	}
+	return out, nil
+}
+
+// UpdateProgress stores the reading state of the entry; ownership and
+// locks are left alone.
+func (r *mongoUserBookRepo) UpdateProgress(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, error) {
+	set := bson.M{
+		"status":       entry.Status,
+		"current_page": entry.CurrentPage,
+		"note":         entry.Note,
+		"updated_at":   entry.UpdatedAt,
+	}
+	unset := bson.M{}
+	for field, v := range map[string]primitive.DateTime{"started_at": entry.StartedAt, "finished_at": entry.FinishedAt} {
+		if v == 0 {
+			unset[field] = ""
+		} else {
+			set[field] = v
+		}
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	after := options.After
+	opt := options.FindOneAndUpdateOptions{ReturnDocument: &after}
+
+	var updated domain.UserBook
+	if err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": entry.ID}, update, &opt).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
 func (r *mongoUserBookRepo) GetEntry(ctx context.Context, id string) (*domain.UserBook, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -74,35 +306,58 @@ func (r *mongoUserBookRepo) GetEntry(ctx context.Context, id string) (*domain.Us
 	return &u, nil
 }
 
+// DeleteEntry removes an unlocked entry; a locked one fails with
+// ErrBookLocked.
 func (r *mongoUserBookRepo) DeleteEntry(ctx context.Context, id string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
-	_, err = r.coll.DeleteOne(ctx, bson.M{"_id": oid})
-	return err
+	res, err := r.coll.DeleteOne(ctx, withUnlocked(bson.M{"_id": oid}))
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return r.entryConflict(ctx, id, nil)
+	}
+	return nil
 }
 
+// UpdateEntry moves an unlocked entry to another user. The book of an
+// entry never changes: a locked entry fails with ErrBookLocked and a
+// different book with ErrBookChanged.
 func (r *mongoUserBookRepo) UpdateEntry(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, error) {
-	// only user_id and book_id can change
-	update := bson.M{
-		"$set": bson.M{
-			"user_id": entry.UserID,
-			"book_id": entry.BookID,
-		},
-	}
+	update := bson.M{"$set": bson.M{"user_id": entry.UserID}}
 	after := options.After
 	opt := options.FindOneAndUpdateOptions{ReturnDocument: &after}
 
 	var updated domain.UserBook
-	if err := r.coll.
-		FindOneAndUpdate(ctx, bson.M{"_id": entry.ID}, update, &opt).
-		Decode(&updated); err != nil {
+	filter := withUnlocked(bson.M{"_id": entry.ID, "book_id": entry.BookID})
+	if err := r.coll.FindOneAndUpdate(ctx, filter, update, &opt).Decode(&updated); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrAlreadyAssigned
+		}
+		if err == mongo.ErrNoDocuments {
+			return nil, r.entryConflict(ctx, entry.ID.Hex(), &entry.BookID)
+		}
 		return nil, err
 	}
 	return &updated, nil
 }
 
+// entryConflict explains why a write to the entry matched nothing: it is
+// gone, holds another book than bookID (if given) or is locked.
+func (r *mongoUserBookRepo) entryConflict(ctx context.Context, id string, bookID *primitive.ObjectID) error {
+	existing, err := r.GetEntry(ctx, id)
+	if err != nil {
+		return err
+	}
+	if bookID != nil && existing.BookID != *bookID {
+		return domain.ErrBookChanged
+	}
+	return domain.ErrBookLocked
+}
+
 func (r *mongoUserBookRepo) ListAllEntries(ctx context.Context) ([]*domain.UserBook, error) {
 	cur, err := r.coll.Find(ctx, bson.M{})
 	if err != nil {
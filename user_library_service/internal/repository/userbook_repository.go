@@ -7,13 +7,21 @@ import (
 )
 
 type UserBookRepo interface {
-	AssignBook(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, error)
-	UnassignBook(ctx context.Context, userID, bookID string) error
+	AssignBook(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, bool, error)
+	UnassignBook(ctx context.Context, userID, bookID, offerID string) error
 	ListUserBooks(ctx context.Context, userID string) ([]*domain.UserBook, error)
+	ListUserBooksByStatus(ctx context.Context, userID, status string) ([]*domain.UserBook, error)
+	UpdateProgress(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, error)
 
 	GetEntry(ctx context.Context, id string) (*domain.UserBook, error)
 	DeleteEntry(ctx context.Context, id string) error
 	UpdateEntry(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, error)
 	ListAllEntries(ctx context.Context) ([]*domain.UserBook, error)
 	ListByBook(ctx context.Context, bookID string) ([]*domain.UserBook, error)
+	DeleteByUser(ctx context.Context, userID string) (int, error)
+	DeleteByBook(ctx context.Context, bookID string) ([]string, error)
+
+	LockBook(ctx context.Context, userID, bookID, offerID string) (*domain.UserBook, error)
+	UnlockBook(ctx context.Context, userID, bookID, offerID string) error
+	ReleaseLocks(ctx context.Context, offerID string) ([]string, error)
 }
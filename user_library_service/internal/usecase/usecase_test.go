@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/pkg/refcheck"
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/cache"
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/user_library_service/internal/repository"
+)
+
+type fakeRepo struct {
+	repository.UserBookRepo
+	entry   *domain.UserBook
+	entries []*domain.UserBook
+}
+
+func (r *fakeRepo) AssignBook(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, bool, error) {
+	for _, e := range r.entries {
+		if e.UserID == entry.UserID && e.BookID == entry.BookID {
+			return e, false, nil
+		}
+	}
+	r.entries = append(r.entries, entry)
+	return entry, true, nil
+}
+
+func (r *fakeRepo) GetEntry(ctx context.Context, id string) (*domain.UserBook, error) {
+	cp := *r.entry
+	return &cp, nil
+}
+func (r *fakeRepo) UpdateProgress(ctx context.Context, entry *domain.UserBook) (*domain.UserBook, error) {
+	cp := *entry
+	r.entry = &cp
+	return entry, nil
+}
+
+type fakeCache struct {
+	cache.UserLibraryCache
+	invalidated []string
+}
+
+func (c *fakeCache) Invalidate(ctx context.Context, userID string) error {
+	c.invalidated = append(c.invalidated, userID)
+	return nil
+}
+
+// fakeRefs knows every ID except the ones listed as missing.
+type fakeRefs struct {
+	missing map[string]bool
+}
+
+func (f fakeRefs) UserExists(ctx context.Context, id string) error {
+	if f.missing[id] {
+		return fmt.Errorf("%w: %s", refcheck.ErrUnknownUser, id)
+	}
+	return nil
+}
+
+func (f fakeRefs) BookExists(ctx context.Context, id string) error {
+	if f.missing[id] {
+		return fmt.Errorf("%w: %s", refcheck.ErrUnknownBook, id)
+	}
+	return nil
+}
+
+func TestAssignBook_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	c := &fakeCache{}
+	uc := NewUserLibraryUseCase(repo, c, fakeRefs{})
+	user, book := primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()
+
+	first, created, err := uc.AssignBook(ctx, user, book)
+	if err != nil || !created {
+		t.Fatalf("first assign: created=%v err=%v", created, err)
+	}
+	second, created, err := uc.AssignBook(ctx, user, book)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || second.ID != first.ID {
+		t.Errorf("second assign should return entry %s, got %s (created=%v)", first.ID.Hex(), second.ID.Hex(), created)
+	}
+	if len(repo.entries) != 1 || len(c.invalidated) != 1 {
+		t.Errorf("expected one entry and one invalidation, got %d and %v", len(repo.entries), c.invalidated)
+	}
+}
+
+func TestAssignBook_DanglingReferences(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	user, book, ghost := primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()
+	uc := NewUserLibraryUseCase(repo, &fakeCache{}, fakeRefs{missing: map[string]bool{ghost: true}})
+
+	if _, _, err := uc.AssignBook(ctx, ghost, book); !errors.Is(err, refcheck.ErrUnknownUser) {
+		t.Errorf("expected ErrUnknownUser, got %v", err)
+	}
+	if _, _, err := uc.AssignBook(ctx, user, ghost); !errors.Is(err, refcheck.ErrUnknownBook) {
+		t.Errorf("expected ErrUnknownBook, got %v", err)
+	}
+	if len(repo.entries) != 0 {
+		t.Errorf("dangling entries stored: %v", repo.entries)
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestUpdateProgress(t *testing.T) {
+	ctx := context.Background()
+	entry := &domain.UserBook{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: domain.StatusWantToRead}
+	repo := &fakeRepo{entry: entry}
+	c := &fakeCache{}
+	uc := NewUserLibraryUseCase(repo, c, fakeRefs{})
+	id := entry.ID.Hex()
+
+	// страница без статуса означает, что книгу начали читать
+	got, prev, err := uc.UpdateProgress(ctx, id, domain.Progress{CurrentPage: intPtr(12)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev != domain.StatusWantToRead || got.Status != domain.StatusReading || got.StartedAt == 0 {
+		t.Errorf("expected READING with a start date, got %s (%v) from %s", got.Status, got.StartedAt, prev)
+	}
+	if len(c.invalidated) != 1 || c.invalidated[0] != entry.UserID.Hex() {
+		t.Errorf("cache not invalidated: %v", c.invalidated)
+	}
+
+	note := "перечитать вторую главу"
+	got, _, err = uc.UpdateProgress(ctx, id, domain.Progress{Status: domain.StatusFinished, Note: &note})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FinishedAt == 0 || got.Note != note || got.CurrentPage != 12 {
+		t.Errorf("unexpected finished entry: %+v", got)
+	}
+
+	got, _, err = uc.UpdateProgress(ctx, id, domain.Progress{Status: domain.StatusWantToRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.StartedAt != 0 || got.FinishedAt != 0 || got.CurrentPage != 0 {
+		t.Errorf("want-to-read should reset progress: %+v", got)
+	}
+
+	if _, _, err := uc.UpdateProgress(ctx, id, domain.Progress{Status: "SKIMMED"}); !errors.Is(err, domain.ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+	if _, _, err := uc.UpdateProgress(ctx, id, domain.Progress{CurrentPage: intPtr(-1)}); !errors.Is(err, domain.ErrInvalidProgress) {
+		t.Errorf("expected ErrInvalidProgress for a negative page, got %v", err)
+	}
+	later := time.Now()
+	earlier := later.Add(-48 * time.Hour)
+	if _, _, err := uc.UpdateProgress(ctx, id, domain.Progress{Status: domain.StatusFinished, StartedAt: &later, FinishedAt: &earlier}); !errors.Is(err, domain.ErrInvalidProgress) {
+		t.Errorf("expected ErrInvalidProgress for finishing before starting, got %v", err)
+	}
+}
+
+func TestEntryChanges(t *testing.T) {
+	ctx := context.Background()
+	entry := &domain.UserBook{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), BookID: primitive.NewObjectID()}
+	repo := &fakeRepo{entry: entry}
+	uc := NewUserLibraryUseCase(repo, &fakeCache{}, fakeRefs{})
+
+	other := &domain.UserBook{ID: entry.ID, UserID: entry.UserID, BookID: primitive.NewObjectID()}
+	if _, err := uc.UpdateEntry(ctx, other); !errors.Is(err, domain.ErrBookChanged) {
+		t.Errorf("changed book: expected ErrBookChanged, got %v", err)
+	}
+
+	entry.LockedBy = primitive.NewObjectID()
+	moved := &domain.UserBook{ID: entry.ID, UserID: primitive.NewObjectID(), BookID: entry.BookID}
+	if _, err := uc.UpdateEntry(ctx, moved); !errors.Is(err, domain.ErrBookLocked) {
+		t.Errorf("locked entry moved: expected ErrBookLocked, got %v", err)
+	}
+	if err := uc.DeleteEntry(ctx, entry.ID.Hex()); !errors.Is(err, domain.ErrBookLocked) {
+		t.Errorf("locked entry deleted: expected ErrBookLocked, got %v", err)
+	}
+}
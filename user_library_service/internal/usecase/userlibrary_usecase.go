@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/cache"
 	"github.com/OshakbayAigerim/read_space/user_library_service/internal/domain"
@@ -10,52 +11,86 @@ import (
 )
 
 type UserLibraryUseCase interface {
-	AssignBook(ctx context.Context, userID, bookID string) (*domain.UserBook, error)
-	UnassignBook(ctx context.Context, userID, bookID string) error
+	AssignBook(ctx context.Context, userID, bookID string) (entry *domain.UserBook, created bool, err error)
+	UnassignBook(ctx context.Context, userID, bookID, offerID string) error
 	ListUserBooks(ctx context.Context, userID string) ([]*domain.UserBook, error)
+	ListUserBooksByStatus(ctx context.Context, userID, status string) ([]*domain.UserBook, error)
+	UpdateProgress(ctx context.Context, entryID string, p domain.Progress) (updated *domain.UserBook, previousStatus string, err error)
 
 	GetEntry(ctx context.Context, id string) (*domain.UserBook, error)
 	DeleteEntry(ctx context.Context, id string) error
 	UpdateEntry(ctx context.Context, ub *domain.UserBook) (*domain.UserBook, error)
 	ListAllEntries(ctx context.Context) ([]*domain.UserBook, error)
 	ListByBook(ctx context.Context, bookID string) ([]*domain.UserBook, error)
+
+	LockBook(ctx context.Context, userID, bookID, offerID string) (*domain.UserBook, error)
+	UnlockBook(ctx context.Context, userID, bookID, offerID string) error
+	ReleaseLocks(ctx context.Context, offerID string) (int, error)
+
+	RemoveUser(ctx context.Context, userID string) (int, error)
+	RemoveBook(ctx context.Context, bookID string) (int, error)
+}
+
+// RefChecker tells whether the users and books an entry points at exist.
+type RefChecker interface {
+	UserExists(ctx context.Context, id string) error
+	BookExists(ctx context.Context, id string) error
 }
 
 type userLibraryUseCase struct {
 	repo  repository.UserBookRepo
 	cache cache.UserLibraryCache
+	refs  RefChecker
 }
 
-func NewUserLibraryUseCase(repo repository.UserBookRepo, c cache.UserLibraryCache) UserLibraryUseCase {
-	return &userLibraryUseCase{repo: repo, cache: c}
+func NewUserLibraryUseCase(repo repository.UserBookRepo, c cache.UserLibraryCache, refs RefChecker) UserLibraryUseCase {
+	return &userLibraryUseCase{repo: repo, cache: c, refs: refs}
 }
 
-func (uc *userLibraryUseCase) AssignBook(ctx context.Context, userID, bookID string) (*domain.UserBook, error) {
+func (uc *userLibraryUseCase) checkRefs(ctx context.Context, userID, bookID string) error {
+	if err := uc.refs.UserExists(ctx, userID); err != nil {
+		return err
+	}
+	return uc.refs.BookExists(ctx, bookID)
+}
+
+// AssignBook is idempotent: assigning a book the user already has returns
+// the existing entry with created set to false.
+func (uc *userLibraryUseCase) AssignBook(ctx context.Context, userID, bookID string) (*domain.UserBook, bool, error) {
 	uid, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	bid, err := primitive.ObjectIDFromHex(bookID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	if err := uc.checkRefs(ctx, userID, bookID); err != nil {
+		return nil, false, err
 	}
 
 	entry := &domain.UserBook{
 		ID:     primitive.NewObjectID(),
 		UserID: uid,
 		BookID: bid,
+		Status: domain.StatusWantToRead,
 	}
 
-	assigned, err := uc.repo.AssignBook(ctx, entry)
+	assigned, created, err := uc.repo.AssignBook(ctx, entry)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	_ = uc.cache.Invalidate(ctx, userID)
-	return assigned, nil
+	if created {
+		_ = uc.cache.Invalidate(ctx, userID)
+	}
+	return assigned, created, nil
 }
 
-func (uc *userLibraryUseCase) UnassignBook(ctx context.Context, userID, bookID string) error {
-	if err := uc.repo.UnassignBook(ctx, userID, bookID); err != nil {
+// UnassignBook removes a copy of the book. Copies committed to a pending
+// exchange can only be removed by that exchange (offerID), and an exchange
+// only removes the copy it holds.
+func (uc *userLibraryUseCase) UnassignBook(ctx context.Context, userID, bookID, offerID string) error {
+	if err := uc.repo.UnassignBook(ctx, userID, bookID, offerID); err != nil {
 		return err
 	}
 	_ = uc.cache.Invalidate(ctx, userID)
@@ -66,19 +101,74 @@ func (uc *userLibraryUseCase) ListUserBooks(ctx context.Context, userID string)
 	return uc.cache.Get(ctx, userID)
 }
 
+func (uc *userLibraryUseCase) ListUserBooksByStatus(ctx context.Context, userID, status string) ([]*domain.UserBook, error) {
+	if !domain.ValidStatus(status) {
+		return nil, domain.ErrInvalidStatus
+	}
+	return uc.repo.ListUserBooksByStatus(ctx, userID, status)
+}
+
+// UpdateProgress changes the reading status, page, dates or note of an
+// entry and returns it along with the status it had before.
+func (uc *userLibraryUseCase) UpdateProgress(ctx context.Context, entryID string, p domain.Progress) (*domain.UserBook, string, error) {
+	entry, err := uc.repo.GetEntry(ctx, entryID)
+	if err != nil {
+		return nil, "", err
+	}
+	previous := entry.Status
+	if err := entry.ApplyProgress(p, time.Now()); err != nil {
+		return nil, "", err
+	}
+	updated, err := uc.repo.UpdateProgress(ctx, entry)
+	if err != nil {
+		return nil, "", err
+	}
+	_ = uc.cache.Invalidate(ctx, updated.UserID.Hex())
+	return updated, previous, nil
+}
+
 func (uc *userLibraryUseCase) GetEntry(ctx context.Context, id string) (*domain.UserBook, error) {
 	return uc.repo.GetEntry(ctx, id)
 }
 
+// DeleteEntry removes an entry unless its copy is committed to a pending
+// exchange.
 func (uc *userLibraryUseCase) DeleteEntry(ctx context.Context, id string) error {
-	return uc.repo.DeleteEntry(ctx, id)
+	e, err := uc.repo.GetEntry(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.Locked() {
+		return domain.ErrBookLocked
+	}
+	if err := uc.repo.DeleteEntry(ctx, id); err != nil {
+		return err
+	}
+	_ = uc.cache.Invalidate(ctx, e.UserID.Hex())
+	return nil
 }
 
+// UpdateEntry moves an unlocked entry to another user, keeping its reading
+// progress. The book cannot change: a different title is a new entry.
 func (uc *userLibraryUseCase) UpdateEntry(ctx context.Context, ub *domain.UserBook) (*domain.UserBook, error) {
+	existing, err := uc.repo.GetEntry(ctx, ub.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if existing.BookID != ub.BookID {
+		return nil, domain.ErrBookChanged
+	}
+	if existing.Locked() {
+		return nil, domain.ErrBookLocked
+	}
+	if err := uc.checkRefs(ctx, ub.UserID.Hex(), ub.BookID.Hex()); err != nil {
+		return nil, err
+	}
 	updated, err := uc.repo.UpdateEntry(ctx, ub)
 	if err != nil {
 		return nil, err
 	}
+	_ = uc.cache.Invalidate(ctx, existing.UserID.Hex())
 	_ = uc.cache.Invalidate(ctx, ub.UserID.Hex())
 	return updated, nil
 }
@@ -90,3 +180,56 @@ func (uc *userLibraryUseCase) ListAllEntries(ctx context.Context) ([]*domain.Use
 func (uc *userLibraryUseCase) ListByBook(ctx context.Context, bookID string) ([]*domain.UserBook, error) {
 	return uc.repo.ListByBook(ctx, bookID)
 }
+
+func (uc *userLibraryUseCase) LockBook(ctx context.Context, userID, bookID, offerID string) (*domain.UserBook, error) {
+	locked, err := uc.repo.LockBook(ctx, userID, bookID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	_ = uc.cache.Invalidate(ctx, userID)
+	return locked, nil
+}
+
+func (uc *userLibraryUseCase) UnlockBook(ctx context.Context, userID, bookID, offerID string) error {
+	if err := uc.repo.UnlockBook(ctx, userID, bookID, offerID); err != nil {
+		return err
+	}
+	_ = uc.cache.Invalidate(ctx, userID)
+	return nil
+}
+
+// ReleaseLocks unlocks every copy committed to the offer and returns how
+// many libraries were affected.
+func (uc *userLibraryUseCase) ReleaseLocks(ctx context.Context, offerID string) (int, error) {
+	users, err := uc.repo.ReleaseLocks(ctx, offerID)
+	if err != nil {
+		return 0, err
+	}
+	for _, u := range users {
+		_ = uc.cache.Invalidate(ctx, u)
+	}
+	return len(users), nil
+}
+
+// RemoveUser drops the library of a deleted user.
+func (uc *userLibraryUseCase) RemoveUser(ctx context.Context, userID string) (int, error) {
+	n, err := uc.repo.DeleteByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	_ = uc.cache.Invalidate(ctx, userID)
+	return n, nil
+}
+
+// RemoveBook drops a deleted book from every library and returns how many
+// libraries held it.
+func (uc *userLibraryUseCase) RemoveBook(ctx context.Context, bookID string) (int, error) {
+	users, err := uc.repo.DeleteByBook(ctx, bookID)
+	if err != nil {
+		return 0, err
+	}
+	for _, u := range users {
+		_ = uc.cache.Invalidate(ctx, u)
+	}
+	return len(users), nil
+}
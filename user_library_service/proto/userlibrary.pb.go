@@ -9,6 +9,7 @@ package userlibrarypb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,12 +22,19 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Запись «пользователь-владеет-книгой»
 type UserBook struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                       // ID записи
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // ID пользователя
-	BookId        string                 `protobuf:"bytes,3,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"` // ID книги из каталога
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	BookId string                 `protobuf:"bytes,3,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	// locked_by is the pending exchange offer this copy is committed to.
+	LockedBy      string `protobuf:"bytes,4,opt,name=locked_by,json=lockedBy,proto3" json:"locked_by,omitempty"`
+	Status        string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CurrentPage   int32  `protobuf:"varint,6,opt,name=current_page,json=currentPage,proto3" json:"current_page,omitempty"`
+	StartedAt     string `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    string `protobuf:"bytes,8,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Note          string `protobuf:"bytes,9,opt,name=note,proto3" json:"note,omitempty"`
+	UpdatedAt     string `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,18 +90,207 @@ func (x *UserBook) GetBookId() string {
 	return ""
 }
 
-// Запросы
+func (x *UserBook) GetLockedBy() string {
+	if x != nil {
+		return x.LockedBy
+	}
+	return ""
+}
+
+func (x *UserBook) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UserBook) GetCurrentPage() int32 {
+	if x != nil {
+		return x.CurrentPage
+	}
+	return 0
+}
+
+func (x *UserBook) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *UserBook) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+func (x *UserBook) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *UserBook) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// UpdateProgressRequest changes the reading state of an entry; unset
+// fields keep their value. Dates are RFC 3339 times.
+type UpdateProgressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntryId       string                 `protobuf:"bytes,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	CurrentPage   *int32                 `protobuf:"varint,3,opt,name=current_page,json=currentPage,proto3,oneof" json:"current_page,omitempty"`
+	Note          *string                `protobuf:"bytes,4,opt,name=note,proto3,oneof" json:"note,omitempty"`
+	StartedAt     string                 `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    string                 `protobuf:"bytes,6,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProgressRequest) Reset() {
+	*x = UpdateProgressRequest{}
+	mi := &file_userlibrary_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProgressRequest) ProtoMessage() {}
+
+func (x *UpdateProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProgressRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProgressRequest) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateProgressRequest) GetEntryId() string {
+	if x != nil {
+		return x.EntryId
+	}
+	return ""
+}
+
+func (x *UpdateProgressRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateProgressRequest) GetCurrentPage() int32 {
+	if x != nil && x.CurrentPage != nil {
+		return *x.CurrentPage
+	}
+	return 0
+}
+
+func (x *UpdateProgressRequest) GetNote() string {
+	if x != nil && x.Note != nil {
+		return *x.Note
+	}
+	return ""
+}
+
+func (x *UpdateProgressRequest) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *UpdateProgressRequest) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+type ListByStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListByStatusRequest) Reset() {
+	*x = ListByStatusRequest{}
+	mi := &file_userlibrary_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListByStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListByStatusRequest) ProtoMessage() {}
+
+func (x *ListByStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListByStatusRequest.ProtoReflect.Descriptor instead.
+func (*ListByStatusRequest) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListByStatusRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListByStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// AssignBook is idempotent: a book the user already has is returned as is,
+// or rejected with ALREADY_EXISTS when fail_if_exists is set.
 type AssignBookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	BookId        string                 `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	FailIfExists  bool                   `protobuf:"varint,3,opt,name=fail_if_exists,json=failIfExists,proto3" json:"fail_if_exists,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AssignBookRequest) Reset() {
 	*x = AssignBookRequest{}
-	mi := &file_userlibrary_proto_msgTypes[1]
+	mi := &file_userlibrary_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -105,7 +302,7 @@ func (x *AssignBookRequest) String() string {
 func (*AssignBookRequest) ProtoMessage() {}
 
 func (x *AssignBookRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_userlibrary_proto_msgTypes[1]
+	mi := &file_userlibrary_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -118,7 +315,7 @@ func (x *AssignBookRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AssignBookRequest.ProtoReflect.Descriptor instead.
 func (*AssignBookRequest) Descriptor() ([]byte, []int) {
-	return file_userlibrary_proto_rawDescGZIP(), []int{1}
+	return file_userlibrary_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *AssignBookRequest) GetUserId() string {
@@ -135,17 +332,27 @@ func (x *AssignBookRequest) GetBookId() string {
 	return ""
 }
 
+func (x *AssignBookRequest) GetFailIfExists() bool {
+	if x != nil {
+		return x.FailIfExists
+	}
+	return false
+}
+
+// offer_id lets the exchange holding the lock remove a locked copy; without
+// such a copy the call fails rather than removing an unlocked one.
 type UnassignBookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	BookId        string                 `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	OfferId       string                 `protobuf:"bytes,3,opt,name=offer_id,json=offerId,proto3" json:"offer_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UnassignBookRequest) Reset() {
 	*x = UnassignBookRequest{}
-	mi := &file_userlibrary_proto_msgTypes[2]
+	mi := &file_userlibrary_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -157,7 +364,7 @@ func (x *UnassignBookRequest) String() string {
 func (*UnassignBookRequest) ProtoMessage() {}
 
 func (x *UnassignBookRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_userlibrary_proto_msgTypes[2]
+	mi := &file_userlibrary_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -170,7 +377,7 @@ func (x *UnassignBookRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnassignBookRequest.ProtoReflect.Descriptor instead.
 func (*UnassignBookRequest) Descriptor() ([]byte, []int) {
-	return file_userlibrary_proto_rawDescGZIP(), []int{2}
+	return file_userlibrary_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UnassignBookRequest) GetUserId() string {
@@ -187,6 +394,161 @@ func (x *UnassignBookRequest) GetBookId() string {
 	return ""
 }
 
+func (x *UnassignBookRequest) GetOfferId() string {
+	if x != nil {
+		return x.OfferId
+	}
+	return ""
+}
+
+type LockBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	BookId        string                 `protobuf:"bytes,2,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	OfferId       string                 `protobuf:"bytes,3,opt,name=offer_id,json=offerId,proto3" json:"offer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockBookRequest) Reset() {
+	*x = LockBookRequest{}
+	mi := &file_userlibrary_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockBookRequest) ProtoMessage() {}
+
+func (x *LockBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockBookRequest.ProtoReflect.Descriptor instead.
+func (*LockBookRequest) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LockBookRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LockBookRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+func (x *LockBookRequest) GetOfferId() string {
+	if x != nil {
+		return x.OfferId
+	}
+	return ""
+}
+
+type ReleaseLocksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OfferId       string                 `protobuf:"bytes,1,opt,name=offer_id,json=offerId,proto3" json:"offer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseLocksRequest) Reset() {
+	*x = ReleaseLocksRequest{}
+	mi := &file_userlibrary_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseLocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseLocksRequest) ProtoMessage() {}
+
+func (x *ReleaseLocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseLocksRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseLocksRequest) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReleaseLocksRequest) GetOfferId() string {
+	if x != nil {
+		return x.OfferId
+	}
+	return ""
+}
+
+type ReleaseLocksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Libraries     int32                  `protobuf:"varint,1,opt,name=libraries,proto3" json:"libraries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseLocksResponse) Reset() {
+	*x = ReleaseLocksResponse{}
+	mi := &file_userlibrary_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseLocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseLocksResponse) ProtoMessage() {}
+
+func (x *ReleaseLocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseLocksResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseLocksResponse) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReleaseLocksResponse) GetLibraries() int32 {
+	if x != nil {
+		return x.Libraries
+	}
+	return 0
+}
+
 type ListUserBooksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -196,7 +558,7 @@ type ListUserBooksRequest struct {
 
 func (x *ListUserBooksRequest) Reset() {
 	*x = ListUserBooksRequest{}
-	mi := &file_userlibrary_proto_msgTypes[3]
+	mi := &file_userlibrary_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -208,7 +570,7 @@ func (x *ListUserBooksRequest) String() string {
 func (*ListUserBooksRequest) ProtoMessage() {}
 
 func (x *ListUserBooksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_userlibrary_proto_msgTypes[3]
+	mi := &file_userlibrary_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -221,7 +583,7 @@ func (x *ListUserBooksRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserBooksRequest.ProtoReflect.Descriptor instead.
 func (*ListUserBooksRequest) Descriptor() ([]byte, []int) {
-	return file_userlibrary_proto_rawDescGZIP(), []int{3}
+	return file_userlibrary_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListUserBooksRequest) GetUserId() string {
@@ -231,17 +593,194 @@ func (x *ListUserBooksRequest) GetUserId() string {
 	return ""
 }
 
-// Ответы
-type AssignBookResponse struct {
+type GetEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEntryRequest) Reset() {
+	*x = GetEntryRequest{}
+	mi := &file_userlibrary_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEntryRequest) ProtoMessage() {}
+
+func (x *GetEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEntryRequest.ProtoReflect.Descriptor instead.
+func (*GetEntryRequest) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetEntryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEntryRequest) Reset() {
+	*x = DeleteEntryRequest{}
+	mi := &file_userlibrary_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEntryRequest) ProtoMessage() {}
+
+func (x *DeleteEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEntryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteEntryRequest) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteEntryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateEntryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Entry         *UserBook              `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *UpdateEntryRequest) Reset() {
+	*x = UpdateEntryRequest{}
+	mi := &file_userlibrary_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEntryRequest) ProtoMessage() {}
+
+func (x *UpdateEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEntryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEntryRequest) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateEntryRequest) GetEntry() *UserBook {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type ListByBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookId        string                 `protobuf:"bytes,1,opt,name=book_id,json=bookId,proto3" json:"book_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListByBookRequest) Reset() {
+	*x = ListByBookRequest{}
+	mi := &file_userlibrary_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListByBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListByBookRequest) ProtoMessage() {}
+
+func (x *ListByBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_userlibrary_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListByBookRequest.ProtoReflect.Descriptor instead.
+func (*ListByBookRequest) Descriptor() ([]byte, []int) {
+	return file_userlibrary_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListByBookRequest) GetBookId() string {
+	if x != nil {
+		return x.BookId
+	}
+	return ""
+}
+
+type AssignBookResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Entry *UserBook              `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// created is false when the book was already in the library.
+	Created       bool `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
 func (x *AssignBookResponse) Reset() {
 	*x = AssignBookResponse{}
-	mi := &file_userlibrary_proto_msgTypes[4]
+	mi := &file_userlibrary_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -253,7 +792,7 @@ func (x *AssignBookResponse) String() string {
 func (*AssignBookResponse) ProtoMessage() {}
 
 func (x *AssignBookResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_userlibrary_proto_msgTypes[4]
+	mi := &file_userlibrary_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -266,7 +805,7 @@ func (x *AssignBookResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AssignBookResponse.ProtoReflect.Descriptor instead.
 func (*AssignBookResponse) Descriptor() ([]byte, []int) {
-	return file_userlibrary_proto_rawDescGZIP(), []int{4}
+	return file_userlibrary_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *AssignBookResponse) GetEntry() *UserBook {
@@ -276,6 +815,13 @@ func (x *AssignBookResponse) GetEntry() *UserBook {
 	return nil
 }
 
+func (x *AssignBookResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
 type UnassignBookResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -285,7 +831,7 @@ type UnassignBookResponse struct {
 
 func (x *UnassignBookResponse) Reset() {
 	*x = UnassignBookResponse{}
-	mi := &file_userlibrary_proto_msgTypes[5]
+	mi := &file_userlibrary_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -297,7 +843,7 @@ func (x *UnassignBookResponse) String() string {
 func (*UnassignBookResponse) ProtoMessage() {}
 
 func (x *UnassignBookResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_userlibrary_proto_msgTypes[5]
+	mi := &file_userlibrary_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -310,7 +856,7 @@ func (x *UnassignBookResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnassignBookResponse.ProtoReflect.Descriptor instead.
 func (*UnassignBookResponse) Descriptor() ([]byte, []int) {
-	return file_userlibrary_proto_rawDescGZIP(), []int{5}
+	return file_userlibrary_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *UnassignBookResponse) GetSuccess() bool {
@@ -329,7 +875,7 @@ type ListUserBooksResponse struct {
 
 func (x *ListUserBooksResponse) Reset() {
 	*x = ListUserBooksResponse{}
-	mi := &file_userlibrary_proto_msgTypes[6]
+	mi := &file_userlibrary_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -341,7 +887,7 @@ func (x *ListUserBooksResponse) String() string {
 func (*ListUserBooksResponse) ProtoMessage() {}
 
 func (x *ListUserBooksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_userlibrary_proto_msgTypes[6]
+	mi := &file_userlibrary_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -354,7 +900,7 @@ func (x *ListUserBooksResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUserBooksResponse.ProtoReflect.Descriptor instead.
 func (*ListUserBooksResponse) Descriptor() ([]byte, []int) {
-	return file_userlibrary_proto_rawDescGZIP(), []int{6}
+	return file_userlibrary_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *ListUserBooksResponse) GetEntries() []*UserBook {
@@ -368,30 +914,86 @@ var File_userlibrary_proto protoreflect.FileDescriptor
 
 const file_userlibrary_proto_rawDesc = "" +
 	"\n" +
-	"\x11userlibrary.proto\x12\vuserlibrary\"L\n" +
+	"\x11userlibrary.proto\x12\vuserlibrary\x1a\x1bgoogle/protobuf/empty.proto\"\x97\x02\n" +
 	"\bUserBook\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x17\n" +
-	"\abook_id\x18\x03 \x01(\tR\x06bookId\"E\n" +
+	"\abook_id\x18\x03 \x01(\tR\x06bookId\x12\x1b\n" +
+	"\tlocked_by\x18\x04 \x01(\tR\blockedBy\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12!\n" +
+	"\fcurrent_page\x18\x06 \x01(\x05R\vcurrentPage\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\a \x01(\tR\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\b \x01(\tR\n" +
+	"finishedAt\x12\x12\n" +
+	"\x04note\x18\t \x01(\tR\x04note\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\tR\tupdatedAt\"\xe5\x01\n" +
+	"\x15UpdateProgressRequest\x12\x19\n" +
+	"\bentry_id\x18\x01 \x01(\tR\aentryId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12&\n" +
+	"\fcurrent_page\x18\x03 \x01(\x05H\x00R\vcurrentPage\x88\x01\x01\x12\x17\n" +
+	"\x04note\x18\x04 \x01(\tH\x01R\x04note\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\tR\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x06 \x01(\tR\n" +
+	"finishedAtB\x0f\n" +
+	"\r_current_pageB\a\n" +
+	"\x05_note\"F\n" +
+	"\x13ListByStatusRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"k\n" +
 	"\x11AssignBookRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
-	"\abook_id\x18\x02 \x01(\tR\x06bookId\"G\n" +
+	"\abook_id\x18\x02 \x01(\tR\x06bookId\x12$\n" +
+	"\x0efail_if_exists\x18\x03 \x01(\bR\ffailIfExists\"b\n" +
 	"\x13UnassignBookRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
-	"\abook_id\x18\x02 \x01(\tR\x06bookId\"/\n" +
+	"\abook_id\x18\x02 \x01(\tR\x06bookId\x12\x19\n" +
+	"\boffer_id\x18\x03 \x01(\tR\aofferId\"^\n" +
+	"\x0fLockBookRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\abook_id\x18\x02 \x01(\tR\x06bookId\x12\x19\n" +
+	"\boffer_id\x18\x03 \x01(\tR\aofferId\"0\n" +
+	"\x13ReleaseLocksRequest\x12\x19\n" +
+	"\boffer_id\x18\x01 \x01(\tR\aofferId\"4\n" +
+	"\x14ReleaseLocksResponse\x12\x1c\n" +
+	"\tlibraries\x18\x01 \x01(\x05R\tlibraries\"/\n" +
 	"\x14ListUserBooksRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"A\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"!\n" +
+	"\x0fGetEntryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"$\n" +
+	"\x12DeleteEntryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"A\n" +
+	"\x12UpdateEntryRequest\x12+\n" +
+	"\x05entry\x18\x01 \x01(\v2\x15.userlibrary.UserBookR\x05entry\",\n" +
+	"\x11ListByBookRequest\x12\x17\n" +
+	"\abook_id\x18\x01 \x01(\tR\x06bookId\"[\n" +
 	"\x12AssignBookResponse\x12+\n" +
-	"\x05entry\x18\x01 \x01(\v2\x15.userlibrary.UserBookR\x05entry\"0\n" +
+	"\x05entry\x18\x01 \x01(\v2\x15.userlibrary.UserBookR\x05entry\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\"0\n" +
 	"\x14UnassignBookResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\"H\n" +
 	"\x15ListUserBooksResponse\x12/\n" +
-	"\aentries\x18\x01 \x03(\v2\x15.userlibrary.UserBookR\aentries2\x90\x02\n" +
+	"\aentries\x18\x01 \x03(\v2\x15.userlibrary.UserBookR\aentries2\xc4\b\n" +
 	"\x12UserLibraryService\x12M\n" +
 	"\n" +
 	"AssignBook\x12\x1e.userlibrary.AssignBookRequest\x1a\x1f.userlibrary.AssignBookResponse\x12S\n" +
 	"\fUnassignBook\x12 .userlibrary.UnassignBookRequest\x1a!.userlibrary.UnassignBookResponse\x12V\n" +
-	"\rListUserBooks\x12!.userlibrary.ListUserBooksRequest\x1a\".userlibrary.ListUserBooksResponseB^Z\\github.com/OshakbayAigerim/read_space/user_library_service/proto/userlibrarypb;userlibrarypbb\x06proto3"
+	"\rListUserBooks\x12!.userlibrary.ListUserBooksRequest\x1a\".userlibrary.ListUserBooksResponse\x12]\n" +
+	"\x15ListUserBooksByStatus\x12 .userlibrary.ListByStatusRequest\x1a\".userlibrary.ListUserBooksResponse\x12U\n" +
+	"\x0eUpdateProgress\x12\".userlibrary.UpdateProgressRequest\x1a\x1f.userlibrary.AssignBookResponse\x12I\n" +
+	"\bGetEntry\x12\x1c.userlibrary.GetEntryRequest\x1a\x1f.userlibrary.AssignBookResponse\x12Q\n" +
+	"\vDeleteEntry\x12\x1f.userlibrary.DeleteEntryRequest\x1a!.userlibrary.UnassignBookResponse\x12O\n" +
+	"\vUpdateEntry\x12\x1f.userlibrary.UpdateEntryRequest\x1a\x1f.userlibrary.AssignBookResponse\x12L\n" +
+	"\x0eListAllEntries\x12\x16.google.protobuf.Empty\x1a\".userlibrary.ListUserBooksResponse\x12P\n" +
+	"\n" +
+	"ListByBook\x12\x1e.userlibrary.ListByBookRequest\x1a\".userlibrary.ListUserBooksResponse\x12I\n" +
+	"\bLockBook\x12\x1c.userlibrary.LockBookRequest\x1a\x1f.userlibrary.AssignBookResponse\x12M\n" +
+	"\n" +
+	"UnlockBook\x12\x1c.userlibrary.LockBookRequest\x1a!.userlibrary.UnassignBookResponse\x12S\n" +
+	"\fReleaseLocks\x12 .userlibrary.ReleaseLocksRequest\x1a!.userlibrary.ReleaseLocksResponseB^Z\\github.com/OshakbayAigerim/read_space/user_library_service/proto/userlibrarypb;userlibrarypbb\x06proto3"
 
 var (
 	file_userlibrary_proto_rawDescOnce sync.Once
@@ -405,30 +1007,61 @@ func file_userlibrary_proto_rawDescGZIP() []byte {
 	return file_userlibrary_proto_rawDescData
 }
 
-var file_userlibrary_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_userlibrary_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_userlibrary_proto_goTypes = []any{
 	(*UserBook)(nil),              // 0: userlibrary.UserBook
-	(*AssignBookRequest)(nil),     // 1: userlibrary.AssignBookRequest
-	(*UnassignBookRequest)(nil),   // 2: userlibrary.UnassignBookRequest
-	(*ListUserBooksRequest)(nil),  // 3: userlibrary.ListUserBooksRequest
-	(*AssignBookResponse)(nil),    // 4: userlibrary.AssignBookResponse
-	(*UnassignBookResponse)(nil),  // 5: userlibrary.UnassignBookResponse
-	(*ListUserBooksResponse)(nil), // 6: userlibrary.ListUserBooksResponse
+	(*UpdateProgressRequest)(nil), // 1: userlibrary.UpdateProgressRequest
+	(*ListByStatusRequest)(nil),   // 2: userlibrary.ListByStatusRequest
+	(*AssignBookRequest)(nil),     // 3: userlibrary.AssignBookRequest
+	(*UnassignBookRequest)(nil),   // 4: userlibrary.UnassignBookRequest
+	(*LockBookRequest)(nil),       // 5: userlibrary.LockBookRequest
+	(*ReleaseLocksRequest)(nil),   // 6: userlibrary.ReleaseLocksRequest
+	(*ReleaseLocksResponse)(nil),  // 7: userlibrary.ReleaseLocksResponse
+	(*ListUserBooksRequest)(nil),  // 8: userlibrary.ListUserBooksRequest
+	(*GetEntryRequest)(nil),       // 9: userlibrary.GetEntryRequest
+	(*DeleteEntryRequest)(nil),    // 10: userlibrary.DeleteEntryRequest
+	(*UpdateEntryRequest)(nil),    // 11: userlibrary.UpdateEntryRequest
+	(*ListByBookRequest)(nil),     // 12: userlibrary.ListByBookRequest
+	(*AssignBookResponse)(nil),    // 13: userlibrary.AssignBookResponse
+	(*UnassignBookResponse)(nil),  // 14: userlibrary.UnassignBookResponse
+	(*ListUserBooksResponse)(nil), // 15: userlibrary.ListUserBooksResponse
+	(*emptypb.Empty)(nil),         // 16: google.protobuf.Empty
 }
 var file_userlibrary_proto_depIdxs = []int32{
-	0, // 0: userlibrary.AssignBookResponse.entry:type_name -> userlibrary.UserBook
-	0, // 1: userlibrary.ListUserBooksResponse.entries:type_name -> userlibrary.UserBook
-	1, // 2: userlibrary.UserLibraryService.AssignBook:input_type -> userlibrary.AssignBookRequest
-	2, // 3: userlibrary.UserLibraryService.UnassignBook:input_type -> userlibrary.UnassignBookRequest
-	3, // 4: userlibrary.UserLibraryService.ListUserBooks:input_type -> userlibrary.ListUserBooksRequest
-	4, // 5: userlibrary.UserLibraryService.AssignBook:output_type -> userlibrary.AssignBookResponse
-	5, // 6: userlibrary.UserLibraryService.UnassignBook:output_type -> userlibrary.UnassignBookResponse
-	6, // 7: userlibrary.UserLibraryService.ListUserBooks:output_type -> userlibrary.ListUserBooksResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: userlibrary.UpdateEntryRequest.entry:type_name -> userlibrary.UserBook
+	0,  // 1: userlibrary.AssignBookResponse.entry:type_name -> userlibrary.UserBook
+	0,  // 2: userlibrary.ListUserBooksResponse.entries:type_name -> userlibrary.UserBook
+	3,  // 3: userlibrary.UserLibraryService.AssignBook:input_type -> userlibrary.AssignBookRequest
+	4,  // 4: userlibrary.UserLibraryService.UnassignBook:input_type -> userlibrary.UnassignBookRequest
+	8,  // 5: userlibrary.UserLibraryService.ListUserBooks:input_type -> userlibrary.ListUserBooksRequest
+	2,  // 6: userlibrary.UserLibraryService.ListUserBooksByStatus:input_type -> userlibrary.ListByStatusRequest
+	1,  // 7: userlibrary.UserLibraryService.UpdateProgress:input_type -> userlibrary.UpdateProgressRequest
+	9,  // 8: userlibrary.UserLibraryService.GetEntry:input_type -> userlibrary.GetEntryRequest
+	10, // 9: userlibrary.UserLibraryService.DeleteEntry:input_type -> userlibrary.DeleteEntryRequest
+	11, // 10: userlibrary.UserLibraryService.UpdateEntry:input_type -> userlibrary.UpdateEntryRequest
+	16, // 11: userlibrary.UserLibraryService.ListAllEntries:input_type -> google.protobuf.Empty
+	12, // 12: userlibrary.UserLibraryService.ListByBook:input_type -> userlibrary.ListByBookRequest
+	5,  // 13: userlibrary.UserLibraryService.LockBook:input_type -> userlibrary.LockBookRequest
+	5,  // 14: userlibrary.UserLibraryService.UnlockBook:input_type -> userlibrary.LockBookRequest
+	6,  // 15: userlibrary.UserLibraryService.ReleaseLocks:input_type -> userlibrary.ReleaseLocksRequest
+	13, // 16: userlibrary.UserLibraryService.AssignBook:output_type -> userlibrary.AssignBookResponse
+	14, // 17: userlibrary.UserLibraryService.UnassignBook:output_type -> userlibrary.UnassignBookResponse
+	15, // 18: userlibrary.UserLibraryService.ListUserBooks:output_type -> userlibrary.ListUserBooksResponse
+	15, // 19: userlibrary.UserLibraryService.ListUserBooksByStatus:output_type -> userlibrary.ListUserBooksResponse
+	13, // 20: userlibrary.UserLibraryService.UpdateProgress:output_type -> userlibrary.AssignBookResponse
+	13, // 21: userlibrary.UserLibraryService.GetEntry:output_type -> userlibrary.AssignBookResponse
+	14, // 22: userlibrary.UserLibraryService.DeleteEntry:output_type -> userlibrary.UnassignBookResponse
+	13, // 23: userlibrary.UserLibraryService.UpdateEntry:output_type -> userlibrary.AssignBookResponse
+	15, // 24: userlibrary.UserLibraryService.ListAllEntries:output_type -> userlibrary.ListUserBooksResponse
+	15, // 25: userlibrary.UserLibraryService.ListByBook:output_type -> userlibrary.ListUserBooksResponse
+	13, // 26: userlibrary.UserLibraryService.LockBook:output_type -> userlibrary.AssignBookResponse
+	14, // 27: userlibrary.UserLibraryService.UnlockBook:output_type -> userlibrary.UnassignBookResponse
+	7,  // 28: userlibrary.UserLibraryService.ReleaseLocks:output_type -> userlibrary.ReleaseLocksResponse
+	16, // [16:29] is the sub-list for method output_type
+	3,  // [3:16] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_userlibrary_proto_init() }
@@ -436,13 +1069,14 @@ func file_userlibrary_proto_init() {
 	if File_userlibrary_proto != nil {
 		return
 	}
+	file_userlibrary_proto_msgTypes[1].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_userlibrary_proto_rawDesc), len(file_userlibrary_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -11,6 +11,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -19,20 +20,38 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserLibraryService_AssignBook_FullMethodName    = "/userlibrary.UserLibraryService/AssignBook"
-	UserLibraryService_UnassignBook_FullMethodName  = "/userlibrary.UserLibraryService/UnassignBook"
-	UserLibraryService_ListUserBooks_FullMethodName = "/userlibrary.UserLibraryService/ListUserBooks"
+	UserLibraryService_AssignBook_FullMethodName            = "/userlibrary.UserLibraryService/AssignBook"
+	UserLibraryService_UnassignBook_FullMethodName          = "/userlibrary.UserLibraryService/UnassignBook"
+	UserLibraryService_ListUserBooks_FullMethodName         = "/userlibrary.UserLibraryService/ListUserBooks"
+	UserLibraryService_ListUserBooksByStatus_FullMethodName = "/userlibrary.UserLibraryService/ListUserBooksByStatus"
+	UserLibraryService_UpdateProgress_FullMethodName        = "/userlibrary.UserLibraryService/UpdateProgress"
+	UserLibraryService_GetEntry_FullMethodName              = "/userlibrary.UserLibraryService/GetEntry"
+	UserLibraryService_DeleteEntry_FullMethodName           = "/userlibrary.UserLibraryService/DeleteEntry"
+	UserLibraryService_UpdateEntry_FullMethodName           = "/userlibrary.UserLibraryService/UpdateEntry"
+	UserLibraryService_ListAllEntries_FullMethodName        = "/userlibrary.UserLibraryService/ListAllEntries"
+	UserLibraryService_ListByBook_FullMethodName            = "/userlibrary.UserLibraryService/ListByBook"
+	UserLibraryService_LockBook_FullMethodName              = "/userlibrary.UserLibraryService/LockBook"
+	UserLibraryService_UnlockBook_FullMethodName            = "/userlibrary.UserLibraryService/UnlockBook"
+	UserLibraryService_ReleaseLocks_FullMethodName          = "/userlibrary.UserLibraryService/ReleaseLocks"
 )
 
 // UserLibraryServiceClient is the client API for UserLibraryService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-//
-// Сервис
 type UserLibraryServiceClient interface {
 	AssignBook(ctx context.Context, in *AssignBookRequest, opts ...grpc.CallOption) (*AssignBookResponse, error)
 	UnassignBook(ctx context.Context, in *UnassignBookRequest, opts ...grpc.CallOption) (*UnassignBookResponse, error)
 	ListUserBooks(ctx context.Context, in *ListUserBooksRequest, opts ...grpc.CallOption) (*ListUserBooksResponse, error)
+	ListUserBooksByStatus(ctx context.Context, in *ListByStatusRequest, opts ...grpc.CallOption) (*ListUserBooksResponse, error)
+	UpdateProgress(ctx context.Context, in *UpdateProgressRequest, opts ...grpc.CallOption) (*AssignBookResponse, error)
+	GetEntry(ctx context.Context, in *GetEntryRequest, opts ...grpc.CallOption) (*AssignBookResponse, error)
+	DeleteEntry(ctx context.Context, in *DeleteEntryRequest, opts ...grpc.CallOption) (*UnassignBookResponse, error)
+	UpdateEntry(ctx context.Context, in *UpdateEntryRequest, opts ...grpc.CallOption) (*AssignBookResponse, error)
+	ListAllEntries(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListUserBooksResponse, error)
+	ListByBook(ctx context.Context, in *ListByBookRequest, opts ...grpc.CallOption) (*ListUserBooksResponse, error)
+	LockBook(ctx context.Context, in *LockBookRequest, opts ...grpc.CallOption) (*AssignBookResponse, error)
+	UnlockBook(ctx context.Context, in *LockBookRequest, opts ...grpc.CallOption) (*UnassignBookResponse, error)
+	ReleaseLocks(ctx context.Context, in *ReleaseLocksRequest, opts ...grpc.CallOption) (*ReleaseLocksResponse, error)
 }
 
 type userLibraryServiceClient struct {
@@ -73,15 +92,123 @@ func (c *userLibraryServiceClient) ListUserBooks(ctx context.Context, in *ListUs
 	return out, nil
 }
 
+func (c *userLibraryServiceClient) ListUserBooksByStatus(ctx context.Context, in *ListByStatusRequest, opts ...grpc.CallOption) (*ListUserBooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserBooksResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_ListUserBooksByStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) UpdateProgress(ctx context.Context, in *UpdateProgressRequest, opts ...grpc.CallOption) (*AssignBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignBookResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_UpdateProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) GetEntry(ctx context.Context, in *GetEntryRequest, opts ...grpc.CallOption) (*AssignBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignBookResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_GetEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) DeleteEntry(ctx context.Context, in *DeleteEntryRequest, opts ...grpc.CallOption) (*UnassignBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnassignBookResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_DeleteEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) UpdateEntry(ctx context.Context, in *UpdateEntryRequest, opts ...grpc.CallOption) (*AssignBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignBookResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_UpdateEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) ListAllEntries(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListUserBooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserBooksResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_ListAllEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) ListByBook(ctx context.Context, in *ListByBookRequest, opts ...grpc.CallOption) (*ListUserBooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserBooksResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_ListByBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) LockBook(ctx context.Context, in *LockBookRequest, opts ...grpc.CallOption) (*AssignBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignBookResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_LockBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) UnlockBook(ctx context.Context, in *LockBookRequest, opts ...grpc.CallOption) (*UnassignBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnassignBookResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_UnlockBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userLibraryServiceClient) ReleaseLocks(ctx context.Context, in *ReleaseLocksRequest, opts ...grpc.CallOption) (*ReleaseLocksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseLocksResponse)
+	err := c.cc.Invoke(ctx, UserLibraryService_ReleaseLocks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserLibraryServiceServer is the server API for UserLibraryService service.
 // All implementations must embed UnimplementedUserLibraryServiceServer
 // for forward compatibility.
-//
-// Сервис
 type UserLibraryServiceServer interface {
 	AssignBook(context.Context, *AssignBookRequest) (*AssignBookResponse, error)
 	UnassignBook(context.Context, *UnassignBookRequest) (*UnassignBookResponse, error)
 	ListUserBooks(context.Context, *ListUserBooksRequest) (*ListUserBooksResponse, error)
+	ListUserBooksByStatus(context.Context, *ListByStatusRequest) (*ListUserBooksResponse, error)
+	UpdateProgress(context.Context, *UpdateProgressRequest) (*AssignBookResponse, error)
+	GetEntry(context.Context, *GetEntryRequest) (*AssignBookResponse, error)
+	DeleteEntry(context.Context, *DeleteEntryRequest) (*UnassignBookResponse, error)
+	UpdateEntry(context.Context, *UpdateEntryRequest) (*AssignBookResponse, error)
+	ListAllEntries(context.Context, *emptypb.Empty) (*ListUserBooksResponse, error)
+	ListByBook(context.Context, *ListByBookRequest) (*ListUserBooksResponse, error)
+	LockBook(context.Context, *LockBookRequest) (*AssignBookResponse, error)
+	UnlockBook(context.Context, *LockBookRequest) (*UnassignBookResponse, error)
+	ReleaseLocks(context.Context, *ReleaseLocksRequest) (*ReleaseLocksResponse, error)
 	mustEmbedUnimplementedUserLibraryServiceServer()
 }
 
@@ -101,6 +228,36 @@ func (UnimplementedUserLibraryServiceServer) UnassignBook(context.Context, *Unas
 func (UnimplementedUserLibraryServiceServer) ListUserBooks(context.Context, *ListUserBooksRequest) (*ListUserBooksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUserBooks not implemented")
 }
+func (UnimplementedUserLibraryServiceServer) ListUserBooksByStatus(context.Context, *ListByStatusRequest) (*ListUserBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserBooksByStatus not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) UpdateProgress(context.Context, *UpdateProgressRequest) (*AssignBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProgress not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) GetEntry(context.Context, *GetEntryRequest) (*AssignBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEntry not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) DeleteEntry(context.Context, *DeleteEntryRequest) (*UnassignBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEntry not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) UpdateEntry(context.Context, *UpdateEntryRequest) (*AssignBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEntry not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) ListAllEntries(context.Context, *emptypb.Empty) (*ListUserBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAllEntries not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) ListByBook(context.Context, *ListByBookRequest) (*ListUserBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListByBook not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) LockBook(context.Context, *LockBookRequest) (*AssignBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LockBook not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) UnlockBook(context.Context, *LockBookRequest) (*UnassignBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockBook not implemented")
+}
+func (UnimplementedUserLibraryServiceServer) ReleaseLocks(context.Context, *ReleaseLocksRequest) (*ReleaseLocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseLocks not implemented")
+}
 func (UnimplementedUserLibraryServiceServer) mustEmbedUnimplementedUserLibraryServiceServer() {}
 func (UnimplementedUserLibraryServiceServer) testEmbeddedByValue()                            {}
 
@@ -176,6 +333,186 @@ func _UserLibraryService_ListUserBooks_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserLibraryService_ListUserBooksByStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListByStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).ListUserBooksByStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_ListUserBooksByStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).ListUserBooksByStatus(ctx, req.(*ListByStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_UpdateProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).UpdateProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_UpdateProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).UpdateProgress(ctx, req.(*UpdateProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_GetEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).GetEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_GetEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).GetEntry(ctx, req.(*GetEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_DeleteEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).DeleteEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_DeleteEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).DeleteEntry(ctx, req.(*DeleteEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_UpdateEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).UpdateEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_UpdateEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).UpdateEntry(ctx, req.(*UpdateEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_ListAllEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).ListAllEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_ListAllEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).ListAllEntries(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_ListByBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListByBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).ListByBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_ListByBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).ListByBook(ctx, req.(*ListByBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_LockBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).LockBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_LockBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).LockBook(ctx, req.(*LockBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_UnlockBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).UnlockBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_UnlockBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).UnlockBook(ctx, req.(*LockBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserLibraryService_ReleaseLocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseLocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserLibraryServiceServer).ReleaseLocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserLibraryService_ReleaseLocks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserLibraryServiceServer).ReleaseLocks(ctx, req.(*ReleaseLocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserLibraryService_ServiceDesc is the grpc.ServiceDesc for UserLibraryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -195,6 +532,46 @@ var UserLibraryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListUserBooks",
 			Handler:    _UserLibraryService_ListUserBooks_Handler,
 		},
+		{
+			MethodName: "ListUserBooksByStatus",
+			Handler:    _UserLibraryService_ListUserBooksByStatus_Handler,
+		},
+		{
+			MethodName: "UpdateProgress",
+			Handler:    _UserLibraryService_UpdateProgress_Handler,
+		},
+		{
+			MethodName: "GetEntry",
+			Handler:    _UserLibraryService_GetEntry_Handler,
+		},
+		{
+			MethodName: "DeleteEntry",
+			Handler:    _UserLibraryService_DeleteEntry_Handler,
+		},
+		{
+			MethodName: "UpdateEntry",
+			Handler:    _UserLibraryService_UpdateEntry_Handler,
+		},
+		{
+			MethodName: "ListAllEntries",
+			Handler:    _UserLibraryService_ListAllEntries_Handler,
+		},
+		{
+			MethodName: "ListByBook",
+			Handler:    _UserLibraryService_ListByBook_Handler,
+		},
+		{
+			MethodName: "LockBook",
+			Handler:    _UserLibraryService_LockBook_Handler,
+		},
+		{
+			MethodName: "UnlockBook",
+			Handler:    _UserLibraryService_UnlockBook_Handler,
+		},
+		{
+			MethodName: "ReleaseLocks",
+			Handler:    _UserLibraryService_ReleaseLocks_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "userlibrary.proto",
@@ -8,6 +8,7 @@ import (
 	"github.com/nats-io/nats.go"
 	"google.golang.org/grpc"
 
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
 	"github.com/OshakbayAigerim/read_space/user_service/internal/cache"
 	"github.com/OshakbayAigerim/read_space/user_service/internal/config"
 	"github.com/OshakbayAigerim/read_space/user_service/internal/handler"
@@ -21,6 +22,9 @@ func main() {
 	db := client.Database("readspace")
 
 	migrations.CreateUserCollectionIndexes(db)
+	migrations.HashPlaintextPasswords(db)
+	migrations.BackfillRoles(db)
+	migrations.BootstrapAdmin(db, config.AdminEmail())
 
 	redisClient := config.ConnectRedis()
 	userCache := cache.NewUserCache(redisClient)
@@ -33,13 +37,17 @@ func main() {
 
 	userRepo := repository.NewMongoUserRepository(db, userCache)
 	userUC := usecase.NewUserUseCase(userRepo)
-	srv := handler.NewUserHandler(userUC, nc)
+	jwtSecret := auth.SecretFromEnv()
+	srv := handler.NewUserHandler(userUC, nc, jwtSecret)
 
 	lis, err := net.Listen("tcp", ":50052")
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(jwtSecret)),
+		grpc.StreamInterceptor(auth.StreamServerInterceptor(jwtSecret)),
+	)
 	pb.RegisterUserServiceServer(grpcServer, srv)
 
 	log.Println("UserService gRPC server started on port 50052")
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -46,3 +47,8 @@ func ConnectRedis() *redis.Client {
 	log.Println(" Connected to Redis for UserService")
 	return client
 }
+
+// AdminEmail is the account that receives the admin role on startup.
+func AdminEmail() string {
+	return os.Getenv("ADMIN_EMAIL")
+}
@@ -1,10 +1,60 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"errors"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+)
+
+// User is cached in Redis as JSON; the credential fields are left out of it
+// and are only ever read from MongoDB.
 type User struct {
-	ID       primitive.ObjectID
-	Name     string
-	Email    string
-	Password string
+	ID               primitive.ObjectID `bson:"_id,omitempty"`
+	Name             string             `bson:"name"`
+	Email            string             `bson:"email"`
+	DisplayName      string             `bson:"display_name"`
+	AvatarURL        string             `bson:"avatar_url"`
+	Bio              string             `bson:"bio"`
+	Languages        []string           `bson:"preferred_languages"`
+	Genres           []string           `bson:"preferred_genres"`
+	PasswordHash     string             `bson:"password_hash" json:"-"`
+	Roles            []string           `bson:"roles"`
+	ResetTokenHash   string             `bson:"reset_token_hash,omitempty" json:"-"`
+	ResetTokenExpiry primitive.DateTime `bson:"reset_token_expiry,omitempty" json:"-"`
+}
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidResetToken  = errors.New("invalid or expired reset token")
+	ErrWeakPassword       = errors.New("password must be at least 8 characters")
+	ErrUnknownRole        = errors.New("unknown role")
+)
+
+// Roles lists every role that can be granted. New users start as readers;
+// librarians manage the catalog and admins manage users and see global
+// listings.
+var Roles = []string{auth.RoleReader, auth.RoleLibrarian, auth.RoleAdmin}
+
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// UserDeletedEvent is published on "user.deleted" so that other services
+// can drop the user's orders, offers and library entries.
+type UserDeletedEvent struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+type PasswordResetRequestedEvent struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	Token  string `json:"token"`
 }
@@ -3,12 +3,17 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"time"
 
 	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
 	"github.com/OshakbayAigerim/read_space/user_service/internal/domain"
 	"github.com/OshakbayAigerim/read_space/user_service/internal/usecase"
 	pb "github.com/OshakbayAigerim/read_space/user_service/proto"
@@ -16,24 +21,30 @@ import (
 
 type UserHandler struct {
 	pb.UnimplementedUserServiceServer
-	uc usecase.UserUseCase
-	nc *nats.Conn
+	uc        usecase.UserUseCase
+	nc        *nats.Conn
+	jwtSecret []byte
 }
 
-func NewUserHandler(u usecase.UserUseCase, nc *nats.Conn) *UserHandler {
-	return &UserHandler{uc: u, nc: nc}
+func NewUserHandler(u usecase.UserUseCase, nc *nats.Conn, jwtSecret []byte) *UserHandler {
+	return &UserHandler{uc: u, nc: nc, jwtSecret: jwtSecret}
 }
 
 func (h *UserHandler) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
 	if req == nil || req.User == nil {
 		return nil, status.Error(codes.InvalidArgument, "empty request")
 	}
-	user := &domain.User{
-		Name:     req.User.Name,
-		Email:    req.User.Email,
-		Password: req.User.Password,
+	if req.User.Email == "" || req.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and password are required")
+	}
+	user := fromProto(req.User)
+	created, err := h.uc.CreateUser(ctx, user, req.Password)
+	if errors.Is(err, domain.ErrWeakPassword) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, status.Error(codes.AlreadyExists, "email is already registered")
 	}
-	created, err := h.uc.CreateUser(ctx, user)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot create user: %v", err)
 	}
@@ -53,16 +64,11 @@ func (h *UserHandler) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 		}
 	}
 
-	return &pb.UserResponse{
-		User: &pb.User{
-			Id:       created.ID.Hex(),
-			Name:     created.Name,
-			Email:    created.Email,
-			Password: created.Password,
-		},
-	}, nil
+	return &pb.UserResponse{User: toProto(created)}, nil
 }
 
+// GetUser returns the whole profile to the user, admins and services;
+// anyone else gets only the public part of it.
 func (h *UserHandler) GetUser(ctx context.Context, req *pb.UserID) (*pb.UserResponse, error) {
 	if req == nil || req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "user ID is required")
@@ -71,30 +77,260 @@ func (h *UserHandler) GetUser(ctx context.Context, req *pb.UserID) (*pb.UserResp
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
 	}
-	return &pb.UserResponse{
-		User: &pb.User{
-			Id:       user.ID.Hex(),
-			Name:     user.Name,
-			Email:    user.Email,
-			Password: user.Password,
-		},
-	}, nil
+	if auth.AuthorizeUser(ctx, req.Id) != nil {
+		return &pb.UserResponse{User: publicProto(user)}, nil
+	}
+	return &pb.UserResponse{User: toProto(user)}, nil
 }
 
 func (h *UserHandler) ListAllUsers(_ *pb.Empty, stream pb.UserService_ListAllUsersServer) error {
+	if err := auth.RequireRole(stream.Context(), auth.RoleAdmin); err != nil {
+		return err
+	}
 	users, err := h.uc.ListUsers(stream.Context())
 	if err != nil {
 		return status.Errorf(codes.Internal, "cannot list users: %v", err)
 	}
 	for _, u := range users {
-		if err := stream.Send(&pb.User{
-			Id:       u.ID.Hex(),
-			Name:     u.Name,
-			Email:    u.Email,
-			Password: u.Password,
-		}); err != nil {
+		if err := stream.Send(toProto(u)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+func (h *UserHandler) GetUserByEmail(ctx context.Context, req *pb.EmailRequest) (*pb.UserResponse, error) {
+	if req == nil || req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	if _, err := auth.RequireIdentity(ctx); err != nil {
+		return nil, err
+	}
+	user, err := h.uc.GetUserByEmail(ctx, req.Email)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get user: %v", err)
+	}
+	return &pb.UserResponse{User: toProto(user)}, nil
+}
+
+func (h *UserHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
+	if req == nil || req.User == nil || req.User.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "user ID is required")
+	}
+	if req.User.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	objID, err := primitive.ObjectIDFromHex(req.User.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+	if err := auth.AuthorizeUser(ctx, req.User.Id); err != nil {
+		return nil, err
+	}
+	user := fromProto(req.User)
+	user.ID = objID
+	updated, err := h.uc.UpdateUser(ctx, user)
+	switch {
+	case mongo.IsDuplicateKeyError(err):
+		return nil, status.Error(codes.AlreadyExists, "email is already registered")
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "cannot update user: %v", err)
+	}
+	return &pb.UserResponse{User: toProto(updated)}, nil
+}
+
+func (h *UserHandler) DeleteUser(ctx context.Context, req *pb.UserID) (*pb.Empty, error) {
+	if req == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "user ID is required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	deleted, err := h.uc.DeleteUser(ctx, req.Id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot delete user: %v", err)
+	}
+
+	evt := domain.UserDeletedEvent{
+		UserID: deleted.ID.Hex(),
+		Email:  deleted.Email,
+	}
+	if data, err := json.Marshal(evt); err == nil {
+		if err := h.nc.Publish("user.deleted", data); err != nil {
+			log.Printf("⚠ NATS publish error (user.deleted): %v", err)
+		}
+	}
+	return &pb.Empty{}, nil
+}
+
+func (h *UserHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	if req == nil || req.Email == "" || req.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and password are required")
+	}
+	user, err := h.uc.Login(ctx, req.Email, req.Password)
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot log in: %v", err)
+	}
+	token, expiresAt, err := auth.IssueToken(h.jwtSecret, user.ID.Hex(), user.Roles, auth.AccessTokenTTL)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot issue token: %v", err)
+	}
+	return &pb.LoginResponse{
+		User:        toProto(user),
+		AccessToken: token,
+		ExpiresAt:   expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (h *UserHandler) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.Empty, error) {
+	if req == nil || req.UserId == "" || req.OldPassword == "" || req.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id, old_password and new_password are required")
+	}
+	if err := auth.AuthorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	err := h.uc.ChangePassword(ctx, req.UserId, req.OldPassword, req.NewPassword)
+	switch {
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return nil, status.Error(codes.Unauthenticated, "old password is incorrect")
+	case errors.Is(err, domain.ErrWeakPassword):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return nil, status.Error(codes.NotFound, "user not found")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "cannot change password: %v", err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (h *UserHandler) RequestPasswordReset(ctx context.Context, req *pb.PasswordResetRequest) (*pb.Empty, error) {
+	if req == nil || req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+	user, token, err := h.uc.RequestPasswordReset(ctx, req.Email)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// не раскрываем, зарегистрирован ли email
+		return &pb.Empty{}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot request password reset: %v", err)
+	}
+
+	evt := domain.PasswordResetRequestedEvent{
+		UserID: user.ID.Hex(),
+		Email:  user.Email,
+		Token:  token,
+	}
+	if data, err := json.Marshal(evt); err == nil {
+		if err := h.nc.Publish("user.password_reset_requested", data); err != nil {
+			log.Printf("⚠ NATS publish error (user.password_reset_requested): %v", err)
+		}
+	}
+	return &pb.Empty{}, nil
+}
+
+func (h *UserHandler) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.Empty, error) {
+	if req == nil || req.Token == "" || req.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "token and new_password are required")
+	}
+	err := h.uc.ResetPassword(ctx, req.Token, req.NewPassword)
+	switch {
+	case errors.Is(err, domain.ErrInvalidResetToken):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrWeakPassword):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "cannot reset password: %v", err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (h *UserHandler) GrantRole(ctx context.Context, req *pb.RoleRequest) (*pb.UserResponse, error) {
+	if req == nil || req.UserId == "" || req.Role == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and role are required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	user, err := h.uc.GrantRole(ctx, req.UserId, req.Role)
+	if err != nil {
+		return nil, roleError(err)
+	}
+	return &pb.UserResponse{User: toProto(user)}, nil
+}
+
+func (h *UserHandler) RevokeRole(ctx context.Context, req *pb.RoleRequest) (*pb.UserResponse, error) {
+	if req == nil || req.UserId == "" || req.Role == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and role are required")
+	}
+	if err := auth.RequireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+	// не даём администратору случайно лишить себя доступа
+	if id, _ := auth.FromContext(ctx); id.UserID == req.UserId && req.Role == auth.RoleAdmin {
+		return nil, status.Error(codes.FailedPrecondition, "cannot revoke your own admin role")
+	}
+	user, err := h.uc.RevokeRole(ctx, req.UserId, req.Role)
+	if err != nil {
+		return nil, roleError(err)
+	}
+	return &pb.UserResponse{User: toProto(user)}, nil
+}
+
+func roleError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnknownRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, "user not found")
+	default:
+		return status.Errorf(codes.Internal, "cannot update roles: %v", err)
+	}
+}
+
+func toProto(u *domain.User) *pb.User {
+	return &pb.User{
+		Id:                 u.ID.Hex(),
+		Name:               u.Name,
+		Email:              u.Email,
+		Roles:              u.Roles,
+		DisplayName:        u.DisplayName,
+		AvatarUrl:          u.AvatarURL,
+		Bio:                u.Bio,
+		PreferredLanguages: u.Languages,
+		PreferredGenres:    u.Genres,
+	}
+}
+
+// publicProto leaves out the email and roles, which only the user, admins
+// and services may see.
+func publicProto(u *domain.User) *pb.User {
+	p := toProto(u)
+	p.Email, p.Roles = "", nil
+	return p
+}
+
+// fromProto copies the profile fields; ID, roles and credentials are set
+// by the caller where appropriate.
+func fromProto(u *pb.User) *domain.User {
+	return &domain.User{
+		Name:        u.Name,
+		Email:       u.Email,
+		DisplayName: u.DisplayName,
+		AvatarURL:   u.AvatarUrl,
+		Bio:         u.Bio,
+		Languages:   u.PreferredLanguages,
+		Genres:      u.PreferredGenres,
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
 )
 
 func CreateUserCollectionIndexes(db *mongo.Database) {
@@ -17,6 +20,10 @@ func CreateUserCollectionIndexes(db *mongo.Database) {
 			Keys:    bson.D{{Key: "email", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		{
+			Keys:    bson.D{{Key: "reset_token_hash", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -29,3 +36,93 @@ func CreateUserCollectionIndexes(db *mongo.Database) {
 
 	log.Println("Created indexes for users collection")
 }
+
+// HashPlaintextPasswords converts accounts created before password hashing
+// was introduced: the legacy plaintext "password" field is replaced by a
+// bcrypt "password_hash".
+func HashPlaintextPasswords(db *mongo.Database) {
+	collection := db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	filter := bson.M{"password": bson.M{"$exists": true}}
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		log.Fatalf("Failed to find users with plaintext passwords: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID       interface{} `bson:"_id"`
+			Password string      `bson:"password"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			log.Fatalf("Failed to decode user: %v", err)
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(doc.Password), bcrypt.DefaultCost)
+		if err != nil {
+			log.Fatalf("Failed to hash password: %v", err)
+		}
+		update := bson.M{
+			"$set":   bson.M{"password_hash": string(hash)},
+			"$unset": bson.M{"password": ""},
+		}
+		if _, err := collection.UpdateByID(ctx, doc.ID, update); err != nil {
+			log.Fatalf("Failed to update user %v: %v", doc.ID, err)
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("Hashed plaintext passwords for %d users", migrated)
+	}
+}
+
+// BackfillRoles gives the reader role to accounts created before roles
+// were introduced.
+func BackfillRoles(db *mongo.Database) {
+	collection := db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	filter := bson.M{"roles": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"roles": bson.A{auth.RoleReader}}}
+	res, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		log.Fatalf("Failed to backfill user roles: %v", err)
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("Granted reader role to %d users", res.ModifiedCount)
+	}
+}
+
+// BootstrapAdmin grants the admin role to the account with the given email,
+// so that a fresh deployment has someone who can grant roles to others.
+func BootstrapAdmin(db *mongo.Database, email string) {
+	if email == "" {
+		return
+	}
+	collection := db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateOne(ctx,
+		bson.M{"email": email},
+		bson.M{"$addToSet": bson.M{"roles": auth.RoleAdmin}},
+	)
+	if err != nil {
+		log.Fatalf("Failed to bootstrap admin: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		log.Printf("Bootstrap admin %s is not registered yet", email)
+		return
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("Granted admin role to %s", email)
+	}
+}
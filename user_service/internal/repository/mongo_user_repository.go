@@ -45,6 +45,19 @@ func (r *mongoUserRepo) GetByID(ctx context.Context, id string) (*domain.User, e
 		return user, nil
 	}
 
+	user, err := r.GetWithCredentials(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		_ = r.cache.Set(context.Background(), user)
+	}()
+
+	return user, nil
+}
+
+func (r *mongoUserRepo) GetWithCredentials(ctx context.Context, id string) (*domain.User, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
@@ -58,11 +71,6 @@ func (r *mongoUserRepo) GetByID(ctx context.Context, id string) (*domain.User, e
 	if err != nil {
 		return nil, err
 	}
-
-	go func() {
-		_ = r.cache.Set(context.Background(), &user)
-	}()
-
 	return &user, nil
 }
 
@@ -86,3 +94,144 @@ func (r *mongoUserRepo) ListAll(ctx context.Context) ([]*domain.User, error) {
 	}
 	return users, nil
 }
+
+func (r *mongoUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var user domain.User
+	if err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Update overwrites the profile fields of the user; credentials and roles
+// are changed through their own methods.
+func (r *mongoUserRepo) Update(ctx context.Context, user *domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"name":                user.Name,
+		"email":               user.Email,
+		"display_name":        user.DisplayName,
+		"avatar_url":          user.AvatarURL,
+		"bio":                 user.Bio,
+		"preferred_languages": user.Languages,
+		"preferred_genres":    user.Genres,
+	}}
+	res, err := r.collection.UpdateByID(ctx, user.ID, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_ = r.cache.Delete(ctx, user.ID.Hex())
+	return nil
+}
+
+func (r *mongoUserRepo) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_ = r.cache.Delete(ctx, id)
+	return nil
+}
+
+func (r *mongoUserRepo) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set":   bson.M{"password_hash": passwordHash},
+		"$unset": bson.M{"reset_token_hash": "", "reset_token_expiry": ""},
+	}
+	res, err := r.collection.UpdateByID(ctx, objID, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_ = r.cache.Delete(ctx, id)
+	return nil
+}
+
+func (r *mongoUserRepo) SetResetToken(ctx context.Context, id, tokenHash string, expiresAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"reset_token_hash":   tokenHash,
+		"reset_token_expiry": primitive.NewDateTimeFromTime(expiresAt),
+	}}
+	if _, err := r.collection.UpdateByID(ctx, objID, update); err != nil {
+		return err
+	}
+
+	_ = r.cache.Delete(ctx, id)
+	return nil
+}
+
+func (r *mongoUserRepo) GetByResetToken(ctx context.Context, tokenHash string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var user domain.User
+	if err := r.collection.FindOne(ctx, bson.M{"reset_token_hash": tokenHash}).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *mongoUserRepo) AddRole(ctx context.Context, id, role string) error {
+	return r.updateRoles(ctx, id, bson.M{"$addToSet": bson.M{"roles": role}})
+}
+
+func (r *mongoUserRepo) RemoveRole(ctx context.Context, id, role string) error {
+	return r.updateRoles(ctx, id, bson.M{"$pull": bson.M{"roles": role}})
+}
+
+func (r *mongoUserRepo) updateRoles(ctx context.Context, id string, update bson.M) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := r.collection.UpdateByID(ctx, objID, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_ = r.cache.Delete(ctx, id)
+	return nil
+}
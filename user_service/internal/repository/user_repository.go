@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/OshakbayAigerim/read_space/user_service/internal/domain"
 )
@@ -9,5 +10,16 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) (*domain.User, error)
 	GetByID(ctx context.Context, id string) (*domain.User, error)
+	// GetWithCredentials reads the user from the database, bypassing the
+	// cache, which never holds the password and reset token hashes.
+	GetWithCredentials(ctx context.Context, id string) (*domain.User, error)
 	ListAll(ctx context.Context) ([]*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	Update(ctx context.Context, user *domain.User) error
+	Delete(ctx context.Context, id string) error
+	UpdatePassword(ctx context.Context, id, passwordHash string) error
+	SetResetToken(ctx context.Context, id, tokenHash string, expiresAt time.Time) error
+	GetByResetToken(ctx context.Context, tokenHash string) (*domain.User, error)
+	AddRole(ctx context.Context, id, role string) error
+	RemoveRole(ctx context.Context, id, role string) error
 }
@@ -2,15 +2,40 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
 
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
 	"github.com/OshakbayAigerim/read_space/user_service/internal/domain"
 	"github.com/OshakbayAigerim/read_space/user_service/internal/repository"
 )
 
+const (
+	minPasswordLength = 8
+	resetTokenTTL     = 30 * time.Minute
+)
+
 type UserUseCase interface {
-	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User, password string) (*domain.User, error)
 	GetUserByID(ctx context.Context, id string) (*domain.User, error)
 	ListUsers(ctx context.Context) ([]*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	DeleteUser(ctx context.Context, id string) (*domain.User, error)
+
+	Login(ctx context.Context, email, password string) (*domain.User, error)
+	ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error
+	RequestPasswordReset(ctx context.Context, email string) (*domain.User, string, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+
+	GrantRole(ctx context.Context, userID, role string) (*domain.User, error)
+	RevokeRole(ctx context.Context, userID, role string) (*domain.User, error)
 }
 
 type userUseCase struct {
@@ -21,7 +46,13 @@ func NewUserUseCase(r repository.UserRepository) UserUseCase {
 	return &userUseCase{repo: r}
 }
 
-func (u *userUseCase) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+func (u *userUseCase) CreateUser(ctx context.Context, user *domain.User, password string) (*domain.User, error) {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = hash
+	user.Roles = []string{auth.RoleReader}
 	return u.repo.Create(ctx, user)
 }
 
@@ -32,3 +63,130 @@ func (u *userUseCase) GetUserByID(ctx context.Context, id string) (*domain.User,
 func (u *userUseCase) ListUsers(ctx context.Context) ([]*domain.User, error) {
 	return u.repo.ListAll(ctx)
 }
+
+func (u *userUseCase) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return u.repo.GetByEmail(ctx, email)
+}
+
+func (u *userUseCase) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	if err := u.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return u.repo.GetByID(ctx, user.ID.Hex())
+}
+
+// DeleteUser removes the account and returns it as it was before deletion.
+func (u *userUseCase) DeleteUser(ctx context.Context, id string) (*domain.User, error) {
+	user, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *userUseCase) Login(ctx context.Context, email, password string) (*domain.User, error) {
+	user, err := u.repo.GetByEmail(ctx, email)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+func (u *userUseCase) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	user, err := u.repo.GetWithCredentials(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPassword)) != nil {
+		return domain.ErrInvalidCredentials
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	return u.repo.UpdatePassword(ctx, userID, hash)
+}
+
+// RequestPasswordReset issues a one-time reset token for the account with the
+// given email. Only the token's hash is stored; the plain token is returned so
+// the caller can deliver it to the user.
+func (u *userUseCase) RequestPasswordReset(ctx context.Context, email string) (*domain.User, string, error) {
+	user, err := u.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := u.repo.SetResetToken(ctx, user.ID.Hex(), hashToken(token), time.Now().Add(resetTokenTTL)); err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+func (u *userUseCase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	user, err := u.repo.GetByResetToken(ctx, hashToken(token))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if time.Now().After(user.ResetTokenExpiry.Time()) {
+		return domain.ErrInvalidResetToken
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	return u.repo.UpdatePassword(ctx, user.ID.Hex(), hash)
+}
+
+func (u *userUseCase) GrantRole(ctx context.Context, userID, role string) (*domain.User, error) {
+	if !domain.IsValidRole(role) {
+		return nil, domain.ErrUnknownRole
+	}
+	if err := u.repo.AddRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+	return u.repo.GetByID(ctx, userID)
+}
+
+func (u *userUseCase) RevokeRole(ctx context.Context, userID, role string) (*domain.User, error) {
+	if !domain.IsValidRole(role) {
+		return nil, domain.ErrUnknownRole
+	}
+	if err := u.repo.RemoveRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+	return u.repo.GetByID(ctx, userID)
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", domain.ErrWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OshakbayAigerim/read_space/pkg/auth"
+	"github.com/OshakbayAigerim/read_space/user_service/internal/domain"
+	"github.com/OshakbayAigerim/read_space/user_service/internal/repository"
+)
+
+type fakeRepo struct {
+	repository.UserRepository
+	users map[string]*domain.User
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{users: map[string]*domain.User{}}
+}
+
+func (r *fakeRepo) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	user.ID = primitive.NewObjectID()
+	r.users[user.ID.Hex()] = user
+	return user, nil
+}
+func (r *fakeRepo) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+func (r *fakeRepo) GetWithCredentials(ctx context.Context, id string) (*domain.User, error) {
+	return r.GetByID(ctx, id)
+}
+func (r *fakeRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+func (r *fakeRepo) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	u := r.users[id]
+	u.PasswordHash = passwordHash
+	u.ResetTokenHash = ""
+	return nil
+}
+func (r *fakeRepo) SetResetToken(ctx context.Context, id, tokenHash string, expiresAt time.Time) error {
+	u := r.users[id]
+	u.ResetTokenHash = tokenHash
+	u.ResetTokenExpiry = primitive.NewDateTimeFromTime(expiresAt)
+	return nil
+}
+func (r *fakeRepo) GetByResetToken(ctx context.Context, tokenHash string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.ResetTokenHash == tokenHash {
+			return u, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *fakeRepo) AddRole(ctx context.Context, id, role string) error {
+	u, ok := r.users[id]
+	if !ok {
+		return mongo.ErrNoDocuments
+	}
+	for _, have := range u.Roles {
+		if have == role {
+			return nil
+		}
+	}
+	u.Roles = append(u.Roles, role)
+	return nil
+}
+func (r *fakeRepo) RemoveRole(ctx context.Context, id, role string) error {
+	u, ok := r.users[id]
+	if !ok {
+		return mongo.ErrNoDocuments
+	}
+	kept := u.Roles[:0]
+	for _, have := range u.Roles {
+		if have != role {
+			kept = append(kept, have)
+		}
+	}
+	u.Roles = kept
+	return nil
+}
+
+func (r *fakeRepo) Update(ctx context.Context, user *domain.User) error {
+	u, ok := r.users[user.ID.Hex()]
+	if !ok {
+		return mongo.ErrNoDocuments
+	}
+	u.Name, u.Email, u.DisplayName, u.AvatarURL, u.Bio = user.Name, user.Email, user.DisplayName, user.AvatarURL, user.Bio
+	u.Languages, u.Genres = user.Languages, user.Genres
+	return nil
+}
+func (r *fakeRepo) Delete(ctx context.Context, id string) error {
+	if _, ok := r.users[id]; !ok {
+		return mongo.ErrNoDocuments
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func TestCreateUser_HashesPassword(t *testing.T) {
+	uc := NewUserUseCase(newFakeRepo())
+
+	u, err := uc.CreateUser(context.Background(), &domain.User{Email: "a@b.c"}, "secret-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.PasswordHash == "" || u.PasswordHash == "secret-pass" {
+		t.Errorf("password not hashed: %q", u.PasswordHash)
+	}
+
+	if _, err := uc.CreateUser(context.Background(), &domain.User{Email: "x@y.z"}, "short"); !errors.Is(err, domain.ErrWeakPassword) {
+		t.Errorf("expected ErrWeakPassword, got %v", err)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	uc := NewUserUseCase(newFakeRepo())
+	ctx := context.Background()
+	uc.CreateUser(ctx, &domain.User{Email: "a@b.c"}, "secret-pass")
+
+	if _, err := uc.Login(ctx, "a@b.c", "secret-pass"); err != nil {
+		t.Errorf("valid login failed: %v", err)
+	}
+	if _, err := uc.Login(ctx, "a@b.c", "wrong-pass"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for wrong password, got %v", err)
+	}
+	if _, err := uc.Login(ctx, "nobody@b.c", "secret-pass"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for unknown email, got %v", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	uc := NewUserUseCase(newFakeRepo())
+	ctx := context.Background()
+	u, _ := uc.CreateUser(ctx, &domain.User{Email: "a@b.c"}, "secret-pass")
+
+	if err := uc.ChangePassword(ctx, u.ID.Hex(), "wrong-pass", "new-secret"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if err := uc.ChangePassword(ctx, u.ID.Hex(), "secret-pass", "new-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Login(ctx, "a@b.c", "new-secret"); err != nil {
+		t.Errorf("login with new password failed: %v", err)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	repo := newFakeRepo()
+	uc := NewUserUseCase(repo)
+	ctx := context.Background()
+	u, _ := uc.CreateUser(ctx, &domain.User{Email: "a@b.c"}, "secret-pass")
+
+	_, token, err := uc.RequestPasswordReset(ctx, "a@b.c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.users[u.ID.Hex()].ResetTokenHash == token {
+		t.Error("reset token stored in plaintext")
+	}
+
+	if err := uc.ResetPassword(ctx, "bogus", "new-secret"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Errorf("expected ErrInvalidResetToken, got %v", err)
+	}
+	if err := uc.ResetPassword(ctx, token, "new-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Login(ctx, "a@b.c", "new-secret"); err != nil {
+		t.Errorf("login after reset failed: %v", err)
+	}
+	if err := uc.ResetPassword(ctx, token, "another-secret"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Errorf("reset token reusable, got %v", err)
+	}
+}
+
+func TestResetPassword_Expired(t *testing.T) {
+	repo := newFakeRepo()
+	uc := NewUserUseCase(repo)
+	ctx := context.Background()
+	u, _ := uc.CreateUser(ctx, &domain.User{Email: "a@b.c"}, "secret-pass")
+
+	_, token, _ := uc.RequestPasswordReset(ctx, "a@b.c")
+	repo.users[u.ID.Hex()].ResetTokenExpiry = primitive.NewDateTimeFromTime(time.Now().Add(-time.Minute))
+
+	if err := uc.ResetPassword(ctx, token, "new-secret"); !errors.Is(err, domain.ErrInvalidResetToken) {
+		t.Errorf("expected ErrInvalidResetToken for expired token, got %v", err)
+	}
+}
+
+func TestGrantAndRevokeRole(t *testing.T) {
+	uc := NewUserUseCase(newFakeRepo())
+	ctx := context.Background()
+	u, _ := uc.CreateUser(ctx, &domain.User{Email: "a@b.c"}, "secret-pass")
+	if len(u.Roles) != 1 || u.Roles[0] != auth.RoleReader {
+		t.Fatalf("new user roles = %v, want [reader]", u.Roles)
+	}
+
+	if _, err := uc.GrantRole(ctx, u.ID.Hex(), "superuser"); !errors.Is(err, domain.ErrUnknownRole) {
+		t.Errorf("expected ErrUnknownRole, got %v", err)
+	}
+	u, err := uc.GrantRole(ctx, u.ID.Hex(), auth.RoleLibrarian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ = uc.GrantRole(ctx, u.ID.Hex(), auth.RoleLibrarian)
+	if len(u.Roles) != 2 {
+		t.Errorf("roles after granting librarian twice = %v", u.Roles)
+	}
+	u, err = uc.RevokeRole(ctx, u.ID.Hex(), auth.RoleLibrarian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(u.Roles) != 1 || u.Roles[0] != auth.RoleReader {
+		t.Errorf("roles after revoke = %v, want [reader]", u.Roles)
+	}
+}
+
+func TestUpdateUser_KeepsCredentials(t *testing.T) {
+	uc := NewUserUseCase(newFakeRepo())
+	ctx := context.Background()
+	u, _ := uc.CreateUser(ctx, &domain.User{Name: "A", Email: "a@b.c"}, "secret-pass")
+
+	updated, err := uc.UpdateUser(ctx, &domain.User{
+		ID:          u.ID,
+		Name:        "Alice",
+		Email:       "alice@b.c",
+		DisplayName: "alice",
+		Genres:      []string{"Fantasy"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Alice" || updated.DisplayName != "alice" || len(updated.Genres) != 1 {
+		t.Errorf("profile not updated: %+v", updated)
+	}
+	if _, err := uc.Login(ctx, "alice@b.c", "secret-pass"); err != nil {
+		t.Errorf("login after email change failed: %v", err)
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	uc := NewUserUseCase(newFakeRepo())
+	ctx := context.Background()
+	u, _ := uc.CreateUser(ctx, &domain.User{Email: "a@b.c"}, "secret-pass")
+
+	deleted, err := uc.DeleteUser(ctx, u.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.Email != "a@b.c" {
+		t.Errorf("deleted user email = %q", deleted.Email)
+	}
+	if _, err := uc.GetUserByID(ctx, u.ID.Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments after delete, got %v", err)
+	}
+	if _, err := uc.DeleteUser(ctx, u.ID.Hex()); !errors.Is(err, mongo.ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments on second delete, got %v", err)
+	}
+}